@@ -22,8 +22,13 @@ import (
 
 	"cloud.google.com/go/pubsub"
 
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/handler"
+	"github.com/google/knative-gcp/pkg/broker/handler/auth"
+	"github.com/google/knative-gcp/pkg/broker/handler/breaker"
+	"github.com/google/knative-gcp/pkg/broker/status"
+	"github.com/google/knative-gcp/pkg/gclient/storage"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/utils"
 	"github.com/google/knative-gcp/pkg/utils/appcredentials"
@@ -31,16 +36,19 @@ import (
 	"github.com/google/knative-gcp/pkg/utils/mainhelper"
 
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
-	component        = "broker-fanout"
-	metricNamespace  = "trigger"
-	poolResyncPeriod = 15 * time.Second
+	component          = "broker-fanout"
+	metricNamespace    = "trigger"
+	poolResyncPeriod   = 15 * time.Second
+	statusReportPeriod = 30 * time.Second
 )
 
 type envConfig struct {
 	PodName                string `envconfig:"POD_NAME" required:"true"`
+	PodUID                 string `envconfig:"POD_UID"`
 	TargetsConfigPath      string `envconfig:"TARGETS_CONFIG_PATH" default:"/var/run/cloud-run-events/broker/targets"`
 	HandlerConcurrency     int    `envconfig:"HANDLER_CONCURRENCY"`
 	MaxConcurrencyPerEvent int    `envconfig:"MAX_CONCURRENCY_PER_EVENT"`
@@ -59,6 +67,23 @@ type envConfig struct {
 
 	// Max to 10m.
 	TimeoutPerEvent time.Duration `envconfig:"TIMEOUT_PER_EVENT"`
+
+	// CircuitBreakerFailureThreshold is the number of consecutive failed deliveries
+	// to a subscriber after which its events are sent to the retry queue without
+	// being delivered. Zero disables the circuit breakers.
+	CircuitBreakerFailureThreshold int `envconfig:"CIRCUIT_BREAKER_FAILURE_THRESHOLD" default:"5"`
+
+	// CircuitBreakerOpenTimeout is the duration after which an open circuit breaker
+	// lets a trial event be delivered to the subscriber.
+	CircuitBreakerOpenTimeout time.Duration `envconfig:"CIRCUIT_BREAKER_OPEN_TIMEOUT" default:"30s"`
+
+	// ClaimCheckBucket is the Cloud Storage bucket the ingress offloads the data of
+	// the large events to. The data is loaded back before the events are delivered.
+	ClaimCheckBucket string `envconfig:"CLAIM_CHECK_BUCKET"`
+
+	// HeaderSecretsPath is where the Secret holding the values of the secret headers of
+	// the triggers is mounted.
+	HeaderSecretsPath string `envconfig:"HEADER_SECRETS_PATH" default:"/var/run/cloud-run-events/broker-headers"`
 }
 
 func main() {
@@ -84,6 +109,23 @@ func main() {
 		logger.Fatalf("failed to get default ProjectID: %v", err)
 	}
 
+	var breakers *breaker.Breakers
+	if env.CircuitBreakerFailureThreshold > 0 {
+		breakers = breaker.New(env.CircuitBreakerFailureThreshold, env.CircuitBreakerOpenTimeout)
+	}
+
+	deliveries := status.NewDeliveries()
+
+	var claimChecks *claimcheck.Store
+	if env.ClaimCheckBucket != "" {
+		client, err := storage.NewClient(ctx)
+		if err != nil {
+			logger.Fatal("Failed to create storage client", zap.Error(err))
+		}
+		// The threshold only matters to the ingress offloading the data.
+		claimChecks = claimcheck.NewStore(client, env.ClaimCheckBucket, 0)
+	}
+
 	syncSignal := poolSyncSignal(ctx, targetsUpdateCh)
 	syncPool, err := InitializeSyncPool(
 		ctx,
@@ -94,7 +136,7 @@ func main() {
 			volume.WithPath(env.TargetsConfigPath),
 			volume.WithNotifyChan(targetsUpdateCh),
 		},
-		buildHandlerOptions(env)...,
+		buildHandlerOptions(ctx, env, breakers, deliveries, claimChecks)...,
 	)
 	if err != nil {
 		logger.Fatal("Failed to create fanout sync pool", zap.Error(err))
@@ -103,6 +145,13 @@ func main() {
 		logger.Fatalw("Failed to start fanout sync pool", zap.Error(err))
 	}
 
+	// The deliveries and the state of the circuit breakers are surfaced on the triggers
+	// by the controller.
+	if env.PodUID != "" {
+		reporter := status.NewReporter(res.KubeClient, status.Namespace(), env.PodName, types.UID(env.PodUID), targetStatus(deliveries, breakers))
+		go reporter.Run(ctx, statusReportPeriod)
+	}
+
 	// Context will be done if a TERM signal is issued.
 	<-ctx.Done()
 	// Wait a grace period for the handlers to shutdown.
@@ -130,7 +179,27 @@ func poolSyncSignal(ctx context.Context, targetsUpdateCh chan struct{}) chan str
 	return ch
 }
 
-func buildHandlerOptions(env envConfig) []handler.Option {
+// targetStatus returns the status of the targets with recorded deliveries or with a
+// circuit breaker which isn't closed.
+func targetStatus(deliveries *status.Deliveries, breakers *breaker.Breakers) func(context.Context) map[string]status.TargetStatus {
+	return func(context.Context) map[string]status.TargetStatus {
+		targets := deliveries.Status()
+		if breakers == nil {
+			return targets
+		}
+		for id, s := range breakers.States() {
+			ts, ok := targets[id]
+			if !ok {
+				ts = status.TargetStatus{Namespace: s.Namespace, Name: s.Name}
+			}
+			ts.CircuitBreaker = s.State.String()
+			targets[id] = ts
+		}
+		return targets
+	}
+}
+
+func buildHandlerOptions(ctx context.Context, env envConfig, breakers *breaker.Breakers, deliveries *status.Deliveries, claimChecks *claimcheck.Store) []handler.Option {
 	rs := pubsub.DefaultReceiveSettings
 	var opts []handler.Option
 	if env.HandlerConcurrency > 0 {
@@ -151,6 +220,16 @@ func buildHandlerOptions(env envConfig) []handler.Option {
 		rs.MaxOutstandingMessages = env.MaxOutstandingMessages
 	}
 	opts = append(opts, handler.WithPubsubReceiveSettings(rs))
+	// The tokens of the subscribers are cached and refreshed for the lifetime of the binary.
+	opts = append(opts, handler.WithSubscriberTokens(auth.NewTokens(ctx)))
+	opts = append(opts, handler.WithDeliveries(deliveries))
+	opts = append(opts, handler.WithHeaderSecretsDir(env.HeaderSecretsPath))
+	if claimChecks != nil {
+		opts = append(opts, handler.WithClaimChecks(claimChecks))
+	}
+	if breakers != nil {
+		opts = append(opts, handler.WithCircuitBreakers(breakers))
+	}
 	// The default CeClient is good?
 	return opts
 }
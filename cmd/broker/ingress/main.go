@@ -17,6 +17,11 @@ limitations under the License.
 package main
 
 import (
+	"context"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	"github.com/google/knative-gcp/pkg/broker/ingress"
+	"github.com/google/knative-gcp/pkg/gclient/storage"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/utils"
 	"github.com/google/knative-gcp/pkg/utils/appcredentials"
@@ -25,14 +30,37 @@ import (
 
 	"cloud.google.com/go/pubsub"
 	"go.uber.org/zap"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/system"
 )
 
 type envConfig struct {
 	PodName string `envconfig:"POD_NAME" required:"true"`
 	Port    int    `envconfig:"PORT" default:"8080"`
+	// GRPCPort is the port of the gRPC ingress.
+	GRPCPort int `envconfig:"GRPC_PORT" default:"8081"`
 
 	// Default 300Mi.
 	PublishBufferedByteLimit int `envconfig:"PUBLISH_BUFFERED_BYTES_LIMIT" default:"314572800"`
+
+	// The maximum number of events kept to deduplicate the events of brokers with a deduplication window.
+	DeduplicationCacheSize int `envconfig:"DEDUPLICATION_CACHE_SIZE" default:"100000"`
+
+	// AuthenticationMode is the kind of tokens senders must present: "kubernetes", "oidc", or empty to
+	// only authenticate the senders of brokers with allowed senders.
+	AuthenticationMode string `envconfig:"AUTHENTICATION_MODE"`
+	// AuthenticationAudience is the audience the tokens of the senders must be issued for.
+	AuthenticationAudience string `envconfig:"AUTHENTICATION_AUDIENCE"`
+
+	// ClaimCheckBucket is the Cloud Storage bucket the data of the large events is offloaded to.
+	// If empty, events larger than the Pub/Sub limit are rejected.
+	ClaimCheckBucket string `envconfig:"CLAIM_CHECK_BUCKET"`
+	// ClaimCheckThreshold is the size of the data above which it's offloaded. Default 9Mb, leaving
+	// room for the attributes within the 10Mb Pub/Sub limit.
+	ClaimCheckThreshold int `envconfig:"CLAIM_CHECK_THRESHOLD" default:"9000000"`
+	// ClaimCheckMaxBodyBytes is the max size of the requests when the claim check bucket is set.
+	// Default 100Mb.
+	ClaimCheckMaxBodyBytes int64 `envconfig:"CLAIM_CHECK_MAX_BODY_BYTES" default:"100000000"`
 }
 
 const (
@@ -40,11 +68,22 @@ const (
 	metricNamespace = "broker"
 )
 
+// servers are the ingress servers receiving events over HTTP and gRPC.
+type servers struct {
+	Handler    *ingress.Handler
+	GRPCServer *ingress.GRPCServer
+}
+
 // main creates and starts an ingress handler using default options.
-// 1. It listens on port specified by "PORT" env var, or default 8080 if env var is not set
+// 1. It listens on port specified by "PORT" env var, or default 8080 if env var is not set, and
+//    receives events over gRPC on port specified by "GRPC_PORT" env var, or default 8081
 // 2. It reads "PROJECT_ID" env var for pubsub project. If the env var is empty, it retrieves project ID from
 //    GCE metadata.
 // 3. It expects broker configmap mounted at "/var/run/cloud-run-events/broker/targets"
+// 4. It watches the rate limits in the "config-ingress-rate-limit" configmap of the system namespace.
+// 5. It authenticates senders with the tokens of the "AUTHENTICATION_MODE" env var, issued for the
+//    "AUTHENTICATION_AUDIENCE" env var.
+// 6. It offloads the data of the large events to the Cloud Storage bucket of the "CLAIM_CHECK_BUCKET" env var.
 func main() {
 	appcredentials.MustExistOrUnsetEnv()
 
@@ -59,24 +98,60 @@ func main() {
 	}
 	logger.Desugar().Info("Starting ingress handler", zap.Any("envConfig", env), zap.Any("Project ID", projectID))
 
-	ingress, err := InitializeHandler(
+	// The rate limits are watched separately as the ConfigMap watcher of the init result is already started.
+	cmw := configmap.NewInformedWatcher(res.KubeClient, system.Namespace())
+
+	claimChecks, maxBodyBytes := claimCheckStore(ctx, logger.Desugar(), env)
+
+	servers, err := InitializeServers(
 		ctx,
 		clients.Port(env.Port),
+		clients.GRPCPort(env.GRPCPort),
 		clients.ProjectID(projectID),
 		metrics.PodName(env.PodName),
 		metrics.ContainerName(component),
 		publishSetting(logger.Desugar(), env),
+		ingress.DeduplicationCacheSize(env.DeduplicationCacheSize),
+		cmw,
+		ingress.AuthenticationMode(env.AuthenticationMode),
+		ingress.AuthenticationAudience(env.AuthenticationAudience),
+		res.KubeClient,
+		claimChecks,
+		maxBodyBytes,
 	)
 	if err != nil {
 		logger.Desugar().Fatal("Unable to create ingress handler: ", zap.Error(err))
 	}
+	if err := cmw.Start(ctx.Done()); err != nil {
+		logger.Desugar().Fatal("Failed to start rate limit ConfigMap watcher", zap.Error(err))
+	}
+
+	go func() {
+		logger.Desugar().Info("Starting gRPC ingress.", zap.Int("port", env.GRPCPort))
+		if err := servers.GRPCServer.Start(ctx); err != nil {
+			logger.Desugar().Fatal("failed to start gRPC ingress: ", zap.Error(err))
+		}
+	}()
 
-	logger.Desugar().Info("Starting ingress.", zap.Any("ingress", ingress))
-	if err := ingress.Start(ctx); err != nil {
+	logger.Desugar().Info("Starting ingress.", zap.Any("ingress", servers.Handler))
+	if err := servers.Handler.Start(ctx); err != nil {
 		logger.Desugar().Fatal("failed to start ingress: ", zap.Error(err))
 	}
 }
 
+// claimCheckStore returns the store the data of the large events is offloaded to, and the max size
+// of the requests. The store is nil if the claim check bucket isn't set.
+func claimCheckStore(ctx context.Context, logger *zap.Logger, env envConfig) (*claimcheck.Store, ingress.MaxRequestBodyBytes) {
+	if env.ClaimCheckBucket == "" {
+		return nil, ingress.DefaultMaxRequestBodyBytes
+	}
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		logger.Fatal("Failed to create storage client", zap.Error(err))
+	}
+	return claimcheck.NewStore(client, env.ClaimCheckBucket, env.ClaimCheckThreshold), ingress.MaxRequestBodyBytes(env.ClaimCheckMaxBodyBytes)
+}
+
 func publishSetting(logger *zap.Logger, env envConfig) pubsub.PublishSettings {
 	s := pubsub.DefaultPublishSettings
 	if env.PublishBufferedByteLimit > 0 {
@@ -22,23 +22,36 @@ import (
 	"context"
 
 	"cloud.google.com/go/pubsub"
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/ingress"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/utils/clients"
 	"github.com/google/wire"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/configmap"
 )
 
-func InitializeHandler(
+func InitializeServers(
 	ctx context.Context,
 	port clients.Port,
+	grpcPort clients.GRPCPort,
 	projectID clients.ProjectID,
 	podName metrics.PodName,
 	containerName metrics.ContainerName,
 	publishSettings pubsub.PublishSettings,
-) (*ingress.Handler, error) {
+	deduplicationCacheSize ingress.DeduplicationCacheSize,
+	cmw configmap.DefaultingWatcher,
+	authenticationMode ingress.AuthenticationMode,
+	authenticationAudience ingress.AuthenticationAudience,
+	kubeClient kubernetes.Interface,
+	claimChecks *claimcheck.Store,
+	maxBodyBytes ingress.MaxRequestBodyBytes,
+) (*servers, error) {
 	panic(wire.Build(
 		ingress.HandlerSet,
+		ingress.NewGRPCServer,
+		wire.Struct(new(servers), "*"),
 		wire.Value([]volume.Option(nil)),
 		volume.NewTargetsFromFile,
 	))
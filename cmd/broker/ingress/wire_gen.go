@@ -8,15 +8,18 @@ package main
 import (
 	"cloud.google.com/go/pubsub"
 	"context"
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/ingress"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/utils/clients"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/configmap"
 )
 
 // Injectors from wire.go:
 
-func InitializeHandler(ctx context.Context, port clients.Port, projectID clients.ProjectID, podName metrics.PodName, containerName metrics.ContainerName, publishSettings pubsub.PublishSettings) (*ingress.Handler, error) {
+func InitializeServers(ctx context.Context, port clients.Port, grpcPort clients.GRPCPort, projectID clients.ProjectID, podName metrics.PodName, containerName metrics.ContainerName, publishSettings pubsub.PublishSettings, deduplicationCacheSize ingress.DeduplicationCacheSize, cmw configmap.DefaultingWatcher, authenticationMode ingress.AuthenticationMode, authenticationAudience ingress.AuthenticationAudience, kubeClient kubernetes.Interface, claimChecks *claimcheck.Store, maxBodyBytes ingress.MaxRequestBodyBytes) (*servers, error) {
 	httpMessageReceiver := clients.NewHTTPMessageReceiver(port)
 	v := _wireValue
 	readonlyTargets, err := volume.NewTargetsFromFile(v...)
@@ -28,12 +31,25 @@ func InitializeHandler(ctx context.Context, port clients.Port, projectID clients
 		return nil, err
 	}
 	multiTopicDecoupleSink := ingress.NewMultiTopicDecoupleSink(ctx, readonlyTargets, client, publishSettings)
+	claimCheckDecoupleSink := ingress.NewClaimCheckDecoupleSink(multiTopicDecoupleSink, claimChecks)
+	lruDeduplicationStore := ingress.NewLRUDeduplicationStore(deduplicationCacheSize)
 	ingressReporter, err := metrics.NewIngressReporter(podName, containerName)
 	if err != nil {
 		return nil, err
 	}
-	handler := ingress.NewHandler(ctx, httpMessageReceiver, multiTopicDecoupleSink, ingressReporter)
-	return handler, nil
+	deduplicatingDecoupleSink := ingress.NewDeduplicatingDecoupleSink(claimCheckDecoupleSink, readonlyTargets, lruDeduplicationStore, ingressReporter)
+	rateLimitingDecoupleSink := ingress.NewRateLimitingDecoupleSink(ctx, deduplicatingDecoupleSink, cmw)
+	authorizer, err := ingress.NewAuthorizer(ctx, authenticationMode, authenticationAudience, kubeClient, readonlyTargets)
+	if err != nil {
+		return nil, err
+	}
+	handler := ingress.NewHandler(ctx, httpMessageReceiver, rateLimitingDecoupleSink, authorizer, ingressReporter, maxBodyBytes)
+	grpcServer := ingress.NewGRPCServer(ctx, grpcPort, rateLimitingDecoupleSink, authorizer, ingressReporter, maxBodyBytes)
+	mainServers := &servers{
+		Handler:    handler,
+		GRPCServer: grpcServer,
+	}
+	return mainServers, nil
 }
 
 var (
@@ -23,9 +23,17 @@ import (
 
 	"cloud.google.com/go/pubsub"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/handler"
+	"github.com/google/knative-gcp/pkg/broker/handler/auth"
+	"github.com/google/knative-gcp/pkg/broker/handler/limiter"
+	"github.com/google/knative-gcp/pkg/broker/status"
+	"github.com/google/knative-gcp/pkg/gclient/storage"
+	"github.com/google/knative-gcp/pkg/logging"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/utils"
 	"github.com/google/knative-gcp/pkg/utils/appcredentials"
@@ -37,10 +45,16 @@ const (
 	component        = "broker-retry"
 	metricNamespace  = "trigger"
 	poolResyncPeriod = 15 * time.Second
+	// The backlog of the retry subscriptions is sampled every minute by Pub/Sub.
+	statusReportPeriod = time.Minute
+	// retrySubscriptionPrefix is the prefix of the IDs of the retry subscriptions,
+	// see resources.GenerateRetrySubscriptionName.
+	retrySubscriptionPrefix = "cre-tgr_"
 )
 
 type envConfig struct {
 	PodName            string `envconfig:"POD_NAME" required:"true"`
+	PodUID             string `envconfig:"POD_UID"`
 	TargetsConfigPath  string `envconfig:"TARGETS_CONFIG_PATH" default:"/var/run/cloud-run-events/broker/targets"`
 	HandlerConcurrency int    `envconfig:"HANDLER_CONCURRENCY"`
 
@@ -57,6 +71,22 @@ type envConfig struct {
 
 	// Max to 10m.
 	TimeoutPerEvent time.Duration `envconfig:"TIMEOUT_PER_EVENT"`
+
+	// AdaptiveConcurrency adapts the number of events delivered concurrently to each
+	// subscriber, up to OutstandingMessagesPerSub, to the latency and the errors of
+	// the deliveries.
+	AdaptiveConcurrency bool `envconfig:"ADAPTIVE_CONCURRENCY" default:"true"`
+	// SlowDeliveryLatency is the delivery latency from which a subscriber is considered
+	// overloaded by the adaptive concurrency.
+	SlowDeliveryLatency time.Duration `envconfig:"SLOW_DELIVERY_LATENCY" default:"5s"`
+
+	// ClaimCheckBucket is the Cloud Storage bucket the ingress offloads the data of
+	// the large events to. The data is loaded back before the events are delivered.
+	ClaimCheckBucket string `envconfig:"CLAIM_CHECK_BUCKET"`
+
+	// HeaderSecretsPath is where the Secret holding the values of the secret headers of
+	// the triggers is mounted.
+	HeaderSecretsPath string `envconfig:"HEADER_SECRETS_PATH" default:"/var/run/cloud-run-events/broker-headers"`
 }
 
 func main() {
@@ -82,6 +112,18 @@ func main() {
 		logger.Fatalf("failed to get default ProjectID: %v", err)
 	}
 
+	deliveries := status.NewDeliveries()
+
+	var claimChecks *claimcheck.Store
+	if env.ClaimCheckBucket != "" {
+		client, err := storage.NewClient(ctx)
+		if err != nil {
+			logger.Fatal("Failed to create storage client", zap.Error(err))
+		}
+		// The threshold only matters to the ingress offloading the data.
+		claimChecks = claimcheck.NewStore(client, env.ClaimCheckBucket, 0)
+	}
+
 	syncSignal := poolSyncSignal(ctx, targetsUpdateCh)
 	syncPool, err := InitializeSyncPool(
 		ctx,
@@ -92,7 +134,7 @@ func main() {
 			volume.WithPath(env.TargetsConfigPath),
 			volume.WithNotifyChan(targetsUpdateCh),
 		},
-		buildHandlerOptions(env)...,
+		buildHandlerOptions(ctx, env, deliveries, claimChecks)...,
 	)
 	if err != nil {
 		logger.Fatal("Failed to get retry sync pool", zap.Error(err))
@@ -101,6 +143,19 @@ func main() {
 		logger.Fatal("Failed to start retry sync pool", zap.Error(err))
 	}
 
+	// The deliveries and the backlog of the retry queues are surfaced on the triggers
+	// by the controller.
+	if env.PodUID != "" {
+		backlogs, err := status.NewBacklogs(ctx, projectID, retrySubscriptionPrefix)
+		if err != nil {
+			logger.Warn("Failed to create the retry backlogs client, the backlogs won't be reported", zap.Error(err))
+		} else {
+			defer backlogs.Close()
+		}
+		reporter := status.NewReporter(res.KubeClient, status.Namespace(), env.PodName, types.UID(env.PodUID), targetStatus(deliveries, backlogs, syncPool.Targets()))
+		go reporter.Run(ctx, statusReportPeriod)
+	}
+
 	// Context will be done if a TERM signal is issued.
 	<-ctx.Done()
 	logger.Info("Exiting...")
@@ -126,7 +181,21 @@ func poolSyncSignal(ctx context.Context, targetsUpdateCh chan struct{}) chan str
 	return ch
 }
 
-func buildHandlerOptions(env envConfig) []handler.Option {
+// targetStatus returns the status of the targets with recorded deliveries or with a
+// retry backlog.
+func targetStatus(deliveries *status.Deliveries, backlogs *status.Backlogs, targets config.ReadonlyTargets) func(context.Context) map[string]status.TargetStatus {
+	return func(ctx context.Context) map[string]status.TargetStatus {
+		ts := deliveries.Status()
+		if backlogs != nil {
+			if err := backlogs.AddRetryBacklogs(ctx, targets, ts); err != nil {
+				logging.FromContext(ctx).Warn("Failed to get the backlog of the retry queues", zap.Error(err))
+			}
+		}
+		return ts
+	}
+}
+
+func buildHandlerOptions(ctx context.Context, env envConfig, deliveries *status.Deliveries, claimChecks *claimcheck.Store) []handler.Option {
 	rs := pubsub.DefaultReceiveSettings
 	// If Synchronous is true, then no more than MaxOutstandingMessages will be in memory at one time.
 	// MaxOutstandingBytes still refers to the total bytes processed, rather than in memory.
@@ -144,6 +213,16 @@ func buildHandlerOptions(env envConfig) []handler.Option {
 		opts = append(opts, handler.WithTimeoutPerEvent(env.TimeoutPerEvent))
 	}
 	opts = append(opts, handler.WithPubsubReceiveSettings(rs))
+	if env.AdaptiveConcurrency {
+		opts = append(opts, handler.WithAdaptiveConcurrency(limiter.Settings{SlowLatency: env.SlowDeliveryLatency}))
+	}
+	// The tokens of the subscribers are cached and refreshed for the lifetime of the binary.
+	opts = append(opts, handler.WithSubscriberTokens(auth.NewTokens(ctx)))
+	opts = append(opts, handler.WithDeliveries(deliveries))
+	opts = append(opts, handler.WithHeaderSecretsDir(env.HeaderSecretsPath))
+	if claimChecks != nil {
+		opts = append(opts, handler.WithClaimChecks(claimChecks))
+	}
 	// The default CeClient is good?
 	return opts
 }
@@ -28,9 +28,14 @@ import (
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell"
 	"github.com/google/knative-gcp/pkg/reconciler/deployment"
 	"github.com/google/knative-gcp/pkg/reconciler/events/auditlogs"
+	"github.com/google/knative-gcp/pkg/reconciler/events/billingbudget"
 	"github.com/google/knative-gcp/pkg/reconciler/events/build"
+	"github.com/google/knative-gcp/pkg/reconciler/events/containerregistry"
+	"github.com/google/knative-gcp/pkg/reconciler/events/firestore"
+	"github.com/google/knative-gcp/pkg/reconciler/events/monitoring"
 	"github.com/google/knative-gcp/pkg/reconciler/events/pubsub"
 	"github.com/google/knative-gcp/pkg/reconciler/events/scheduler"
+	"github.com/google/knative-gcp/pkg/reconciler/events/secretmanager"
 	"github.com/google/knative-gcp/pkg/reconciler/events/storage"
 	kedapullsubscription "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/keda"
 	staticpullsubscription "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/static"
@@ -59,6 +64,11 @@ func Controllers(
 	schedulerController scheduler.Constructor,
 	pubsubController pubsub.Constructor,
 	buildController build.Constructor,
+	firestoreController firestore.Constructor,
+	containerRegistryController containerregistry.Constructor,
+	monitoringController monitoring.Constructor,
+	billingbudgetController billingbudget.Constructor,
+	secretmanagerController secretmanager.Constructor,
 	pullsubscriptionController staticpullsubscription.Constructor,
 	kedaPullsubscriptionController kedapullsubscription.Constructor,
 	topicController topic.Constructor,
@@ -74,6 +84,11 @@ func Controllers(
 		injection.ControllerConstructor(schedulerController),
 		injection.ControllerConstructor(pubsubController),
 		injection.ControllerConstructor(buildController),
+		injection.ControllerConstructor(firestoreController),
+		injection.ControllerConstructor(containerRegistryController),
+		injection.ControllerConstructor(monitoringController),
+		injection.ControllerConstructor(billingbudgetController),
+		injection.ControllerConstructor(secretmanagerController),
 		injection.ControllerConstructor(pullsubscriptionController),
 		injection.ControllerConstructor(kedaPullsubscriptionController),
 		injection.ControllerConstructor(topicController),
@@ -24,9 +24,14 @@ import (
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell"
 	"github.com/google/knative-gcp/pkg/reconciler/deployment"
 	"github.com/google/knative-gcp/pkg/reconciler/events/auditlogs"
+	"github.com/google/knative-gcp/pkg/reconciler/events/billingbudget"
 	"github.com/google/knative-gcp/pkg/reconciler/events/build"
+	"github.com/google/knative-gcp/pkg/reconciler/events/containerregistry"
+	"github.com/google/knative-gcp/pkg/reconciler/events/firestore"
+	"github.com/google/knative-gcp/pkg/reconciler/events/monitoring"
 	"github.com/google/knative-gcp/pkg/reconciler/events/pubsub"
 	"github.com/google/knative-gcp/pkg/reconciler/events/scheduler"
+	"github.com/google/knative-gcp/pkg/reconciler/events/secretmanager"
 	"github.com/google/knative-gcp/pkg/reconciler/events/storage"
 	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/keda"
@@ -50,6 +55,11 @@ func InitializeControllers(ctx context.Context) ([]injection.ControllerConstruct
 		scheduler.NewConstructor,
 		pubsub.NewConstructor,
 		build.NewConstructor,
+		firestore.NewConstructor,
+		containerregistry.NewConstructor,
+		monitoring.NewConstructor,
+		billingbudget.NewConstructor,
+		secretmanager.NewConstructor,
 		static.NewConstructor,
 		keda.NewConstructor,
 		topic.NewConstructor,
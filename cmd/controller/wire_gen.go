@@ -14,9 +14,14 @@ import (
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell"
 	"github.com/google/knative-gcp/pkg/reconciler/deployment"
 	"github.com/google/knative-gcp/pkg/reconciler/events/auditlogs"
+	"github.com/google/knative-gcp/pkg/reconciler/events/billingbudget"
 	"github.com/google/knative-gcp/pkg/reconciler/events/build"
+	"github.com/google/knative-gcp/pkg/reconciler/events/containerregistry"
+	"github.com/google/knative-gcp/pkg/reconciler/events/firestore"
+	"github.com/google/knative-gcp/pkg/reconciler/events/monitoring"
 	"github.com/google/knative-gcp/pkg/reconciler/events/pubsub"
 	"github.com/google/knative-gcp/pkg/reconciler/events/scheduler"
+	"github.com/google/knative-gcp/pkg/reconciler/events/secretmanager"
 	"github.com/google/knative-gcp/pkg/reconciler/events/storage"
 	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/keda"
@@ -49,6 +54,11 @@ func InitializeControllers(ctx context.Context) ([]injection.ControllerConstruct
 	schedulerConstructor := scheduler.NewConstructor(iamPolicyManager, storeSingleton)
 	pubsubConstructor := pubsub.NewConstructor(iamPolicyManager, storeSingleton)
 	buildConstructor := build.NewConstructor(iamPolicyManager, storeSingleton)
+	firestoreConstructor := firestore.NewConstructor(iamPolicyManager, storeSingleton)
+	containerregistryConstructor := containerregistry.NewConstructor(iamPolicyManager, storeSingleton)
+	monitoringConstructor := monitoring.NewConstructor(iamPolicyManager, storeSingleton)
+	billingbudgetConstructor := billingbudget.NewConstructor(iamPolicyManager, storeSingleton)
+	secretmanagerConstructor := secretmanager.NewConstructor(iamPolicyManager, storeSingleton)
 	staticConstructor := static.NewConstructor(iamPolicyManager, storeSingleton)
 	kedaConstructor := keda.NewConstructor(iamPolicyManager, storeSingleton)
 	dataresidencyStoreSingleton := &dataresidency.StoreSingleton{}
@@ -58,6 +68,6 @@ func InitializeControllers(ctx context.Context) ([]injection.ControllerConstruct
 	brokerConstructor := broker.NewConstructor(dataresidencyStoreSingleton)
 	deploymentConstructor := deployment.NewConstructor()
 	brokercellConstructor := brokercell.NewConstructor()
-	v2 := Controllers(constructor, storageConstructor, schedulerConstructor, pubsubConstructor, buildConstructor, staticConstructor, kedaConstructor, topicConstructor, channelConstructor, triggerConstructor, brokerConstructor, deploymentConstructor, brokercellConstructor)
+	v2 := Controllers(constructor, storageConstructor, schedulerConstructor, pubsubConstructor, buildConstructor, firestoreConstructor, containerregistryConstructor, monitoringConstructor, billingbudgetConstructor, secretmanagerConstructor, staticConstructor, kedaConstructor, topicConstructor, channelConstructor, triggerConstructor, brokerConstructor, deploymentConstructor, brokercellConstructor)
 	return v2, nil
 }
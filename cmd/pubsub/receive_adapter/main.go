@@ -78,6 +78,14 @@ type envConfig struct {
 	// event.
 	ExtensionsBase64 string `envconfig:"K_CE_EXTENSIONS" required:"true"`
 
+	// EventFilterBase64 is a base64 encoded json string of a map of
+	// CloudEvents attributes that the outbound events must have.
+	EventFilterBase64 string `envconfig:"K_EVENT_FILTER"`
+
+	// ConverterArgsBase64 is a base64 encoded json string of a map of
+	// arguments of the converter selected by AdapterType.
+	ConverterArgsBase64 string `envconfig:"K_CONVERTER_ARGS"`
+
 	// MetricsConfigJson is a json string of metrics.ExporterOptions.
 	// This is used to configure the metrics exporter options, the config is
 	// stored in a config map inside the controllers namespace and copied here.
@@ -165,6 +173,22 @@ func main() {
 		logger.Error("Failed to convert base64 extensions to map: %v", zap.Error(err))
 	}
 
+	// Convert base64 encoded json map to event filter map.
+	var eventFilter map[string]string
+	if env.EventFilterBase64 != "" {
+		if eventFilter, err = utils.Base64ToMap(env.EventFilterBase64); err != nil {
+			logger.Fatal("Failed to convert base64 event filter to map", zap.Error(err))
+		}
+	}
+
+	// Convert base64 encoded json map to converter arguments map.
+	var converterArgs map[string]string
+	if env.ConverterArgsBase64 != "" {
+		if converterArgs, err = utils.Base64ToMap(env.ConverterArgsBase64); err != nil {
+			logger.Fatal("Failed to convert base64 converter arguments to map", zap.Error(err))
+		}
+	}
+
 	logger.Info("Initializing adapter", zap.String("projectID", projectID), zap.String("topicID", env.Topic), zap.String("subscriptionID", env.Subscription))
 
 	args := &AdapterArgs{
@@ -173,6 +197,8 @@ func main() {
 		SinkURI:        env.Sink,
 		TransformerURI: env.Transformer,
 		Extensions:     extensions,
+		EventFilter:    eventFilter,
+		ConverterArgs:  converterArgs,
 	}
 
 	adapter, err := InitializeAdapter(ctx,
@@ -59,16 +59,21 @@ var types = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
 	messagingv1beta1.SchemeGroupVersion.WithKind("Channel"): &messagingv1beta1.Channel{},
 
 	// For group events.cloud.google.com.
-	eventsv1beta1.SchemeGroupVersion.WithKind("CloudStorageSource"):   &eventsv1beta1.CloudStorageSource{},
-	eventsv1beta1.SchemeGroupVersion.WithKind("CloudSchedulerSource"): &eventsv1beta1.CloudSchedulerSource{},
-	eventsv1beta1.SchemeGroupVersion.WithKind("CloudPubSubSource"):    &eventsv1beta1.CloudPubSubSource{},
-	eventsv1beta1.SchemeGroupVersion.WithKind("CloudAuditLogsSource"): &eventsv1beta1.CloudAuditLogsSource{},
-	eventsv1beta1.SchemeGroupVersion.WithKind("CloudBuildSource"):     &eventsv1beta1.CloudBuildSource{},
-	eventsv1.SchemeGroupVersion.WithKind("CloudStorageSource"):        &eventsv1.CloudStorageSource{},
-	eventsv1.SchemeGroupVersion.WithKind("CloudSchedulerSource"):      &eventsv1.CloudSchedulerSource{},
-	eventsv1.SchemeGroupVersion.WithKind("CloudPubSubSource"):         &eventsv1.CloudPubSubSource{},
-	eventsv1.SchemeGroupVersion.WithKind("CloudAuditLogsSource"):      &eventsv1.CloudAuditLogsSource{},
-	eventsv1.SchemeGroupVersion.WithKind("CloudBuildSource"):          &eventsv1.CloudBuildSource{},
+	eventsv1beta1.SchemeGroupVersion.WithKind("CloudStorageSource"):      &eventsv1beta1.CloudStorageSource{},
+	eventsv1beta1.SchemeGroupVersion.WithKind("CloudSchedulerSource"):    &eventsv1beta1.CloudSchedulerSource{},
+	eventsv1beta1.SchemeGroupVersion.WithKind("CloudPubSubSource"):       &eventsv1beta1.CloudPubSubSource{},
+	eventsv1beta1.SchemeGroupVersion.WithKind("CloudAuditLogsSource"):    &eventsv1beta1.CloudAuditLogsSource{},
+	eventsv1beta1.SchemeGroupVersion.WithKind("CloudBuildSource"):        &eventsv1beta1.CloudBuildSource{},
+	eventsv1.SchemeGroupVersion.WithKind("CloudStorageSource"):           &eventsv1.CloudStorageSource{},
+	eventsv1.SchemeGroupVersion.WithKind("CloudSchedulerSource"):         &eventsv1.CloudSchedulerSource{},
+	eventsv1.SchemeGroupVersion.WithKind("CloudPubSubSource"):            &eventsv1.CloudPubSubSource{},
+	eventsv1.SchemeGroupVersion.WithKind("CloudAuditLogsSource"):         &eventsv1.CloudAuditLogsSource{},
+	eventsv1.SchemeGroupVersion.WithKind("CloudBuildSource"):             &eventsv1.CloudBuildSource{},
+	eventsv1.SchemeGroupVersion.WithKind("CloudFirestoreSource"):         &eventsv1.CloudFirestoreSource{},
+	eventsv1.SchemeGroupVersion.WithKind("CloudContainerRegistrySource"): &eventsv1.CloudContainerRegistrySource{},
+	eventsv1.SchemeGroupVersion.WithKind("CloudMonitoringAlertSource"):   &eventsv1.CloudMonitoringAlertSource{},
+	eventsv1.SchemeGroupVersion.WithKind("CloudBillingBudgetSource"):     &eventsv1.CloudBillingBudgetSource{},
+	eventsv1.SchemeGroupVersion.WithKind("CloudSecretManagerSource"):     &eventsv1.CloudSecretManagerSource{},
 
 	// For group internal.events.cloud.google.com.
 	inteventsv1beta1.SchemeGroupVersion.WithKind("PullSubscription"): &inteventsv1beta1.PullSubscription{},
@@ -0,0 +1,68 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"knative.dev/pkg/apis"
+)
+
+// AllowedSendersAnnotation is the annotation key used to restrict the senders of the events
+// of a Broker, as a comma separated list of identities. The ingress then requires a bearer
+// token of one of the identities, e.g. "system:serviceaccount:<namespace>:<name>" for a
+// Kubernetes service account token, or the email of a Google service account for an OIDC
+// ID token.
+const AllowedSendersAnnotation = "events.cloud.google.com/allowedSenders"
+
+var allowedSendersAnnotationPath = fmt.Sprintf("metadata.annotations[%s]", AllowedSendersAnnotation)
+
+// GetAllowedSenders returns the identities allowed to send events to the Broker, or nil if
+// any sender is allowed. An error is returned if the annotation is invalid, in which case
+// no sender should be allowed.
+func (b *Broker) GetAllowedSenders() ([]string, error) {
+	v, ok := b.GetAnnotations()[AllowedSendersAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	return parseAllowedSenders(v)
+}
+
+func parseAllowedSenders(v string) ([]string, error) {
+	var senders []string
+	for _, s := range strings.Split(v, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" || strings.IndexFunc(s, unicode.IsSpace) >= 0 {
+			return nil, fmt.Errorf("invalid sender %q", s)
+		}
+		senders = append(senders, s)
+	}
+	return senders, nil
+}
+
+func validateAllowedSendersAnnotation(annotations map[string]string) *apis.FieldError {
+	v, ok := annotations[AllowedSendersAnnotation]
+	if !ok {
+		return nil
+	}
+	if _, err := parseAllowedSenders(v); err != nil {
+		return apis.ErrInvalidValue(v, allowedSendersAnnotationPath)
+	}
+	return nil
+}
@@ -30,6 +30,10 @@ const (
 	// BrokerClass is the annotation value to use when creating a
 	// Google Cloud Broker object.
 	BrokerClass = "googlecloud"
+
+	// PubsubDeadLetterSinkScheme is the URI scheme of a dead letter sink
+	// which is a Pub/Sub topic, e.g. pubsub://my-dead-letter-topic.
+	PubsubDeadLetterSinkScheme = "pubsub"
 )
 
 // +genclient
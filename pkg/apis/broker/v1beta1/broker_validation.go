@@ -26,13 +26,17 @@ import (
 
 // Validate verifies that the Broker is valid.
 func (b *Broker) Validate(ctx context.Context) *apis.FieldError {
-	// We validate the GCP Broker's delivery spec. The eventing webhook will run
-	// the other usual validations.
+	// We validate the GCP Broker's delivery spec and custom annotations. The
+	// eventing webhook will run the other usual validations.
+	withNS := apis.AllowDifferentNamespace(apis.WithinParent(ctx, b.ObjectMeta))
+	errs := validateOrderingAnnotations(b.GetAnnotations()).
+		Also(validateDeduplicationAnnotation(b.GetAnnotations())).
+		Also(validateAllowedSendersAnnotation(b.GetAnnotations())).
+		Also(b.validateTransformerAnnotation(withNS))
 	if b.Spec.Delivery == nil {
-		return nil
+		return errs
 	}
-	withNS := apis.AllowDifferentNamespace(apis.WithinParent(ctx, b.ObjectMeta))
-	return ValidateDeliverySpec(withNS, b.Spec.Delivery).ViaField("spec", "delivery")
+	return errs.Also(ValidateDeliverySpec(withNS, b.Spec.Delivery).ViaField("spec", "delivery"))
 }
 
 func ValidateDeliverySpec(ctx context.Context, spec *eventingduckv1beta1.DeliverySpec) *apis.FieldError {
@@ -46,15 +50,17 @@ func ValidateDeliverySpec(ctx context.Context, spec *eventingduckv1beta1.Deliver
 	return errs.Also(ValidateDeadLetterSink(ctx, spec.DeadLetterSink).ViaField("deadLetterSink"))
 }
 
+// ValidateDeadLetterSink validates the dead letter sink. A Pub/Sub topic sink must have a
+// valid topic ID, any other sink must be a valid destination.
 func ValidateDeadLetterSink(ctx context.Context, sink *duckv1.Destination) *apis.FieldError {
 	if sink == nil {
 		return nil
 	}
-	if sink.URI == nil {
+	if sink.Ref == nil && sink.URI == nil {
 		return apis.ErrMissingField("uri")
 	}
-	if scheme := sink.URI.Scheme; scheme != "pubsub" {
-		return apis.ErrInvalidValue("Dead letter sink URI scheme should be pubsub", "uri")
+	if !IsPubsubDeadLetterSink(sink) {
+		return sink.Validate(ctx)
 	}
 	topicID := sink.URI.Host
 	if topicID == "" {
@@ -65,3 +71,10 @@ func ValidateDeadLetterSink(ctx context.Context, sink *duckv1.Destination) *apis
 	}
 	return nil
 }
+
+// IsPubsubDeadLetterSink returns true if the dead letter sink is a Pub/Sub topic, i.e. its
+// URI is pubsub://<topic-id>. Such a sink is set as the dead letter policy of the Pub/Sub
+// subscription, other sinks are resolved and events are sent to them by the data plane.
+func IsPubsubDeadLetterSink(sink *duckv1.Destination) bool {
+	return sink != nil && sink.Ref == nil && sink.URI != nil && sink.URI.Scheme == PubsubDeadLetterSinkScheme
+}
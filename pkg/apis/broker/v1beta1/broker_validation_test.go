@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/pkg/apis"
@@ -40,6 +41,85 @@ func TestBroker_Validate(t *testing.T) {
 		broker: Broker{
 			Spec: v1beta1.BrokerSpec{},
 		},
+	}, {
+		name: "valid ordered delivery",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{OrderedDeliveryAnnotation: "true"},
+			},
+		},
+	}, {
+		name: "invalid ordering key attribute",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					OrderedDeliveryAnnotation:      "true",
+					OrderingKeyAttributeAnnotation: "Order ID",
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("Order ID", "metadata.annotations[events.cloud.google.com/orderingKeyAttribute]"),
+	}, {
+		name: "valid deduplication window",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{DeduplicationWindowAnnotation: "PT10M"},
+			},
+		},
+	}, {
+		name: "invalid deduplication window",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{DeduplicationWindowAnnotation: "10m"},
+			},
+		},
+		want: apis.ErrInvalidValue("10m", "metadata.annotations[events.cloud.google.com/deduplicationWindow]"),
+	}, {
+		name: "deduplication window too long",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{DeduplicationWindowAnnotation: "P1D"},
+			},
+		},
+		want: apis.ErrOutOfBoundsValue("P1D", "PT0S", "PT1H", "metadata.annotations[events.cloud.google.com/deduplicationWindow]"),
+	}, {
+		name: "valid allowed senders",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{AllowedSendersAnnotation: "system:serviceaccount:ns:sa, sender@project.iam.gserviceaccount.com"},
+			},
+		},
+	}, {
+		name: "invalid allowed senders",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{AllowedSendersAnnotation: "system:serviceaccount:ns:sa,,"},
+			},
+		},
+		want: apis.ErrInvalidValue("system:serviceaccount:ns:sa,,", "metadata.annotations[events.cloud.google.com/allowedSenders]"),
+	}, {
+		name: "valid transformer",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{TransformerAnnotation: `{"ref":{"apiVersion":"serving.knative.dev/v1","kind":"Service","name":"transformer"}}`},
+			},
+		},
+	}, {
+		name: "invalid transformer json",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{TransformerAnnotation: `{"uri":`},
+			},
+		},
+		want: apis.ErrInvalidValue(`{"uri":`, "metadata.annotations[events.cloud.google.com/transformer]"),
+	}, {
+		name: "invalid transformer destination",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{TransformerAnnotation: `{}`},
+			},
+		},
+		want: apis.ErrGeneric("expected at least one, got none", "ref", "uri").ViaFieldKey("annotations", TransformerAnnotation).ViaField("metadata"),
 	}, {
 		name: "missing backoff policy",
 		broker: Broker{
@@ -73,7 +153,7 @@ func TestBroker_Validate(t *testing.T) {
 		},
 		want: apis.ErrMissingField("spec.delivery.deadLetterSink.uri"),
 	}, {
-		name: "invalid dead letter sink uri scheme",
+		name: "valid dead letter sink uri",
 		broker: Broker{
 			Spec: v1beta1.BrokerSpec{
 				Delivery: &eventingduckv1beta1.DeliverySpec{
@@ -82,13 +162,45 @@ func TestBroker_Validate(t *testing.T) {
 					DeadLetterSink: &duckv1.Destination{
 						URI: &apis.URL{
 							Scheme: "http",
-							Host:   "test-topic-id",
+							Host:   "dead-letter.ns.svc.cluster.local",
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "valid dead letter sink ref",
+		broker: Broker{
+			Spec: v1beta1.BrokerSpec{
+				Delivery: &eventingduckv1beta1.DeliverySpec{
+					BackoffDelay:  &bod,
+					BackoffPolicy: &bop,
+					DeadLetterSink: &duckv1.Destination{
+						Ref: &duckv1.KReference{
+							APIVersion: "serving.knative.dev/v1",
+							Kind:       "Service",
+							Name:       "dead-letter",
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "invalid relative dead letter sink uri",
+		broker: Broker{
+			Spec: v1beta1.BrokerSpec{
+				Delivery: &eventingduckv1beta1.DeliverySpec{
+					BackoffDelay:  &bod,
+					BackoffPolicy: &bop,
+					DeadLetterSink: &duckv1.Destination{
+						URI: &apis.URL{
+							Path: "/dead-letter",
 						},
 					},
 				},
 			},
 		},
-		want: apis.ErrInvalidValue("Dead letter sink URI scheme should be pubsub", "spec.delivery.deadLetterSink.uri"),
+		want: apis.ErrInvalidValue("Relative URI is not allowed when Ref and [apiVersion, kind, name] is absent", "spec.delivery.deadLetterSink.uri"),
 	}, {
 		name: "invalid empty dead letter topic id",
 		broker: Broker{
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"time"
+
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// DeduplicationWindowAnnotation is the annotation key used to set the deduplication
+	// window of a Broker, as an ISO 8601 duration. An event sent to the Broker again
+	// within the window, i.e. an event with the same source and id, is accepted without
+	// being published again.
+	DeduplicationWindowAnnotation = "events.cloud.google.com/deduplicationWindow"
+
+	// MaxDeduplicationWindow is the longest deduplication window of a Broker.
+	MaxDeduplicationWindow = time.Hour
+)
+
+var deduplicationWindowAnnotationPath = fmt.Sprintf("metadata.annotations[%s]", DeduplicationWindowAnnotation)
+
+// GetDeduplicationWindow returns the deduplication window of the Broker, or 0 if the
+// Broker doesn't deduplicate events.
+func (b *Broker) GetDeduplicationWindow() time.Duration {
+	v, ok := b.GetAnnotations()[DeduplicationWindowAnnotation]
+	if !ok {
+		return 0
+	}
+	d, err := parseDuration(v)
+	if err != nil || d < 0 || d > MaxDeduplicationWindow {
+		return 0
+	}
+	return d
+}
+
+func validateDeduplicationAnnotation(annotations map[string]string) *apis.FieldError {
+	v, ok := annotations[DeduplicationWindowAnnotation]
+	if !ok {
+		return nil
+	}
+	d, err := parseDuration(v)
+	if err != nil {
+		return apis.ErrInvalidValue(v, deduplicationWindowAnnotationPath)
+	}
+	if d < 0 || d > MaxDeduplicationWindow {
+		return apis.ErrOutOfBoundsValue(v, "PT0S", "PT1H", deduplicationWindowAnnotationPath)
+	}
+	return nil
+}
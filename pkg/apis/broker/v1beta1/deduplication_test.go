@@ -0,0 +1,52 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetDeduplicationWindow(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        time.Duration
+	}{{
+		name: "no annotations",
+	}, {
+		name:        "window",
+		annotations: map[string]string{DeduplicationWindowAnnotation: "PT5M"},
+		want:        5 * time.Minute,
+	}, {
+		name:        "invalid window",
+		annotations: map[string]string{DeduplicationWindowAnnotation: "5m"},
+	}, {
+		name:        "window too long",
+		annotations: map[string]string{DeduplicationWindowAnnotation: "PT2H"},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := Broker{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			if got := b.GetDeduplicationWindow(); got != test.want {
+				t.Errorf("GetDeduplicationWindow got=%v, want=%v", got, test.want)
+			}
+		})
+	}
+}
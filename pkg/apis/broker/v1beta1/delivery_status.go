@@ -0,0 +1,146 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// The delivery status is recorded in the status annotations of the Trigger rather than in
+// typed TriggerStatus fields. The Triggers are validated by the eventing webhook, which
+// rejects the unknown status fields, see the TODO of TriggerStatus. The annotations should
+// be replaced by fields once the webhook allows them.
+const (
+	// LastSuccessTimeAnnotation is the status annotation key of the time of the last
+	// successful delivery of an event to the subscriber of a Trigger, as an RFC 3339 timestamp.
+	LastSuccessTimeAnnotation = "events.cloud.google.com/lastSuccessTime"
+
+	// LastFailureTimeAnnotation is the status annotation key of the time of the last failed
+	// delivery of an event to the subscriber of a Trigger, as an RFC 3339 timestamp.
+	LastFailureTimeAnnotation = "events.cloud.google.com/lastFailureTime"
+
+	// LastErrorAnnotation is the status annotation key of the error of the last failed delivery.
+	LastErrorAnnotation = "events.cloud.google.com/lastError"
+
+	// LastErrorCodeAnnotation is the status annotation key of the HTTP status code of the last
+	// failed delivery. It's not set if the subscriber didn't respond.
+	LastErrorCodeAnnotation = "events.cloud.google.com/lastErrorCode"
+
+	// RetryBacklogAnnotation is the status annotation key of the number of undelivered events
+	// in the retry queue of a Trigger.
+	RetryBacklogAnnotation = "events.cloud.google.com/retryBacklog"
+)
+
+// DeliveryStatus is the status of the delivery of the events to the subscriber of a Trigger,
+// as reported by the broker data plane.
+// +k8s:deepcopy-gen=false
+type DeliveryStatus struct {
+	// LastSuccessTime is the time of the last successful delivery.
+	LastSuccessTime *time.Time
+	// LastFailureTime is the time of the last failed delivery.
+	LastFailureTime *time.Time
+	// LastError is the error of the last failed delivery.
+	LastError string
+	// LastErrorCode is the HTTP status code of the last failed delivery, zero if the
+	// subscriber didn't respond.
+	LastErrorCode int
+	// RetryBacklog is the number of undelivered events in the retry queue.
+	RetryBacklog *int64
+}
+
+// IsEmpty returns true if nothing is known about the delivery of the events.
+func (ds DeliveryStatus) IsEmpty() bool {
+	return ds.LastSuccessTime == nil && ds.LastFailureTime == nil && ds.RetryBacklog == nil
+}
+
+// IsUnreachable returns true if the last delivery failed because the subscriber is unreachable
+// or overloaded: it didn't respond, or responded with a server error or 429.
+func (ds DeliveryStatus) IsUnreachable() bool {
+	if ds.LastFailureTime == nil || (ds.LastSuccessTime != nil && !ds.LastFailureTime.After(*ds.LastSuccessTime)) {
+		return false
+	}
+	return ds.LastErrorCode == 0 || ds.LastErrorCode >= 500 || ds.LastErrorCode == http.StatusTooManyRequests
+}
+
+// GetDeliveryStatus returns the delivery status of the Trigger recorded in the status
+// annotations. The invalid annotations are ignored.
+func (ts *TriggerStatus) GetDeliveryStatus() DeliveryStatus {
+	var ds DeliveryStatus
+	if t, err := time.Parse(time.RFC3339, ts.Annotations[LastSuccessTimeAnnotation]); err == nil {
+		ds.LastSuccessTime = &t
+	}
+	if t, err := time.Parse(time.RFC3339, ts.Annotations[LastFailureTimeAnnotation]); err == nil {
+		ds.LastFailureTime = &t
+		ds.LastError = ts.Annotations[LastErrorAnnotation]
+		ds.LastErrorCode, _ = strconv.Atoi(ts.Annotations[LastErrorCodeAnnotation])
+	}
+	if b, err := strconv.ParseInt(ts.Annotations[RetryBacklogAnnotation], 10, 64); err == nil {
+		ds.RetryBacklog = &b
+	}
+	return ds
+}
+
+// PropagateDeliveryStatus records the delivery status in the status annotations of the Trigger,
+// and marks whether its subscriber is reachable according to the last deliveries.
+func (ts *TriggerStatus) PropagateDeliveryStatus(ds DeliveryStatus) {
+	ts.setStatusAnnotation(LastSuccessTimeAnnotation, formatTime(ds.LastSuccessTime))
+	ts.setStatusAnnotation(LastFailureTimeAnnotation, formatTime(ds.LastFailureTime))
+	var lastError, lastErrorCode, retryBacklog string
+	if ds.LastFailureTime != nil {
+		lastError = ds.LastError
+		if ds.LastErrorCode != 0 {
+			lastErrorCode = strconv.Itoa(ds.LastErrorCode)
+		}
+	}
+	if ds.RetryBacklog != nil {
+		retryBacklog = strconv.FormatInt(*ds.RetryBacklog, 10)
+	}
+	ts.setStatusAnnotation(LastErrorAnnotation, lastError)
+	ts.setStatusAnnotation(LastErrorCodeAnnotation, lastErrorCode)
+	ts.setStatusAnnotation(RetryBacklogAnnotation, retryBacklog)
+
+	switch {
+	case ds.IsUnreachable():
+		ts.MarkSubscriberUnreachable(ds.LastErrorCode, ds.LastError)
+	case ds.LastSuccessTime != nil || ds.LastFailureTime != nil:
+		ts.MarkSubscriberReachable()
+	}
+}
+
+// setStatusAnnotation sets the status annotation, or removes it if the value is empty.
+func (ts *TriggerStatus) setStatusAnnotation(key, value string) {
+	if value == "" {
+		delete(ts.Annotations, key)
+		if len(ts.Annotations) == 0 {
+			ts.Annotations = nil
+		}
+		return
+	}
+	if ts.Annotations == nil {
+		ts.Annotations = make(map[string]string)
+	}
+	ts.Annotations[key] = value
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
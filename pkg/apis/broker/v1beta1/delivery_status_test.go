@@ -0,0 +1,138 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDeliveryStatus(t *testing.T) {
+	success := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	earlier, later := success.Add(-time.Minute), success.Add(time.Minute)
+	backlog := int64(42)
+	tests := []struct {
+		name            string
+		ds              DeliveryStatus
+		wantAnnotations map[string]string
+		wantReachable   corev1.ConditionStatus
+		wantReason      string
+	}{{
+		name: "empty",
+	}, {
+		name: "success",
+		ds:   DeliveryStatus{LastSuccessTime: &success},
+		wantAnnotations: map[string]string{
+			LastSuccessTimeAnnotation: "2020-10-01T12:00:00Z",
+		},
+		wantReachable: corev1.ConditionTrue,
+		wantReason:    "SubscriberReachable",
+	}, {
+		name: "failure before success",
+		ds:   DeliveryStatus{LastSuccessTime: &success, LastFailureTime: &earlier, LastError: "connection refused"},
+		wantAnnotations: map[string]string{
+			LastSuccessTimeAnnotation: "2020-10-01T12:00:00Z",
+			LastFailureTimeAnnotation: "2020-10-01T11:59:00Z",
+			LastErrorAnnotation:       "connection refused",
+		},
+		wantReachable: corev1.ConditionTrue,
+		wantReason:    "SubscriberReachable",
+	}, {
+		name: "unreachable",
+		ds:   DeliveryStatus{LastSuccessTime: &success, LastFailureTime: &later, LastError: "connection refused"},
+		wantAnnotations: map[string]string{
+			LastSuccessTimeAnnotation: "2020-10-01T12:00:00Z",
+			LastFailureTimeAnnotation: "2020-10-01T12:01:00Z",
+			LastErrorAnnotation:       "connection refused",
+		},
+		wantReachable: corev1.ConditionFalse,
+		wantReason:    "SubscriberUnreachable",
+	}, {
+		name: "server error",
+		ds:   DeliveryStatus{LastFailureTime: &later, LastError: "HTTP status code 503", LastErrorCode: 503, RetryBacklog: &backlog},
+		wantAnnotations: map[string]string{
+			LastFailureTimeAnnotation: "2020-10-01T12:01:00Z",
+			LastErrorAnnotation:       "HTTP status code 503",
+			LastErrorCodeAnnotation:   "503",
+			RetryBacklogAnnotation:    "42",
+		},
+		wantReachable: corev1.ConditionFalse,
+		wantReason:    "SubscriberUnavailable",
+	}, {
+		name: "client error",
+		ds:   DeliveryStatus{LastSuccessTime: &success, LastFailureTime: &later, LastError: "HTTP status code 400", LastErrorCode: 400},
+		wantAnnotations: map[string]string{
+			LastSuccessTimeAnnotation: "2020-10-01T12:00:00Z",
+			LastFailureTimeAnnotation: "2020-10-01T12:01:00Z",
+			LastErrorAnnotation:       "HTTP status code 400",
+			LastErrorCodeAnnotation:   "400",
+		},
+		wantReachable: corev1.ConditionTrue,
+		wantReason:    "SubscriberReachable",
+	}, {
+		name: "backlog only",
+		ds:   DeliveryStatus{RetryBacklog: &backlog},
+		wantAnnotations: map[string]string{
+			RetryBacklogAnnotation: "42",
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var ts TriggerStatus
+			ts.InitializeConditions()
+			ts.PropagateDeliveryStatus(test.ds)
+			if diff := cmp.Diff(test.wantAnnotations, ts.Annotations); diff != "" {
+				t.Errorf("Status annotations (-want,+got): %v", diff)
+			}
+			if diff := cmp.Diff(test.ds, ts.GetDeliveryStatus()); diff != "" {
+				t.Errorf("GetDeliveryStatus (-want,+got): %v", diff)
+			}
+			c := ts.GetCondition(TriggerConditionSubscriberReachable)
+			if test.wantReachable == "" {
+				if c != nil {
+					t.Errorf("SubscriberReachable condition got=%v, want nil", c)
+				}
+				return
+			}
+			if c == nil || c.Status != test.wantReachable || c.Reason != test.wantReason {
+				t.Errorf("SubscriberReachable condition got=%v, want status %v with reason %v", c, test.wantReachable, test.wantReason)
+			}
+		})
+	}
+}
+
+func TestDeliveryStatusCleared(t *testing.T) {
+	success := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	backlog := int64(3)
+	ts := TriggerStatus{}
+	ts.SetReplayWindow(success, success)
+	ts.PropagateDeliveryStatus(DeliveryStatus{LastSuccessTime: &success, RetryBacklog: &backlog})
+
+	// The backlog is removed when it's no longer reported, other annotations are kept.
+	ts.PropagateDeliveryStatus(DeliveryStatus{LastSuccessTime: &success})
+	want := map[string]string{
+		ReplayFromAnnotation:      "2020-10-01T12:00:00Z",
+		ReplayUntilAnnotation:     "2020-10-01T12:00:00Z",
+		LastSuccessTimeAnnotation: "2020-10-01T12:00:00Z",
+	}
+	if diff := cmp.Diff(want, ts.Annotations); diff != "" {
+		t.Errorf("Status annotations (-want,+got): %v", diff)
+	}
+}
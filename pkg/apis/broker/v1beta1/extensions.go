@@ -0,0 +1,81 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"knative.dev/pkg/apis"
+)
+
+// ExtensionsAnnotation is the annotation key used to set CloudEvents extensions on the
+// events delivered to the subscriber of a Trigger, e.g. the name of the Trigger. The
+// value is a JSON object of extension names to values. An extension of the event with
+// the same name is overwritten.
+const ExtensionsAnnotation = "events.cloud.google.com/extensions"
+
+var extensionsAnnotationPath = fmt.Sprintf("metadata.annotations[%s]", ExtensionsAnnotation)
+
+// extensionNameRegexp matches the valid CloudEvents attribute names.
+var extensionNameRegexp = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// reservedExtensions can't be set by the extensions annotation. They're the CloudEvents
+// context attributes, and the hops extension used by the broker.
+var reservedExtensions = map[string]bool{
+	"specversion":     true,
+	"id":              true,
+	"source":          true,
+	"type":            true,
+	"subject":         true,
+	"time":            true,
+	"datacontenttype": true,
+	"dataschema":      true,
+	"data":            true,
+	"data_base64":     true,
+	"kgcphops":        true,
+}
+
+// GetExtensions returns the extensions set by the extensions annotation of the Trigger.
+func (t *Trigger) GetExtensions() (map[string]string, error) {
+	v, ok := t.GetAnnotations()[ExtensionsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var extensions map[string]string
+	if err := json.Unmarshal([]byte(v), &extensions); err != nil {
+		return nil, fmt.Errorf("unmarshalling extensions: %w", err)
+	}
+	return extensions, nil
+}
+
+func (t *Trigger) validateExtensionsAnnotation() *apis.FieldError {
+	extensions, err := t.GetExtensions()
+	if err != nil {
+		return apis.ErrInvalidValue(t.GetAnnotations()[ExtensionsAnnotation], extensionsAnnotationPath)
+	}
+	var errs *apis.FieldError
+	for name := range extensions {
+		if !extensionNameRegexp.MatchString(name) {
+			errs = errs.Also(apis.ErrInvalidKeyName(name, apis.CurrentField, "extension names must only contain lowercase letters and digits"))
+		} else if reservedExtensions[name] {
+			errs = errs.Also(apis.ErrInvalidKeyName(name, apis.CurrentField, "the attribute can't be set as an extension"))
+		}
+	}
+	return errs.ViaField(extensionsAnnotationPath)
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetExtensions(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        map[string]string
+		wantErr     bool
+	}{{
+		name: "no extensions",
+	}, {
+		name:        "extensions",
+		annotations: map[string]string{ExtensionsAnnotation: `{"knativetrigger":"trigger","team":"a"}`},
+		want:        map[string]string{"knativetrigger": "trigger", "team": "a"},
+	}, {
+		name:        "invalid extensions",
+		annotations: map[string]string{ExtensionsAnnotation: `knativetrigger=trigger`},
+		wantErr:     true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			got, err := trig.GetExtensions()
+			if (err != nil) != test.wantErr {
+				t.Errorf("GetExtensions error got=%v, wantErr=%v", err, test.wantErr)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("GetExtensions (-want,+got): %v", diff)
+			}
+		})
+	}
+}
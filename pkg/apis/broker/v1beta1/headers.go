@@ -0,0 +1,125 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"knative.dev/pkg/apis"
+)
+
+// HeadersAnnotation is the annotation key used to set additional HTTP headers on the
+// requests to the subscriber of a Trigger, e.g. the API key of an API gateway. The
+// value is a JSON list of DeliveryHeaders.
+const HeadersAnnotation = "events.cloud.google.com/headers"
+
+// HeadersSecretLabelKey is the label the Secrets referenced by the headers of Triggers must
+// have, set to "true". Only the Secrets with the label are watched and read by the broker.
+const HeadersSecretLabelKey = "events.cloud.google.com/trigger-headers"
+
+var headersAnnotationPath = fmt.Sprintf("metadata.annotations[%s]", HeadersAnnotation)
+
+// reservedHeaders can't be set by the headers annotation, as they're set by the
+// delivery of the events.
+var reservedHeaders = map[string]bool{
+	"Authorization":  true,
+	"Content-Length": true,
+	"Content-Type":   true,
+	"Host":           true,
+	"Traceparent":    true,
+	"Tracestate":     true,
+}
+
+// DeliveryHeader is an HTTP header set on the requests to a subscriber.
+type DeliveryHeader struct {
+	// Name of the header.
+	Name string `json:"name"`
+
+	// Value of the header.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// SecretKeyRef selects a key of a Secret in the namespace of the Trigger as the
+	// value of the header. The Secret must have the HeadersSecretLabelKey label.
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// GetHeaders returns the headers set by the headers annotation of the Trigger.
+func (t *Trigger) GetHeaders() ([]DeliveryHeader, error) {
+	v, ok := t.GetAnnotations()[HeadersAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var headers []DeliveryHeader
+	if err := json.Unmarshal([]byte(v), &headers); err != nil {
+		return nil, fmt.Errorf("unmarshalling headers: %w", err)
+	}
+	return headers, nil
+}
+
+func (t *Trigger) validateHeadersAnnotation() *apis.FieldError {
+	headers, err := t.GetHeaders()
+	if err != nil {
+		return apis.ErrInvalidValue(t.GetAnnotations()[HeadersAnnotation], headersAnnotationPath)
+	}
+	var errs *apis.FieldError
+	names := make(map[string]bool, len(headers))
+	for i, h := range headers {
+		errs = errs.Also(h.validate().ViaIndex(i))
+		name := http.CanonicalHeaderKey(h.Name)
+		if names[name] {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("duplicate header %q", h.Name), "name").ViaIndex(i))
+		}
+		names[name] = true
+	}
+	return errs.ViaField(headersAnnotationPath)
+}
+
+func (h *DeliveryHeader) validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if h.Name == "" {
+		errs = errs.Also(apis.ErrMissingField("name"))
+	} else if msgs := validation.IsHTTPHeaderName(h.Name); len(msgs) > 0 {
+		err := apis.ErrInvalidValue(h.Name, "name")
+		err.Details = strings.Join(msgs, "; ")
+		errs = errs.Also(err)
+	} else if reservedHeaders[http.CanonicalHeaderKey(h.Name)] || strings.HasPrefix(strings.ToLower(h.Name), "ce-") {
+		err := apis.ErrInvalidValue(h.Name, "name")
+		err.Details = "the header is reserved for the delivery of events"
+		errs = errs.Also(err)
+	}
+	switch {
+	case h.Value != "" && h.SecretKeyRef != nil:
+		errs = errs.Also(apis.ErrMultipleOneOf("value", "secretKeyRef"))
+	case h.SecretKeyRef != nil:
+		if h.SecretKeyRef.Name == "" {
+			errs = errs.Also(apis.ErrMissingField("secretKeyRef.name"))
+		}
+		if h.SecretKeyRef.Key == "" {
+			errs = errs.Also(apis.ErrMissingField("secretKeyRef.key"))
+		}
+	case h.Value == "":
+		errs = errs.Also(apis.ErrMissingOneOf("value", "secretKeyRef"))
+	}
+	return errs
+}
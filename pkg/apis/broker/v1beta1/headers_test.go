@@ -0,0 +1,65 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        []DeliveryHeader
+		wantErr     bool
+	}{{
+		name: "no headers",
+	}, {
+		name:        "headers",
+		annotations: map[string]string{HeadersAnnotation: `[{"name":"X-Route","value":"a"},{"name":"X-Api-Key","secretKeyRef":{"name":"gateway","key":"apiKey"}}]`},
+		want: []DeliveryHeader{{
+			Name:  "X-Route",
+			Value: "a",
+		}, {
+			Name: "X-Api-Key",
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "gateway"},
+				Key:                  "apiKey",
+			},
+		}},
+	}, {
+		name:        "invalid headers",
+		annotations: map[string]string{HeadersAnnotation: `X-Route: a`},
+		wantErr:     true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			got, err := trig.GetHeaders()
+			if (err != nil) != test.wantErr {
+				t.Errorf("GetHeaders error got=%v, wantErr=%v", err, test.wantErr)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("GetHeaders (-want,+got): %v", diff)
+			}
+		})
+	}
+}
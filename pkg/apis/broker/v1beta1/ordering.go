@@ -0,0 +1,86 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// OrderedDeliveryAnnotation is the annotation key used to opt a Broker or a Trigger
+	// into ordered delivery. Events of a Broker with ordered delivery are published to
+	// its decouple topic with an ordering key. Events are delivered in order to the
+	// subscriber of a Trigger with ordered delivery, including when they're retried.
+	// Ordering is enabled on the Pub/Sub subscriptions, so it can only be changed
+	// before the Broker or the Trigger is created.
+	OrderedDeliveryAnnotation = "events.cloud.google.com/orderedDelivery"
+
+	// OrderingKeyAttributeAnnotation is the annotation key used to set the CloudEvents
+	// attribute or extension the ordering key is derived from. Defaults to
+	// DefaultOrderingKeyAttribute.
+	OrderingKeyAttributeAnnotation = "events.cloud.google.com/orderingKeyAttribute"
+
+	// DefaultOrderingKeyAttribute is the default attribute the ordering key is derived from.
+	DefaultOrderingKeyAttribute = "subject"
+)
+
+var (
+	orderedDeliveryAnnotationPath      = fmt.Sprintf("metadata.annotations[%s]", OrderedDeliveryAnnotation)
+	orderingKeyAttributeAnnotationPath = fmt.Sprintf("metadata.annotations[%s]", OrderingKeyAttributeAnnotation)
+
+	// CloudEvents attribute names consist of lower-case letters and digits.
+	attributeNameRegexp = regexp.MustCompile(`^[a-z0-9]+$`)
+)
+
+// GetOrderingKeyAttribute returns the attribute the ordering key of the Broker's events
+// is derived from, or an empty string if the Broker doesn't have ordered delivery.
+func (b *Broker) GetOrderingKeyAttribute() string {
+	return orderingKeyAttribute(b.GetAnnotations())
+}
+
+// GetOrderingKeyAttribute returns the attribute the ordering key of the Trigger's events
+// is derived from, or an empty string if the Trigger doesn't have ordered delivery.
+func (t *Trigger) GetOrderingKeyAttribute() string {
+	return orderingKeyAttribute(t.GetAnnotations())
+}
+
+func orderingKeyAttribute(annotations map[string]string) string {
+	if ordered, _ := strconv.ParseBool(annotations[OrderedDeliveryAnnotation]); !ordered {
+		return ""
+	}
+	if attribute, ok := annotations[OrderingKeyAttributeAnnotation]; ok {
+		return attribute
+	}
+	return DefaultOrderingKeyAttribute
+}
+
+func validateOrderingAnnotations(annotations map[string]string) *apis.FieldError {
+	var errs *apis.FieldError
+	if v, ok := annotations[OrderedDeliveryAnnotation]; ok {
+		if _, err := strconv.ParseBool(v); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(v, orderedDeliveryAnnotationPath))
+		}
+	}
+	if v, ok := annotations[OrderingKeyAttributeAnnotation]; ok && !attributeNameRegexp.MatchString(v) {
+		errs = errs.Also(apis.ErrInvalidValue(v, orderingKeyAttributeAnnotationPath))
+	}
+	return errs
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetOrderingKeyAttribute(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+	}{{
+		name: "no annotations",
+	}, {
+		name:        "ordered delivery disabled",
+		annotations: map[string]string{OrderedDeliveryAnnotation: "false", OrderingKeyAttributeAnnotation: "orderid"},
+	}, {
+		name:        "default attribute",
+		annotations: map[string]string{OrderedDeliveryAnnotation: "true"},
+		want:        DefaultOrderingKeyAttribute,
+	}, {
+		name:        "custom attribute",
+		annotations: map[string]string{OrderedDeliveryAnnotation: "true", OrderingKeyAttributeAnnotation: "orderid"},
+		want:        "orderid",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			meta := metav1.ObjectMeta{Annotations: test.annotations}
+			b := Broker{ObjectMeta: meta}
+			if got := b.GetOrderingKeyAttribute(); got != test.want {
+				t.Errorf("Broker.GetOrderingKeyAttribute got=%q, want=%q", got, test.want)
+			}
+			trig := Trigger{ObjectMeta: meta}
+			if got := trig.GetOrderingKeyAttribute(); got != test.want {
+				t.Errorf("Trigger.GetOrderingKeyAttribute got=%q, want=%q", got, test.want)
+			}
+		})
+	}
+}
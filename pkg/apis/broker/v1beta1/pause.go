@@ -0,0 +1,47 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"strconv"
+
+	"knative.dev/pkg/apis"
+)
+
+// PausedAnnotation is the annotation key used to pause the delivery of events to the
+// subscriber of a Trigger. The events of a paused Trigger are queued in its retry
+// queue, and they're delivered once the Trigger is resumed by removing the annotation
+// or setting it to false.
+const PausedAnnotation = "events.cloud.google.com/paused"
+
+var pausedAnnotationPath = fmt.Sprintf("metadata.annotations[%s]", PausedAnnotation)
+
+// IsPaused returns true if the delivery of events to the Trigger's subscriber is paused.
+func (t *Trigger) IsPaused() bool {
+	paused, _ := strconv.ParseBool(t.GetAnnotations()[PausedAnnotation])
+	return paused
+}
+
+func validatePausedAnnotation(annotations map[string]string) *apis.FieldError {
+	if v, ok := annotations[PausedAnnotation]; ok {
+		if _, err := strconv.ParseBool(v); err != nil {
+			return apis.ErrInvalidValue(v, pausedAnnotationPath)
+		}
+	}
+	return nil
+}
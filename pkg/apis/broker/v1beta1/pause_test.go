@@ -0,0 +1,51 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsPaused(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{{
+		name: "no annotations",
+	}, {
+		name:        "paused",
+		annotations: map[string]string{PausedAnnotation: "true"},
+		want:        true,
+	}, {
+		name:        "resumed",
+		annotations: map[string]string{PausedAnnotation: "false"},
+	}, {
+		name:        "invalid",
+		annotations: map[string]string{PausedAnnotation: "yes please"},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			if got := trig.IsPaused(); got != test.want {
+				t.Errorf("IsPaused got=%v, want=%v", got, test.want)
+			}
+		})
+	}
+}
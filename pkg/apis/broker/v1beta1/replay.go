@@ -0,0 +1,140 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"time"
+
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// ReplayRetentionAnnotation is the annotation key used to enable the replay of the events
+	// delivered to a Trigger, as an ISO 8601 duration. The events published to the Broker are
+	// retained for that long by a dedicated replay subscription of the Trigger.
+	ReplayRetentionAnnotation = "events.cloud.google.com/replayRetention"
+
+	// ReplayFromAnnotation is the annotation key used to request a replay of the events
+	// published to the Broker since a point in time, as an RFC 3339 timestamp. The retained
+	// events published since then, and until the replay is requested, are redelivered to the
+	// Trigger only. Setting another timestamp requests another replay.
+	// It's also the status annotation key of the start of the last replay of the Trigger.
+	ReplayFromAnnotation = "events.cloud.google.com/replayFrom"
+
+	// ReplayUntilAnnotation is the status annotation key of the end of the last replay of the
+	// Trigger, i.e. the time the replay was requested, as an RFC 3339 timestamp.
+	ReplayUntilAnnotation = "events.cloud.google.com/replayUntil"
+
+	// MinReplayRetention and MaxReplayRetention are the bounds of the retention of the replay
+	// subscription of a Trigger, as supported by Pub/Sub.
+	MinReplayRetention = 10 * time.Minute
+	MaxReplayRetention = 7 * 24 * time.Hour
+)
+
+var (
+	replayRetentionAnnotationPath = fmt.Sprintf("metadata.annotations[%s]", ReplayRetentionAnnotation)
+	replayFromAnnotationPath      = fmt.Sprintf("metadata.annotations[%s]", ReplayFromAnnotation)
+)
+
+// GetReplayRetention returns how long the events published to the Broker are retained to be
+// replayed to the Trigger, or 0 if the Trigger doesn't replay events.
+func (t *Trigger) GetReplayRetention() time.Duration {
+	v, ok := t.GetAnnotations()[ReplayRetentionAnnotation]
+	if !ok {
+		return 0
+	}
+	d, err := parseDuration(v)
+	if err != nil || d < MinReplayRetention || d > MaxReplayRetention {
+		return 0
+	}
+	return d
+}
+
+// GetReplayFrom returns the point in time since which the events are requested to be
+// replayed to the Trigger, if a replay is requested.
+func (t *Trigger) GetReplayFrom() (time.Time, bool) {
+	if t.GetReplayRetention() == 0 {
+		return time.Time{}, false
+	}
+	v, ok := t.GetAnnotations()[ReplayFromAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	from, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return from, true
+}
+
+// GetReplayWindow returns the time range of the events published to the Broker which are
+// replayed to the Trigger, as recorded in the status annotations, if any.
+func (ts *TriggerStatus) GetReplayWindow() (from, until time.Time, ok bool) {
+	from, err := time.Parse(time.RFC3339, ts.Annotations[ReplayFromAnnotation])
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	until, err = time.Parse(time.RFC3339, ts.Annotations[ReplayUntilAnnotation])
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return from, until, true
+}
+
+// SetReplayWindow records the time range of the events replayed to the Trigger in the status
+// annotations.
+func (ts *TriggerStatus) SetReplayWindow(from, until time.Time) {
+	if ts.Annotations == nil {
+		ts.Annotations = make(map[string]string)
+	}
+	ts.Annotations[ReplayFromAnnotation] = from.UTC().Format(time.RFC3339)
+	ts.Annotations[ReplayUntilAnnotation] = until.UTC().Format(time.RFC3339)
+}
+
+// ClearReplayWindow removes the replay window from the status annotations.
+func (ts *TriggerStatus) ClearReplayWindow() {
+	delete(ts.Annotations, ReplayFromAnnotation)
+	delete(ts.Annotations, ReplayUntilAnnotation)
+	if len(ts.Annotations) == 0 {
+		ts.Annotations = nil
+	}
+}
+
+func validateReplayAnnotations(annotations map[string]string) *apis.FieldError {
+	var errs *apis.FieldError
+	retention, hasRetention := annotations[ReplayRetentionAnnotation]
+	if hasRetention {
+		if d, err := parseDuration(retention); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(retention, replayRetentionAnnotationPath))
+		} else if d < MinReplayRetention || d > MaxReplayRetention {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(retention, "PT10M", "P7D", replayRetentionAnnotationPath))
+		}
+	}
+	if from, ok := annotations[ReplayFromAnnotation]; ok {
+		if _, err := time.Parse(time.RFC3339, from); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(from, replayFromAnnotationPath))
+		}
+		if !hasRetention {
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("%s requires %s", ReplayFromAnnotation, ReplayRetentionAnnotation),
+				Paths:   []string{replayFromAnnotationPath},
+			})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetReplayRetention(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        time.Duration
+	}{{
+		name: "no annotations",
+	}, {
+		name:        "retention",
+		annotations: map[string]string{ReplayRetentionAnnotation: "P1D"},
+		want:        24 * time.Hour,
+	}, {
+		name:        "invalid retention",
+		annotations: map[string]string{ReplayRetentionAnnotation: "1d"},
+	}, {
+		name:        "retention too short",
+		annotations: map[string]string{ReplayRetentionAnnotation: "PT1M"},
+	}, {
+		name:        "retention too long",
+		annotations: map[string]string{ReplayRetentionAnnotation: "P8D"},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			if got := trig.GetReplayRetention(); got != test.want {
+				t.Errorf("GetReplayRetention got=%v, want=%v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetReplayFrom(t *testing.T) {
+	from := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        time.Time
+		wantOK      bool
+	}{{
+		name: "no annotations",
+	}, {
+		name: "replay",
+		annotations: map[string]string{
+			ReplayRetentionAnnotation: "P1D",
+			ReplayFromAnnotation:      "2020-10-01T12:00:00Z",
+		},
+		want:   from,
+		wantOK: true,
+	}, {
+		name: "replay without retention",
+		annotations: map[string]string{
+			ReplayFromAnnotation: "2020-10-01T12:00:00Z",
+		},
+	}, {
+		name: "invalid timestamp",
+		annotations: map[string]string{
+			ReplayRetentionAnnotation: "P1D",
+			ReplayFromAnnotation:      "2020-10-01",
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			got, ok := trig.GetReplayFrom()
+			if !got.Equal(test.want) || ok != test.wantOK {
+				t.Errorf("GetReplayFrom got=(%v, %v), want=(%v, %v)", got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
+
+func TestReplayWindow(t *testing.T) {
+	from := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	until := from.Add(time.Hour)
+	var ts TriggerStatus
+	if _, _, ok := ts.GetReplayWindow(); ok {
+		t.Error("GetReplayWindow got a window before it's set")
+	}
+
+	ts.SetReplayWindow(from, until)
+	wantAnnotations := map[string]string{
+		ReplayFromAnnotation:  "2020-10-01T12:00:00Z",
+		ReplayUntilAnnotation: "2020-10-01T13:00:00Z",
+	}
+	if diff := cmp.Diff(wantAnnotations, ts.Annotations); diff != "" {
+		t.Errorf("Status annotations (-want,+got): %v", diff)
+	}
+	gotFrom, gotUntil, ok := ts.GetReplayWindow()
+	if !ok || !gotFrom.Equal(from) || !gotUntil.Equal(until) {
+		t.Errorf("GetReplayWindow got=(%v, %v, %v), want=(%v, %v, true)", gotFrom, gotUntil, ok, from, until)
+	}
+
+	ts.ClearReplayWindow()
+	if ts.Annotations != nil {
+		t.Errorf("Status annotations got=%v, want nil", ts.Annotations)
+	}
+}
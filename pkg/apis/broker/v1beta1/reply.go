@@ -0,0 +1,79 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// ReplyAnnotation is the annotation key used to route the replies of a Trigger's
+// subscriber. The value is a JSON ReplySpec. By default, the replies are published
+// back to the Trigger's Broker.
+const ReplyAnnotation = "events.cloud.google.com/reply"
+
+var replyAnnotationPath = fmt.Sprintf("metadata.annotations[%s]", ReplyAnnotation)
+
+// ReplySpec defines where the replies of a Trigger's subscriber are sent.
+type ReplySpec struct {
+	// Destination is where the replies are sent instead of the Trigger's Broker, e.g.
+	// a Broker in another namespace.
+	// +optional
+	Destination *duckv1.Destination `json:"destination,omitempty"`
+
+	// Drop the replies instead of sending them anywhere.
+	// +optional
+	Drop bool `json:"drop,omitempty"`
+}
+
+// GetReply returns the reply spec set by the reply annotation of the Trigger, or nil
+// if it doesn't have one.
+func (t *Trigger) GetReply() (*ReplySpec, error) {
+	v, ok := t.GetAnnotations()[ReplyAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var spec ReplySpec
+	if err := json.Unmarshal([]byte(v), &spec); err != nil {
+		return nil, fmt.Errorf("unmarshalling reply: %w", err)
+	}
+	return &spec, nil
+}
+
+func (t *Trigger) validateReplyAnnotation(ctx context.Context) *apis.FieldError {
+	spec, err := t.GetReply()
+	if err != nil {
+		return apis.ErrInvalidValue(t.GetAnnotations()[ReplyAnnotation], replyAnnotationPath)
+	}
+	if spec == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	switch {
+	case spec.Destination != nil && spec.Drop:
+		errs = apis.ErrMultipleOneOf("destination", "drop")
+	case spec.Destination != nil:
+		errs = spec.Destination.Validate(ctx).ViaField("destination")
+	case !spec.Drop:
+		errs = apis.ErrMissingOneOf("destination", "drop")
+	}
+	return errs.ViaField(replyAnnotationPath)
+}
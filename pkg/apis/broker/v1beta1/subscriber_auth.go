@@ -0,0 +1,91 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"knative.dev/pkg/apis"
+)
+
+// SubscriberAuthAnnotation is the annotation key used to authenticate the delivery of
+// events to the subscriber of a Trigger, e.g. an authenticated Cloud Run service or an
+// IAP-protected endpoint. The value is a JSON SubscriberAuthSpec.
+const SubscriberAuthAnnotation = "events.cloud.google.com/subscriberAuth"
+
+var subscriberAuthAnnotationPath = fmt.Sprintf("metadata.annotations[%s]", SubscriberAuthAnnotation)
+
+// SubscriberAuthMode is the kind of token attached to the requests to a subscriber.
+type SubscriberAuthMode string
+
+const (
+	// SubscriberAuthIDToken attaches a Google-signed ID token of the broker's identity,
+	// for the audience of the SubscriberAuthSpec.
+	SubscriberAuthIDToken SubscriberAuthMode = "IDToken"
+	// SubscriberAuthAccessToken attaches an OAuth access token of the broker's identity.
+	SubscriberAuthAccessToken SubscriberAuthMode = "AccessToken"
+)
+
+// SubscriberAuthSpec defines how the requests to the subscriber of a Trigger are
+// authenticated. The token is sent as a bearer token in the Authorization header.
+type SubscriberAuthSpec struct {
+	// Mode is the kind of token attached to the requests.
+	Mode SubscriberAuthMode `json:"mode"`
+
+	// Audience of the ID tokens. Defaults to the origin of the subscriber URI, which
+	// is the audience expected by Cloud Run services. Only valid with the IDToken mode.
+	// +optional
+	Audience string `json:"audience,omitempty"`
+}
+
+// GetSubscriberAuth returns the subscriber auth spec set by the subscriber auth
+// annotation of the Trigger, or nil if it doesn't have one.
+func (t *Trigger) GetSubscriberAuth() (*SubscriberAuthSpec, error) {
+	v, ok := t.GetAnnotations()[SubscriberAuthAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var spec SubscriberAuthSpec
+	if err := json.Unmarshal([]byte(v), &spec); err != nil {
+		return nil, fmt.Errorf("unmarshalling subscriber auth: %w", err)
+	}
+	return &spec, nil
+}
+
+func (t *Trigger) validateSubscriberAuthAnnotation() *apis.FieldError {
+	spec, err := t.GetSubscriberAuth()
+	if err != nil {
+		return apis.ErrInvalidValue(t.GetAnnotations()[SubscriberAuthAnnotation], subscriberAuthAnnotationPath)
+	}
+	if spec == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	switch spec.Mode {
+	case SubscriberAuthIDToken:
+	case SubscriberAuthAccessToken:
+		if spec.Audience != "" {
+			errs = errs.Also(apis.ErrDisallowedFields("audience"))
+		}
+	case "":
+		errs = errs.Also(apis.ErrMissingField("mode"))
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(spec.Mode, "mode"))
+	}
+	return errs.ViaField(subscriberAuthAnnotationPath)
+}
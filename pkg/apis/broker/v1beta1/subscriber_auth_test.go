@@ -0,0 +1,59 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetSubscriberAuth(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        *SubscriberAuthSpec
+		wantErr     bool
+	}{{
+		name: "no subscriber auth",
+	}, {
+		name:        "ID token",
+		annotations: map[string]string{SubscriberAuthAnnotation: `{"mode":"IDToken","audience":"https://subscriber.a.run.app"}`},
+		want:        &SubscriberAuthSpec{Mode: SubscriberAuthIDToken, Audience: "https://subscriber.a.run.app"},
+	}, {
+		name:        "access token",
+		annotations: map[string]string{SubscriberAuthAnnotation: `{"mode":"AccessToken"}`},
+		want:        &SubscriberAuthSpec{Mode: SubscriberAuthAccessToken},
+	}, {
+		name:        "invalid subscriber auth",
+		annotations: map[string]string{SubscriberAuthAnnotation: `IDToken`},
+		wantErr:     true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			got, err := trig.GetSubscriberAuth()
+			if (err != nil) != test.wantErr {
+				t.Errorf("GetSubscriberAuth error got=%v, wantErr=%v", err, test.wantErr)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("GetSubscriberAuth (-want,+got): %v", diff)
+			}
+		})
+	}
+}
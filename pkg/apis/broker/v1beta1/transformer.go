@@ -0,0 +1,63 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// TransformerAnnotation is the annotation key used to set the transformer of a Broker.
+// The value is a JSON Destination. Each event published to the Broker is sent to the
+// transformer before it's fanned out to the Triggers, and the transformer's reply
+// replaces the event for all the Triggers. An event is dropped if the transformer
+// replies without an event.
+const TransformerAnnotation = "events.cloud.google.com/transformer"
+
+var transformerAnnotationPath = fmt.Sprintf("metadata.annotations[%s]", TransformerAnnotation)
+
+// GetTransformer returns the transformer set by the transformer annotation of the Broker,
+// or nil if it doesn't have one.
+func (b *Broker) GetTransformer() (*duckv1.Destination, error) {
+	v, ok := b.GetAnnotations()[TransformerAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var dest duckv1.Destination
+	if err := json.Unmarshal([]byte(v), &dest); err != nil {
+		return nil, fmt.Errorf("unmarshalling transformer: %w", err)
+	}
+	return &dest, nil
+}
+
+func (b *Broker) validateTransformerAnnotation(ctx context.Context) *apis.FieldError {
+	dest, err := b.GetTransformer()
+	if err != nil {
+		return apis.ErrInvalidValue(b.GetAnnotations()[TransformerAnnotation], transformerAnnotationPath)
+	}
+	if dest == nil {
+		return nil
+	}
+	if ferr := dest.Validate(ctx); ferr != nil {
+		return ferr.ViaFieldKey("annotations", TransformerAnnotation).ViaField("metadata")
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestGetTransformer(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        *duckv1.Destination
+		wantErr     bool
+	}{{
+		name: "no transformer",
+	}, {
+		name:        "transformer uri",
+		annotations: map[string]string{TransformerAnnotation: `{"uri":"http://transformer.ns.svc.cluster.local"}`},
+		want:        &duckv1.Destination{URI: apis.HTTP("transformer.ns.svc.cluster.local")},
+	}, {
+		name:        "invalid transformer",
+		annotations: map[string]string{TransformerAnnotation: `transformer`},
+		wantErr:     true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := Broker{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			got, err := b.GetTransformer()
+			if (err != nil) != test.wantErr {
+				t.Errorf("GetTransformer error got=%v, wantErr=%v", err, test.wantErr)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("GetTransformer (-want,+got): %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rickb777/date/period"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// DeliveryAnnotation is the annotation key used to set the delivery settings of a
+	// Trigger. The value is a JSON TriggerDeliverySpec. The settings which are set
+	// override the delivery spec of the Broker for this Trigger.
+	DeliveryAnnotation = "events.cloud.google.com/delivery"
+)
+
+var deliveryAnnotationPath = fmt.Sprintf("metadata.annotations[%s]", DeliveryAnnotation)
+
+// TriggerDeliverySpec is the delivery settings of a Trigger.
+type TriggerDeliverySpec struct {
+	// Retry is the minimum number of retries the sender should attempt when
+	// sending an event before moving it to the dead letter sink, or dropping it
+	// if the Broker doesn't have a dead letter sink.
+	// +optional
+	Retry *int32 `json:"retry,omitempty"`
+
+	// BackoffPolicy is the retry backoff policy (linear, exponential).
+	// +optional
+	BackoffPolicy *eventingduckv1beta1.BackoffPolicyType `json:"backoffPolicy,omitempty"`
+
+	// BackoffDelay is the delay before retrying, in ISO 8601 duration format.
+	// +optional
+	BackoffDelay *string `json:"backoffDelay,omitempty"`
+
+	// Timeout is the timeout of each delivery request to the subscriber, in
+	// ISO 8601 duration format.
+	// +optional
+	Timeout *string `json:"timeout,omitempty"`
+}
+
+// GetDelivery returns the delivery settings set by the delivery annotation of the Trigger.
+func (t *Trigger) GetDelivery() (*TriggerDeliverySpec, error) {
+	v, ok := t.GetAnnotations()[DeliveryAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var spec TriggerDeliverySpec
+	if err := json.Unmarshal([]byte(v), &spec); err != nil {
+		return nil, fmt.Errorf("unmarshalling delivery: %w", err)
+	}
+	return &spec, nil
+}
+
+// ApplyTo returns a copy of the Broker delivery spec with the settings of the Trigger applied.
+func (s *TriggerDeliverySpec) ApplyTo(spec *eventingduckv1beta1.DeliverySpec) *eventingduckv1beta1.DeliverySpec {
+	var out *eventingduckv1beta1.DeliverySpec
+	if spec != nil {
+		out = spec.DeepCopy()
+	} else {
+		out = &eventingduckv1beta1.DeliverySpec{}
+	}
+	if s == nil {
+		return out
+	}
+	if s.Retry != nil {
+		retry := *s.Retry
+		out.Retry = &retry
+	}
+	if s.BackoffPolicy != nil {
+		policy := *s.BackoffPolicy
+		out.BackoffPolicy = &policy
+	}
+	if s.BackoffDelay != nil {
+		delay := *s.BackoffDelay
+		out.BackoffDelay = &delay
+	}
+	return out
+}
+
+// GetTimeout returns the parsed delivery timeout, or false if it isn't set or is invalid.
+func (s *TriggerDeliverySpec) GetTimeout() (time.Duration, bool) {
+	if s == nil || s.Timeout == nil {
+		return 0, false
+	}
+	d, err := parseDuration(*s.Timeout)
+	if err != nil {
+		return 0, false
+	}
+	return d, true
+}
+
+// Validate checks that the delivery settings are valid.
+func (s *TriggerDeliverySpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if s.Retry != nil && *s.Retry < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*s.Retry, "retry"))
+	}
+	if s.BackoffPolicy != nil {
+		switch *s.BackoffPolicy {
+		case eventingduckv1beta1.BackoffPolicyExponential, eventingduckv1beta1.BackoffPolicyLinear:
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(*s.BackoffPolicy, "backoffPolicy"))
+		}
+	}
+	if s.BackoffDelay != nil {
+		if _, err := parseDuration(*s.BackoffDelay); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(*s.BackoffDelay, "backoffDelay"))
+		}
+	}
+	if s.Timeout != nil {
+		if d, err := parseDuration(*s.Timeout); err != nil || d <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(*s.Timeout, "timeout"))
+		}
+	}
+	return errs
+}
+
+// validateDeliveryAnnotation validates the delivery settings set by the delivery annotation.
+func (t *Trigger) validateDeliveryAnnotation() *apis.FieldError {
+	spec, err := t.GetDelivery()
+	if err != nil {
+		return apis.ErrInvalidValue(err.Error(), deliveryAnnotationPath)
+	}
+	if spec == nil {
+		return nil
+	}
+	return spec.Validate().ViaField(deliveryAnnotationPath)
+}
+
+// parseDuration parses an ISO 8601 duration.
+func parseDuration(s string) (time.Duration, error) {
+	p, err := period.Parse(s)
+	if err != nil {
+		return 0, err
+	}
+	d, _ := p.Duration()
+	return d, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestTriggerDeliverySpec_ApplyTo(t *testing.T) {
+	exponential := eventingduckv1beta1.BackoffPolicyExponential
+	linear := eventingduckv1beta1.BackoffPolicyLinear
+	brokerSpec := &eventingduckv1beta1.DeliverySpec{
+		DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dead-letter")},
+		Retry:          ptr.Int32(5),
+		BackoffPolicy:  &exponential,
+		BackoffDelay:   ptr.String("PT1S"),
+	}
+
+	tests := []struct {
+		name       string
+		spec       *TriggerDeliverySpec
+		brokerSpec *eventingduckv1beta1.DeliverySpec
+		want       *eventingduckv1beta1.DeliverySpec
+	}{{
+		name:       "no trigger delivery",
+		brokerSpec: brokerSpec,
+		want:       brokerSpec,
+	}, {
+		name:       "no broker delivery",
+		spec:       &TriggerDeliverySpec{Retry: ptr.Int32(2)},
+		brokerSpec: nil,
+		want:       &eventingduckv1beta1.DeliverySpec{Retry: ptr.Int32(2)},
+	}, {
+		name: "override",
+		spec: &TriggerDeliverySpec{
+			Retry:         ptr.Int32(2),
+			BackoffPolicy: &linear,
+			BackoffDelay:  ptr.String("PT10S"),
+			Timeout:       ptr.String("PT30S"),
+		},
+		brokerSpec: brokerSpec,
+		want: &eventingduckv1beta1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dead-letter")},
+			Retry:          ptr.Int32(2),
+			BackoffPolicy:  &linear,
+			BackoffDelay:   ptr.String("PT10S"),
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.spec.ApplyTo(test.brokerSpec)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("ApplyTo (-want,+got): %v", diff)
+			}
+			if got == test.brokerSpec {
+				t.Error("ApplyTo returned the broker delivery spec instead of a copy")
+			}
+		})
+	}
+}
+
+func TestTriggerDeliverySpec_GetTimeout(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   *TriggerDeliverySpec
+		want   time.Duration
+		wantOK bool
+	}{{
+		name: "nil spec",
+	}, {
+		name: "no timeout",
+		spec: &TriggerDeliverySpec{},
+	}, {
+		name: "invalid timeout",
+		spec: &TriggerDeliverySpec{Timeout: ptr.String("30s")},
+	}, {
+		name:   "timeout",
+		spec:   &TriggerDeliverySpec{Timeout: ptr.String("PT1M30S")},
+		want:   90 * time.Second,
+		wantOK: true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := test.spec.GetTimeout()
+			if got != test.want || ok != test.wantOK {
+				t.Errorf("GetTimeout got=(%v, %v), want=(%v, %v)", got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"knative.dev/pkg/apis"
+
+	"github.com/google/knative-gcp/pkg/utils/celfilter"
+)
+
+const (
+	// FiltersAnnotation is the annotation key used to set CloudEvents Subscriptions API
+	// filter expressions on a Trigger. The value is a JSON list of SubscriptionsAPIFilter.
+	// An event must pass all of the filter expressions, in addition to spec.filter, to be
+	// delivered to the subscriber.
+	FiltersAnnotation = "events.cloud.google.com/filters"
+
+	// CELFilterAnnotation is the annotation key used to set a CEL filter expression on a
+	// Trigger. The expression must evaluate to a bool. An event must pass it, in addition
+	// to spec.filter and the filters annotation, to be delivered to the subscriber.
+	CELFilterAnnotation = "events.cloud.google.com/celFilter"
+)
+
+var (
+	filtersAnnotationPath   = fmt.Sprintf("metadata.annotations[%s]", FiltersAnnotation)
+	celFilterAnnotationPath = fmt.Sprintf("metadata.annotations[%s]", CELFilterAnnotation)
+)
+
+// SubscriptionsAPIFilter is a filter expression in one of the dialects defined by the
+// CloudEvents Subscriptions API. Exactly one dialect must be set.
+type SubscriptionsAPIFilter struct {
+	// All evaluates to true if all the nested expressions evaluate to true.
+	// +optional
+	All []SubscriptionsAPIFilter `json:"all,omitempty"`
+
+	// Any evaluates to true if at least one of the nested expressions evaluates to true.
+	// +optional
+	Any []SubscriptionsAPIFilter `json:"any,omitempty"`
+
+	// Not evaluates to true if the nested expression evaluates to false.
+	// +optional
+	Not *SubscriptionsAPIFilter `json:"not,omitempty"`
+
+	// Exact evaluates to true if the values of the matching CloudEvents attributes
+	// all exactly match the given values.
+	// +optional
+	Exact map[string]string `json:"exact,omitempty"`
+
+	// Prefix evaluates to true if the values of the matching CloudEvents attributes
+	// all start with the given values.
+	// +optional
+	Prefix map[string]string `json:"prefix,omitempty"`
+
+	// Suffix evaluates to true if the values of the matching CloudEvents attributes
+	// all end with the given values.
+	// +optional
+	Suffix map[string]string `json:"suffix,omitempty"`
+}
+
+// GetFilters returns the filter expressions set by the filters annotation of the Trigger.
+func (t *Trigger) GetFilters() ([]SubscriptionsAPIFilter, error) {
+	v, ok := t.GetAnnotations()[FiltersAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var filters []SubscriptionsAPIFilter
+	if err := json.Unmarshal([]byte(v), &filters); err != nil {
+		return nil, fmt.Errorf("unmarshalling filters: %w", err)
+	}
+	return filters, nil
+}
+
+// Validate checks that exactly one dialect is set on the filter expression and
+// that the nested expressions are valid.
+func (f *SubscriptionsAPIFilter) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	var dialects []string
+	if len(f.All) > 0 {
+		dialects = append(dialects, "all")
+		for i := range f.All {
+			errs = errs.Also(f.All[i].Validate().ViaFieldIndex("all", i))
+		}
+	}
+	if len(f.Any) > 0 {
+		dialects = append(dialects, "any")
+		for i := range f.Any {
+			errs = errs.Also(f.Any[i].Validate().ViaFieldIndex("any", i))
+		}
+	}
+	if f.Not != nil {
+		dialects = append(dialects, "not")
+		errs = errs.Also(f.Not.Validate().ViaField("not"))
+	}
+	if len(f.Exact) > 0 {
+		dialects = append(dialects, "exact")
+		errs = errs.Also(validateFilterAttributes(f.Exact).ViaField("exact"))
+	}
+	if len(f.Prefix) > 0 {
+		dialects = append(dialects, "prefix")
+		errs = errs.Also(validateFilterAttributes(f.Prefix).ViaField("prefix"))
+	}
+	if len(f.Suffix) > 0 {
+		dialects = append(dialects, "suffix")
+		errs = errs.Also(validateFilterAttributes(f.Suffix).ViaField("suffix"))
+	}
+	switch len(dialects) {
+	case 0:
+		errs = errs.Also(apis.ErrMissingOneOf("all", "any", "not", "exact", "prefix", "suffix"))
+	case 1:
+	default:
+		errs = errs.Also(apis.ErrMultipleOneOf(dialects...))
+	}
+	return errs
+}
+
+func validateFilterAttributes(attrs map[string]string) *apis.FieldError {
+	var errs *apis.FieldError
+	for k := range attrs {
+		if k == "" {
+			errs = errs.Also(apis.ErrInvalidKeyName(k, apis.CurrentField, "attribute name must not be empty"))
+		}
+	}
+	return errs
+}
+
+// ValidateFilters validates the filter expressions and the CEL filter expression set by the
+// annotations of the Trigger.
+func (t *Trigger) ValidateFilters() *apis.FieldError {
+	return t.validateFiltersAnnotation().Also(t.validateCELFilterAnnotation())
+}
+
+// validateFiltersAnnotation validates the filter expressions set by the filters annotation.
+func (t *Trigger) validateFiltersAnnotation() *apis.FieldError {
+	filters, err := t.GetFilters()
+	if err != nil {
+		return apis.ErrInvalidValue(err.Error(), filtersAnnotationPath)
+	}
+	var errs *apis.FieldError
+	for i := range filters {
+		errs = errs.Also(filters[i].Validate().ViaIndex(i))
+	}
+	return errs.ViaField(filtersAnnotationPath)
+}
+
+// GetCELFilter returns the CEL filter expression set by the CEL filter annotation of the
+// Trigger, or an empty string if it isn't set.
+func (t *Trigger) GetCELFilter() string {
+	return t.GetAnnotations()[CELFilterAnnotation]
+}
+
+// validateCELFilterAnnotation checks that the CEL filter expression compiles.
+func (t *Trigger) validateCELFilterAnnotation() *apis.FieldError {
+	expr, ok := t.GetAnnotations()[CELFilterAnnotation]
+	if !ok {
+		return nil
+	}
+	if _, err := celfilter.Compile(expr); err != nil {
+		return apis.ErrInvalidValue(err.Error(), celFilterAnnotationPath)
+	}
+	return nil
+}
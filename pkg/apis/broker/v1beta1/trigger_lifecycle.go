@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/pkg/apis"
@@ -29,11 +31,33 @@ var triggerCondSet = apis.NewLivingConditionSet(
 	eventingv1beta1.TriggerConditionSubscriberResolved,
 	TriggerConditionTopic,
 	TriggerConditionSubscription,
+	TriggerConditionFilter,
 )
 
 const (
 	TriggerConditionTopic        apis.ConditionType = "TopicReady"
 	TriggerConditionSubscription apis.ConditionType = "SubscriptionReady"
+
+	// TriggerConditionFilter reports whether the filters and the CEL filter set by the annotations
+	// of the Trigger are valid. A Trigger with invalid filters doesn't receive any events.
+	TriggerConditionFilter apis.ConditionType = "FilterValid"
+
+	// TriggerConditionReplay reports the progress of the replays of the Trigger. It's only
+	// set if the Trigger replays events, and doesn't affect the readiness of the Trigger.
+	TriggerConditionReplay apis.ConditionType = "ReplayReady"
+
+	replayPendingReason = "ReplayPending"
+	replayStartedReason = "ReplayStarted"
+
+	// TriggerConditionCircuitBreaker reports whether the circuit breaker of the subscriber
+	// is closed in the fanout pods. It's only set once a breaker was opened, and doesn't
+	// affect the readiness of the Trigger.
+	TriggerConditionCircuitBreaker apis.ConditionType = "CircuitBreakerClosed"
+
+	// TriggerConditionSubscriberReachable reports whether the last deliveries to the subscriber
+	// reached it. It's only set once events were delivered, and doesn't affect the readiness of
+	// the Trigger.
+	TriggerConditionSubscriberReachable apis.ConditionType = "SubscriberReachable"
 )
 
 // GetCondition returns the condition currently associated with the given type, or nil.
@@ -112,6 +136,97 @@ func (bs *TriggerStatus) MarkSubscriptionReady() {
 	triggerCondSet.Manage(bs).MarkTrue(TriggerConditionSubscription)
 }
 
+func (ts *TriggerStatus) MarkFilterValid() {
+	triggerCondSet.Manage(ts).MarkTrue(TriggerConditionFilter)
+}
+
+func (ts *TriggerStatus) MarkFilterFailed(reason, format string, args ...interface{}) {
+	triggerCondSet.Manage(ts).MarkFalse(TriggerConditionFilter, reason, format, args...)
+}
+
+// IsReplayReady returns true if the replay subscription of the Trigger is ready, whether a
+// replay is in progress or not.
+func (ts *TriggerStatus) IsReplayReady() bool {
+	c := ts.GetCondition(TriggerConditionReplay)
+	return c != nil && (c.IsTrue() || c.Reason == replayPendingReason)
+}
+
+// IsReplayStarted returns true if the replay subscription of the Trigger was seeked to the
+// start of the replay window.
+func (ts *TriggerStatus) IsReplayStarted() bool {
+	c := ts.GetCondition(TriggerConditionReplay)
+	return c != nil && c.IsTrue() && c.Reason == replayStartedReason
+}
+
+// MarkReplayAvailable marks the replay subscription of the Trigger ready, without a replay in progress.
+func (ts *TriggerStatus) MarkReplayAvailable() {
+	triggerCondSet.Manage(ts).MarkTrueWithReason(TriggerConditionReplay, "ReplayAvailable", "Events can be replayed")
+}
+
+// MarkReplayPending marks a replay of the Trigger as requested. The replay subscription is
+// seeked once the data plane has loaded the replay window.
+func (ts *TriggerStatus) MarkReplayPending(from, until time.Time) {
+	triggerCondSet.Manage(ts).MarkUnknown(TriggerConditionReplay, replayPendingReason,
+		"Waiting to replay the events published from %s until %s", from.UTC().Format(time.RFC3339), until.UTC().Format(time.RFC3339))
+}
+
+// MarkReplayStarted marks the replay subscription of the Trigger as seeked to the start of the replay.
+func (ts *TriggerStatus) MarkReplayStarted(from, until time.Time) {
+	triggerCondSet.Manage(ts).MarkTrueWithReason(TriggerConditionReplay, replayStartedReason,
+		"Replaying the events published from %s until %s", from.UTC().Format(time.RFC3339), until.UTC().Format(time.RFC3339))
+}
+
+func (ts *TriggerStatus) MarkReplayFailed(reason, messageFormat string, messageA ...interface{}) {
+	triggerCondSet.Manage(ts).MarkFalse(TriggerConditionReplay, reason, messageFormat, messageA...)
+}
+
+func (ts *TriggerStatus) MarkReplayUnknown(reason, messageFormat string, messageA ...interface{}) {
+	triggerCondSet.Manage(ts).MarkUnknown(TriggerConditionReplay, reason, messageFormat, messageA...)
+}
+
+// ClearReplay removes the replay condition and window of a Trigger which doesn't replay events.
+func (ts *TriggerStatus) ClearReplay() {
+	triggerCondSet.Manage(ts).ClearCondition(TriggerConditionReplay)
+	ts.ClearReplayWindow()
+}
+
+// MarkCircuitBreakerClosed marks the circuit breaker of the subscriber closed in all the fanout pods.
+func (ts *TriggerStatus) MarkCircuitBreakerClosed() {
+	triggerCondSet.Manage(ts).MarkTrueWithReason(TriggerConditionCircuitBreaker, "CircuitBreakerClosed", "Events are delivered to the subscriber")
+}
+
+// MarkCircuitBreakerOpen marks the circuit breaker of the subscriber open in the given number of
+// fanout pods, which send the events to the retry queue without delivering them.
+func (ts *TriggerStatus) MarkCircuitBreakerOpen(pods int) {
+	triggerCondSet.Manage(ts).MarkFalse(TriggerConditionCircuitBreaker, "CircuitBreakerOpen",
+		"The circuit breaker of the subscriber is open in %d fanout pod(s), events are sent to the retry queue without being delivered", pods)
+}
+
+// MarkCircuitBreakerHalfOpen marks the circuit breaker of the subscriber half-open in the given
+// number of fanout pods, which deliver trial events to the subscriber.
+func (ts *TriggerStatus) MarkCircuitBreakerHalfOpen(pods int) {
+	triggerCondSet.Manage(ts).MarkUnknown(TriggerConditionCircuitBreaker, "CircuitBreakerHalfOpen",
+		"The circuit breaker of the subscriber is half-open in %d fanout pod(s), trial events are delivered to the subscriber", pods)
+}
+
+// MarkSubscriberReachable marks the subscriber reachable by the last deliveries, even if it
+// rejected the events.
+func (ts *TriggerStatus) MarkSubscriberReachable() {
+	triggerCondSet.Manage(ts).MarkTrueWithReason(TriggerConditionSubscriberReachable, "SubscriberReachable", "Events are delivered to the subscriber")
+}
+
+// MarkSubscriberUnreachable marks the subscriber unreachable or overloaded since the last failed
+// delivery, with the HTTP status code of its response, zero if it didn't respond.
+func (ts *TriggerStatus) MarkSubscriberUnreachable(code int, lastError string) {
+	if code == 0 {
+		triggerCondSet.Manage(ts).MarkFalse(TriggerConditionSubscriberReachable, "SubscriberUnreachable",
+			"The last delivery to the subscriber failed: %s", lastError)
+		return
+	}
+	triggerCondSet.Manage(ts).MarkFalse(TriggerConditionSubscriberReachable, "SubscriberUnavailable",
+		"The last delivery to the subscriber failed with HTTP status code %d", code)
+}
+
 func (ts *TriggerStatus) MarkSubscriberResolvedSucceeded() {
 	triggerCondSet.Manage(ts).MarkTrue(eventingv1beta1.TriggerConditionSubscriberResolved)
 }
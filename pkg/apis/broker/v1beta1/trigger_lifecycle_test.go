@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -150,6 +151,9 @@ func TestTriggerInitializeConditions(t *testing.T) {
 					}, {
 						Type:   eventingv1beta1.TriggerConditionDependency,
 						Status: corev1.ConditionUnknown,
+					}, {
+						Type:   TriggerConditionFilter,
+						Status: corev1.ConditionUnknown,
 					}, {
 						Type:   eventingv1beta1.TriggerConditionReady,
 						Status: corev1.ConditionUnknown,
@@ -187,6 +191,9 @@ func TestTriggerInitializeConditions(t *testing.T) {
 					}, {
 						Type:   eventingv1beta1.TriggerConditionDependency,
 						Status: corev1.ConditionUnknown,
+					}, {
+						Type:   TriggerConditionFilter,
+						Status: corev1.ConditionUnknown,
 					}, {
 						Type:   eventingv1beta1.TriggerConditionReady,
 						Status: corev1.ConditionUnknown,
@@ -224,6 +231,9 @@ func TestTriggerInitializeConditions(t *testing.T) {
 					}, {
 						Type:   eventingv1beta1.TriggerConditionDependency,
 						Status: corev1.ConditionUnknown,
+					}, {
+						Type:   TriggerConditionFilter,
+						Status: corev1.ConditionUnknown,
 					}, {
 						Type:   eventingv1beta1.TriggerConditionReady,
 						Status: corev1.ConditionUnknown,
@@ -264,6 +274,7 @@ func TestTriggerConditionStatus(t *testing.T) {
 		subscriptionStatus       corev1.ConditionStatus
 		subscriberResolvedStatus corev1.ConditionStatus
 		dependencyStatus         *duckv1.Source
+		invalidFilter            bool
 		wantConditionStatus      corev1.ConditionStatus
 	}{{
 		name:                     "all happy",
@@ -369,6 +380,15 @@ func TestTriggerConditionStatus(t *testing.T) {
 		subscriberResolvedStatus: corev1.ConditionTrue,
 		dependencyStatus:         TestHelper.FalseDependencyStatus(),
 		wantConditionStatus:      corev1.ConditionFalse,
+	}, {
+		name:                     "invalid filter",
+		brokerStatus:             TestHelper.ReadyBrokerStatus(),
+		subscriptionStatus:       corev1.ConditionTrue,
+		topicStatus:              corev1.ConditionTrue,
+		subscriberResolvedStatus: corev1.ConditionTrue,
+		dependencyStatus:         nil,
+		invalidFilter:            true,
+		wantConditionStatus:      corev1.ConditionFalse,
 	}, {
 		name:                     "all sad",
 		brokerStatus:             TestHelper.FalseBrokerStatus(),
@@ -376,6 +396,7 @@ func TestTriggerConditionStatus(t *testing.T) {
 		topicStatus:              corev1.ConditionFalse,
 		subscriberResolvedStatus: corev1.ConditionFalse,
 		dependencyStatus:         TestHelper.FalseDependencyStatus(),
+		invalidFilter:            true,
 		wantConditionStatus:      corev1.ConditionFalse,
 	}}
 	for _, test := range tests {
@@ -410,6 +431,11 @@ func TestTriggerConditionStatus(t *testing.T) {
 			} else {
 				ts.PropagateDependencyStatus(test.dependencyStatus)
 			}
+			if test.invalidFilter {
+				ts.MarkFilterFailed("InvalidFilter", "induced failure")
+			} else {
+				ts.MarkFilterValid()
+			}
 			got := ts.GetTopLevelCondition().Status
 			if test.wantConditionStatus != got {
 				t.Errorf("unexpected readiness: want %v, got %v", test.wantConditionStatus, got)
@@ -428,3 +454,105 @@ func TestTriggerConditionStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestTriggerReplayCondition(t *testing.T) {
+	ts := &TriggerStatus{}
+	ts.InitializeConditions()
+	ts.PropagateBrokerStatus(TestHelper.ReadyBrokerStatus())
+	ts.MarkTopicReady()
+	ts.MarkSubscriptionReady()
+	ts.MarkSubscriberResolvedSucceeded()
+	ts.MarkDependencySucceeded()
+	ts.MarkFilterValid()
+
+	// The replay condition doesn't affect the readiness of the Trigger.
+	ts.MarkReplayFailed("ReplaySeekFailed", "induced failure")
+	if !ts.IsReady() {
+		t.Error("Trigger with a failed replay isn't ready")
+	}
+	if got := ts.GetCondition(TriggerConditionReplay); got == nil || got.Status != corev1.ConditionFalse || got.Severity != apis.ConditionSeverityInfo {
+		t.Errorf("Replay condition got=%v, want a false informational condition", got)
+	}
+
+	from := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	ts.MarkReplayStarted(from, from.Add(time.Hour))
+	want := "Replaying the events published from 2020-10-01T12:00:00Z until 2020-10-01T13:00:00Z"
+	if got := ts.GetCondition(TriggerConditionReplay); got == nil || got.Status != corev1.ConditionTrue || got.Message != want {
+		t.Errorf("Replay condition got=%v, want true with message %q", got, want)
+	}
+
+	ts.SetReplayWindow(from, from.Add(time.Hour))
+	ts.ClearReplay()
+	if got := ts.GetCondition(TriggerConditionReplay); got != nil {
+		t.Errorf("Replay condition got=%v, want nil", got)
+	}
+	if _, _, ok := ts.GetReplayWindow(); ok {
+		t.Error("Replay window wasn't cleared")
+	}
+}
+
+func TestTriggerCircuitBreakerCondition(t *testing.T) {
+	ts := &TriggerStatus{}
+	ts.InitializeConditions()
+	ts.PropagateBrokerStatus(TestHelper.ReadyBrokerStatus())
+	ts.MarkTopicReady()
+	ts.MarkSubscriptionReady()
+	ts.MarkSubscriberResolvedSucceeded()
+	ts.MarkDependencySucceeded()
+	ts.MarkFilterValid()
+
+	// The circuit breaker condition doesn't affect the readiness of the Trigger.
+	ts.MarkCircuitBreakerOpen(2)
+	if !ts.IsReady() {
+		t.Error("Trigger with an open circuit breaker isn't ready")
+	}
+	want := "The circuit breaker of the subscriber is open in 2 fanout pod(s), events are sent to the retry queue without being delivered"
+	if got := ts.GetCondition(TriggerConditionCircuitBreaker); got == nil || got.Status != corev1.ConditionFalse || got.Severity != apis.ConditionSeverityInfo || got.Message != want {
+		t.Errorf("CircuitBreaker condition got=%v, want a false informational condition with message %q", got, want)
+	}
+
+	ts.MarkCircuitBreakerHalfOpen(1)
+	if !ts.IsReady() {
+		t.Error("Trigger with a half-open circuit breaker isn't ready")
+	}
+	if got := ts.GetCondition(TriggerConditionCircuitBreaker); got == nil || got.Status != corev1.ConditionUnknown || got.Reason != "CircuitBreakerHalfOpen" {
+		t.Errorf("CircuitBreaker condition got=%v, want unknown with reason CircuitBreakerHalfOpen", got)
+	}
+
+	ts.MarkCircuitBreakerClosed()
+	if got := ts.GetCondition(TriggerConditionCircuitBreaker); got == nil || got.Status != corev1.ConditionTrue {
+		t.Errorf("CircuitBreaker condition got=%v, want true", got)
+	}
+}
+
+func TestTriggerSubscriberReachableCondition(t *testing.T) {
+	ts := &TriggerStatus{}
+	ts.InitializeConditions()
+	ts.PropagateBrokerStatus(TestHelper.ReadyBrokerStatus())
+	ts.MarkTopicReady()
+	ts.MarkSubscriptionReady()
+	ts.MarkSubscriberResolvedSucceeded()
+	ts.MarkDependencySucceeded()
+	ts.MarkFilterValid()
+
+	// The subscriber reachable condition doesn't affect the readiness of the Trigger.
+	ts.MarkSubscriberUnreachable(0, "connection refused")
+	if !ts.IsReady() {
+		t.Error("Trigger with an unreachable subscriber isn't ready")
+	}
+	want := "The last delivery to the subscriber failed: connection refused"
+	if got := ts.GetCondition(TriggerConditionSubscriberReachable); got == nil || got.Status != corev1.ConditionFalse || got.Severity != apis.ConditionSeverityInfo || got.Message != want {
+		t.Errorf("SubscriberReachable condition got=%v, want a false informational condition with message %q", got, want)
+	}
+
+	ts.MarkSubscriberUnreachable(503, "event delivery failed: HTTP status code 503")
+	want = "The last delivery to the subscriber failed with HTTP status code 503"
+	if got := ts.GetCondition(TriggerConditionSubscriberReachable); got == nil || got.Reason != "SubscriberUnavailable" || got.Message != want {
+		t.Errorf("SubscriberReachable condition got=%v, want reason SubscriberUnavailable with message %q", got, want)
+	}
+
+	ts.MarkSubscriberReachable()
+	if got := ts.GetCondition(TriggerConditionSubscriberReachable); got == nil || got.Status != corev1.ConditionTrue {
+		t.Errorf("SubscriberReachable condition got=%v, want true", got)
+	}
+}
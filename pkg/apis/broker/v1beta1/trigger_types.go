@@ -79,7 +79,8 @@ type TriggerStatus struct {
 	//TODO these fields don't work yet.
 	//TODO this requires updating the eventing webhook to allow unknown fields. Since the only unknown
 	// fields required are in status, maybe we can use a separate webhook just for broker and trigger
-	// status with allow unknown fields set.
+	// status with allow unknown fields set. Until then, the delivery status is recorded in the
+	// status annotations, see DeliveryStatus.
 
 	// ProjectID is the resolved project ID in use by the Trigger's PubSub resources.
 	// +optional
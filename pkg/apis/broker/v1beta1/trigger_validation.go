@@ -24,7 +24,16 @@ import (
 
 // Validate the Trigger.
 func (t *Trigger) Validate(ctx context.Context) *apis.FieldError {
-	// The Google Cloud Broker doesn't have any custom validations. The
+	// The Google Cloud Broker only validates its custom annotations. The
 	// eventing webhook will run the usual validations.
-	return nil
+	withNS := apis.AllowDifferentNamespace(apis.WithinParent(ctx, t.ObjectMeta))
+	return t.ValidateFilters().
+		Also(t.validateDeliveryAnnotation()).
+		Also(validateOrderingAnnotations(t.GetAnnotations())).
+		Also(validateReplayAnnotations(t.GetAnnotations())).
+		Also(validatePausedAnnotation(t.GetAnnotations())).
+		Also(t.validateReplyAnnotation(withNS)).
+		Also(t.validateSubscriberAuthAnnotation()).
+		Also(t.validateHeadersAnnotation()).
+		Also(t.validateExtensionsAnnotation())
 }
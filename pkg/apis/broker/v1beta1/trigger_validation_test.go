@@ -19,11 +19,259 @@ package v1beta1
 import (
 	"context"
 	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/google/knative-gcp/pkg/utils/celfilter"
 )
 
 func TestTrigger_Validate(t *testing.T) {
-	trig := Trigger{}
-	if err := trig.Validate(context.TODO()); err != nil {
-		t.Errorf("expected nil, got %v", err)
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     string
+	}{{
+		name: "no annotations",
+	}, {
+		name: "valid filters",
+		annotations: map[string]string{
+			FiltersAnnotation: `[{"prefix":{"type":"com.google.cloud.storage."}},{"any":[{"suffix":{"subject":".jpg"}},{"not":{"exact":{"subject":"a"}}}]}]`,
+		},
+	}, {
+		name: "filters not json",
+		annotations: map[string]string{
+			FiltersAnnotation: `prefix`,
+		},
+		wantErr: "invalid value: unmarshalling filters: invalid character 'p' looking for beginning of value: metadata.annotations[events.cloud.google.com/filters]",
+	}, {
+		name: "filter without dialect",
+		annotations: map[string]string{
+			FiltersAnnotation: `[{}]`,
+		},
+		wantErr: "expected exactly one, got neither: metadata.annotations[events.cloud.google.com/filters][0].all, metadata.annotations[events.cloud.google.com/filters][0].any, metadata.annotations[events.cloud.google.com/filters][0].exact, metadata.annotations[events.cloud.google.com/filters][0].not, metadata.annotations[events.cloud.google.com/filters][0].prefix, metadata.annotations[events.cloud.google.com/filters][0].suffix",
+	}, {
+		name: "filter with multiple dialects",
+		annotations: map[string]string{
+			FiltersAnnotation: `[{"exact":{"type":"a"},"prefix":{"type":"b"}}]`,
+		},
+		wantErr: "expected exactly one, got both: metadata.annotations[events.cloud.google.com/filters][0].exact, metadata.annotations[events.cloud.google.com/filters][0].prefix",
+	}, {
+		name: "nested filter with empty attribute name",
+		annotations: map[string]string{
+			FiltersAnnotation: `[{"all":[{"suffix":{"":"a"}}]}]`,
+		},
+		wantErr: "invalid key name \"\": metadata.annotations[events.cloud.google.com/filters][0].all[0].suffix\nattribute name must not be empty",
+	}, {
+		name: "valid CEL filter",
+		annotations: map[string]string{
+			CELFilterAnnotation: `ce.type == "foo" && data.bucket == "prod-uploads"`,
+		},
+	}, {
+		name: "invalid CEL filter",
+		annotations: map[string]string{
+			CELFilterAnnotation: `ce.type ==`,
+		},
+		wantErr: "invalid value: " + compileErr(t, `ce.type ==`) + ": metadata.annotations[events.cloud.google.com/celFilter]",
+	}, {
+		name: "valid delivery",
+		annotations: map[string]string{
+			DeliveryAnnotation: `{"retry":3,"backoffPolicy":"linear","backoffDelay":"PT2S","timeout":"PT30S"}`,
+		},
+	}, {
+		name: "delivery not json",
+		annotations: map[string]string{
+			DeliveryAnnotation: `retry`,
+		},
+		wantErr: "invalid value: unmarshalling delivery: invalid character 'r' looking for beginning of value: metadata.annotations[events.cloud.google.com/delivery]",
+	}, {
+		name: "invalid delivery",
+		annotations: map[string]string{
+			DeliveryAnnotation: `{"retry":-1,"backoffPolicy":"random","backoffDelay":"2s","timeout":"PT0S"}`,
+		},
+		wantErr: "invalid value: -1: metadata.annotations[events.cloud.google.com/delivery].retry\n" +
+			"invalid value: 2s: metadata.annotations[events.cloud.google.com/delivery].backoffDelay\n" +
+			"invalid value: PT0S: metadata.annotations[events.cloud.google.com/delivery].timeout\n" +
+			"invalid value: random: metadata.annotations[events.cloud.google.com/delivery].backoffPolicy",
+	}, {
+		name: "valid ordered delivery",
+		annotations: map[string]string{
+			OrderedDeliveryAnnotation:      "true",
+			OrderingKeyAttributeAnnotation: "orderid",
+		},
+	}, {
+		name: "invalid ordered delivery",
+		annotations: map[string]string{
+			OrderedDeliveryAnnotation:      "yes",
+			OrderingKeyAttributeAnnotation: "order-id",
+		},
+		wantErr: "invalid value: order-id: metadata.annotations[events.cloud.google.com/orderingKeyAttribute]\n" +
+			"invalid value: yes: metadata.annotations[events.cloud.google.com/orderedDelivery]",
+	}, {
+		name: "valid replay",
+		annotations: map[string]string{
+			ReplayRetentionAnnotation: "P1D",
+			ReplayFromAnnotation:      "2020-10-01T00:00:00Z",
+		},
+	}, {
+		name: "replay retention out of bounds",
+		annotations: map[string]string{
+			ReplayRetentionAnnotation: "P8D",
+		},
+		wantErr: "expected PT10M <= P8D <= P7D: metadata.annotations[events.cloud.google.com/replayRetention]",
+	}, {
+		name: "replay without retention",
+		annotations: map[string]string{
+			ReplayFromAnnotation: "yesterday",
+		},
+		wantErr: "events.cloud.google.com/replayFrom requires events.cloud.google.com/replayRetention: metadata.annotations[events.cloud.google.com/replayFrom]\n" +
+			"invalid value: yesterday: metadata.annotations[events.cloud.google.com/replayFrom]",
+	}, {
+		name: "valid paused",
+		annotations: map[string]string{
+			PausedAnnotation: "true",
+		},
+	}, {
+		name: "invalid paused",
+		annotations: map[string]string{
+			PausedAnnotation: "yes please",
+		},
+		wantErr: "invalid value: yes please: metadata.annotations[events.cloud.google.com/paused]",
+	}, {
+		name: "valid reply destination",
+		annotations: map[string]string{
+			ReplyAnnotation: `{"destination":{"ref":{"apiVersion":"eventing.knative.dev/v1beta1","kind":"Broker","name":"default","namespace":"other"}}}`,
+		},
+	}, {
+		name: "valid drop replies",
+		annotations: map[string]string{
+			ReplyAnnotation: `{"drop":true}`,
+		},
+	}, {
+		name: "reply not json",
+		annotations: map[string]string{
+			ReplyAnnotation: `drop`,
+		},
+		wantErr: "invalid value: drop: metadata.annotations[events.cloud.google.com/reply]",
+	}, {
+		name: "empty reply",
+		annotations: map[string]string{
+			ReplyAnnotation: `{}`,
+		},
+		wantErr: "expected exactly one, got neither: metadata.annotations[events.cloud.google.com/reply].destination, metadata.annotations[events.cloud.google.com/reply].drop",
+	}, {
+		name: "reply destination and drop",
+		annotations: map[string]string{
+			ReplyAnnotation: `{"destination":{"uri":"http://sink.ns.svc.cluster.local"},"drop":true}`,
+		},
+		wantErr: "expected exactly one, got both: metadata.annotations[events.cloud.google.com/reply].destination, metadata.annotations[events.cloud.google.com/reply].drop",
+	}, {
+		name: "invalid reply destination",
+		annotations: map[string]string{
+			ReplyAnnotation: `{"destination":{}}`,
+		},
+		wantErr: "expected at least one, got none: metadata.annotations[events.cloud.google.com/reply].destination.ref, metadata.annotations[events.cloud.google.com/reply].destination.uri",
+	}, {
+		name: "valid ID token subscriber auth",
+		annotations: map[string]string{
+			SubscriberAuthAnnotation: `{"mode":"IDToken","audience":"https://subscriber.a.run.app"}`,
+		},
+	}, {
+		name: "valid access token subscriber auth",
+		annotations: map[string]string{
+			SubscriberAuthAnnotation: `{"mode":"AccessToken"}`,
+		},
+	}, {
+		name: "subscriber auth not json",
+		annotations: map[string]string{
+			SubscriberAuthAnnotation: `IDToken`,
+		},
+		wantErr: "invalid value: IDToken: metadata.annotations[events.cloud.google.com/subscriberAuth]",
+	}, {
+		name: "subscriber auth without mode",
+		annotations: map[string]string{
+			SubscriberAuthAnnotation: `{}`,
+		},
+		wantErr: "missing field(s): metadata.annotations[events.cloud.google.com/subscriberAuth].mode",
+	}, {
+		name: "invalid subscriber auth mode",
+		annotations: map[string]string{
+			SubscriberAuthAnnotation: `{"mode":"Basic"}`,
+		},
+		wantErr: "invalid value: Basic: metadata.annotations[events.cloud.google.com/subscriberAuth].mode",
+	}, {
+		name: "access token subscriber auth with audience",
+		annotations: map[string]string{
+			SubscriberAuthAnnotation: `{"mode":"AccessToken","audience":"https://subscriber.a.run.app"}`,
+		},
+		wantErr: "must not set the field(s): metadata.annotations[events.cloud.google.com/subscriberAuth].audience",
+	}, {
+		name: "valid headers",
+		annotations: map[string]string{
+			HeadersAnnotation: `[{"name":"X-Route","value":"a"},{"name":"X-Api-Key","secretKeyRef":{"name":"gateway","key":"apiKey"}}]`,
+		},
+	}, {
+		name: "headers not json",
+		annotations: map[string]string{
+			HeadersAnnotation: `X-Route: a`,
+		},
+		wantErr: "invalid value: X-Route: a: metadata.annotations[events.cloud.google.com/headers]",
+	}, {
+		name: "invalid headers",
+		annotations: map[string]string{
+			HeadersAnnotation: `[{"value":"a"},{"name":"X Route","value":"a"},{"name":"Content-Type","value":"a"},{"name":"ce-id","value":"a"},{"name":"X-Api-Key","secretKeyRef":{}},{"name":"x-api-key"}]`,
+		},
+		wantErr: "duplicate header \"x-api-key\": metadata.annotations[events.cloud.google.com/headers][5].name\n" +
+			"expected exactly one, got neither: metadata.annotations[events.cloud.google.com/headers][5].secretKeyRef, metadata.annotations[events.cloud.google.com/headers][5].value\n" +
+			"invalid value: Content-Type: metadata.annotations[events.cloud.google.com/headers][2].name\n" +
+			"the header is reserved for the delivery of events\n" +
+			"invalid value: X Route: metadata.annotations[events.cloud.google.com/headers][1].name\n" +
+			"a valid HTTP header must consist of alphanumeric characters or '-' (e.g. 'X-Header-Name', regex used for validation is '[-A-Za-z0-9]+')\n" +
+			"invalid value: ce-id: metadata.annotations[events.cloud.google.com/headers][3].name\n" +
+			"the header is reserved for the delivery of events\n" +
+			"missing field(s): metadata.annotations[events.cloud.google.com/headers][0].name, metadata.annotations[events.cloud.google.com/headers][4].secretKeyRef.key, metadata.annotations[events.cloud.google.com/headers][4].secretKeyRef.name",
+	}, {
+		name: "valid extensions",
+		annotations: map[string]string{
+			ExtensionsAnnotation: `{"knativetrigger":"trigger"}`,
+		},
+	}, {
+		name: "extensions not json",
+		annotations: map[string]string{
+			ExtensionsAnnotation: `knativetrigger=trigger`,
+		},
+		wantErr: "invalid value: knativetrigger=trigger: metadata.annotations[events.cloud.google.com/extensions]",
+	}, {
+		name: "invalid extensions",
+		annotations: map[string]string{
+			ExtensionsAnnotation: `{"Trigger":"trigger","type":"a"}`,
+		},
+		wantErr: "invalid key name \"Trigger\": metadata.annotations[events.cloud.google.com/extensions]\n" +
+			"extension names must only contain lowercase letters and digits\n" +
+			"invalid key name \"type\": metadata.annotations[events.cloud.google.com/extensions]\n" +
+			"the attribute can't be set as an extension",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			err := trig.Validate(context.TODO())
+			if test.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected nil, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.wantErr {
+				t.Errorf("unexpected error, want %q, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func compileErr(t *testing.T, expr string) string {
+	t.Helper()
+	_, err := celfilter.Compile(expr)
+	if err == nil {
+		t.Fatalf("expected expression %q to fail to compile", expr)
 	}
+	return err.Error()
 }
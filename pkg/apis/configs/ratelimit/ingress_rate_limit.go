@@ -0,0 +1,70 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// configName is the name of config map for the rate limits of the Broker ingress.
+	configName = "config-ingress-rate-limit"
+
+	// limitsKey is the key in the ConfigMap to get the rate limits of the Broker ingress.
+	limitsKey = "ingress-rate-limit-config"
+)
+
+// ConfigMapName returns the name of the configmap to read for the rate limits of the Broker ingress.
+func ConfigMapName() string {
+	return configName
+}
+
+// NewLimitsConfigFromConfigMap creates a Limits from the supplied configMap.
+func NewLimitsConfigFromConfigMap(config *corev1.ConfigMap) (*Limits, error) {
+	return NewLimitsConfigFromMap(config.Data)
+}
+
+// NewLimitsConfigFromMap creates a Limits from the supplied Map. A missing or empty key
+// means that nothing is rate limited.
+func NewLimitsConfigFromMap(data map[string]string) (*Limits, error) {
+	nc := &Limits{}
+
+	// Parse out the rate limit configuration.
+	value, present := data[limitsKey]
+	if !present || value == "" {
+		return nc, nil
+	}
+	if err := parseEntry(value, nc); err != nil {
+		return nil, fmt.Errorf("failed to parse the entry: %s", err)
+	}
+	if err := nc.validate(); err != nil {
+		return nil, err
+	}
+	return nc, nil
+}
+
+func parseEntry(entry string, out interface{}) error {
+	j, err := yaml.YAMLToJSON([]byte(entry))
+	if err != nil {
+		return fmt.Errorf("ConfigMap's value could not be converted to JSON: %s : %v", err, entry)
+	}
+	return json.Unmarshal(j, &out)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "knative.dev/pkg/configmap/testing"
+	_ "knative.dev/pkg/system/testing"
+)
+
+func TestLimitsConfigurationFromFile(t *testing.T) {
+	_, example := ConfigMapsFromTestFile(t, configName, limitsKey)
+	if _, err := NewLimitsConfigFromConfigMap(example); err != nil {
+		t.Errorf("NewLimitsConfigFromConfigMap(example) = %v", err)
+	}
+}
+
+func TestNewLimitsConfigFromConfigMap(t *testing.T) {
+	_, example := ConfigMapsFromTestFile(t, configName, limitsKey)
+	limits, err := NewLimitsConfigFromConfigMap(example)
+	if err != nil {
+		t.Fatalf("NewLimitsConfigFromConfigMap(example) = %v", err)
+	}
+
+	testCases := []struct {
+		name           string
+		namespace      string
+		broker         string
+		brokerLimit    *ScopedLimits
+		namespaceLimit *ScopedLimits
+		burst          int
+	}{
+		{
+			name:           "cluster limits",
+			namespace:      "cluster",
+			broker:         "default",
+			namespaceLimit: &ScopedLimits{EventsPerSecond: 1000, Burst: 2000},
+		},
+		{
+			name:           "namespace limits",
+			namespace:      "noisy-ns",
+			broker:         "other",
+			namespaceLimit: &ScopedLimits{EventsPerSecond: 10},
+		},
+		{
+			name:           "broker limits",
+			namespace:      "noisy-ns",
+			broker:         "default",
+			brokerLimit:    &ScopedLimits{EventsPerSecond: 5, Burst: 20},
+			namespaceLimit: &ScopedLimits{EventsPerSecond: 10},
+		},
+		{
+			name:      "unlimited namespace",
+			namespace: "trusted-ns",
+			broker:    "default",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.brokerLimit, limits.BrokerLimit(tc.namespace, tc.broker)); diff != "" {
+				t.Errorf("Unexpected broker limit (-want +got): %s", diff)
+			}
+			if diff := cmp.Diff(tc.namespaceLimit, limits.NamespaceLimit(tc.namespace)); diff != "" {
+				t.Errorf("Unexpected namespace limit (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestNewLimitsConfigFromEmptyConfigMap(t *testing.T) {
+	limits, err := NewLimitsConfigFromConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "cloud-run-events",
+			Name:      configName,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewLimitsConfigFromConfigMap() = %v", err)
+	}
+	if got := limits.BrokerLimit("ns", "default"); got != nil {
+		t.Errorf("Unexpected broker limit: %v", got)
+	}
+	if got := limits.NamespaceLimit("ns"); got != nil {
+		t.Errorf("Unexpected namespace limit: %v", got)
+	}
+}
+
+func TestNewLimitsConfigFromConfigMapWithError(t *testing.T) {
+	testCases := map[string]string{
+		"wrong format": `
+  clusterLimits:
+    eventsPerSecond: many`,
+		"negative rate": `
+  clusterLimits:
+    eventsPerSecond: -1`,
+		"negative burst": `
+  namespaceLimits:
+    ns:
+      eventsPerSecond: 1
+      burst: -1`,
+		"invalid broker": `
+  brokerLimits:
+    default:
+      eventsPerSecond: 1`,
+	}
+	for n, value := range testCases {
+		t.Run(n, func(t *testing.T) {
+			_, err := NewLimitsConfigFromConfigMap(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "cloud-run-events",
+					Name:      configName,
+				},
+				Data: map[string]string{
+					limitsKey: value,
+				},
+			})
+			if err == nil {
+				t.Fatalf("Expected an error, actually nil")
+			}
+		})
+	}
+}
+
+func TestBurstOrDefault(t *testing.T) {
+	testCases := []struct {
+		name   string
+		limits ScopedLimits
+		want   int
+	}{
+		{
+			name:   "burst",
+			limits: ScopedLimits{EventsPerSecond: 10, Burst: 20},
+			want:   20,
+		},
+		{
+			name:   "rate rounded up",
+			limits: ScopedLimits{EventsPerSecond: 2.5},
+			want:   3,
+		},
+		{
+			name:   "at least one event",
+			limits: ScopedLimits{EventsPerSecond: 0.1},
+			want:   1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.limits.BurstOrDefault(); got != tc.want {
+				t.Errorf("BurstOrDefault() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
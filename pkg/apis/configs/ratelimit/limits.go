@@ -0,0 +1,108 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Limits includes the token bucket rate limits enforced by the Broker ingress.
+type Limits struct {
+	// BrokerLimits are the rate limits of specific Brokers. The key is the namespace and the name
+	// of the Broker separated by a slash, e.g. "my-ns/default".
+	BrokerLimits map[string]ScopedLimits `json:"brokerLimits,omitempty"`
+	// NamespaceLimits are the rate limits shared by all the Brokers of specific namespaces. The
+	// namespace is the key.
+	NamespaceLimits map[string]ScopedLimits `json:"namespaceLimits,omitempty"`
+	// ClusterLimits are the rate limits shared by all the Brokers of each namespace that is not in
+	// NamespaceLimits.
+	ClusterLimits ScopedLimits `json:"clusterLimits,omitempty"`
+}
+
+// ScopedLimits is a token bucket rate limit.
+type ScopedLimits struct {
+	// EventsPerSecond is the rate at which the bucket is refilled. Zero means unlimited.
+	EventsPerSecond float64 `json:"eventsPerSecond,omitempty"`
+	// Burst is the size of the bucket, i.e. the number of events accepted at once. It
+	// defaults to EventsPerSecond rounded up.
+	Burst int `json:"burst,omitempty"`
+}
+
+// BrokerLimit returns the rate limit of a Broker, or nil if the Broker isn't limited on its own.
+func (l *Limits) BrokerLimit(namespace, name string) *ScopedLimits {
+	if sl, present := l.BrokerLimits[namespace+"/"+name]; present {
+		return sl.limited()
+	}
+	return nil
+}
+
+// NamespaceLimit returns the rate limit shared by all the Brokers of a namespace, or nil if the
+// namespace isn't limited.
+func (l *Limits) NamespaceLimit(namespace string) *ScopedLimits {
+	if sl, present := l.NamespaceLimits[namespace]; present {
+		return sl.limited()
+	}
+	return l.ClusterLimits.limited()
+}
+
+// limited returns the limits, or nil if they are unlimited.
+func (sl ScopedLimits) limited() *ScopedLimits {
+	if sl.EventsPerSecond <= 0 {
+		return nil
+	}
+	return &sl
+}
+
+// BurstOrDefault returns the size of the bucket, which is at least one event.
+func (sl *ScopedLimits) BurstOrDefault() int {
+	if sl.Burst > 0 {
+		return sl.Burst
+	}
+	return int(math.Max(1, math.Ceil(sl.EventsPerSecond)))
+}
+
+func (l *Limits) validate() error {
+	for key, sl := range l.BrokerLimits {
+		if parts := strings.Split(key, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid broker %q, expected <namespace>/<name>", key)
+		}
+		if err := sl.validate(); err != nil {
+			return fmt.Errorf("invalid limits of broker %q: %w", key, err)
+		}
+	}
+	for ns, sl := range l.NamespaceLimits {
+		if err := sl.validate(); err != nil {
+			return fmt.Errorf("invalid limits of namespace %q: %w", ns, err)
+		}
+	}
+	if err := l.ClusterLimits.validate(); err != nil {
+		return fmt.Errorf("invalid cluster limits: %w", err)
+	}
+	return nil
+}
+
+func (sl ScopedLimits) validate() error {
+	if sl.EventsPerSecond < 0 {
+		return fmt.Errorf("eventsPerSecond must not be negative: %v", sl.EventsPerSecond)
+	}
+	if sl.Burst < 0 {
+		return fmt.Errorf("burst must not be negative: %v", sl.Burst)
+	}
+	return nil
+}
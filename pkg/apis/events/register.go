@@ -22,6 +22,9 @@ import "k8s.io/apimachinery/pkg/runtime/schema"
 const (
 	GroupName       = "events.cloud.google.com"
 	CloudBuildTopic = "cloud-builds"
+	// ContainerRegistryTopic is the topic to which Container Registry and
+	// Artifact Registry publish the notifications of their images.
+	ContainerRegistryTopic = "gcr"
 )
 
 var (
@@ -50,4 +53,29 @@ var (
 		Group:    GroupName,
 		Resource: "cloudbuildsources",
 	}
+	// CloudFirestoreSourcesResource represents a CloudFirestoreSource.
+	CloudFirestoreSourcesResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "cloudfirestoresources",
+	}
+	// CloudContainerRegistrySourcesResource represents a CloudContainerRegistrySource.
+	CloudContainerRegistrySourcesResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "cloudcontainerregistrysources",
+	}
+	// CloudMonitoringAlertSourcesResource represents a CloudMonitoringAlertSource.
+	CloudMonitoringAlertSourcesResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "cloudmonitoringalertsources",
+	}
+	// CloudBillingBudgetSourcesResource represents a CloudBillingBudgetSource.
+	CloudBillingBudgetSourcesResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "cloudbillingbudgetsources",
+	}
+	// CloudSecretManagerSourcesResource represents a CloudSecretManagerSource.
+	CloudSecretManagerSourcesResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "cloudsecretmanagersources",
+	}
 )
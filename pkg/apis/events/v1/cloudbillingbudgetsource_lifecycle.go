@@ -0,0 +1,59 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"knative.dev/pkg/apis"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (s *CloudBillingBudgetSourceStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return billingBudgetSourceCondSet.Manage(s).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level condition.
+func (s *CloudBillingBudgetSourceStatus) GetTopLevelCondition() *apis.Condition {
+	return billingBudgetSourceCondSet.Manage(s).GetTopLevelCondition()
+}
+
+// IsReady returns true if the resource is ready overall.
+func (s *CloudBillingBudgetSourceStatus) IsReady() bool {
+	return billingBudgetSourceCondSet.Manage(s).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (s *CloudBillingBudgetSourceStatus) InitializeConditions() {
+	billingBudgetSourceCondSet.Manage(s).InitializeConditions()
+}
+
+// MarkBudgetNotReady sets the condition that the budget has not been
+// successfully configured to publish its notifications.
+func (s *CloudBillingBudgetSourceStatus) MarkBudgetNotReady(reason, messageFormat string, messageA ...interface{}) {
+	billingBudgetSourceCondSet.Manage(s).MarkFalse(BudgetReady, reason, messageFormat, messageA...)
+}
+
+// MarkBudgetUnknown sets the condition that the status of the budget
+// notifications is unknown.
+func (s *CloudBillingBudgetSourceStatus) MarkBudgetUnknown(reason, messageFormat string, messageA ...interface{}) {
+	billingBudgetSourceCondSet.Manage(s).MarkUnknown(BudgetReady, reason, messageFormat, messageA...)
+}
+
+// MarkBudgetReady sets the condition that the budget publishes its
+// notifications to the topic.
+func (s *CloudBillingBudgetSourceStatus) MarkBudgetReady() {
+	billingBudgetSourceCondSet.Manage(s).MarkTrue(BudgetReady)
+}
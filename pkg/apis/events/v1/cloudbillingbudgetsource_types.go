@@ -0,0 +1,142 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	kngcpduck "github.com/google/knative-gcp/pkg/duck/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/webhook/resourcesemantics"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudBillingBudgetSource is a specification for a Cloud Billing budget
+// notification event source.
+type CloudBillingBudgetSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudBillingBudgetSourceSpec   `json:"spec"`
+	Status CloudBillingBudgetSourceStatus `json:"status"`
+}
+
+// Verify that CloudBillingBudgetSource matches various duck types.
+var (
+	_ apis.Defaultable             = (*CloudBillingBudgetSource)(nil)
+	_ apis.Validatable             = (*CloudBillingBudgetSource)(nil)
+	_ runtime.Object               = (*CloudBillingBudgetSource)(nil)
+	_ kmeta.OwnerRefable           = (*CloudBillingBudgetSource)(nil)
+	_ resourcesemantics.GenericCRD = (*CloudBillingBudgetSource)(nil)
+	_ kngcpduck.Identifiable       = (*CloudBillingBudgetSource)(nil)
+	_ kngcpduck.PubSubable         = (*CloudBillingBudgetSource)(nil)
+	_ duckv1.KRShaped              = (*CloudBillingBudgetSource)(nil)
+)
+
+// CloudBillingBudgetSourceSpec defines the desired state of the CloudBillingBudgetSource.
+type CloudBillingBudgetSourceSpec struct {
+	// This brings in the PubSub based Source Specs. Includes:
+	// Sink, CloudEventOverrides, Secret and Project.
+	gcpduckv1.PubSubSpec `json:",inline"`
+
+	// BillingAccount is the ID of the billing account owning the budget,
+	// e.g. 012345-6789AB-CDEF01.
+	BillingAccount string `json:"billingAccount"`
+
+	// Budget is the ID of the budget whose notifications are sent.
+	Budget string `json:"budget"`
+}
+
+const (
+	// BudgetReady has status True when the budget has been successfully
+	// configured to publish its notifications to the topic.
+	BudgetReady apis.ConditionType = "BudgetReady"
+)
+
+var billingBudgetSourceCondSet = apis.NewLivingConditionSet(
+	gcpduckv1.PullSubscriptionReady,
+	gcpduckv1.TopicReady,
+	BudgetReady,
+)
+
+// CloudBillingBudgetSourceStatus defines the observed state of CloudBillingBudgetSource.
+type CloudBillingBudgetSourceStatus struct {
+	// This brings in our GCP PubSub based events importers
+	// duck/v1 Status, SinkURI, ProjectID, TopicID and SubscriptionID
+	gcpduckv1.PubSubStatus `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudBillingBudgetSourceList contains a list of CloudBillingBudgetSources.
+type CloudBillingBudgetSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CloudBillingBudgetSource `json:"items"`
+}
+
+// GetGroupVersionKind returns the GroupVersionKind.
+func (*CloudBillingBudgetSource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("CloudBillingBudgetSource")
+}
+
+// Methods for identifiable interface.
+// IdentitySpec returns the IdentitySpec portion of the Spec.
+func (s *CloudBillingBudgetSource) IdentitySpec() *gcpduckv1.IdentitySpec {
+	return &s.Spec.IdentitySpec
+}
+
+// IdentityStatus returns the IdentityStatus portion of the Status.
+func (s *CloudBillingBudgetSource) IdentityStatus() *gcpduckv1.IdentityStatus {
+	return &s.Status.IdentityStatus
+}
+
+// Methods for pubsubable interface.
+
+// PubSubSpec returns the PubSubSpec portion of the Spec.
+func (s *CloudBillingBudgetSource) PubSubSpec() *gcpduckv1.PubSubSpec {
+	return &s.Spec.PubSubSpec
+}
+
+// PubSubStatus returns the PubSubStatus portion of the Status.
+func (s *CloudBillingBudgetSource) PubSubStatus() *gcpduckv1.PubSubStatus {
+	return &s.Status.PubSubStatus
+}
+
+// ConditionSet returns the apis.ConditionSet of the embedding object.
+func (s *CloudBillingBudgetSource) ConditionSet() *apis.ConditionSet {
+	return &billingBudgetSourceCondSet
+}
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*CloudBillingBudgetSource) GetConditionSet() apis.ConditionSet {
+	return billingBudgetSourceCondSet
+}
+
+// GetStatus retrieves the status of the CloudBillingBudgetSource. Implements the KRShaped interface.
+func (s *CloudBillingBudgetSource) GetStatus() *duckv1.Status {
+	return &s.Status.Status
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	kngcpduck "github.com/google/knative-gcp/pkg/duck/v1"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/webhook/resourcesemantics"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudContainerRegistrySource is a specification for a Container Registry
+// and Artifact Registry image event source.
+type CloudContainerRegistrySource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudContainerRegistrySourceSpec   `json:"spec"`
+	Status CloudContainerRegistrySourceStatus `json:"status"`
+}
+
+// Verify that CloudContainerRegistrySource matches various duck types.
+var (
+	_ apis.Defaultable             = (*CloudContainerRegistrySource)(nil)
+	_ apis.Validatable             = (*CloudContainerRegistrySource)(nil)
+	_ runtime.Object               = (*CloudContainerRegistrySource)(nil)
+	_ kmeta.OwnerRefable           = (*CloudContainerRegistrySource)(nil)
+	_ resourcesemantics.GenericCRD = (*CloudContainerRegistrySource)(nil)
+	_ kngcpduck.Identifiable       = (*CloudContainerRegistrySource)(nil)
+	_ kngcpduck.PubSubable         = (*CloudContainerRegistrySource)(nil)
+	_ kngcpduck.EventFilterable    = (*CloudContainerRegistrySource)(nil)
+	_ duckv1.KRShaped              = (*CloudContainerRegistrySource)(nil)
+)
+
+var containerRegistrySourceCondSet = apis.NewLivingConditionSet(
+	gcpduckv1.PullSubscriptionReady,
+)
+
+// CloudContainerRegistrySourceSpec defines the desired state of the CloudContainerRegistrySource.
+type CloudContainerRegistrySourceSpec struct {
+	// This brings in the PubSub based Source Specs. Includes:
+	// Sink, CloudEventOverrides, Secret and Project.
+	gcpduckv1.PubSubSpec `json:",inline"`
+
+	// Repository restricts the events to the images of a repository, e.g.
+	// gcr.io/my-project/hello-world or
+	// us-docker.pkg.dev/my-project/my-repo/hello-world.
+	// +optional
+	Repository string `json:"repository,omitempty"`
+
+	// Tag restricts the events to the images with a tag, e.g. latest.
+	// +optional
+	Tag string `json:"tag,omitempty"`
+}
+
+// CloudContainerRegistrySourceStatus defines the observed state of CloudContainerRegistrySource.
+type CloudContainerRegistrySourceStatus struct {
+	gcpduckv1.PubSubStatus `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudContainerRegistrySourceList contains a list of CloudContainerRegistrySources.
+type CloudContainerRegistrySourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CloudContainerRegistrySource `json:"items"`
+}
+
+// GetGroupVersionKind returns the GroupVersionKind.
+func (*CloudContainerRegistrySource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("CloudContainerRegistrySource")
+}
+
+// Methods for identifiable interface.
+// IdentitySpec returns the IdentitySpec portion of the Spec.
+func (s *CloudContainerRegistrySource) IdentitySpec() *gcpduckv1.IdentitySpec {
+	return &s.Spec.IdentitySpec
+}
+
+// IdentityStatus returns the IdentityStatus portion of the Status.
+func (s *CloudContainerRegistrySource) IdentityStatus() *gcpduckv1.IdentityStatus {
+	return &s.Status.IdentityStatus
+}
+
+// Methods for pubsubable interface.
+
+// PubSubSpec returns the PubSubSpec portion of the Spec.
+func (s *CloudContainerRegistrySource) PubSubSpec() *gcpduckv1.PubSubSpec {
+	return &s.Spec.PubSubSpec
+}
+
+// PubSubStatus returns the PubSubStatus portion of the Status.
+func (s *CloudContainerRegistrySource) PubSubStatus() *gcpduckv1.PubSubStatus {
+	return &s.Status.PubSubStatus
+}
+
+// ConditionSet returns the apis.ConditionSet of the embedding object.
+func (s *CloudContainerRegistrySource) ConditionSet() *apis.ConditionSet {
+	return &containerRegistrySourceCondSet
+}
+
+// EventFilter returns the repository and tag extensions that the events
+// must have. Implements the EventFilterable interface.
+func (s *CloudContainerRegistrySource) EventFilter() map[string]string {
+	filter := make(map[string]string)
+	if s.Spec.Repository != "" {
+		filter[schemasv1.CloudContainerRegistryRepositoryExtension] = s.Spec.Repository
+	}
+	if s.Spec.Tag != "" {
+		filter[schemasv1.CloudContainerRegistryTagExtension] = s.Spec.Tag
+	}
+	if len(filter) == 0 {
+		return nil
+	}
+	return filter
+}
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*CloudContainerRegistrySource) GetConditionSet() apis.ConditionSet {
+	return containerRegistrySourceCondSet
+}
+
+// GetStatus retrieves the status of the CloudContainerRegistrySource. Implements the KRShaped interface.
+func (s *CloudContainerRegistrySource) GetStatus() *duckv1.Status {
+	return &s.Status.Status
+}
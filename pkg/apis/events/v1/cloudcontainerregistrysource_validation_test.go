@@ -0,0 +1,547 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	metadatatesting "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+var (
+	containerRegistrySourceSpec = CloudContainerRegistrySourceSpec{
+		PubSubSpec: gcpduckv1.PubSubSpec{
+			Secret: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "secret-name",
+				},
+				Key: "secret-key",
+			},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "foo",
+						Kind:       "bar",
+						Namespace:  "baz",
+						Name:       "qux",
+					},
+				},
+			},
+			Project: "my-eventing-project",
+		},
+	}
+
+	containerRegistrySourceSpecWithKSA = CloudContainerRegistrySourceSpec{
+		PubSubSpec: gcpduckv1.PubSubSpec{
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "foo",
+						Kind:       "bar",
+						Namespace:  "baz",
+						Name:       "qux",
+					},
+				},
+			},
+			IdentitySpec: gcpduckv1.IdentitySpec{
+				ServiceAccountName: "old-service-account",
+			},
+			Project: "my-eventing-project",
+		},
+	}
+)
+
+func TestCloudContainerRegistrySourceCheckValidationFields(t *testing.T) {
+	testCases := map[string]struct {
+		spec  CloudContainerRegistrySourceSpec
+		error bool
+	}{
+		"ok": {
+			spec:  containerRegistrySourceSpec,
+			error: false,
+		},
+		"bad sink, name": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.Sink.Ref.Name = ""
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, apiVersion": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.Sink.Ref.APIVersion = ""
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, kind": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.Sink.Ref.Kind = ""
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, empty": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.Sink = duckv1.Destination{}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, uri scheme": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.Sink = duckv1.Destination{
+					URI: &apis.URL{
+						Host: "example.com",
+					},
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, uri host": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.Sink = duckv1.Destination{
+					URI: &apis.URL{
+						Scheme: "http",
+					},
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, uri and ref": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.Sink = duckv1.Destination{
+					URI: &apis.URL{
+						Scheme: "http",
+						Host:   "example.com",
+					},
+					Ref: &duckv1.KReference{
+						Name: "foo",
+					},
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"ok, repository and tag": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.Repository = "us-docker.pkg.dev/my-project/my-repo/hello-world"
+				obj.Tag = "v1.0.2"
+				return *obj
+			}(),
+			error: false,
+		},
+		"bad repository, no path": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.Repository = "gcr.io"
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad repository, empty segment": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.Repository = "gcr.io//hello-world"
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad repository, with tag": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.Repository = "gcr.io/my-project/hello-world:latest"
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad repository, with digest": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.Repository = "gcr.io/my-project/hello-world@sha256:6ec128e26cd5"
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad tag": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.Tag = ".latest"
+				return *obj
+			}(),
+			error: true,
+		},
+		"invalid secret, missing key": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.Secret = &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "name",
+					},
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"nil service account": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				return *obj
+			}(),
+			error: false,
+		},
+		"invalid k8s service account": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.ServiceAccountName = invalidServiceAccountName
+				return *obj
+			}(),
+			error: true,
+		},
+		"have k8s service account and secret at the same time": {
+			spec: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.ServiceAccountName = validServiceAccountName
+				obj.Secret = &gcpauthtesthelper.Secret
+				return *obj
+			}(),
+			error: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			err := tc.spec.Validate(context.TODO())
+			if tc.error != (err != nil) {
+				t.Fatalf("Unexpected validation failure. Got %v", err)
+			}
+		})
+	}
+}
+
+func TestCloudContainerRegistrySourceCheckImmutableFields(t *testing.T) {
+	testCases := map[string]struct {
+		orig              interface{}
+		updated           CloudContainerRegistrySourceSpec
+		origAnnotation    map[string]string
+		updatedAnnotation map[string]string
+		allowed           bool
+	}{
+		"nil orig": {
+			updated: containerRegistrySourceSpec,
+			allowed: true,
+		},
+		"ClusterName annotation changed": {
+			origAnnotation: map[string]string{
+				duck.ClusterNameAnnotation: metadatatesting.FakeClusterName + "old",
+			},
+			updatedAnnotation: map[string]string{
+				duck.ClusterNameAnnotation: metadatatesting.FakeClusterName + "new",
+			},
+			allowed: false,
+		},
+		"AnnotationClass annotation changed": {
+			origAnnotation: map[string]string{
+				duck.AutoscalingClassAnnotation: duck.KEDA,
+			},
+			updatedAnnotation: map[string]string{
+				duck.AutoscalingClassAnnotation: duck.KEDA + "new",
+			},
+			allowed: false,
+		},
+		"AnnotationClass annotation added": {
+			origAnnotation: map[string]string{},
+			updatedAnnotation: map[string]string{
+				duck.AutoscalingClassAnnotation: duck.KEDA,
+			},
+			allowed: false,
+		},
+		"AnnotationClass annotation deleted": {
+			origAnnotation: map[string]string{
+				duck.AutoscalingClassAnnotation: duck.KEDA,
+			},
+			updatedAnnotation: map[string]string{},
+			allowed:           false,
+		},
+		"Secret.Name changed": {
+			orig: &containerRegistrySourceSpec,
+			updated: CloudContainerRegistrySourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "some-other-name",
+						},
+						Key: containerRegistrySourceSpec.Secret.Key,
+					},
+					Project: containerRegistrySourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: containerRegistrySourceSpec.Sink,
+					},
+				},
+			},
+			allowed: false,
+		},
+		"Secret.Key changed": {
+			orig: &containerRegistrySourceSpec,
+			updated: CloudContainerRegistrySourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: containerRegistrySourceSpec.Secret.Name,
+						},
+						Key: "some-other-key",
+					},
+					Project: containerRegistrySourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: containerRegistrySourceSpec.Sink,
+					},
+				},
+			},
+			allowed: false,
+		},
+		"Project changed": {
+			orig: &containerRegistrySourceSpec,
+			updated: CloudContainerRegistrySourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: containerRegistrySourceSpec.Secret.Name,
+						},
+						Key: containerRegistrySourceSpec.Secret.Key,
+					},
+					Project: "some-other-project",
+					SourceSpec: duckv1.SourceSpec{
+						Sink: containerRegistrySourceSpec.Sink,
+					},
+				},
+			},
+			allowed: false,
+		},
+		"ServiceAccountName changed": {
+			orig: &containerRegistrySourceSpecWithKSA,
+			updated: CloudContainerRegistrySourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					IdentitySpec: gcpduckv1.IdentitySpec{
+						ServiceAccountName: "new-service-account",
+					},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: containerRegistrySourceSpecWithKSA.Sink,
+					},
+					Project: containerRegistrySourceSpecWithKSA.Project,
+				},
+			},
+			allowed: false,
+		},
+		"ServiceAccountName added": {
+			orig: &containerRegistrySourceSpec,
+			updated: CloudContainerRegistrySourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: containerRegistrySourceSpec.Secret.Name,
+						},
+						Key: containerRegistrySourceSpec.Secret.Key,
+					},
+					Project: containerRegistrySourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: containerRegistrySourceSpec.Sink,
+					},
+					IdentitySpec: gcpduckv1.IdentitySpec{
+						ServiceAccountName: "old-service-account",
+					},
+				},
+			},
+			allowed: false,
+		},
+		"ClusterName annotation added": {
+			origAnnotation: nil,
+			updatedAnnotation: map[string]string{
+				duck.ClusterNameAnnotation: metadatatesting.FakeClusterName + "new",
+			},
+			allowed: true,
+		},
+		"Sink.APIVersion changed": {
+			orig: &containerRegistrySourceSpec,
+			updated: CloudContainerRegistrySourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: containerRegistrySourceSpec.Secret.Name,
+						},
+						Key: containerRegistrySourceSpec.Secret.Key,
+					},
+					Project: containerRegistrySourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "some-other-api-version",
+								Kind:       containerRegistrySourceSpec.Sink.Ref.Kind,
+								Namespace:  containerRegistrySourceSpec.Sink.Ref.Namespace,
+								Name:       containerRegistrySourceSpec.Sink.Ref.Name,
+							},
+						},
+					},
+				},
+			},
+			allowed: true,
+		},
+		"Sink.Kind changed": {
+			orig: &containerRegistrySourceSpec,
+			updated: CloudContainerRegistrySourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: containerRegistrySourceSpec.Secret.Name,
+						},
+						Key: containerRegistrySourceSpec.Secret.Key,
+					},
+					Project: containerRegistrySourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: containerRegistrySourceSpec.Sink.Ref.APIVersion,
+								Kind:       "some-other-kind",
+								Namespace:  containerRegistrySourceSpec.Sink.Ref.Namespace,
+								Name:       containerRegistrySourceSpec.Sink.Ref.Name,
+							},
+						},
+					},
+				},
+			},
+			allowed: true,
+		},
+		"Sink.Namespace changed": {
+			orig: &containerRegistrySourceSpec,
+			updated: CloudContainerRegistrySourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: containerRegistrySourceSpec.Secret.Name,
+						},
+						Key: containerRegistrySourceSpec.Secret.Key,
+					},
+					Project: containerRegistrySourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: containerRegistrySourceSpec.Sink.Ref.APIVersion,
+								Kind:       containerRegistrySourceSpec.Sink.Ref.Kind,
+								Namespace:  "some-other-namespace",
+								Name:       containerRegistrySourceSpec.Sink.Ref.Name,
+							},
+						},
+					},
+				},
+			},
+			allowed: true,
+		},
+		"Sink.Name changed": {
+			orig: &containerRegistrySourceSpec,
+			updated: CloudContainerRegistrySourceSpec{
+				PubSubSpec: gcpduckv1.PubSubSpec{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: containerRegistrySourceSpec.Secret.Name,
+						},
+						Key: containerRegistrySourceSpec.Secret.Key,
+					},
+					Project: containerRegistrySourceSpec.Project,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: containerRegistrySourceSpec.Sink.Ref.APIVersion,
+								Kind:       containerRegistrySourceSpec.Sink.Ref.Kind,
+								Namespace:  containerRegistrySourceSpec.Sink.Ref.Namespace,
+								Name:       "some-other-name",
+							},
+						},
+					},
+				},
+			},
+			allowed: true,
+		},
+		"Repository and Tag changed": {
+			orig: &containerRegistrySourceSpec,
+			updated: func() CloudContainerRegistrySourceSpec {
+				obj := containerRegistrySourceSpec.DeepCopy()
+				obj.Repository = "gcr.io/my-project/hello-world"
+				obj.Tag = "latest"
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"no change": {
+			orig:    &containerRegistrySourceSpec,
+			updated: containerRegistrySourceSpec,
+			allowed: true,
+		},
+		"no spec": {
+			orig:    []string{"wrong"},
+			updated: containerRegistrySourceSpec,
+			allowed: true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var orig *CloudContainerRegistrySource
+
+			if tc.origAnnotation != nil {
+				orig = &CloudContainerRegistrySource{
+					ObjectMeta: v1.ObjectMeta{
+						Annotations: tc.origAnnotation,
+					},
+				}
+			} else if tc.orig != nil {
+				if spec, ok := tc.orig.(*CloudContainerRegistrySourceSpec); ok {
+					orig = &CloudContainerRegistrySource{
+						Spec: *spec,
+					}
+				}
+			}
+			updated := &CloudContainerRegistrySource{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: tc.updatedAnnotation,
+				},
+				Spec: tc.updated,
+			}
+			err := updated.CheckImmutableFields(context.TODO(), orig)
+			if tc.allowed != (err == nil) {
+				t.Fatalf("Unexpected immutable field check. Expected %v. Actual %v", tc.allowed, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"strings"
+
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	kngcpduck "github.com/google/knative-gcp/pkg/duck/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/webhook/resourcesemantics"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudFirestoreSource is a specification for a Cloud Firestore event source.
+type CloudFirestoreSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudFirestoreSourceSpec   `json:"spec"`
+	Status CloudFirestoreSourceStatus `json:"status"`
+}
+
+// Verify that CloudFirestoreSource matches various duck types.
+var (
+	_ apis.Defaultable                = (*CloudFirestoreSource)(nil)
+	_ apis.Validatable                = (*CloudFirestoreSource)(nil)
+	_ runtime.Object                  = (*CloudFirestoreSource)(nil)
+	_ kmeta.OwnerRefable              = (*CloudFirestoreSource)(nil)
+	_ resourcesemantics.GenericCRD    = (*CloudFirestoreSource)(nil)
+	_ kngcpduck.Identifiable          = (*CloudFirestoreSource)(nil)
+	_ kngcpduck.PubSubable            = (*CloudFirestoreSource)(nil)
+	_ kngcpduck.ConverterConfigurable = (*CloudFirestoreSource)(nil)
+	_ duckv1.KRShaped                 = (*CloudFirestoreSource)(nil)
+)
+
+const (
+	// CloudFirestoreSourceDatabase is the converter argument holding the database of the source.
+	CloudFirestoreSourceDatabase = "database"
+	// CloudFirestoreSourceCollection is the converter argument holding the collection of the source.
+	CloudFirestoreSourceCollection = "collection"
+	// CloudFirestoreSourceEventTypes is the converter argument holding the
+	// comma-separated event types of the source.
+	CloudFirestoreSourceEventTypes = "eventTypes"
+)
+
+var firestoreSourceCondSet = apis.NewLivingConditionSet(
+	gcpduckv1.PullSubscriptionReady,
+	gcpduckv1.TopicReady,
+	SinkReady,
+)
+
+// CloudFirestoreSourceSpec defines the desired state of the CloudFirestoreSource.
+// The changes of the documents are read from the Data Access audit logs of
+// Cloud Firestore, which must be enabled for the project.
+type CloudFirestoreSourceSpec struct {
+	// This brings in the PubSub based Source Specs. Includes:
+	gcpduckv1.PubSubSpec `json:",inline"`
+
+	// Database is the ID of the Cloud Firestore database. Defaults to "(default)".
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// Collection is the path of the collection whose documents are watched,
+	// relative to the database, e.g. "users" or "users/*/orders". A "*"
+	// matches any document ID. Required.
+	Collection string `json:"collection"`
+
+	// EventTypes to subscribe to. If unspecified, then subscribe to all events.
+	// +optional
+	EventTypes []string `json:"eventTypes,omitempty"`
+}
+
+// CloudFirestoreSourceStatus defines the observed state of the CloudFirestoreSource.
+type CloudFirestoreSourceStatus struct {
+	gcpduckv1.PubSubStatus `json:",inline"`
+
+	// ID of the Stackdriver sink used to publish the audit logs of the document changes.
+	StackdriverSink string `json:"stackdriverSink,omitempty"`
+}
+
+func (*CloudFirestoreSource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("CloudFirestoreSource")
+}
+
+// Methods for identifiable interface.
+// IdentitySpec returns the IdentitySpec portion of the Spec.
+func (s *CloudFirestoreSource) IdentitySpec() *gcpduckv1.IdentitySpec {
+	return &s.Spec.IdentitySpec
+}
+
+// IdentityStatus returns the IdentityStatus portion of the Status.
+func (s *CloudFirestoreSource) IdentityStatus() *gcpduckv1.IdentityStatus {
+	return &s.Status.IdentityStatus
+}
+
+// ConditionSet returns the apis.ConditionSet of the embedding object.
+func (*CloudFirestoreSource) ConditionSet() *apis.ConditionSet {
+	return &firestoreSourceCondSet
+}
+
+// Methods for pubsubable interface.
+
+// PubSubSpec returns the PubSubSpec portion of the Spec.
+func (s *CloudFirestoreSource) PubSubSpec() *gcpduckv1.PubSubSpec {
+	return &s.Spec.PubSubSpec
+}
+
+// PubSubStatus returns the PubSubStatus portion of the Status.
+func (s *CloudFirestoreSource) PubSubStatus() *gcpduckv1.PubSubStatus {
+	return &s.Status.PubSubStatus
+}
+
+// ConverterArgs returns the database, collection and event types of the
+// source, which select the write of a commit that the event is converted
+// from. Implements the ConverterConfigurable interface.
+func (s *CloudFirestoreSource) ConverterArgs() map[string]string {
+	return map[string]string{
+		CloudFirestoreSourceDatabase:   s.Spec.Database,
+		CloudFirestoreSourceCollection: s.Spec.Collection,
+		CloudFirestoreSourceEventTypes: strings.Join(s.Spec.EventTypes, ","),
+	}
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudFirestoreSourceList is a list of CloudFirestoreSource resources.
+type CloudFirestoreSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []CloudFirestoreSource `json:"items"`
+}
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*CloudFirestoreSource) GetConditionSet() apis.ConditionSet {
+	return firestoreSourceCondSet
+}
+
+// GetStatus retrieves the status of the CloudFirestoreSource. Implements the KRShaped interface.
+func (s *CloudFirestoreSource) GetStatus() *duckv1.Status {
+	return &s.Status.Status
+}
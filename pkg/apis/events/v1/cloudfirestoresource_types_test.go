@@ -0,0 +1,139 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+)
+
+func TestFirestoreGetGroupVersionKind(t *testing.T) {
+	want := schema.GroupVersionKind{
+		Group:   "events.cloud.google.com",
+		Version: "v1",
+		Kind:    "CloudFirestoreSource",
+	}
+
+	c := &CloudFirestoreSource{}
+
+	got := c.GetGroupVersionKind()
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("failed to get expected (-want, +got) = %v", diff)
+	}
+}
+
+func TestFirestoreConditionSet(t *testing.T) {
+	want := []apis.Condition{{
+		Type: SinkReady,
+	}, {
+		Type: duckv1.TopicReady,
+	}, {
+		Type: duckv1.PullSubscriptionReady,
+	}, {
+		Type: apis.ConditionReady,
+	}}
+	c := &CloudFirestoreSource{}
+
+	c.ConditionSet().Manage(&c.Status).InitializeConditions()
+	var got []apis.Condition = c.Status.GetConditions()
+
+	compareConditionTypes := cmp.Transformer("ConditionType", func(c apis.Condition) apis.ConditionType {
+		return c.Type
+	})
+	sortConditionTypes := cmpopts.SortSlices(func(a, b apis.Condition) bool {
+		return a.Type < b.Type
+	})
+	if diff := cmp.Diff(want, got, sortConditionTypes, compareConditionTypes); diff != "" {
+		t.Errorf("failed to get expected (-want, +got) = %v", diff)
+	}
+}
+
+func TestCloudFirestoreSourceIdentitySpec(t *testing.T) {
+	s := &CloudFirestoreSource{
+		Spec: CloudFirestoreSourceSpec{
+			PubSubSpec: duckv1.PubSubSpec{
+				IdentitySpec: duckv1.IdentitySpec{
+					ServiceAccountName: "test",
+				},
+			},
+		},
+	}
+	want := "test"
+	got := s.IdentitySpec().ServiceAccountName
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("failed to get expected (-want, +got) = %v", diff)
+	}
+}
+
+func TestCloudFirestoreSourceIdentityStatus(t *testing.T) {
+	s := &CloudFirestoreSource{
+		Status: CloudFirestoreSourceStatus{
+			PubSubStatus: duckv1.PubSubStatus{},
+		},
+	}
+	want := &duckv1.IdentityStatus{}
+	got := s.IdentityStatus()
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("failed to get expected (-want, +got) = %v", diff)
+	}
+}
+
+func TestCloudFirestoreSource_GetConditionSet(t *testing.T) {
+	s := &CloudFirestoreSource{}
+
+	if got, want := s.GetConditionSet().GetTopLevelConditionType(), apis.ConditionReady; got != want {
+		t.Errorf("GetTopLevelCondition=%v, want=%v", got, want)
+	}
+}
+
+func TestCloudFirestoreSource_GetStatus(t *testing.T) {
+	s := &CloudFirestoreSource{
+		Status: CloudFirestoreSourceStatus{},
+	}
+	if got, want := s.GetStatus(), &s.Status.Status; got != want {
+		t.Errorf("GetStatus=%v, want=%v", got, want)
+	}
+}
+
+func TestCloudFirestoreSource_ConverterArgs(t *testing.T) {
+	s := &CloudFirestoreSource{
+		Spec: CloudFirestoreSourceSpec{
+			Database:   "(default)",
+			Collection: "users/*/orders",
+			EventTypes: []string{
+				schemasv1.CloudFirestoreDocumentCreatedEventType,
+				schemasv1.CloudFirestoreDocumentDeletedEventType,
+			},
+		},
+	}
+	want := map[string]string{
+		CloudFirestoreSourceDatabase:   "(default)",
+		CloudFirestoreSourceCollection: "users/*/orders",
+		CloudFirestoreSourceEventTypes: "google.cloud.firestore.document.v1.created,google.cloud.firestore.document.v1.deleted",
+	}
+	if diff := cmp.Diff(want, s.ConverterArgs()); diff != "" {
+		t.Errorf("ConverterArgs (-want, +got) = %v", diff)
+	}
+}
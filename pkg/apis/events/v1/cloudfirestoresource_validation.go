@@ -0,0 +1,118 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/knative-gcp/pkg/apis/duck"
+)
+
+func (current *CloudFirestoreSource) Validate(ctx context.Context) *apis.FieldError {
+	errs := current.Spec.Validate(ctx).ViaField("spec")
+
+	if apis.IsInUpdate(ctx) {
+		original := apis.GetBaseline(ctx).(*CloudFirestoreSource)
+		errs = errs.Also(current.CheckImmutableFields(ctx, original))
+	}
+	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
+}
+
+func (current *CloudFirestoreSourceSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	// Sink [required]
+	if equality.Semantic.DeepEqual(current.Sink, duckv1.Destination{}) {
+		errs = errs.Also(apis.ErrMissingField("sink"))
+	} else if err := current.Sink.Validate(ctx); err != nil {
+		errs = errs.Also(err.ViaField("sink"))
+	}
+
+	// Collection [required]
+	if current.Collection == "" {
+		errs = errs.Also(apis.ErrMissingField("collection"))
+	} else if !validCollection(current.Collection) {
+		errs = errs.Also(apis.ErrInvalidValue(current.Collection, "collection"))
+	}
+
+	for i, t := range current.EventTypes {
+		if !validFirestoreEventType(t) {
+			errs = errs.Also(apis.ErrInvalidArrayValue(t, "eventTypes", i))
+		}
+	}
+
+	if err := duck.ValidateCredential(current.Secret, current.ServiceAccountName); err != nil {
+		errs = errs.Also(err)
+	}
+
+	return errs
+}
+
+// validCollection returns true if the path alternates collection IDs and
+// document IDs, ending with a collection ID. Only the document IDs may be "*".
+func validCollection(path string) bool {
+	segments := strings.Split(path, "/")
+	if len(segments)%2 == 0 {
+		return false
+	}
+	for i, s := range segments {
+		if s == "" || (s == "*" && i%2 == 0) || (s != "*" && strings.Contains(s, "*")) {
+			return false
+		}
+	}
+	return true
+}
+
+func validFirestoreEventType(t string) bool {
+	for _, v := range allFirestoreEventTypes {
+		if t == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (current *CloudFirestoreSource) CheckImmutableFields(ctx context.Context, original *CloudFirestoreSource) *apis.FieldError {
+	if original == nil {
+		return nil
+	}
+
+	var errs *apis.FieldError
+	// Modification of Secret, ServiceAccountName, Project, Database, Collection and EventTypes are not allowed.
+	// Everything else is mutable.
+	if diff := cmp.Diff(original.Spec, current.Spec,
+		cmpopts.IgnoreFields(CloudFirestoreSourceSpec{},
+			"Sink", "CloudEventOverrides")); diff != "" {
+		errs = errs.Also(&apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{"spec"},
+			Details: diff,
+		})
+	}
+	// Modification of AutoscalingClassAnnotations is not allowed.
+	errs = duck.CheckImmutableAutoscalingClassAnnotations(&current.ObjectMeta, &original.ObjectMeta, errs)
+
+	// Modification of non-empty cluster name annotation is not allowed.
+	return duck.CheckImmutableClusterNameAnnotation(&current.ObjectMeta, &original.ObjectMeta, errs)
+}
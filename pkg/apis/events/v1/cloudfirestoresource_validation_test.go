@@ -0,0 +1,240 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// Bare minimum is Collection and Sink
+	minimalCloudFirestoreSourceSpec = CloudFirestoreSourceSpec{
+		Collection: "users",
+		PubSubSpec: gcpduckv1.PubSubSpec{
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "foo",
+						Kind:       "bar",
+						Namespace:  "baz",
+						Name:       "qux",
+					},
+				},
+			},
+		},
+	}
+
+	firestoreSourceSpec = CloudFirestoreSourceSpec{
+		Database:   schemasv1.CloudFirestoreDefaultDatabase,
+		Collection: "users/*/orders",
+		EventTypes: []string{schemasv1.CloudFirestoreDocumentCreatedEventType},
+		PubSubSpec: gcpduckv1.PubSubSpec{
+			Secret: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "secret-name",
+				},
+				Key: "secret-key",
+			},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "foo",
+						Kind:       "bar",
+						Namespace:  "baz",
+						Name:       "qux",
+					},
+				},
+			},
+			Project: "my-eventing-project",
+		},
+	}
+)
+
+func TestCloudFirestoreSourceSpecValidationFields(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec *CloudFirestoreSourceSpec
+		want *apis.FieldError
+	}{{
+		name: "empty",
+		spec: &CloudFirestoreSourceSpec{},
+		want: apis.ErrMissingField("collection", "sink"),
+	}, {
+		name: "minimal",
+		spec: &minimalCloudFirestoreSourceSpec,
+		want: nil,
+	}, {
+		name: "full",
+		spec: &firestoreSourceSpec,
+		want: nil,
+	}, {
+		name: "missing sink",
+		spec: &CloudFirestoreSourceSpec{Collection: "users"},
+		want: apis.ErrMissingField("sink"),
+	}, {
+		name: "collection ending with a document",
+		spec: func() *CloudFirestoreSourceSpec {
+			s := minimalCloudFirestoreSourceSpec.DeepCopy()
+			s.Collection = "users/alice"
+			return s
+		}(),
+		want: apis.ErrInvalidValue("users/alice", "collection"),
+	}, {
+		name: "wildcard collection id",
+		spec: func() *CloudFirestoreSourceSpec {
+			s := minimalCloudFirestoreSourceSpec.DeepCopy()
+			s.Collection = "*/alice/orders"
+			return s
+		}(),
+		want: apis.ErrInvalidValue("*/alice/orders", "collection"),
+	}, {
+		name: "partial wildcard",
+		spec: func() *CloudFirestoreSourceSpec {
+			s := minimalCloudFirestoreSourceSpec.DeepCopy()
+			s.Collection = "users/a*/orders"
+			return s
+		}(),
+		want: apis.ErrInvalidValue("users/a*/orders", "collection"),
+	}, {
+		name: "empty segment",
+		spec: func() *CloudFirestoreSourceSpec {
+			s := minimalCloudFirestoreSourceSpec.DeepCopy()
+			s.Collection = "users//orders"
+			return s
+		}(),
+		want: apis.ErrInvalidValue("users//orders", "collection"),
+	}, {
+		name: "invalid event type",
+		spec: func() *CloudFirestoreSourceSpec {
+			s := minimalCloudFirestoreSourceSpec.DeepCopy()
+			s.EventTypes = []string{schemasv1.CloudFirestoreDocumentDeletedEventType, "foo"}
+			return s
+		}(),
+		want: apis.ErrInvalidArrayValue("foo", "eventTypes", 1),
+	}, {
+		name: "invalid secret, missing key",
+		spec: func() *CloudFirestoreSourceSpec {
+			s := minimalCloudFirestoreSourceSpec.DeepCopy()
+			s.Secret = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "name",
+				},
+			}
+			return s
+		}(),
+		want: apis.ErrMissingField("secret.key"),
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.spec.Validate(context.TODO())
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: Validate CloudFirestoreSourceSpec (-want, +got) = %v", tc.name, diff)
+			}
+		})
+	}
+}
+
+func TestCloudFirestoreSourceCheckImmutableFields(t *testing.T) {
+	testCases := map[string]struct {
+		orig    *CloudFirestoreSourceSpec
+		updated CloudFirestoreSourceSpec
+		allowed bool
+	}{
+		"nil orig": {
+			updated: firestoreSourceSpec,
+			allowed: true,
+		},
+		"no change": {
+			orig:    &firestoreSourceSpec,
+			updated: firestoreSourceSpec,
+			allowed: true,
+		},
+		"Database changed": {
+			orig: &firestoreSourceSpec,
+			updated: func() CloudFirestoreSourceSpec {
+				s := firestoreSourceSpec.DeepCopy()
+				s.Database = "other"
+				return *s
+			}(),
+			allowed: false,
+		},
+		"Collection changed": {
+			orig: &firestoreSourceSpec,
+			updated: func() CloudFirestoreSourceSpec {
+				s := firestoreSourceSpec.DeepCopy()
+				s.Collection = "orders"
+				return *s
+			}(),
+			allowed: false,
+		},
+		"EventTypes changed": {
+			orig: &firestoreSourceSpec,
+			updated: func() CloudFirestoreSourceSpec {
+				s := firestoreSourceSpec.DeepCopy()
+				s.EventTypes = []string{schemasv1.CloudFirestoreDocumentDeletedEventType}
+				return *s
+			}(),
+			allowed: false,
+		},
+		"Project changed": {
+			orig: &firestoreSourceSpec,
+			updated: func() CloudFirestoreSourceSpec {
+				s := firestoreSourceSpec.DeepCopy()
+				s.Project = "some-other-project"
+				return *s
+			}(),
+			allowed: false,
+		},
+		"Sink.Name changed": {
+			orig: &firestoreSourceSpec,
+			updated: func() CloudFirestoreSourceSpec {
+				s := firestoreSourceSpec.DeepCopy()
+				s.Sink.Ref.Name = "some-other-name"
+				return *s
+			}(),
+			allowed: true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var orig *CloudFirestoreSource
+			if tc.orig != nil {
+				orig = &CloudFirestoreSource{
+					Spec: *tc.orig,
+				}
+			}
+			updated := &CloudFirestoreSource{
+				Spec: tc.updated,
+			}
+			err := updated.CheckImmutableFields(context.TODO(), orig)
+			if tc.allowed != (err == nil) {
+				t.Fatalf("Unexpected immutable field check. Expected %v. Actual %v", tc.allowed, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"knative.dev/pkg/apis"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (s *CloudSecretManagerSourceStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return secretManagerSourceCondSet.Manage(s).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level condition.
+func (s *CloudSecretManagerSourceStatus) GetTopLevelCondition() *apis.Condition {
+	return secretManagerSourceCondSet.Manage(s).GetTopLevelCondition()
+}
+
+// IsReady returns true if the resource is ready overall.
+func (s *CloudSecretManagerSourceStatus) IsReady() bool {
+	return secretManagerSourceCondSet.Manage(s).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (s *CloudSecretManagerSourceStatus) InitializeConditions() {
+	secretManagerSourceCondSet.Manage(s).InitializeConditions()
+}
+
+// MarkSecretsNotReady sets the condition that the secrets have not been
+// successfully configured to publish their events.
+func (s *CloudSecretManagerSourceStatus) MarkSecretsNotReady(reason, messageFormat string, messageA ...interface{}) {
+	secretManagerSourceCondSet.Manage(s).MarkFalse(SecretsReady, reason, messageFormat, messageA...)
+}
+
+// MarkSecretsUnknown sets the condition that the status of the secret
+// events is unknown.
+func (s *CloudSecretManagerSourceStatus) MarkSecretsUnknown(reason, messageFormat string, messageA ...interface{}) {
+	secretManagerSourceCondSet.Manage(s).MarkUnknown(SecretsReady, reason, messageFormat, messageA...)
+}
+
+// MarkSecretsReady sets the condition that all the secrets publish their
+// events to the topic.
+func (s *CloudSecretManagerSourceStatus) MarkSecretsReady() {
+	secretManagerSourceCondSet.Manage(s).MarkTrue(SecretsReady)
+}
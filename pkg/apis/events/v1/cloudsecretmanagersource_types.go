@@ -0,0 +1,139 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	kngcpduck "github.com/google/knative-gcp/pkg/duck/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/webhook/resourcesemantics"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudSecretManagerSource is a specification for a Secret Manager secret
+// event source.
+type CloudSecretManagerSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudSecretManagerSourceSpec   `json:"spec"`
+	Status CloudSecretManagerSourceStatus `json:"status"`
+}
+
+// Verify that CloudSecretManagerSource matches various duck types.
+var (
+	_ apis.Defaultable             = (*CloudSecretManagerSource)(nil)
+	_ apis.Validatable             = (*CloudSecretManagerSource)(nil)
+	_ runtime.Object               = (*CloudSecretManagerSource)(nil)
+	_ kmeta.OwnerRefable           = (*CloudSecretManagerSource)(nil)
+	_ resourcesemantics.GenericCRD = (*CloudSecretManagerSource)(nil)
+	_ kngcpduck.Identifiable       = (*CloudSecretManagerSource)(nil)
+	_ kngcpduck.PubSubable         = (*CloudSecretManagerSource)(nil)
+	_ duckv1.KRShaped              = (*CloudSecretManagerSource)(nil)
+)
+
+// CloudSecretManagerSourceSpec defines the desired state of the CloudSecretManagerSource.
+type CloudSecretManagerSourceSpec struct {
+	// This brings in the PubSub based Source Specs. Includes:
+	// Sink, CloudEventOverrides, Secret and Project.
+	gcpduckv1.PubSubSpec `json:",inline"`
+
+	// Secrets are the IDs of the secrets whose events are sent. The secrets
+	// belong to the project of the source.
+	Secrets []string `json:"secrets"`
+}
+
+const (
+	// SecretsReady has status True when all the secrets have been
+	// successfully configured to publish their events to the topic.
+	SecretsReady apis.ConditionType = "SecretsReady"
+)
+
+var secretManagerSourceCondSet = apis.NewLivingConditionSet(
+	gcpduckv1.PullSubscriptionReady,
+	gcpduckv1.TopicReady,
+	SecretsReady,
+)
+
+// CloudSecretManagerSourceStatus defines the observed state of CloudSecretManagerSource.
+type CloudSecretManagerSourceStatus struct {
+	// This brings in our GCP PubSub based events importers
+	// duck/v1 Status, SinkURI, ProjectID, TopicID and SubscriptionID
+	gcpduckv1.PubSubStatus `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudSecretManagerSourceList contains a list of CloudSecretManagerSources.
+type CloudSecretManagerSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CloudSecretManagerSource `json:"items"`
+}
+
+// GetGroupVersionKind returns the GroupVersionKind.
+func (*CloudSecretManagerSource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("CloudSecretManagerSource")
+}
+
+// Methods for identifiable interface.
+// IdentitySpec returns the IdentitySpec portion of the Spec.
+func (s *CloudSecretManagerSource) IdentitySpec() *gcpduckv1.IdentitySpec {
+	return &s.Spec.IdentitySpec
+}
+
+// IdentityStatus returns the IdentityStatus portion of the Status.
+func (s *CloudSecretManagerSource) IdentityStatus() *gcpduckv1.IdentityStatus {
+	return &s.Status.IdentityStatus
+}
+
+// Methods for pubsubable interface.
+
+// PubSubSpec returns the PubSubSpec portion of the Spec.
+func (s *CloudSecretManagerSource) PubSubSpec() *gcpduckv1.PubSubSpec {
+	return &s.Spec.PubSubSpec
+}
+
+// PubSubStatus returns the PubSubStatus portion of the Status.
+func (s *CloudSecretManagerSource) PubSubStatus() *gcpduckv1.PubSubStatus {
+	return &s.Status.PubSubStatus
+}
+
+// ConditionSet returns the apis.ConditionSet of the embedding object.
+func (s *CloudSecretManagerSource) ConditionSet() *apis.ConditionSet {
+	return &secretManagerSourceCondSet
+}
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*CloudSecretManagerSource) GetConditionSet() apis.ConditionSet {
+	return secretManagerSourceCondSet
+}
+
+// GetStatus retrieves the status of the CloudSecretManagerSource. Implements the KRShaped interface.
+func (s *CloudSecretManagerSource) GetStatus() *duckv1.Status {
+	return &s.Status.Status
+}
@@ -49,6 +49,16 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CloudAuditLogsSourceList{},
 		&CloudBuildSource{},
 		&CloudBuildSourceList{},
+		&CloudFirestoreSource{},
+		&CloudFirestoreSourceList{},
+		&CloudContainerRegistrySource{},
+		&CloudContainerRegistrySourceList{},
+		&CloudMonitoringAlertSource{},
+		&CloudMonitoringAlertSourceList{},
+		&CloudBillingBudgetSource{},
+		&CloudBillingBudgetSourceList{},
+		&CloudSecretManagerSource{},
+		&CloudSecretManagerSourceList{},
 		&CloudPubSubSource{},
 		&CloudPubSubSourceList{},
 		&CloudSchedulerSource{},
@@ -61,6 +61,11 @@ func TestKnownTypes(t *testing.T) {
 	for _, name := range []string{
 		"CloudAuditLogsSource",
 		"CloudBuildSource",
+		"CloudFirestoreSource",
+		"CloudContainerRegistrySource",
+		"CloudMonitoringAlertSource",
+		"CloudBillingBudgetSource",
+		"CloudSecretManagerSource",
 		"CloudPubSubSource",
 		"CloudSchedulerSource",
 		"CloudStorageSource",
@@ -119,6 +119,101 @@ func (in *CloudAuditLogsSourceStatus) DeepCopy() *CloudAuditLogsSourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudBillingBudgetSource) DeepCopyInto(out *CloudBillingBudgetSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudBillingBudgetSource.
+func (in *CloudBillingBudgetSource) DeepCopy() *CloudBillingBudgetSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudBillingBudgetSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudBillingBudgetSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudBillingBudgetSourceList) DeepCopyInto(out *CloudBillingBudgetSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CloudBillingBudgetSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudBillingBudgetSourceList.
+func (in *CloudBillingBudgetSourceList) DeepCopy() *CloudBillingBudgetSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudBillingBudgetSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudBillingBudgetSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudBillingBudgetSourceSpec) DeepCopyInto(out *CloudBillingBudgetSourceSpec) {
+	*out = *in
+	in.PubSubSpec.DeepCopyInto(&out.PubSubSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudBillingBudgetSourceSpec.
+func (in *CloudBillingBudgetSourceSpec) DeepCopy() *CloudBillingBudgetSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudBillingBudgetSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudBillingBudgetSourceStatus) DeepCopyInto(out *CloudBillingBudgetSourceStatus) {
+	*out = *in
+	in.PubSubStatus.DeepCopyInto(&out.PubSubStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudBillingBudgetSourceStatus.
+func (in *CloudBillingBudgetSourceStatus) DeepCopy() *CloudBillingBudgetSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudBillingBudgetSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudBuildSource) DeepCopyInto(out *CloudBuildSource) {
 	*out = *in
@@ -214,6 +309,296 @@ func (in *CloudBuildSourceStatus) DeepCopy() *CloudBuildSourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudContainerRegistrySource) DeepCopyInto(out *CloudContainerRegistrySource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudContainerRegistrySource.
+func (in *CloudContainerRegistrySource) DeepCopy() *CloudContainerRegistrySource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudContainerRegistrySource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudContainerRegistrySource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudContainerRegistrySourceList) DeepCopyInto(out *CloudContainerRegistrySourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CloudContainerRegistrySource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudContainerRegistrySourceList.
+func (in *CloudContainerRegistrySourceList) DeepCopy() *CloudContainerRegistrySourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudContainerRegistrySourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudContainerRegistrySourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudContainerRegistrySourceSpec) DeepCopyInto(out *CloudContainerRegistrySourceSpec) {
+	*out = *in
+	in.PubSubSpec.DeepCopyInto(&out.PubSubSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudContainerRegistrySourceSpec.
+func (in *CloudContainerRegistrySourceSpec) DeepCopy() *CloudContainerRegistrySourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudContainerRegistrySourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudContainerRegistrySourceStatus) DeepCopyInto(out *CloudContainerRegistrySourceStatus) {
+	*out = *in
+	in.PubSubStatus.DeepCopyInto(&out.PubSubStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudContainerRegistrySourceStatus.
+func (in *CloudContainerRegistrySourceStatus) DeepCopy() *CloudContainerRegistrySourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudContainerRegistrySourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudFirestoreSource) DeepCopyInto(out *CloudFirestoreSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudFirestoreSource.
+func (in *CloudFirestoreSource) DeepCopy() *CloudFirestoreSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudFirestoreSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudFirestoreSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudFirestoreSourceList) DeepCopyInto(out *CloudFirestoreSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CloudFirestoreSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudFirestoreSourceList.
+func (in *CloudFirestoreSourceList) DeepCopy() *CloudFirestoreSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudFirestoreSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudFirestoreSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudFirestoreSourceSpec) DeepCopyInto(out *CloudFirestoreSourceSpec) {
+	*out = *in
+	in.PubSubSpec.DeepCopyInto(&out.PubSubSpec)
+	if in.EventTypes != nil {
+		in, out := &in.EventTypes, &out.EventTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudFirestoreSourceSpec.
+func (in *CloudFirestoreSourceSpec) DeepCopy() *CloudFirestoreSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudFirestoreSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudFirestoreSourceStatus) DeepCopyInto(out *CloudFirestoreSourceStatus) {
+	*out = *in
+	in.PubSubStatus.DeepCopyInto(&out.PubSubStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudFirestoreSourceStatus.
+func (in *CloudFirestoreSourceStatus) DeepCopy() *CloudFirestoreSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudFirestoreSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudMonitoringAlertSource) DeepCopyInto(out *CloudMonitoringAlertSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudMonitoringAlertSource.
+func (in *CloudMonitoringAlertSource) DeepCopy() *CloudMonitoringAlertSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudMonitoringAlertSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudMonitoringAlertSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudMonitoringAlertSourceList) DeepCopyInto(out *CloudMonitoringAlertSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CloudMonitoringAlertSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudMonitoringAlertSourceList.
+func (in *CloudMonitoringAlertSourceList) DeepCopy() *CloudMonitoringAlertSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudMonitoringAlertSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudMonitoringAlertSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudMonitoringAlertSourceSpec) DeepCopyInto(out *CloudMonitoringAlertSourceSpec) {
+	*out = *in
+	in.PubSubSpec.DeepCopyInto(&out.PubSubSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudMonitoringAlertSourceSpec.
+func (in *CloudMonitoringAlertSourceSpec) DeepCopy() *CloudMonitoringAlertSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudMonitoringAlertSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudMonitoringAlertSourceStatus) DeepCopyInto(out *CloudMonitoringAlertSourceStatus) {
+	*out = *in
+	in.PubSubStatus.DeepCopyInto(&out.PubSubStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudMonitoringAlertSourceStatus.
+func (in *CloudMonitoringAlertSourceStatus) DeepCopy() *CloudMonitoringAlertSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudMonitoringAlertSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudPubSubSource) DeepCopyInto(out *CloudPubSubSource) {
 	*out = *in
@@ -414,6 +799,106 @@ func (in *CloudSchedulerSourceStatus) DeepCopy() *CloudSchedulerSourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudSecretManagerSource) DeepCopyInto(out *CloudSecretManagerSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudSecretManagerSource.
+func (in *CloudSecretManagerSource) DeepCopy() *CloudSecretManagerSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudSecretManagerSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudSecretManagerSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudSecretManagerSourceList) DeepCopyInto(out *CloudSecretManagerSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CloudSecretManagerSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudSecretManagerSourceList.
+func (in *CloudSecretManagerSourceList) DeepCopy() *CloudSecretManagerSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudSecretManagerSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudSecretManagerSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudSecretManagerSourceSpec) DeepCopyInto(out *CloudSecretManagerSourceSpec) {
+	*out = *in
+	in.PubSubSpec.DeepCopyInto(&out.PubSubSpec)
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudSecretManagerSourceSpec.
+func (in *CloudSecretManagerSourceSpec) DeepCopy() *CloudSecretManagerSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudSecretManagerSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudSecretManagerSourceStatus) DeepCopyInto(out *CloudSecretManagerSourceStatus) {
+	*out = *in
+	in.PubSubStatus.DeepCopyInto(&out.PubSubStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudSecretManagerSourceStatus.
+func (in *CloudSecretManagerSourceStatus) DeepCopy() *CloudSecretManagerSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudSecretManagerSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudStorageSource) DeepCopyInto(out *CloudStorageSource) {
 	*out = *in
@@ -94,6 +94,18 @@ type PullSubscriptionSpec struct {
 	// PullSubscription uses.
 	// +optional
 	AdapterType string `json:"adapterType,omitempty"`
+
+	// EventFilter restricts the events sent to the sink to those whose
+	// CloudEvents attributes, or extensions, equal all of its entries. The
+	// filter applies to the events converted by the receive adapter.
+	// +optional
+	EventFilter map[string]string `json:"eventFilter,omitempty"`
+
+	// ConverterArgs holds the arguments of the converter selected by
+	// AdapterType, for the converters which depend on the source, e.g. to
+	// select the matching part of a message.
+	// +optional
+	ConverterArgs map[string]string `json:"converterArgs,omitempty"`
 }
 
 // GetAckDeadline parses AckDeadline and returns the default if an error occurs.
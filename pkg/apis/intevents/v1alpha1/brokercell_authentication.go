@@ -0,0 +1,76 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// IngressAuthenticationAnnotation is the annotation key used to require the senders of the
+	// events of all the Brokers of a BrokerCell to present a bearer token, of the kind given by
+	// the value. Without it, only the senders of the Brokers with allowed senders are
+	// authenticated, with Kubernetes service account tokens.
+	IngressAuthenticationAnnotation = "events.cloud.google.com/ingressAuthentication"
+
+	// IngressAudienceAnnotation is the annotation key used to set the audience the bearer tokens
+	// must be issued for. Without it, Kubernetes service account tokens must be issued for the
+	// API server, and OIDC ID tokens may be issued for any audience.
+	IngressAudienceAnnotation = "events.cloud.google.com/ingressAudience"
+
+	// IngressAuthenticationKubernetes authenticates senders with Kubernetes service account
+	// tokens, verified with the TokenReview API.
+	IngressAuthenticationKubernetes = "kubernetes"
+
+	// IngressAuthenticationOIDC authenticates senders with OIDC ID tokens signed by Google,
+	// e.g. the ID tokens of Google service accounts.
+	IngressAuthenticationOIDC = "oidc"
+)
+
+var ingressAuthenticationAnnotationPath = fmt.Sprintf("metadata.annotations[%s]", IngressAuthenticationAnnotation)
+
+// GetIngressAuthentication returns the kind of bearer tokens required by the ingress of the
+// BrokerCell, or an empty string if the ingress doesn't require them.
+func (bc *BrokerCell) GetIngressAuthentication() string {
+	switch v := bc.GetAnnotations()[IngressAuthenticationAnnotation]; v {
+	case IngressAuthenticationKubernetes, IngressAuthenticationOIDC:
+		return v
+	default:
+		return ""
+	}
+}
+
+// GetIngressAudience returns the audience of the bearer tokens verified by the ingress of the
+// BrokerCell.
+func (bc *BrokerCell) GetIngressAudience() string {
+	return bc.GetAnnotations()[IngressAudienceAnnotation]
+}
+
+func validateIngressAuthenticationAnnotation(annotations map[string]string) *apis.FieldError {
+	v, ok := annotations[IngressAuthenticationAnnotation]
+	if !ok {
+		return nil
+	}
+	switch v {
+	case IngressAuthenticationKubernetes, IngressAuthenticationOIDC:
+		return nil
+	default:
+		return apis.ErrInvalidValue(v, ingressAuthenticationAnnotationPath)
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetIngressAuthentication(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+	}{{
+		name: "no annotations",
+	}, {
+		name:        "kubernetes",
+		annotations: map[string]string{IngressAuthenticationAnnotation: "kubernetes"},
+		want:        IngressAuthenticationKubernetes,
+	}, {
+		name:        "oidc",
+		annotations: map[string]string{IngressAuthenticationAnnotation: "oidc"},
+		want:        IngressAuthenticationOIDC,
+	}, {
+		name:        "invalid",
+		annotations: map[string]string{IngressAuthenticationAnnotation: "true"},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bc := BrokerCell{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			if got := bc.GetIngressAuthentication(); got != test.want {
+				t.Errorf("GetIngressAuthentication got=%q, want=%q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+
+	"knative.dev/pkg/apis"
+)
+
+// ClaimCheckBucketAnnotation is the annotation key used to set the Cloud Storage bucket the data
+// of the events too large for Pub/Sub is offloaded to. The events published to the decouple topics
+// then only reference their data, which is loaded back before the events are delivered. Without
+// it, the ingress rejects the events larger than the Pub/Sub limit.
+const ClaimCheckBucketAnnotation = "events.cloud.google.com/claimCheckBucket"
+
+var (
+	claimCheckBucketAnnotationPath = fmt.Sprintf("metadata.annotations[%s]", ClaimCheckBucketAnnotation)
+
+	// See https://cloud.google.com/storage/docs/naming-buckets.
+	bucketNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{1,220}[a-z0-9]$`)
+)
+
+// GetClaimCheckBucket returns the bucket the data of the large events is offloaded to, or an
+// empty string if the large events are rejected.
+func (bc *BrokerCell) GetClaimCheckBucket() string {
+	if v := bc.GetAnnotations()[ClaimCheckBucketAnnotation]; bucketNameRegexp.MatchString(v) {
+		return v
+	}
+	return ""
+}
+
+func validateClaimCheckBucketAnnotation(annotations map[string]string) *apis.FieldError {
+	v, ok := annotations[ClaimCheckBucketAnnotation]
+	if !ok || bucketNameRegexp.MatchString(v) {
+		return nil
+	}
+	return apis.ErrInvalidValue(v, claimCheckBucketAnnotationPath)
+}
@@ -27,7 +27,9 @@ import (
 
 // Validate verifies that the BrokerCell is valid.
 func (bc *BrokerCell) Validate(ctx context.Context) *apis.FieldError {
-	fieldErrors := bc.Spec.Validate(ctx).ViaField("spec")
+	fieldErrors := validateIngressAuthenticationAnnotation(bc.GetAnnotations())
+	fieldErrors = fieldErrors.Also(validateClaimCheckBucketAnnotation(bc.GetAnnotations()))
+	fieldErrors = fieldErrors.Also(bc.Spec.Validate(ctx).ViaField("spec"))
 	return fieldErrors
 }
 
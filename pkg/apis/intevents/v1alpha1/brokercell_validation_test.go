@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/ptr"
 )
@@ -37,6 +38,42 @@ func TestBrokerCell_Validate(t *testing.T) {
 				Spec: MakeDefaultBrokerCellSpec(),
 			},
 			want: nil,
+		}, {
+			name: "Valid ingress authentication",
+			brokerCell: BrokerCell{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{IngressAuthenticationAnnotation: IngressAuthenticationOIDC},
+				},
+				Spec: MakeDefaultBrokerCellSpec(),
+			},
+			want: nil,
+		}, {
+			name: "Invalid ingress authentication",
+			brokerCell: BrokerCell{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{IngressAuthenticationAnnotation: "true"},
+				},
+				Spec: MakeDefaultBrokerCellSpec(),
+			},
+			want: apis.ErrInvalidValue("true", "metadata.annotations[events.cloud.google.com/ingressAuthentication]"),
+		}, {
+			name: "Valid claim check bucket",
+			brokerCell: BrokerCell{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{ClaimCheckBucketAnnotation: "my-bucket"},
+				},
+				Spec: MakeDefaultBrokerCellSpec(),
+			},
+			want: nil,
+		}, {
+			name: "Invalid claim check bucket",
+			brokerCell: BrokerCell{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{ClaimCheckBucketAnnotation: "My Bucket"},
+				},
+				Spec: MakeDefaultBrokerCellSpec(),
+			},
+			want: apis.ErrInvalidValue("My Bucket", "metadata.annotations[events.cloud.google.com/claimCheckBucket]"),
 		}, {
 			name: "Memory request should not exceed the memory limit",
 			brokerCell: BrokerCell{
@@ -40,6 +40,8 @@ func (source *PullSubscription) ConvertTo(ctx context.Context, to apis.Convertib
 		sink.Spec.RetentionDuration = source.Spec.RetentionDuration
 		sink.Spec.Transformer = source.Spec.Transformer
 		sink.Spec.AdapterType = source.Spec.AdapterType
+		sink.Spec.EventFilter = source.Spec.EventFilter
+		sink.Spec.ConverterArgs = source.Spec.ConverterArgs
 		sink.Status.PubSubStatus = convert.ToV1PubSubStatus(source.Status.PubSubStatus)
 		sink.Status.TransformerURI = source.Status.TransformerURI
 		sink.Status.SubscriptionID = source.Status.SubscriptionID
@@ -65,6 +67,8 @@ func (sink *PullSubscription) ConvertFrom(ctx context.Context, from apis.Convert
 		// Since we remove Mode from PullSubscriptionSpec in v1, we treat it as an empty string.
 		sink.Spec.Mode = ""
 		sink.Spec.AdapterType = source.Spec.AdapterType
+		sink.Spec.EventFilter = source.Spec.EventFilter
+		sink.Spec.ConverterArgs = source.Spec.ConverterArgs
 		sink.Status.PubSubStatus = convert.FromV1PubSubStatus(source.Status.PubSubStatus)
 		sink.Status.TransformerURI = source.Status.TransformerURI
 		sink.Status.SubscriptionID = source.Status.SubscriptionID
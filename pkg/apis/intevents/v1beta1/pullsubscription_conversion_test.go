@@ -46,6 +46,8 @@ var (
 			Transformer:         &gcptesting.CompleteDestination,
 			Mode:                ModeCloudEventsBinary,
 			AdapterType:         "adapterType",
+			EventFilter:         map[string]string{"tag": "latest"},
+			ConverterArgs:       map[string]string{"arg": "value"},
 		},
 		Status: PullSubscriptionStatus{
 			PubSubStatus:   gcptesting.CompleteV1beta1PubSubStatus,
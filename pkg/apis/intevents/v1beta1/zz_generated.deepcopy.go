@@ -107,6 +107,20 @@ func (in *PullSubscriptionSpec) DeepCopyInto(out *PullSubscriptionSpec) {
 		*out = new(v1.Destination)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.EventFilter != nil {
+		in, out := &in.EventFilter, &out.EventFilter
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ConverterArgs != nil {
+		in, out := &in.ConverterArgs, &out.ConverterArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
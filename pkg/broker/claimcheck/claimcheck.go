@@ -0,0 +1,147 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package claimcheck offloads the data of the events too large to be published to Pub/Sub
+// to Cloud Storage objects, following the claim-check pattern. The published events only
+// hold a reference to their object, and their data is loaded back before they're delivered.
+package claimcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/google/knative-gcp/pkg/gclient/storage"
+)
+
+const (
+	// Extension is the extension of the events whose data was offloaded. It holds the
+	// gs://<bucket>/<object> URL of the object with the data of the event.
+	Extension = "knativegcpclaimcheck"
+
+	referenceScheme = "gs://"
+)
+
+// ErrInvalidReference is returned when the claim check of an event isn't a reference to an
+// object of the bucket of the Store.
+var ErrInvalidReference = errors.New("invalid claim check reference")
+
+// Store offloads the data of events to the objects of a bucket, and loads it back.
+type Store struct {
+	client storage.Client
+	bucket string
+	// threshold is the size of the data from which it's offloaded.
+	threshold int
+}
+
+// NewStore creates a Store of the data of the events in the bucket. The data of the events
+// larger than threshold bytes is offloaded.
+func NewStore(client storage.Client, bucket string, threshold int) *Store {
+	return &Store{
+		client:    client,
+		bucket:    bucket,
+		threshold: threshold,
+	}
+}
+
+// Offload stores the data of an event sent to the broker in a new object of the bucket if
+// it's larger than the threshold, and replaces the data with a reference to the object.
+func (s *Store) Offload(ctx context.Context, broker types.NamespacedName, e *event.Event) error {
+	if len(e.Data()) <= s.threshold {
+		return nil
+	}
+	name := path.Join(broker.Namespace, broker.Name, uuid.New().String())
+	w := s.client.Bucket(s.bucket).Object(name).NewWriter(ctx)
+	if _, err := w.Write(e.Data()); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write the data of the event to %s: %w", s.reference(name), err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to write the data of the event to %s: %w", s.reference(name), err)
+	}
+	e.SetExtension(Extension, s.reference(name))
+	e.DataEncoded = nil
+	return nil
+}
+
+// Load returns a copy of the event with the data loaded from the object referenced by its
+// claim check, or the event itself if its data wasn't offloaded.
+func (s *Store) Load(ctx context.Context, e *event.Event) (*event.Event, error) {
+	name, ok, err := s.claimCheck(e)
+	if !ok || err != nil {
+		return e, err
+	}
+	r, err := s.client.Bucket(s.bucket).Object(name).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the data of the event from %s: %w", s.reference(name), err)
+	}
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the data of the event from %s: %w", s.reference(name), err)
+	}
+	loaded := e.Clone()
+	loaded.SetExtension(Extension, nil)
+	loaded.DataEncoded = data
+	return &loaded, nil
+}
+
+// Delete deletes the object referenced by the claim check of the event, e.g. because the
+// offloaded event failed to be published.
+func (s *Store) Delete(ctx context.Context, e *event.Event) error {
+	name, ok, err := s.claimCheck(e)
+	if !ok || err != nil {
+		return err
+	}
+	return s.client.Bucket(s.bucket).Object(name).Delete(ctx)
+}
+
+// claimCheck returns the name of the object referenced by the claim check of the event, if
+// it has one.
+func (s *Store) claimCheck(e *event.Event) (string, bool, error) {
+	v, ok := e.Extensions()[Extension]
+	if !ok {
+		return "", false, nil
+	}
+	ref, err := cetypes.ToString(v)
+	if err != nil {
+		return "", true, fmt.Errorf("%w: %v", ErrInvalidReference, err)
+	}
+	name, err := s.objectName(ref)
+	return name, true, err
+}
+
+func (s *Store) reference(name string) string {
+	return referenceScheme + s.bucket + "/" + name
+}
+
+// objectName returns the name of the object of the reference. Only the objects of the bucket
+// of the Store are referenced, so that the senders of events can't get other objects delivered.
+func (s *Store) objectName(ref string) (string, error) {
+	prefix := s.reference("")
+	if !strings.HasPrefix(ref, prefix) || len(ref) == len(prefix) {
+		return "", fmt.Errorf("%w: %q isn't an object of bucket %q", ErrInvalidReference, ref, s.bucket)
+	}
+	return strings.TrimPrefix(ref, prefix), nil
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claimcheck
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+
+	gstorage "github.com/google/knative-gcp/pkg/gclient/storage/testing"
+)
+
+func testEvent(data string) *event.Event {
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	e.SetExtension("ext", "value")
+	e.SetData("text/plain", []byte(data))
+	return &e
+}
+
+func testStore(ctx context.Context, t *testing.T, objects *gstorage.Objects, err error) *Store {
+	client, cerr := gstorage.TestClientCreator(gstorage.TestClientData{
+		Objects:    objects,
+		BucketData: gstorage.TestBucketData{ObjectErr: err},
+	})(ctx)
+	if cerr != nil {
+		t.Fatal(cerr)
+	}
+	return NewStore(client, "test-bucket", 5)
+}
+
+func TestOffloadAndLoad(t *testing.T) {
+	ctx := context.Background()
+	objects := gstorage.NewObjects()
+	s := testStore(ctx, t, objects, nil)
+	broker := types.NamespacedName{Namespace: "ns", Name: "broker"}
+
+	small := testEvent("small")
+	if err := s.Offload(ctx, broker, small); err != nil {
+		t.Fatalf("Offload got unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(testEvent("small"), small); diff != "" {
+		t.Errorf("Offload changed the event under the threshold (-want,+got): %v", diff)
+	}
+
+	large := testEvent("large data")
+	if err := s.Offload(ctx, broker, large); err != nil {
+		t.Fatalf("Offload got unexpected error: %v", err)
+	}
+	if len(large.Data()) != 0 {
+		t.Errorf("Offloaded event data got=%q, want empty", large.Data())
+	}
+	ref, ok := large.Extensions()[Extension].(string)
+	if !ok || !strings.HasPrefix(ref, "gs://test-bucket/ns/broker/") {
+		t.Fatalf("Offloaded event claim check got=%v, want an object of the broker in test-bucket", large.Extensions()[Extension])
+	}
+	if data, ok := objects.Get("test-bucket", strings.TrimPrefix(ref, "gs://test-bucket/")); !ok || string(data) != "large data" {
+		t.Errorf("Offloaded object got=(%q, %v), want the data of the event", data, ok)
+	}
+
+	loaded, err := s.Load(ctx, large)
+	if err != nil {
+		t.Fatalf("Load got unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(testEvent("large data"), loaded); diff != "" {
+		t.Errorf("Loaded event (-want,+got): %v", diff)
+	}
+	if _, ok := large.Extensions()[Extension]; !ok {
+		t.Error("Load modified the offloaded event")
+	}
+
+	notOffloaded, err := s.Load(ctx, small)
+	if err != nil || notOffloaded != small {
+		t.Errorf("Load got=(%v, %v), want the event itself", notOffloaded, err)
+	}
+
+	if err := s.Delete(ctx, large); err != nil {
+		t.Fatalf("Delete got unexpected error: %v", err)
+	}
+	if _, err := s.Load(ctx, large); !errors.Is(err, storage.ErrObjectNotExist) {
+		t.Errorf("Load of deleted object got error %v, want %v", err, storage.ErrObjectNotExist)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	ctx := context.Background()
+	objects := gstorage.NewObjects()
+	objects.Put("other-bucket", "ns/broker/object", []byte("secret"))
+	tests := []struct {
+		name    string
+		ref     interface{}
+		err     error
+		wantErr error
+	}{{
+		name:    "other bucket",
+		ref:     "gs://other-bucket/ns/broker/object",
+		wantErr: ErrInvalidReference,
+	}, {
+		name:    "bucket prefix",
+		ref:     "gs://test-bucket-other/ns/broker/object",
+		wantErr: ErrInvalidReference,
+	}, {
+		name:    "no object",
+		ref:     "gs://test-bucket/",
+		wantErr: ErrInvalidReference,
+	}, {
+		name:    "missing object",
+		ref:     "gs://test-bucket/ns/broker/missing",
+		wantErr: storage.ErrObjectNotExist,
+	}, {
+		name:    "storage error",
+		ref:     "gs://test-bucket/ns/broker/object",
+		err:     errors.New("storage error"),
+		wantErr: errors.New("storage error"),
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := testStore(ctx, t, objects, test.err)
+			e := testEvent("")
+			e.SetExtension(Extension, test.ref)
+			_, err := s.Load(ctx, e)
+			if err == nil || (!errors.Is(err, test.wantErr) && !strings.Contains(err.Error(), test.wantErr.Error())) {
+				t.Errorf("Load got error %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestOffloadError(t *testing.T) {
+	ctx := context.Background()
+	s := testStore(ctx, t, nil, errors.New("storage error"))
+	e := testEvent("large data")
+	if err := s.Offload(ctx, types.NamespacedName{Namespace: "ns", Name: "broker"}, e); err == nil {
+		t.Error("Offload got no error, want the storage error")
+	}
+	if diff := cmp.Diff(testEvent("large data"), e); diff != "" {
+		t.Errorf("Offload changed the event which failed to be offloaded (-want,+got): %v", diff)
+	}
+}
@@ -21,17 +21,46 @@ import (
 
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
+
+	"github.com/google/knative-gcp/pkg/utils/celfilter"
 )
 
 // CachedTargets provides a in-memory cached copy of targets.
 type CachedTargets struct {
 	Value atomic.Value
+	// celFilters holds the compiled CEL filters keyed by target key.
+	celFilters atomic.Value
 }
 
 var _ ReadonlyTargets = (*CachedTargets)(nil)
 
+type compiledCELFilter struct {
+	expr    string
+	program *celfilter.Program
+	err     error
+}
+
 // Store atomically stores a TargetsConfig.
+// The CEL filters of the targets are compiled before the TargetsConfig is stored.
 func (ct *CachedTargets) Store(t *TargetsConfig) {
+	old, _ := ct.celFilters.Load().(map[string]*compiledCELFilter)
+	filters := make(map[string]*compiledCELFilter)
+	for _, b := range t.GetBrokers() {
+		for _, target := range b.GetTargets() {
+			if target.CelFilter == "" {
+				continue
+			}
+			key := target.Key()
+			// Reuse the compiled filter if the expression hasn't changed.
+			if f, ok := old[key]; ok && f.expr == target.CelFilter {
+				filters[key] = f
+				continue
+			}
+			p, err := celfilter.Compile(target.CelFilter)
+			filters[key] = &compiledCELFilter{expr: target.CelFilter, program: p, err: err}
+		}
+	}
+	ct.celFilters.Store(filters)
 	ct.Value.Store(t)
 }
 
@@ -106,6 +135,20 @@ func (ct *CachedTargets) RangeBrokers(f func(*Broker) bool) {
 	}
 }
 
+// GetCELFilter returns the compiled CEL filter of the target, or nil if the target
+// doesn't have one.
+func (ct *CachedTargets) GetCELFilter(t *Target) (*celfilter.Program, error) {
+	if t.CelFilter == "" {
+		return nil, nil
+	}
+	filters, _ := ct.celFilters.Load().(map[string]*compiledCELFilter)
+	if f, ok := filters[t.Key()]; ok && f.expr == t.CelFilter {
+		return f.program, f.err
+	}
+	// The target wasn't stored by this cache, so the filter has to be compiled.
+	return celfilter.Compile(t.CelFilter)
+}
+
 // Bytes serializes all the targets.
 func (ct *CachedTargets) Bytes() ([]byte, error) {
 	val := ct.Load()
@@ -478,3 +478,63 @@ func TestGetBrokerOrTarget(t *testing.T) {
 		}
 	})
 }
+
+func TestCachedTargetsGetCELFilter(t *testing.T) {
+	val := &TargetsConfig{
+		Brokers: map[string]*Broker{
+			"ns/broker": {
+				Name:      "broker",
+				Namespace: "ns",
+				Targets: map[string]*Target{
+					"valid": {
+						Name:      "valid",
+						Namespace: "ns",
+						Broker:    "broker",
+						CelFilter: `ce.type == "foo"`,
+					},
+					"invalid": {
+						Name:      "invalid",
+						Namespace: "ns",
+						Broker:    "broker",
+						CelFilter: `ce.type ==`,
+					},
+					"none": {
+						Name:      "none",
+						Namespace: "ns",
+						Broker:    "broker",
+					},
+				},
+			},
+		},
+	}
+	targets := &CachedTargets{}
+	targets.Store(val)
+
+	valid, _ := targets.GetTarget("ns", "broker", "valid")
+	p, err := targets.GetCELFilter(valid)
+	if err != nil || p == nil {
+		t.Fatalf("GetCELFilter() got=(%v, %v), want a compiled filter", p, err)
+	}
+	// Storing the config again must not recompile an unchanged filter.
+	targets.Store(proto.Clone(val).(*TargetsConfig))
+	valid, _ = targets.GetTarget("ns", "broker", "valid")
+	if p2, _ := targets.GetCELFilter(valid); p2 != p {
+		t.Error("GetCELFilter() got a recompiled filter for an unchanged expression")
+	}
+
+	invalid, _ := targets.GetTarget("ns", "broker", "invalid")
+	if _, err := targets.GetCELFilter(invalid); err == nil {
+		t.Error("GetCELFilter() got nil error for an invalid expression")
+	}
+
+	none, _ := targets.GetTarget("ns", "broker", "none")
+	if p, err := targets.GetCELFilter(none); p != nil || err != nil {
+		t.Errorf("GetCELFilter() got=(%v, %v), want=(nil, nil)", p, err)
+	}
+
+	// A target which isn't stored in the cache is compiled on demand.
+	other := &Target{Name: "other", Namespace: "ns", Broker: "broker", CelFilter: `ce.source == "bar"`}
+	if p, err := targets.GetCELFilter(other); p == nil || err != nil {
+		t.Errorf("GetCELFilter() got=(%v, %v), want a compiled filter", p, err)
+	}
+}
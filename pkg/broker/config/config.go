@@ -19,6 +19,9 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/google/knative-gcp/pkg/utils/celfilter"
 )
 
 // ReadonlyTargets provides "read" functions for brokers and targets.
@@ -40,6 +43,9 @@ type ReadonlyTargets interface {
 	// RangeBrokers ranges over all brokers.
 	// Do not modify the given Broker copy.
 	RangeBrokers(func(*Broker) bool)
+	// GetCELFilter returns the compiled CEL filter of a target, or nil if
+	// the target doesn't have one.
+	GetCELFilter(*Target) (*celfilter.Program, error)
 	// Bytes serializes all the targets.
 	Bytes() ([]byte, error)
 	// String returns the text format of all the targets.
@@ -63,6 +69,21 @@ type BrokerMutation interface {
 	SetDecoupleQueue(q *Queue) BrokerMutation
 	// SetState sets the broker state.
 	SetState(s State) BrokerMutation
+	// SetOrderingKeyAttribute sets the event attribute the broker's ordering
+	// key is derived from. Empty disables ordering.
+	SetOrderingKeyAttribute(attribute string) BrokerMutation
+	// SetDeduplicationWindow sets the window within which the broker's events
+	// are deduplicated. Zero disables deduplication.
+	SetDeduplicationWindow(window time.Duration) BrokerMutation
+	// SetAllowedSenders sets the identities of the senders allowed to send
+	// events to the broker. Empty allows any sender.
+	SetAllowedSenders(senders []string) BrokerMutation
+	// SetDenyAllSenders sets whether every sender of the broker is rejected,
+	// regardless of its allowed senders.
+	SetDenyAllSenders(deny bool) BrokerMutation
+	// SetTransformerAddress sets the address of the transformer the broker's
+	// events are sent to before they're fanned out. Empty disables it.
+	SetTransformerAddress(address string) BrokerMutation
 	// UpsertTargets upserts Targets to the broker.
 	// The targets' namespace and broker will be forced to be
 	// the same as the broker's namespace and name.
@@ -106,3 +127,10 @@ func (t *Target) Key() string {
 func (b *Broker) Key() string {
 	return BrokerKey(b.Namespace, b.Name)
 }
+
+// SecretKey returns the key of the value of a secret header of a target in the Secret the
+// broker cell mounts in the data plane. Format is namespace_secretName_secretKey, which is
+// unambiguous as namespaces and Secret names can't contain underscores.
+func SecretKey(namespace string, ref *SecretKeyRef) string {
+	return fmt.Sprintf("%s_%s_%s", namespace, ref.Name, ref.Key)
+}
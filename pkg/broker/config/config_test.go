@@ -35,3 +35,11 @@ func TestTriggerKeyAndSplitTriggerKey(t *testing.T) {
 		t.Errorf("unexpected readiness: want %v, got %v", want, got)
 	}
 }
+
+func TestSecretKey(t *testing.T) {
+	want := "namespace_secret_api_key"
+	got := SecretKey("namespace", &SecretKeyRef{Name: "secret", Key: "api_key"})
+	if got != want {
+		t.Errorf("unexpected secret key: want %v, got %v", want, got)
+	}
+}
@@ -18,9 +18,11 @@ package memory
 
 import (
 	"sync"
+	"time"
 
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 type brokerMutation struct {
@@ -52,6 +54,40 @@ func (m *brokerMutation) SetState(s config.State) config.BrokerMutation {
 	return m
 }
 
+func (m *brokerMutation) SetOrderingKeyAttribute(attribute string) config.BrokerMutation {
+	m.delete = false
+	m.b.OrderingKeyAttribute = attribute
+	return m
+}
+
+func (m *brokerMutation) SetDeduplicationWindow(window time.Duration) config.BrokerMutation {
+	m.delete = false
+	if window > 0 {
+		m.b.DeduplicationWindow = durationpb.New(window)
+	} else {
+		m.b.DeduplicationWindow = nil
+	}
+	return m
+}
+
+func (m *brokerMutation) SetAllowedSenders(senders []string) config.BrokerMutation {
+	m.delete = false
+	m.b.AllowedSenders = senders
+	return m
+}
+
+func (m *brokerMutation) SetDenyAllSenders(deny bool) config.BrokerMutation {
+	m.delete = false
+	m.b.DenyAllSenders = deny
+	return m
+}
+
+func (m *brokerMutation) SetTransformerAddress(address string) config.BrokerMutation {
+	m.delete = false
+	m.b.TransformerAddress = address
+	return m
+}
+
 func (m *brokerMutation) UpsertTargets(targets ...*config.Target) config.BrokerMutation {
 	m.delete = false
 	if m.b.Targets == nil {
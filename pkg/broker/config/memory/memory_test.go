@@ -18,9 +18,11 @@ package memory
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 func TestNewEmptyTargets(t *testing.T) {
@@ -93,6 +95,52 @@ func TestMutateBroker(t *testing.T) {
 		assertBroker(t, wantBroker, "ns", "broker", targets)
 	})
 
+	t.Run("update broker ordering key attribute", func(t *testing.T) {
+		wantBroker.OrderingKeyAttribute = "subject"
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetOrderingKeyAttribute("subject")
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+	})
+
+	t.Run("update broker deduplication window", func(t *testing.T) {
+		wantBroker.DeduplicationWindow = durationpb.New(5 * time.Minute)
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetDeduplicationWindow(5 * time.Minute)
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+	})
+
+	t.Run("update broker allowed senders", func(t *testing.T) {
+		wantBroker.AllowedSenders = []string{"system:serviceaccount:ns:sa"}
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetAllowedSenders([]string{"system:serviceaccount:ns:sa"})
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+	})
+
+	t.Run("update broker deny all senders", func(t *testing.T) {
+		wantBroker.DenyAllSenders = true
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetDenyAllSenders(true)
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+
+		wantBroker.DenyAllSenders = false
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetDenyAllSenders(false)
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+	})
+
+	t.Run("update broker transformer address", func(t *testing.T) {
+		wantBroker.TransformerAddress = "http://transformer.ns.svc.cluster.local"
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetTransformerAddress("http://transformer.ns.svc.cluster.local")
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+	})
+
 	t1 := &config.Target{
 		Id:      "uid-1",
 		Address: "consumer1.example.com",
@@ -169,7 +217,9 @@ func TestMutateBroker(t *testing.T) {
 			// Delete should "delete" the broker.
 			m.Delete()
 			// Then make some changes which should "recreate" the broker.
-			m.SetID("b-uid").SetAddress("external.broker.example.com").SetState(config.State_READY)
+			m.SetID("b-uid").SetAddress("external.broker.example.com").SetState(config.State_READY).SetOrderingKeyAttribute("subject").SetDeduplicationWindow(5 * time.Minute)
+			m.SetAllowedSenders([]string{"system:serviceaccount:ns:sa"})
+			m.SetTransformerAddress("http://transformer.ns.svc.cluster.local")
 			m.SetDecoupleQueue(&config.Queue{
 				Topic:        "topic",
 				Subscription: "sub",
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/transformer"
 	"github.com/cloudevents/sdk-go/v2/event"
 	cetypes "github.com/cloudevents/sdk-go/v2/types"
 	"github.com/google/knative-gcp/pkg/logging"
@@ -56,6 +57,7 @@ func UpdateRemainingHops(ctx context.Context, event *event.Event, preemptiveHops
 	event.SetExtension(HopsAttribute, hops)
 }
 
+// SetRemainingHopsTransformer sets the remaining hops on a message sent to a broker.
 type SetRemainingHopsTransformer int32
 
 func (h SetRemainingHopsTransformer) Transform(_ binding.MessageMetadataReader, out binding.MessageMetadataWriter) error {
@@ -63,6 +65,10 @@ func (h SetRemainingHopsTransformer) Transform(_ binding.MessageMetadataReader,
 	return nil
 }
 
+// DeleteRemainingHopsTransformer deletes the remaining hops from a message sent outside of
+// the brokers, e.g. to a subscriber. The hops are internal to the brokers.
+var DeleteRemainingHopsTransformer = transformer.DeleteExtension(HopsAttribute)
+
 // GetRemainingHops returns the remaining hops of the event if it presents.
 // If there is no existing hops value or an invalid one, (0, false) will be returned.
 func GetRemainingHops(ctx context.Context, event *event.Event) (int32, bool) {
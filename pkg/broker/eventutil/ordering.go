@@ -0,0 +1,65 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"unicode/utf8"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+)
+
+// Pubsub rejects messages with ordering keys longer than 1KB.
+const maxOrderingKeyLength = 1024
+
+// OrderingKey returns the Pubsub ordering key of the event derived from the
+// given attribute or extension. An event without the attribute gets an empty
+// ordering key, i.e. it isn't ordered.
+func OrderingKey(e *event.Event, attribute string) string {
+	var key string
+	switch attribute {
+	case "":
+		return ""
+	case "id":
+		key = e.ID()
+	case "source":
+		key = e.Source()
+	case "type":
+		key = e.Type()
+	case "subject":
+		key = e.Subject()
+	default:
+		v, ok := e.Extensions()[attribute]
+		if !ok {
+			return ""
+		}
+		var err error
+		if key, err = cetypes.Format(v); err != nil {
+			return ""
+		}
+	}
+	// Truncating a key only puts more events in the same order. It is cut on a
+	// rune boundary since Pubsub rejects ordering keys that aren't valid UTF-8.
+	if len(key) > maxOrderingKeyLength {
+		i := maxOrderingKeyLength
+		for i > 0 && !utf8.RuneStart(key[i]) {
+			i--
+		}
+		key = key[:i]
+	}
+	return key
+}
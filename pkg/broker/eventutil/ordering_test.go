@@ -0,0 +1,91 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+func TestOrderingKey(t *testing.T) {
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	e.SetSubject("order-1")
+	e.SetExtension("orderid", "order-2")
+	e.SetExtension("sequence", 3)
+
+	long := e.Clone()
+	long.SetSubject(strings.Repeat("a", 2000))
+	// The 2-byte "é" spans the 1024th and 1025th bytes of the subject.
+	multiByte := e.Clone()
+	multiByte.SetSubject(strings.Repeat("a", maxOrderingKeyLength-1) + "ébc")
+
+	tests := []struct {
+		name      string
+		event     *event.Event
+		attribute string
+		want      string
+	}{{
+		name:  "no attribute",
+		event: &e,
+	}, {
+		name:      "subject",
+		event:     &e,
+		attribute: "subject",
+		want:      "order-1",
+	}, {
+		name:      "type",
+		event:     &e,
+		attribute: "type",
+		want:      "type",
+	}, {
+		name:      "string extension",
+		event:     &e,
+		attribute: "orderid",
+		want:      "order-2",
+	}, {
+		name:      "integer extension",
+		event:     &e,
+		attribute: "sequence",
+		want:      "3",
+	}, {
+		name:      "missing extension",
+		event:     &e,
+		attribute: "missing",
+	}, {
+		name:      "truncated key",
+		event:     &long,
+		attribute: "subject",
+		want:      strings.Repeat("a", maxOrderingKeyLength),
+	}, {
+		name:      "truncated key on a rune boundary",
+		event:     &multiByte,
+		attribute: "subject",
+		want:      strings.Repeat("a", maxOrderingKeyLength-1),
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := OrderingKey(test.event, test.attribute); got != test.want {
+				t.Errorf("OrderingKey got=%q, want=%q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"sync"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+const (
+	// maxTrackedMessages is the maximum number of messages whose delivery
+	// attempts are counted by a handler.
+	maxTrackedMessages = 10000
+
+	// trackedMessageTTL is how long the delivery attempts of a message are
+	// remembered since its last delivery. It is well above the maximum backoff
+	// of a pubsub retry policy.
+	trackedMessageTTL = time.Hour
+)
+
+// deliveryAttempts counts the delivery attempts of the pubsub messages received
+// by a handler. Pubsub only reports the delivery attempt of a message if its
+// subscription has a dead letter policy. The count is best effort: it starts
+// over if a message is redelivered to another handler.
+type deliveryAttempts struct {
+	mux      sync.Mutex
+	attempts *cache.LRUExpireCache
+}
+
+func newDeliveryAttempts() *deliveryAttempts {
+	return &deliveryAttempts{
+		attempts: cache.NewLRUExpireCache(maxTrackedMessages),
+	}
+}
+
+// next records a delivery of the message and returns its delivery attempt.
+func (a *deliveryAttempts) next(msg *pubsub.Message) int {
+	if msg.DeliveryAttempt != nil {
+		return *msg.DeliveryAttempt
+	}
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	attempt := 1
+	if prev, ok := a.attempts.Get(msg.ID); ok {
+		attempt = prev.(int) + 1
+	}
+	a.attempts.Add(msg.ID, attempt, trackedMessageTTL)
+	return attempt
+}
+
+// forget stops counting the delivery attempts of the message.
+func (a *deliveryAttempts) forget(msg *pubsub.Message) {
+	a.attempts.Remove(msg.ID)
+}
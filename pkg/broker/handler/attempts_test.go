@@ -0,0 +1,49 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+)
+
+func TestDeliveryAttempts(t *testing.T) {
+	a := newDeliveryAttempts()
+	msg1 := &pubsub.Message{ID: "1"}
+	msg2 := &pubsub.Message{ID: "2"}
+
+	for want := 1; want <= 3; want++ {
+		if got := a.next(msg1); got != want {
+			t.Errorf("delivery attempt of msg1 got=%d, want=%d", got, want)
+		}
+	}
+	if got := a.next(msg2); got != 1 {
+		t.Errorf("delivery attempt of msg2 got=%d, want=1", got)
+	}
+
+	a.forget(msg1)
+	if got := a.next(msg1); got != 1 {
+		t.Errorf("delivery attempt of forgotten msg1 got=%d, want=1", got)
+	}
+
+	// The delivery attempt reported by pubsub takes precedence.
+	reported := 5
+	if got := a.next(&pubsub.Message{ID: "2", DeliveryAttempt: &reported}); got != reported {
+		t.Errorf("delivery attempt of msg2 got=%d, want=%d", got, reported)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package auth provides the tokens the data plane authenticates to the
+// subscribers of triggers with.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/idtoken"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// cloudPlatformScope is the scope of the OAuth access tokens.
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// TokenSource provides the tokens attached to the requests to targets.
+type TokenSource interface {
+	// Token returns the token for the subscriber auth of a target.
+	Token(ctx context.Context, auth *config.SubscriberAuth) (string, error)
+}
+
+// Tokens is a TokenSource of Google-signed ID tokens and OAuth access tokens of the
+// default credentials, e.g. the workload identity of the broker. A token is reused
+// until it's about to expire, and then refreshed.
+type Tokens struct {
+	// ctx is used to refresh the tokens, as they outlive the requests they're
+	// created for.
+	ctx context.Context
+
+	newIDTokenSource     func(ctx context.Context, audience string) (oauth2.TokenSource, error)
+	newAccessTokenSource func(ctx context.Context) (oauth2.TokenSource, error)
+
+	mu           sync.Mutex
+	idTokens     map[string]oauth2.TokenSource
+	accessTokens oauth2.TokenSource
+}
+
+var _ TokenSource = (*Tokens)(nil)
+
+// NewTokens creates Tokens from the default credentials. The tokens are refreshed
+// with ctx.
+func NewTokens(ctx context.Context) *Tokens {
+	return &Tokens{
+		ctx: ctx,
+		newIDTokenSource: func(ctx context.Context, audience string) (oauth2.TokenSource, error) {
+			return idtoken.NewTokenSource(ctx, audience)
+		},
+		newAccessTokenSource: func(ctx context.Context) (oauth2.TokenSource, error) {
+			return google.DefaultTokenSource(ctx, cloudPlatformScope)
+		},
+		idTokens: make(map[string]oauth2.TokenSource),
+	}
+}
+
+// Token returns the token for the subscriber auth of a target, or an empty token if
+// the requests to the target aren't authenticated.
+func (t *Tokens) Token(_ context.Context, auth *config.SubscriberAuth) (string, error) {
+	var ts oauth2.TokenSource
+	var err error
+	switch auth.GetMode() {
+	case config.SubscriberAuth_NONE:
+		return "", nil
+	case config.SubscriberAuth_ID_TOKEN:
+		ts, err = t.idTokenSource(auth.Audience)
+	case config.SubscriberAuth_ACCESS_TOKEN:
+		ts, err = t.accessTokenSource()
+	default:
+		return "", fmt.Errorf("unknown subscriber auth mode %v", auth.GetMode())
+	}
+	if err != nil {
+		return "", err
+	}
+	token, err := ts.Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to get %v token: %w", auth.GetMode(), err)
+	}
+	// ID token sources also return the ID token as the access token.
+	return token.AccessToken, nil
+}
+
+func (t *Tokens) idTokenSource(audience string) (oauth2.TokenSource, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if ts, ok := t.idTokens[audience]; ok {
+		return ts, nil
+	}
+	ts, err := t.newIDTokenSource(t.ctx, audience)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ID token source for audience %q: %w", audience, err)
+	}
+	ts = oauth2.ReuseTokenSource(nil, ts)
+	t.idTokens[audience] = ts
+	return ts, nil
+}
+
+func (t *Tokens) accessTokenSource() (oauth2.TokenSource, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.accessTokens != nil {
+		return t.accessTokens, nil
+	}
+	ts, err := t.newAccessTokenSource(t.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create access token source: %w", err)
+	}
+	t.accessTokens = oauth2.ReuseTokenSource(nil, ts)
+	return t.accessTokens, nil
+}
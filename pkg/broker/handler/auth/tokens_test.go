@@ -0,0 +1,127 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// fakeTokenSource returns a token named after the source, counting the tokens it returns.
+type fakeTokenSource struct {
+	name   string
+	expiry time.Time
+	tokens int
+}
+
+func (s *fakeTokenSource) Token() (*oauth2.Token, error) {
+	s.tokens++
+	return &oauth2.Token{AccessToken: s.name, Expiry: s.expiry}, nil
+}
+
+func newFakeTokens() (*Tokens, map[string]*fakeTokenSource) {
+	sources := make(map[string]*fakeTokenSource)
+	tokens := NewTokens(context.Background())
+	tokens.newIDTokenSource = func(_ context.Context, audience string) (oauth2.TokenSource, error) {
+		if audience == "" {
+			return nil, errors.New("empty audience")
+		}
+		s := &fakeTokenSource{name: "id-" + audience, expiry: time.Now().Add(time.Hour)}
+		sources[s.name] = s
+		return s, nil
+	}
+	tokens.newAccessTokenSource = func(context.Context) (oauth2.TokenSource, error) {
+		// The access token is expired, so that it's refreshed every time.
+		s := &fakeTokenSource{name: "access", expiry: time.Now().Add(-time.Minute)}
+		sources[s.name] = s
+		return s, nil
+	}
+	return tokens, sources
+}
+
+func TestToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		auth    *config.SubscriberAuth
+		want    string
+		wantErr bool
+	}{{
+		name: "no subscriber auth",
+	}, {
+		name: "no auth mode",
+		auth: &config.SubscriberAuth{Mode: config.SubscriberAuth_NONE},
+	}, {
+		name: "ID token",
+		auth: &config.SubscriberAuth{Mode: config.SubscriberAuth_ID_TOKEN, Audience: "https://subscriber.a.run.app"},
+		want: "id-https://subscriber.a.run.app",
+	}, {
+		name:    "ID token without audience",
+		auth:    &config.SubscriberAuth{Mode: config.SubscriberAuth_ID_TOKEN},
+		wantErr: true,
+	}, {
+		name: "access token",
+		auth: &config.SubscriberAuth{Mode: config.SubscriberAuth_ACCESS_TOKEN},
+		want: "access",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tokens, _ := newFakeTokens()
+			got, err := tokens.Token(context.Background(), test.auth)
+			if (err != nil) != test.wantErr {
+				t.Errorf("Token error got=%v, wantErr=%v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("Token got=%q, want=%q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTokenCaching(t *testing.T) {
+	tokens, sources := newFakeTokens()
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		for _, audience := range []string{"a", "b"} {
+			if _, err := tokens.Token(ctx, &config.SubscriberAuth{Mode: config.SubscriberAuth_ID_TOKEN, Audience: audience}); err != nil {
+				t.Fatalf("unexpected error getting ID token: %v", err)
+			}
+		}
+		if _, err := tokens.Token(ctx, &config.SubscriberAuth{Mode: config.SubscriberAuth_ACCESS_TOKEN}); err != nil {
+			t.Fatalf("unexpected error getting access token: %v", err)
+		}
+	}
+
+	if len(sources) != 3 {
+		t.Errorf("token sources got=%d, want=3", len(sources))
+	}
+	// The valid ID tokens are reused.
+	for _, name := range []string{"id-a", "id-b"} {
+		if got := sources[name].tokens; got != 1 {
+			t.Errorf("%s tokens got=%d, want=1", name, got)
+		}
+	}
+	// The expired access token is refreshed.
+	if got := sources["access"].tokens; got != 3 {
+		t.Errorf("access tokens got=%d, want=3", got)
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package breaker provides the circuit breakers of the targets the data plane
+// delivers events to.
+package breaker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// State is the state of the circuit breaker of a target.
+type State int
+
+const (
+	// Closed lets the events be delivered to the target.
+	Closed State = iota
+	// Open short-circuits the delivery of the events to the target.
+	Open
+	// HalfOpen lets a single trial event be delivered to the target. The breaker is
+	// closed if the trial succeeds, and opened again otherwise.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "Closed"
+	case Open:
+		return "Open"
+	case HalfOpen:
+		return "HalfOpen"
+	default:
+		return "Unknown"
+	}
+}
+
+// Outcome is the outcome of the delivery of an event to a target.
+type Outcome int
+
+const (
+	// Success is a delivery the target handled, whether it accepted the event or not.
+	Success Outcome = iota
+	// Failure is a delivery the target didn't handle, e.g. because it's unreachable,
+	// timed out or is overloaded.
+	Failure
+	// Ignored is a delivery which failed before reaching the target, e.g. because
+	// the request couldn't be authenticated. It doesn't change the breaker state.
+	Ignored
+)
+
+// TargetState is the state of the circuit breaker of a target.
+type TargetState struct {
+	Namespace string
+	Name      string
+	State     State
+}
+
+// Breakers are the circuit breakers of the targets, keyed by target ID. A breaker
+// is opened after a number of consecutive failed deliveries, and half-opened once
+// it has been open for the open timeout.
+type Breakers struct {
+	failureThreshold int
+	openTimeout      time.Duration
+	// now is replaced in tests.
+	now func() time.Time
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+// breaker is the circuit breaker of a target. Only the targets with failed
+// deliveries have one, the others are closed.
+type breaker struct {
+	namespace string
+	name      string
+	state     State
+	failures  int
+	openedAt  time.Time
+	// trial is true while the trial delivery of a half-open breaker is in flight.
+	trial bool
+}
+
+// New creates Breakers which are opened after failureThreshold consecutive failed
+// deliveries, and half-opened after openTimeout.
+func New(failureThreshold int, openTimeout time.Duration) *Breakers {
+	return &Breakers{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		now:              time.Now,
+		breakers:         make(map[string]*breaker),
+	}
+}
+
+// Allow returns true if an event can be delivered to the target. When it does, the
+// outcome of the delivery must be recorded with Record.
+func (b *Breakers) Allow(target *config.Target) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	br, ok := b.breakers[target.Id]
+	if !ok {
+		return true
+	}
+	switch br.state {
+	case Open:
+		if b.now().Sub(br.openedAt) < b.openTimeout {
+			return false
+		}
+		br.state = HalfOpen
+		br.trial = true
+		return true
+	case HalfOpen:
+		if br.trial {
+			return false
+		}
+		br.trial = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Record records the outcome of a delivery to the target, and returns the state
+// of its breaker.
+func (b *Breakers) Record(target *config.Target, outcome Outcome) State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	br, ok := b.breakers[target.Id]
+	switch outcome {
+	case Success:
+		delete(b.breakers, target.Id)
+		return Closed
+	case Ignored:
+		if !ok {
+			return Closed
+		}
+		if br.state == HalfOpen && br.trial {
+			// Let another event be the trial.
+			br.trial = false
+		}
+		return br.state
+	}
+
+	if !ok {
+		br = &breaker{namespace: target.Namespace, name: target.Name}
+		b.breakers[target.Id] = br
+	}
+	switch br.state {
+	case Closed:
+		br.failures++
+		if br.failures >= b.failureThreshold {
+			b.open(br)
+		}
+	case HalfOpen:
+		b.open(br)
+	}
+	// The failures of the deliveries started before an open breaker was opened
+	// don't extend its open timeout.
+	return br.state
+}
+
+func (b *Breakers) open(br *breaker) {
+	br.state = Open
+	br.openedAt = b.now()
+	br.trial = false
+}
+
+// States returns the state of the breakers which aren't closed, keyed by target ID.
+func (b *Breakers) States() map[string]TargetState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	states := make(map[string]TargetState)
+	for id, br := range b.breakers {
+		if br.state != Closed {
+			states[id] = TargetState{Namespace: br.namespace, Name: br.name, State: br.state}
+		}
+	}
+	return states
+}
+
+// Retain removes the breakers of the targets which aren't in ids, e.g. because the
+// targets were deleted.
+func (b *Breakers) Retain(ids map[string]bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for id := range b.breakers {
+		if !ids[id] {
+			delete(b.breakers, id)
+		}
+	}
+}
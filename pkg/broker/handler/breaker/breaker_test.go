@@ -0,0 +1,150 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package breaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+var (
+	target = &config.Target{Id: "uid", Namespace: "ns", Name: "trigger"}
+	other  = &config.Target{Id: "other-uid", Namespace: "ns", Name: "other"}
+)
+
+// newTestBreakers returns Breakers with a clock advanced by the returned function.
+func newTestBreakers() (*Breakers, func(time.Duration)) {
+	b := New(3, time.Minute)
+	now := time.Unix(0, 0)
+	b.now = func() time.Time { return now }
+	return b, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreakers()
+	for i := 0; i < 2; i++ {
+		if !b.Allow(target) {
+			t.Fatalf("delivery %d not allowed", i)
+		}
+		if got := b.Record(target, Failure); got != Closed {
+			t.Errorf("state after %d failures got=%v, want=%v", i+1, got, Closed)
+		}
+	}
+	// A success resets the failure count.
+	b.Record(target, Success)
+	for i := 0; i < 2; i++ {
+		b.Record(target, Failure)
+	}
+	if !b.Allow(target) {
+		t.Fatal("delivery not allowed after 2 failures")
+	}
+	if got := b.Record(target, Failure); got != Open {
+		t.Errorf("state after 3 failures got=%v, want=%v", got, Open)
+	}
+	if b.Allow(target) {
+		t.Error("delivery allowed by open breaker")
+	}
+	if !b.Allow(other) {
+		t.Error("delivery to other target not allowed")
+	}
+}
+
+func TestBreakerHalfOpens(t *testing.T) {
+	b, advance := newTestBreakers()
+	for i := 0; i < 3; i++ {
+		b.Record(target, Failure)
+	}
+	advance(59 * time.Second)
+	if b.Allow(target) {
+		t.Fatal("delivery allowed before the open timeout")
+	}
+	advance(time.Second)
+	if !b.Allow(target) {
+		t.Fatal("trial delivery not allowed after the open timeout")
+	}
+	if b.Allow(target) {
+		t.Error("second delivery allowed while the trial is in flight")
+	}
+	if got := b.States()[target.Id].State; got != HalfOpen {
+		t.Errorf("state during trial got=%v, want=%v", got, HalfOpen)
+	}
+
+	// The trial fails: the breaker is opened again for the open timeout.
+	if got := b.Record(target, Failure); got != Open {
+		t.Errorf("state after failed trial got=%v, want=%v", got, Open)
+	}
+	advance(30 * time.Second)
+	if b.Allow(target) {
+		t.Error("delivery allowed after failed trial")
+	}
+	advance(30 * time.Second)
+	if !b.Allow(target) {
+		t.Fatal("second trial delivery not allowed")
+	}
+
+	// An ignored outcome lets another delivery be the trial.
+	if got := b.Record(target, Ignored); got != HalfOpen {
+		t.Errorf("state after ignored trial got=%v, want=%v", got, HalfOpen)
+	}
+	if !b.Allow(target) {
+		t.Fatal("trial delivery not allowed after ignored trial")
+	}
+
+	// The trial succeeds: the breaker is closed.
+	if got := b.Record(target, Success); got != Closed {
+		t.Errorf("state after successful trial got=%v, want=%v", got, Closed)
+	}
+	if !b.Allow(target) || !b.Allow(target) {
+		t.Error("delivery not allowed after successful trial")
+	}
+}
+
+func TestBreakerStates(t *testing.T) {
+	b, advance := newTestBreakers()
+	third := &config.Target{Id: "third-uid", Namespace: "ns", Name: "third"}
+	for i := 0; i < 3; i++ {
+		b.Record(target, Failure)
+		b.Record(third, Failure)
+	}
+	// Closed breakers aren't reported.
+	b.Record(other, Failure)
+	advance(time.Minute)
+	b.Allow(third)
+
+	want := map[string]TargetState{
+		"uid":       {Namespace: "ns", Name: "trigger", State: Open},
+		"third-uid": {Namespace: "ns", Name: "third", State: HalfOpen},
+	}
+	if diff := cmp.Diff(want, b.States()); diff != "" {
+		t.Errorf("States (-want,+got): %v", diff)
+	}
+
+	b.Retain(map[string]bool{"third-uid": true, "other-uid": true})
+	want = map[string]TargetState{
+		"third-uid": {Namespace: "ns", Name: "third", State: HalfOpen},
+	}
+	if diff := cmp.Diff(want, b.States()); diff != "" {
+		t.Errorf("States after Retain (-want,+got): %v", diff)
+	}
+	if got := b.Record(other, Failure); got != Closed {
+		t.Errorf("retained breaker state got=%v, want=%v", got, Closed)
+	}
+}
@@ -21,4 +21,6 @@ import "errors"
 var (
 	ErrTargetKeyNotPresent = errors.New("target key not present in the context")
 	ErrBrokerKeyNotPresent = errors.New("broker key not present in the context")
+
+	ErrDeliveryAttemptNotPresent = errors.New("delivery attempt not present in the context")
 )
@@ -34,6 +34,7 @@ import (
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/fanout"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/transform"
 	"github.com/google/knative-gcp/pkg/metrics"
 )
 
@@ -51,6 +52,8 @@ type FanoutPool struct {
 	// For sending retry events. We only need a shared client.
 	// And we can set retry topic dynamically.
 	deliverRetryClient ceclient.Client
+	// For sending the events of ordered targets to their retry topics.
+	orderedRetryPublisher *deliver.OrderedPublisher
 	// For initial events delivery. We only need a shared client.
 	// And we can set target address dynamically.
 	deliverClient *http.Client
@@ -107,12 +110,13 @@ func NewFanoutPool(
 		options.DeliveryTimeout = options.TimeoutPerEvent - (5 * time.Second)
 	}
 	p := &FanoutPool{
-		targets:            targets,
-		options:            options,
-		pubsubClient:       pubsubClient,
-		deliverClient:      deliverClient,
-		deliverRetryClient: retryClient,
-		statsReporter:      statsReporter,
+		targets:               targets,
+		options:               options,
+		pubsubClient:          pubsubClient,
+		deliverClient:         deliverClient,
+		deliverRetryClient:    retryClient,
+		orderedRetryPublisher: deliver.NewOrderedPublisher(pubsubClient),
+		statsReporter:         statsReporter,
 	}
 	return p, nil
 }
@@ -155,15 +159,26 @@ func (p *FanoutPool) SyncOnce(ctx context.Context) error {
 		h := NewHandler(
 			sub,
 			processors.ChainProcessors(
+				&transform.Processor{
+					DeliverClient:  p.deliverClient,
+					Targets:        p.targets,
+					DeliverTimeout: p.options.DeliveryTimeout,
+				},
 				&fanout.Processor{MaxConcurrency: p.options.MaxConcurrencyPerEvent, Targets: p.targets},
 				&filter.Processor{Targets: p.targets},
 				&deliver.Processor{
-					DeliverClient:      p.deliverClient,
-					Targets:            p.targets,
-					RetryOnFailure:     true,
-					DeliverRetryClient: p.deliverRetryClient,
-					DeliverTimeout:     p.options.DeliveryTimeout,
-					StatsReporter:      p.statsReporter,
+					DeliverClient:         p.deliverClient,
+					Targets:               p.targets,
+					RetryOnFailure:        true,
+					DeliverRetryClient:    p.deliverRetryClient,
+					OrderedRetryPublisher: p.orderedRetryPublisher,
+					DeliverTimeout:        p.options.DeliveryTimeout,
+					StatsReporter:         p.statsReporter,
+					Tokens:                p.options.SubscriberTokens,
+					CircuitBreakers:       p.options.CircuitBreakers,
+					Deliveries:            p.options.Deliveries,
+					ClaimChecks:           p.options.ClaimChecks,
+					HeaderSecretsDir:      p.options.HeaderSecretsDir,
 				},
 			),
 			p.options.TimeoutPerEvent,
@@ -186,5 +201,24 @@ func (p *FanoutPool) SyncOnce(ctx context.Context) error {
 		return true
 	})
 
+	// Stop publishing to the retry topics of deleted or no longer ordered targets,
+	// and forget the circuit breakers and deliveries of deleted targets.
+	orderedRetryTopics := make(map[string]bool)
+	targetIDs := make(map[string]bool)
+	p.targets.RangeAllTargets(func(t *config.Target) bool {
+		if t.OrderingKeyAttribute != "" && t.RetryQueue != nil {
+			orderedRetryTopics[t.RetryQueue.Topic] = true
+		}
+		targetIDs[t.Id] = true
+		return true
+	})
+	p.orderedRetryPublisher.StopUnusedTopics(orderedRetryTopics)
+	if p.options.CircuitBreakers != nil {
+		p.options.CircuitBreakers.Retain(targetIDs)
+	}
+	if p.options.Deliveries != nil {
+		p.options.Deliveries.Retain(targetIDs)
+	}
+
 	return nil
 }
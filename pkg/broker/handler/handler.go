@@ -25,7 +25,11 @@ import (
 	"cloud.google.com/go/pubsub"
 	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/google/knative-gcp/pkg/broker/handler/breaker"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/limiter"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
 	"github.com/google/knative-gcp/pkg/logging"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"go.uber.org/zap"
@@ -49,6 +53,18 @@ type Handler struct {
 
 	// alive is a bool indicator that the handler is still alive.
 	alive atomic.Value
+
+	// attempts counts the delivery attempts of the received messages.
+	attempts *deliveryAttempts
+
+	// window, when set, restricts the processed messages to the ones published
+	// within it. The other messages are acked without being processed.
+	window *publishWindow
+
+	// limiter, when set, limits the number of messages processed concurrently. The
+	// messages over the limit wait to be processed, which stops the subscription
+	// from pulling more messages once its outstanding messages limit is reached.
+	limiter *limiter.Limiter
 }
 
 // NewHandler creates a new Handler.
@@ -61,6 +77,7 @@ func NewHandler(
 		Subscription: sub,
 		Processor:    processor,
 		Timeout:      timeout,
+		attempts:     newDeliveryAttempts(),
 	}
 }
 
@@ -89,6 +106,10 @@ func (h *Handler) IsAlive() bool {
 
 // receive converts message to events and invoke processor chain.
 func (h *Handler) receive(ctx context.Context, msg *pubsub.Message) {
+	if h.window != nil && !h.window.contains(msg.PublishTime) {
+		msg.Ack()
+		return
+	}
 	ctx = metrics.StartEventProcessing(ctx)
 	event, err := binding.ToEvent(ctx, cepubsub.NewMessage(msg))
 	if isNonRetryable(err) {
@@ -104,17 +125,32 @@ func (h *Handler) receive(ctx context.Context, msg *pubsub.Message) {
 		return
 	}
 
+	if h.limiter != nil {
+		if err := h.limiter.Acquire(ctx); err != nil {
+			// The handler is stopped.
+			msg.Nack()
+			return
+		}
+	}
+
+	ctx = handlerctx.WithDeliveryAttempt(ctx, h.attempts.next(msg))
 	if h.Timeout != 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, h.Timeout)
 		defer cancel()
 	}
-	if err := h.Processor.Process(ctx, event); err != nil {
+	start := time.Now()
+	err = h.Processor.Process(ctx, event)
+	if h.limiter != nil {
+		h.limiter.Release(time.Since(start), deliver.Outcome(err) == breaker.Failure)
+	}
+	if err != nil {
 		logging.FromContext(ctx).Error("failed to process event", zap.String("eventID", event.ID()), zap.Error(err))
 		msg.Nack()
 		return
 	}
 
+	h.attempts.forget(msg)
 	msg.Ack()
 }
 
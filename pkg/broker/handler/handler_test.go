@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"sync"
 	"testing"
 	"time"
 
@@ -33,6 +34,7 @@ import (
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
 
+	"github.com/google/knative-gcp/pkg/broker/handler/limiter"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
 	kgcptesting "github.com/google/knative-gcp/pkg/testing"
 )
@@ -160,6 +162,94 @@ func TestHandler(t *testing.T) {
 	})
 }
 
+// concurrencyProcessor records the max number of events processed concurrently.
+type concurrencyProcessor struct {
+	processors.BaseProcessor
+
+	latency   time.Duration
+	processed chan struct{}
+
+	mu       sync.Mutex
+	inflight int
+	max      int
+}
+
+func (p *concurrencyProcessor) Process(ctx context.Context, e *event.Event) error {
+	p.mu.Lock()
+	p.inflight++
+	if p.inflight > p.max {
+		p.max = p.inflight
+	}
+	p.mu.Unlock()
+
+	time.Sleep(p.latency)
+
+	p.mu.Lock()
+	p.inflight--
+	p.mu.Unlock()
+	p.processed <- struct{}{}
+	return nil
+}
+
+func TestHandlerLimiter(t *testing.T) {
+	ctx := context.Background()
+	c, close := testPubsubClient(ctx, t, testProjectID)
+	defer close()
+
+	topic, err := c.CreateTopic(ctx, testTopic)
+	if err != nil {
+		t.Fatalf("failed to create topic: %v", err)
+	}
+	sub, err := c.CreateSubscription(ctx, testSub, pubsub.SubscriptionConfig{
+		Topic: topic,
+	})
+	if err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+	p, err := cepubsub.New(context.Background(),
+		cepubsub.WithClient(c),
+		cepubsub.WithProjectID(testProjectID),
+		cepubsub.WithTopicID(testTopic),
+	)
+	if err != nil {
+		t.Fatalf("failed to create cloudevents pubsub protocol: %v", err)
+	}
+
+	processor := &concurrencyProcessor{latency: 20 * time.Millisecond, processed: make(chan struct{})}
+	h := NewHandler(sub, processor, time.Second)
+	// The deliveries are slow, so the limit is decreased to the min.
+	h.limiter = limiter.New(limiter.Settings{InitialLimit: 2, MaxLimit: 2, SlowLatency: 10 * time.Millisecond}, nil)
+	h.Start(ctx, func(err error) {})
+	defer h.Stop()
+
+	const events = 6
+	for i := 0; i < events; i++ {
+		testEvent := event.New()
+		testEvent.SetID(fmt.Sprintf("id-%d", i))
+		testEvent.SetSource("source")
+		testEvent.SetType("type")
+		if err := p.Send(ctx, binding.ToMessage(&testEvent)); err != nil {
+			t.Fatalf("failed to seed event to pubsub: %v", err)
+		}
+	}
+	for i := 0; i < events; i++ {
+		select {
+		case <-processor.processed:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("processed %d events, want %d", i, events)
+		}
+	}
+
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+	if processor.max > 2 {
+		t.Errorf("max concurrently processed events got=%d, want at most 2", processor.max)
+	}
+	if got := h.limiter.Limit(); got != 1 {
+		t.Errorf("limit got=%d, want=1", got)
+	}
+}
+
 type BenchProcessor struct {
 	processors.BaseProcessor
 
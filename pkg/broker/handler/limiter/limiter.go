@@ -0,0 +1,163 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package limiter provides an adaptive limit of the events a handler processes
+// concurrently, so that slow subscribers aren't overloaded while fast ones are
+// delivered their backlog quickly.
+package limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultInitialLimit = 10
+	defaultMinLimit     = 1
+	defaultMaxLimit     = 1000
+	defaultSlowLatency  = 5 * time.Second
+	defaultBackoffRatio = 0.9
+)
+
+// Settings configures a Limiter. The zero values are replaced by defaults.
+type Settings struct {
+	// InitialLimit is the limit before any event is processed.
+	InitialLimit int
+	// MinLimit and MaxLimit bound the limit.
+	MinLimit int
+	MaxLimit int
+	// SlowLatency is the processing latency from which the subscriber is considered
+	// overloaded.
+	SlowLatency time.Duration
+	// BackoffRatio is the ratio the limit is multiplied by when the subscriber is
+	// overloaded, between 0 and 1.
+	BackoffRatio float64
+}
+
+func (s Settings) withDefaults() Settings {
+	if s.MinLimit <= 0 {
+		s.MinLimit = defaultMinLimit
+	}
+	if s.MaxLimit <= 0 {
+		s.MaxLimit = defaultMaxLimit
+	}
+	if s.MaxLimit < s.MinLimit {
+		s.MaxLimit = s.MinLimit
+	}
+	if s.InitialLimit <= 0 {
+		s.InitialLimit = defaultInitialLimit
+	}
+	if s.InitialLimit < s.MinLimit {
+		s.InitialLimit = s.MinLimit
+	}
+	if s.InitialLimit > s.MaxLimit {
+		s.InitialLimit = s.MaxLimit
+	}
+	if s.SlowLatency <= 0 {
+		s.SlowLatency = defaultSlowLatency
+	}
+	if s.BackoffRatio <= 0 || s.BackoffRatio >= 1 {
+		s.BackoffRatio = defaultBackoffRatio
+	}
+	return s
+}
+
+// Limiter limits the number of events processed concurrently. The limit is adapted
+// with additive increase, multiplicative decrease (AIMD): it's increased by one when
+// an event is processed while the limit is in use, and decreased by the backoff
+// ratio when the processing fails because the subscriber is overloaded, or is slower
+// than the slow latency.
+type Limiter struct {
+	settings Settings
+	// onChange is called with the new limit when it changes.
+	onChange func(limit int)
+
+	mu       sync.Mutex
+	limit    float64
+	inflight int
+	// released is closed and replaced when an event is released, to wake up the
+	// events waiting to be processed.
+	released chan struct{}
+}
+
+// New creates a Limiter. onChange, if not nil, is called with the new limit when it
+// changes.
+func New(settings Settings, onChange func(limit int)) *Limiter {
+	settings = settings.withDefaults()
+	return &Limiter{
+		settings: settings,
+		onChange: onChange,
+		limit:    float64(settings.InitialLimit),
+		released: make(chan struct{}),
+	}
+}
+
+// Acquire waits until an event can be processed, or ctx is done. When it returns
+// nil, Release must be called once the event is processed.
+func (l *Limiter) Acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.inflight < int(l.limit) {
+			l.inflight++
+			l.mu.Unlock()
+			return nil
+		}
+		released := l.released
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+// Release releases an event processed with the given latency. overloaded is true
+// if the processing failed because the subscriber is overloaded.
+func (l *Limiter) Release(latency time.Duration, overloaded bool) {
+	l.mu.Lock()
+	before := int(l.limit)
+	if overloaded || latency >= l.settings.SlowLatency {
+		l.limit *= l.settings.BackoffRatio
+		if l.limit < float64(l.settings.MinLimit) {
+			l.limit = float64(l.settings.MinLimit)
+		}
+	} else if l.inflight*2 >= int(l.limit) {
+		// Only increase the limit when it's in use, so that it doesn't grow unbounded
+		// while there are few events to process.
+		l.limit++
+		if l.limit > float64(l.settings.MaxLimit) {
+			l.limit = float64(l.settings.MaxLimit)
+		}
+	}
+	l.inflight--
+	close(l.released)
+	l.released = make(chan struct{})
+	// The new limit is reported while locked, so that the changes are reported in order.
+	if after := int(l.limit); after != before && l.onChange != nil {
+		l.onChange(after)
+	}
+	l.mu.Unlock()
+}
+
+// Limit returns the current limit.
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit)
+}
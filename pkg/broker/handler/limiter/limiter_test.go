@@ -0,0 +1,130 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSettingsDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		want     Settings
+	}{{
+		name: "defaults",
+		want: Settings{InitialLimit: 10, MinLimit: 1, MaxLimit: 1000, SlowLatency: 5 * time.Second, BackoffRatio: 0.9},
+	}, {
+		name:     "initial limit above max",
+		settings: Settings{InitialLimit: 20, MaxLimit: 5, SlowLatency: time.Second, BackoffRatio: 0.5},
+		want:     Settings{InitialLimit: 5, MinLimit: 1, MaxLimit: 5, SlowLatency: time.Second, BackoffRatio: 0.5},
+	}, {
+		name:     "min limit above max",
+		settings: Settings{MinLimit: 20, MaxLimit: 5, BackoffRatio: 2},
+		want:     Settings{InitialLimit: 20, MinLimit: 20, MaxLimit: 20, SlowLatency: 5 * time.Second, BackoffRatio: 0.9},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.settings.withDefaults()); diff != "" {
+				t.Errorf("withDefaults (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestLimiterAIMD(t *testing.T) {
+	var changes []int
+	l := New(Settings{InitialLimit: 4, MinLimit: 2, MaxLimit: 6, SlowLatency: time.Second, BackoffRatio: 0.5}, func(limit int) {
+		changes = append(changes, limit)
+	})
+	ctx := context.Background()
+
+	// The limit isn't increased while it's not in use.
+	l.Acquire(ctx)
+	l.Release(time.Millisecond, false)
+	if got := l.Limit(); got != 4 {
+		t.Errorf("limit after underused success got=%d, want=4", got)
+	}
+
+	// The limit is increased by one per success while in use, up to the max.
+	for i := 0; i < 4; i++ {
+		if err := l.Acquire(ctx); err != nil {
+			t.Fatalf("Acquire got unexpected error: %v", err)
+		}
+	}
+	for i := 0; i < 4; i++ {
+		l.Release(time.Millisecond, false)
+	}
+	if got := l.Limit(); got != 6 {
+		t.Errorf("limit after successes got=%d, want=6", got)
+	}
+
+	// The limit is decreased on overload and slow deliveries, down to the min.
+	l.Acquire(ctx)
+	l.Release(time.Millisecond, true)
+	if got := l.Limit(); got != 3 {
+		t.Errorf("limit after overload got=%d, want=3", got)
+	}
+	l.Acquire(ctx)
+	l.Release(time.Second, false)
+	if got := l.Limit(); got != 2 {
+		t.Errorf("limit after slow delivery got=%d, want=2", got)
+	}
+
+	if diff := cmp.Diff([]int{5, 6, 3, 2}, changes); diff != "" {
+		t.Errorf("limit changes (-want,+got): %v", diff)
+	}
+}
+
+func TestLimiterAcquireWaits(t *testing.T) {
+	l := New(Settings{InitialLimit: 1, MaxLimit: 1}, nil)
+	ctx := context.Background()
+	if err := l.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire got unexpected error: %v", err)
+	}
+
+	// The limit is reached, so Acquire waits until ctx is done.
+	cctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := l.Acquire(cctx); err != context.DeadlineExceeded {
+		t.Errorf("Acquire got error=%v, want=%v", err, context.DeadlineExceeded)
+	}
+
+	// Or until an event is released.
+	acquired := make(chan error)
+	go func() {
+		acquired <- l.Acquire(ctx)
+	}()
+	select {
+	case err := <-acquired:
+		t.Fatalf("Acquire returned before release with error %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	l.Release(time.Millisecond, false)
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Errorf("Acquire got unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Acquire didn't return after release")
+	}
+}
@@ -21,6 +21,12 @@ import (
 	"time"
 
 	"cloud.google.com/go/pubsub"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	"github.com/google/knative-gcp/pkg/broker/handler/auth"
+	"github.com/google/knative-gcp/pkg/broker/handler/breaker"
+	"github.com/google/knative-gcp/pkg/broker/handler/limiter"
+	"github.com/google/knative-gcp/pkg/broker/status"
 )
 
 var (
@@ -50,6 +56,25 @@ type Options struct {
 	DeliveryTimeout time.Duration
 	// PubsubReceiveSettings is the pubsub receive settings.
 	PubsubReceiveSettings pubsub.ReceiveSettings
+	// SubscriberTokens provides the tokens to authenticate to the subscribers
+	// of triggers with a subscriber auth.
+	SubscriberTokens auth.TokenSource
+	// CircuitBreakers short-circuit the fanout of events to the subscribers
+	// which keep failing. If nil, events are always delivered.
+	CircuitBreakers *breaker.Breakers
+	// AdaptiveConcurrency, when set, configures the adaptive limit of the events
+	// each retry handler delivers concurrently. If the max limit isn't set, it's
+	// the max outstanding messages of the pubsub receive settings.
+	AdaptiveConcurrency *limiter.Settings
+	// Deliveries records the last successful and failed deliveries to the
+	// subscribers. If nil, the deliveries aren't recorded.
+	Deliveries *status.Deliveries
+	// ClaimChecks loads the data the ingress offloaded to Cloud Storage before
+	// the events are delivered. If nil, events are delivered as received.
+	ClaimChecks *claimcheck.Store
+	// HeaderSecretsDir is the directory the Secret holding the values of the secret
+	// headers of the targets is mounted at.
+	HeaderSecretsDir string
 }
 
 // NewOptions creates a Options.
@@ -107,3 +132,45 @@ func WithDeliveryTimeout(t time.Duration) Option {
 		o.DeliveryTimeout = t
 	}
 }
+
+// WithSubscriberTokens sets the SubscriberTokens.
+func WithSubscriberTokens(tokens auth.TokenSource) Option {
+	return func(o *Options) {
+		o.SubscriberTokens = tokens
+	}
+}
+
+// WithCircuitBreakers sets the CircuitBreakers.
+func WithCircuitBreakers(b *breaker.Breakers) Option {
+	return func(o *Options) {
+		o.CircuitBreakers = b
+	}
+}
+
+// WithAdaptiveConcurrency sets the AdaptiveConcurrency.
+func WithAdaptiveConcurrency(s limiter.Settings) Option {
+	return func(o *Options) {
+		o.AdaptiveConcurrency = &s
+	}
+}
+
+// WithDeliveries sets the Deliveries.
+func WithDeliveries(d *status.Deliveries) Option {
+	return func(o *Options) {
+		o.Deliveries = d
+	}
+}
+
+// WithClaimChecks sets the ClaimChecks.
+func WithClaimChecks(s *claimcheck.Store) Option {
+	return func(o *Options) {
+		o.ClaimChecks = s
+	}
+}
+
+// WithHeaderSecretsDir sets the HeaderSecretsDir.
+func WithHeaderSecretsDir(dir string) Option {
+	return func(o *Options) {
+		o.HeaderSecretsDir = dir
+	}
+}
@@ -17,11 +17,19 @@ limitations under the License.
 package handler
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"cloud.google.com/go/pubsub"
 	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	"github.com/google/knative-gcp/pkg/broker/handler/auth"
+	"github.com/google/knative-gcp/pkg/broker/handler/breaker"
+	"github.com/google/knative-gcp/pkg/broker/handler/limiter"
+	"github.com/google/knative-gcp/pkg/broker/status"
+	gstorage "github.com/google/knative-gcp/pkg/gclient/storage/testing"
 )
 
 func TestWithHandlerConcurrency(t *testing.T) {
@@ -96,3 +104,73 @@ func TestWithDeliveryTimeout(t *testing.T) {
 		t.Errorf("options timeout per event got=%v, want=%v", opt.DeliveryTimeout, want)
 	}
 }
+
+func TestWithSubscriberTokens(t *testing.T) {
+	want := auth.NewTokens(context.Background())
+	opt, err := NewOptions(WithSubscriberTokens(want))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if opt.SubscriberTokens != want {
+		t.Errorf("options subscriber tokens got=%v, want=%v", opt.SubscriberTokens, want)
+	}
+}
+
+func TestWithCircuitBreakers(t *testing.T) {
+	want := breaker.New(5, time.Minute)
+	opt, err := NewOptions(WithCircuitBreakers(want))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if opt.CircuitBreakers != want {
+		t.Errorf("options circuit breakers got=%v, want=%v", opt.CircuitBreakers, want)
+	}
+}
+
+func TestWithAdaptiveConcurrency(t *testing.T) {
+	want := limiter.Settings{InitialLimit: 5, SlowLatency: time.Second}
+	opt, err := NewOptions(WithAdaptiveConcurrency(want))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(&want, opt.AdaptiveConcurrency); diff != "" {
+		t.Errorf("options adaptive concurrency (-want,+got): %v", diff)
+	}
+}
+
+func TestWithDeliveries(t *testing.T) {
+	want := status.NewDeliveries()
+	opt, err := NewOptions(WithDeliveries(want))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if opt.Deliveries != want {
+		t.Errorf("options deliveries got=%v, want=%v", opt.Deliveries, want)
+	}
+}
+
+func TestWithClaimChecks(t *testing.T) {
+	client, err := gstorage.TestClientCreator(gstorage.TestClientData{})(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := claimcheck.NewStore(client, "bucket", 100)
+	opt, err := NewOptions(WithClaimChecks(want))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if opt.ClaimChecks != want {
+		t.Errorf("options claim checks got=%v, want=%v", opt.ClaimChecks, want)
+	}
+}
+
+func TestWithHeaderSecretsDir(t *testing.T) {
+	want := "/var/run/secrets"
+	opt, err := NewOptions(WithHeaderSecretsDir(want))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if opt.HeaderSecretsDir != want {
+		t.Errorf("options header secrets dir got=%v, want=%v", opt.HeaderSecretsDir, want)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"context"
+	"sync"
+
+	"cloud.google.com/go/pubsub"
+	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"go.opencensus.io/trace"
+)
+
+// OrderedPublisher publishes events to Pubsub topics with ordering keys.
+// The cloudevents Pubsub protocol doesn't support ordering keys.
+type OrderedPublisher struct {
+	client *pubsub.Client
+
+	mu     sync.Mutex
+	topics map[string]*pubsub.Topic
+}
+
+// NewOrderedPublisher creates a new OrderedPublisher.
+func NewOrderedPublisher(client *pubsub.Client) *OrderedPublisher {
+	return &OrderedPublisher{
+		client: client,
+		topics: make(map[string]*pubsub.Topic),
+	}
+}
+
+// Publish publishes the event to the topic with the ordering key and waits for
+// the result. Events with the same ordering key are published in the order of
+// the calls.
+func (p *OrderedPublisher) Publish(ctx context.Context, topicID, orderingKey string, e *event.Event) error {
+	dt := extensions.FromSpanContext(trace.FromContext(ctx).SpanContext())
+	msg := &pubsub.Message{OrderingKey: orderingKey}
+	if err := cepubsub.WritePubSubMessage(ctx, binding.ToMessage(e), msg, dt.WriteTransformer()); err != nil {
+		return err
+	}
+	topic := p.getTopic(topicID)
+	if _, err := topic.Publish(ctx, msg).Get(ctx); err != nil {
+		// Publishing with the ordering key is paused after a failure. The event
+		// is received again as the error is returned, so resume publishing.
+		topic.ResumePublish(orderingKey)
+		return err
+	}
+	return nil
+}
+
+// StopUnusedTopics stops publishing to the topics which aren't in use.
+func (p *OrderedPublisher) StopUnusedTopics(inUse map[string]bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, topic := range p.topics {
+		if !inUse[id] {
+			topic.Stop()
+			delete(p.topics, id)
+		}
+	}
+}
+
+func (p *OrderedPublisher) getTopic(topicID string) *pubsub.Topic {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	topic, ok := p.topics[topicID]
+	if !ok {
+		topic = p.client.Topic(topicID)
+		topic.EnableMessageOrdering = true
+		p.topics[topicID] = topic
+	}
+	return topic
+}
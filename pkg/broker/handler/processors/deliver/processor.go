@@ -20,12 +20,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
-	"github.com/cloudevents/sdk-go/v2/binding/transformer"
 	ceclient "github.com/cloudevents/sdk-go/v2/client"
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
 	"github.com/cloudevents/sdk-go/v2/event"
@@ -34,14 +36,57 @@ import (
 	"go.opencensus.io/trace"
 	"go.uber.org/zap"
 
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	"github.com/google/knative-gcp/pkg/broker/handler/auth"
+	"github.com/google/knative-gcp/pkg/broker/handler/breaker"
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/broker/status"
 	"github.com/google/knative-gcp/pkg/metrics"
 )
 
-const defaultEventHopsLimit int32 = 255
+const (
+	defaultEventHopsLimit int32 = 255
+
+	// Extensions attached to the events sent to the dead letter address. They
+	// follow the extensions used by the Knative eventing dispatchers.
+	errorDestExtension = "knativeerrordest"
+	errorCodeExtension = "knativeerrorcode"
+	errorDataExtension = "knativeerrordata"
+)
+
+var (
+	// errTargetPaused is returned when an event is received for a paused target.
+	errTargetPaused = errors.New("target is paused")
+	// errCircuitOpen is returned when an event isn't delivered because the circuit
+	// breaker of the target is open.
+	errCircuitOpen = errors.New("circuit breaker of target is open")
+)
+
+// statusCodeError is returned when the target responds with a non 2xx status code.
+type statusCodeError struct {
+	code int
+}
+
+func (e *statusCodeError) Error() string {
+	return fmt.Sprintf("event delivery failed: HTTP status code %d", e.code)
+}
+
+// unreachableError is returned when the request to the target fails without a
+// response, e.g. because of a network error or a timeout.
+type unreachableError struct {
+	err error
+}
+
+func (e *unreachableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *unreachableError) Unwrap() error {
+	return e.err
+}
 
 // Processor delivers events based on the broker/target in the context.
 type Processor struct {
@@ -61,12 +106,39 @@ type Processor struct {
 	// to the retry topic.
 	DeliverRetryClient ceclient.Client
 
+	// OrderedRetryPublisher publishes the events of targets with ordered delivery
+	// to their retry topics with an ordering key. Only used if RetryOnFailure is
+	// set to true. If nil, events are delivered to such targets directly.
+	OrderedRetryPublisher *OrderedPublisher
+
 	// DeliverTimeout is the timeout applied to cancel delivery.
 	// If zero, not additional timeout is applied.
 	DeliverTimeout time.Duration
 
 	// StatsReporter is used to report delivery metrics.
 	StatsReporter *metrics.DeliveryReporter
+
+	// Tokens provides the tokens attached to the requests to the targets with a
+	// subscriber auth. If nil, the requests aren't authenticated.
+	Tokens auth.TokenSource
+
+	// CircuitBreakers short-circuit the delivery of events to the targets which keep
+	// failing. Only used if RetryOnFailure is set to true: while the breaker of a
+	// target is open, its events are sent to its retry topic without being delivered.
+	// If nil, events are always delivered.
+	CircuitBreakers *breaker.Breakers
+
+	// Deliveries records the last successful and failed deliveries to the targets,
+	// which are reported on the triggers. If nil, the deliveries aren't recorded.
+	Deliveries *status.Deliveries
+
+	// ClaimChecks loads the data of the events offloaded to Cloud Storage by the ingress
+	// before they're delivered. If nil, the events are delivered with their claim check.
+	ClaimChecks *claimcheck.Store
+
+	// HeaderSecretsDir is the directory the Secret holding the values of the secret
+	// headers of the targets is mounted at, with a file per config.SecretKey.
+	HeaderSecretsDir string
 }
 
 var _ processors.Interface = (*Processor)(nil)
@@ -102,6 +174,27 @@ func (p *Processor) Process(ctx context.Context, e *event.Event) error {
 		return nil
 	}
 
+	if target.State == config.State_PAUSED {
+		if !p.RetryOnFailure {
+			// The event stays in the retry queue until the target is resumed.
+			return errTargetPaused
+		}
+		// The events of a paused target are queued in its retry queue without
+		// being delivered.
+		trace.FromContext(ctx).Annotate(ceclient.EventTraceAttributes(e), "enqueueing for retry: trigger is paused")
+		if p.OrderedRetryPublisher != nil && target.OrderingKeyAttribute != "" {
+			return p.sendToOrderedRetryTopic(ctx, target, e)
+		}
+		return p.sendToRetryTopic(ctx, target, e)
+	}
+
+	if p.RetryOnFailure && p.OrderedRetryPublisher != nil && target.OrderingKeyAttribute != "" {
+		// Events are only delivered to ordered targets from their ordered retry
+		// queue, so that a failed event isn't overtaken by a later event with the
+		// same ordering key.
+		return p.sendToOrderedRetryTopic(ctx, target, e)
+	}
+
 	// Hops is a broker local counter so remove any hops value before forwarding.
 	// Do not modify the original event as we need to send the original
 	// event to retry queue on failure.
@@ -121,13 +214,40 @@ func (p *Processor) Process(ctx context.Context, e *event.Event) error {
 	p.StatsReporter.FinishEventProcessing(ctx)
 
 	dctx := ctx
-	if p.DeliverTimeout > 0 {
+	if timeout := p.deliverTimeout(target); timeout > 0 {
 		var cancel context.CancelFunc
-		dctx, cancel = context.WithTimeout(dctx, p.DeliverTimeout)
+		dctx, cancel = context.WithTimeout(dctx, timeout)
 		defer cancel()
 	}
 
-	if err := p.deliver(dctx, target, broker, eventutil.NewImmutableEventMessage(e), hops); err != nil {
+	// The event sent to the retry topic keeps its claim check. The dead letter
+	// address receives the loaded event, or the claim check if its data failed
+	// to be loaded.
+	de, err := p.loadClaimCheck(dctx, e)
+	if err == nil {
+		if p.RetryOnFailure && p.CircuitBreakers != nil {
+			err = p.deliverWithBreaker(ctx, dctx, target, broker, de, hops)
+		} else {
+			err = p.deliver(dctx, target, broker, eventutil.NewImmutableEventMessage(de), hops)
+		}
+		p.recordDelivery(target, err)
+	}
+	if err != nil {
+		if p.retriesExhausted(ctx, target) {
+			if target.DeliverySpec.GetDeadLetterAddress() != "" {
+				logging.FromContext(ctx).Warn("target delivery failed, sending event to dead letter address", zap.String("target", tk), zap.Error(err))
+				if de == nil {
+					de = e
+				}
+				return p.sendToDeadLetter(ctx, target, de, err)
+			}
+			logging.FromContext(ctx).Warn("target delivery failed, dropping event after exhausting retries", zap.String("target", tk), zap.Error(err))
+			trace.FromContext(ctx).Annotate(
+				[]trace.Attribute{trace.StringAttribute("error_message", err.Error())},
+				"event dropped: retries exhausted",
+			)
+			return nil
+		}
 		if !p.RetryOnFailure {
 			return err
 		}
@@ -144,18 +264,92 @@ func (p *Processor) Process(ctx context.Context, e *event.Event) error {
 	return p.Next().Process(ctx, e)
 }
 
-// deliver delivers msg to target and sends the target's reply to the broker ingress.
+// loadClaimCheck returns the event with its data loaded if it was offloaded by the ingress.
+func (p *Processor) loadClaimCheck(ctx context.Context, e *event.Event) (*event.Event, error) {
+	if p.ClaimChecks == nil {
+		return e, nil
+	}
+	de, err := p.ClaimChecks.Load(ctx, e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the data of the event: %w", err)
+	}
+	return de, nil
+}
+
+// deliverWithBreaker delivers the event to the target unless its circuit breaker is
+// open, and records the outcome of the delivery in the breaker.
+func (p *Processor) deliverWithBreaker(ctx, dctx context.Context, target *config.Target, broker *config.Broker, e *event.Event, hops int32) error {
+	if !p.CircuitBreakers.Allow(target) {
+		return errCircuitOpen
+	}
+	err := p.deliver(dctx, target, broker, eventutil.NewImmutableEventMessage(e), hops)
+	state := p.CircuitBreakers.Record(target, Outcome(err))
+	p.StatsReporter.ReportCircuitBreakerState(ctx, int(state))
+	return err
+}
+
+// recordDelivery records the delivery to the target which returned err. Only the
+// errors of the target are recorded, e.g. not the events short-circuited by its
+// circuit breaker or the failures to send its reply.
+func (p *Processor) recordDelivery(target *config.Target, err error) {
+	if p.Deliveries == nil {
+		return
+	}
+	if err == nil {
+		p.Deliveries.RecordSuccess(target, time.Now())
+		return
+	}
+	var sce *statusCodeError
+	if errors.As(err, &sce) {
+		p.Deliveries.RecordFailure(target, time.Now(), err, sce.code)
+		return
+	}
+	var ue *unreachableError
+	if errors.As(err, &ue) {
+		p.Deliveries.RecordFailure(target, time.Now(), err, 0)
+	}
+}
+
+// Outcome returns the outcome of a delivery which returned err, e.g. for the circuit
+// breaker of the target. The delivery fails if the target is unreachable, times out,
+// is overloaded or responds with a server error. Other errors, e.g. the failure to
+// send the reply, aren't caused by the target.
+func Outcome(err error) breaker.Outcome {
+	if err == nil {
+		return breaker.Success
+	}
+	var sce *statusCodeError
+	if errors.As(err, &sce) {
+		if sce.code >= 500 || sce.code == http.StatusTooManyRequests {
+			return breaker.Failure
+		}
+		return breaker.Success
+	}
+	var ue *unreachableError
+	if errors.As(err, &ue) {
+		return breaker.Failure
+	}
+	return breaker.Ignored
+}
+
+// deliver delivers msg to target and sends the target's reply to the target's reply
+// address, or to the broker ingress if the target doesn't have one.
 func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *config.Broker, msg binding.Message, hops int32) error {
-	startTime := time.Now()
+	header, err := p.targetHeader(ctx, target)
+	if err != nil {
+		return err
+	}
 	// Remove hops from forwarded event.
-	resp, err := p.sendMsg(ctx, target.Address, msg, transformer.DeleteExtension(eventutil.HopsAttribute))
+	transformers := append([]binding.Transformer{eventutil.DeleteRemainingHopsTransformer}, extensionTransformers(target)...)
+	startTime := time.Now()
+	resp, err := p.sendMsg(ctx, target.Address, header, msg, transformers...)
 	if err != nil {
 		var result *url.Error
 		if errors.As(err, &result) && result.Timeout() {
 			// If the delivery is cancelled because of timeout, report event dispatch time without resp status code.
 			p.StatsReporter.ReportEventDispatchTime(ctx, time.Since(startTime))
 		}
-		return err
+		return &unreachableError{err: err}
 	}
 
 	defer func() {
@@ -173,7 +367,12 @@ func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *
 	p.StatsReporter.ReportEventDispatchTime(cctx, time.Since(startTime))
 
 	if resp.StatusCode/100 != 2 {
-		return fmt.Errorf("event delivery failed: HTTP status code %d", resp.StatusCode)
+		return &statusCodeError{code: resp.StatusCode}
+	}
+
+	if target.DropReplies {
+		trace.FromContext(ctx).Annotate(nil, "event reply dropped: trigger drops replies")
+		return nil
 	}
 
 	respMsg := cehttp.NewMessageFromHttpResponse(resp)
@@ -226,8 +425,18 @@ func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *
 		return nil
 	}
 
-	// Attach the previous hops for the reply.
-	replyResp, err := p.sendMsg(ctx, broker.Address, respMsg, eventutil.SetRemainingHopsTransformer(hops))
+	// Attach the previous hops for the reply. They're kept when the reply is sent to
+	// another broker so that events looping across brokers are eventually dropped, and
+	// removed when it's sent to any other destination.
+	replyAddress := broker.Address
+	var hopsTransformer binding.Transformer = eventutil.SetRemainingHopsTransformer(hops)
+	if target.ReplyAddress != "" {
+		replyAddress = target.ReplyAddress
+		if !target.ReplyToBroker {
+			hopsTransformer = eventutil.DeleteRemainingHopsTransformer
+		}
+	}
+	replyResp, err := p.sendMsg(ctx, replyAddress, nil, respMsg, hopsTransformer)
 	if err != nil {
 		return err
 	}
@@ -237,7 +446,7 @@ func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *
 	return nil
 }
 
-func (p *Processor) sendMsg(ctx context.Context, address string, msg binding.Message, transformers ...binding.Transformer) (*http.Response, error) {
+func (p *Processor) sendMsg(ctx context.Context, address string, header http.Header, msg binding.Message, transformers ...binding.Transformer) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, nil)
 	if err != nil {
 		return nil, err
@@ -245,9 +454,53 @@ func (p *Processor) sendMsg(ctx context.Context, address string, msg binding.Mes
 	if err := cehttp.WriteRequest(ctx, msg, req, transformers...); err != nil {
 		return nil, err
 	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
 	return p.DeliverClient.Do(req)
 }
 
+// targetHeader returns the additional header of the requests to the target: its custom
+// headers, and the Authorization header if the requests are authenticated.
+func (p *Processor) targetHeader(ctx context.Context, target *config.Target) (http.Header, error) {
+	header := make(http.Header, len(target.Headers)+len(target.SecretHeaders)+1)
+	for k, v := range target.Headers {
+		header.Set(k, v)
+	}
+	for k, ref := range target.SecretHeaders {
+		v, err := ioutil.ReadFile(filepath.Join(p.HeaderSecretsDir, config.SecretKey(target.Namespace, ref)))
+		if os.IsNotExist(err) && ref.Optional {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the secret of header %q: %w", k, err)
+		}
+		header.Set(k, string(v))
+	}
+	if p.Tokens == nil || target.SubscriberAuth.GetMode() == config.SubscriberAuth_NONE {
+		return header, nil
+	}
+	token, err := p.Tokens.Token(ctx, target.SubscriberAuth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate to target: %w", err)
+	}
+	header.Set("Authorization", "Bearer "+token)
+	return header, nil
+}
+
+// extensionTransformers returns the transformers setting the extensions of the target on
+// the delivered events, overwriting the extensions of the events with the same names.
+func extensionTransformers(target *config.Target) []binding.Transformer {
+	transformers := make([]binding.Transformer, 0, len(target.Extensions))
+	for name, value := range target.Extensions {
+		name, value := name, value
+		transformers = append(transformers, binding.TransformerFunc(func(_ binding.MessageMetadataReader, out binding.MessageMetadataWriter) error {
+			return out.SetExtension(name, value)
+		}))
+	}
+	return transformers
+}
+
 func (p *Processor) sendToRetryTopic(ctx context.Context, target *config.Target, event *event.Event) error {
 	pctx := cecontext.WithTopic(ctx, target.RetryQueue.Topic)
 	if err := p.DeliverRetryClient.Send(pctx, *event); err != nil {
@@ -255,3 +508,68 @@ func (p *Processor) sendToRetryTopic(ctx context.Context, target *config.Target,
 	}
 	return nil
 }
+
+func (p *Processor) sendToOrderedRetryTopic(ctx context.Context, target *config.Target, e *event.Event) error {
+	orderingKey := eventutil.OrderingKey(e, target.OrderingKeyAttribute)
+	if err := p.OrderedRetryPublisher.Publish(ctx, target.RetryQueue.Topic, orderingKey, e); err != nil {
+		return fmt.Errorf("failed to send event to retry topic: %w", err)
+	}
+	return nil
+}
+
+// deliverTimeout returns the timeout of the target if set, otherwise the
+// processor's DeliverTimeout.
+func (p *Processor) deliverTimeout(target *config.Target) time.Duration {
+	if timeout := target.DeliverySpec.GetTimeout(); timeout != nil {
+		return timeout.AsDuration()
+	}
+	return p.DeliverTimeout
+}
+
+// retriesExhausted returns true if the event should not be retried anymore
+// according to the target's delivery spec. Zero retries means the event is
+// retried until it's acknowledged unless the target has a dead letter address,
+// in which case a failed event is sent to it directly. Only events received from
+// the retry queue have a delivery attempt in the context.
+func (p *Processor) retriesExhausted(ctx context.Context, target *config.Target) bool {
+	retry := target.DeliverySpec.GetRetry()
+	if retry == 0 {
+		return target.DeliverySpec.GetDeadLetterAddress() != ""
+	}
+	if p.RetryOnFailure {
+		return false
+	}
+	attempt, err := handlerctx.GetDeliveryAttempt(ctx)
+	if err != nil {
+		return false
+	}
+	return int32(attempt) >= retry
+}
+
+// sendToDeadLetter sends the event to the dead letter address of the target with
+// the reason of the delivery failure attached as extensions.
+func (p *Processor) sendToDeadLetter(ctx context.Context, target *config.Target, e *event.Event, deliveryErr error) error {
+	dle := e.Clone()
+	dle.SetExtension(errorDestExtension, target.Address)
+	var sce *statusCodeError
+	if errors.As(deliveryErr, &sce) {
+		dle.SetExtension(errorCodeExtension, sce.code)
+	}
+	dle.SetExtension(errorDataExtension, deliveryErr.Error())
+
+	resp, err := p.sendMsg(ctx, target.DeliverySpec.GetDeadLetterAddress(), nil, binding.ToMessage(&dle), eventutil.DeleteRemainingHopsTransformer)
+	if err != nil {
+		return fmt.Errorf("failed to send event to dead letter address: %w", err)
+	}
+	if err := resp.Body.Close(); err != nil {
+		logging.FromContext(ctx).Warn("failed to close dead letter response body", zap.Error(err))
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to send event to dead letter address: HTTP status code %d", resp.StatusCode)
+	}
+	trace.FromContext(ctx).Annotate(
+		[]trace.Attribute{trace.StringAttribute("error_message", deliveryErr.Error())},
+		"event sent to dead letter address",
+	)
+	return nil
+}
@@ -19,11 +19,14 @@ package deliver
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
 	"time"
@@ -42,13 +45,21 @@ import (
 	"go.uber.org/zap/zaptest"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"k8s.io/apimachinery/pkg/types"
 	"knative.dev/pkg/logging"
 	logtest "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/metrics/metricskey"
+	"knative.dev/pkg/metrics/metricstest"
 
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
 	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	"github.com/google/knative-gcp/pkg/broker/handler/breaker"
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/status"
+	gstorage "github.com/google/knative-gcp/pkg/gclient/storage/testing"
 	"github.com/google/knative-gcp/pkg/metrics"
 	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
 
@@ -200,6 +211,393 @@ func TestDeliverSuccess(t *testing.T) {
 	}
 }
 
+// replyingTarget replies to every event with its reply.
+type replyingTarget struct {
+	t     *testing.T
+	reply *event.Event
+}
+
+func (h *replyingTarget) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := cehttp.WriteResponseWriter(req.Context(), binding.ToMessage(h.reply), http.StatusOK, w); err != nil {
+		h.t.Errorf("failed to write reply: %v", err)
+	}
+}
+
+// recordingSink sends the events it receives to its events channel.
+type recordingSink struct {
+	t      *testing.T
+	events chan *event.Event
+}
+
+func (h *recordingSink) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	e, err := binding.ToEvent(req.Context(), cehttp.NewMessageFromHttpRequest(req))
+	if err != nil {
+		h.t.Errorf("sink received message cannot be converted to an event: %v", err)
+	}
+	h.events <- e
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func TestDeliverReply(t *testing.T) {
+	sampleReply := newSampleEvent()
+	sampleReply.SetID("reply")
+
+	cases := []struct {
+		name             string
+		replyToSink      bool
+		replyToBroker    bool
+		dropReplies      bool
+		wantIngressReply bool
+		wantSinkReply    bool
+		wantSinkHops     bool
+	}{{
+		name:             "reply to broker",
+		wantIngressReply: true,
+	}, {
+		name:          "reply to sink",
+		replyToSink:   true,
+		wantSinkReply: true,
+	}, {
+		name:          "reply to another broker",
+		replyToSink:   true,
+		replyToBroker: true,
+		wantSinkReply: true,
+		wantSinkHops:  true,
+	}, {
+		name:        "drop replies",
+		dropReplies: true,
+	}}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			targetSvr := httptest.NewServer(&replyingTarget{t: t, reply: sampleReply})
+			defer targetSvr.Close()
+			ingress := &recordingSink{t: t, events: make(chan *event.Event, 1)}
+			ingressSvr := httptest.NewServer(ingress)
+			defer ingressSvr.Close()
+			sink := &recordingSink{t: t, events: make(chan *event.Event, 1)}
+			sinkSvr := httptest.NewServer(sink)
+			defer sinkSvr.Close()
+
+			broker := &config.Broker{Namespace: "ns", Name: "broker"}
+			target := &config.Target{Namespace: "ns", Name: "target", Broker: "broker", Address: targetSvr.URL, DropReplies: tc.dropReplies}
+			if tc.replyToSink {
+				target.ReplyAddress = sinkSvr.URL
+				target.ReplyToBroker = tc.replyToBroker
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+				bm.SetAddress(ingressSvr.URL)
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &Processor{
+				DeliverClient: http.DefaultClient,
+				Targets:       testTargets,
+				StatsReporter: r,
+			}
+
+			if err := p.Process(ctx, newSampleEvent()); err != nil {
+				t.Errorf("unexpected error from processing: %v", err)
+			}
+
+			assertReply := func(name string, events chan *event.Event, want, wantHops bool) {
+				t.Helper()
+				select {
+				case got := <-events:
+					if !want {
+						t.Errorf("%s received unexpected reply: %v", name, got)
+						return
+					}
+					if got.ID() != sampleReply.ID() {
+						t.Errorf("%s received reply ID got=%s, want=%s", name, got.ID(), sampleReply.ID())
+					}
+					// The hops of the reply are only kept when it's sent to a broker.
+					hops, ok := eventutil.GetRemainingHops(ctx, got)
+					if wantHops && (!ok || hops != defaultEventHopsLimit) {
+						t.Errorf("%s received reply hops got=%v, want=%v", name, got.Extensions()[eventutil.HopsAttribute], defaultEventHopsLimit)
+					}
+					if !wantHops && ok {
+						t.Errorf("%s received reply hops got=%v, want none", name, hops)
+					}
+				default:
+					if want {
+						t.Errorf("%s didn't receive the reply", name)
+					}
+				}
+			}
+			assertReply("ingress", ingress.events, tc.wantIngressReply, true)
+			assertReply("sink", sink.events, tc.wantSinkReply, tc.wantSinkHops)
+		})
+	}
+}
+
+// fakeTokens returns a token named after the subscriber auth mode and audience.
+type fakeTokens struct {
+	err error
+}
+
+func (f *fakeTokens) Token(_ context.Context, auth *config.SubscriberAuth) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	return auth.GetMode().String() + ":" + auth.GetAudience(), nil
+}
+
+func TestDeliverSubscriberAuth(t *testing.T) {
+	cases := []struct {
+		name       string
+		auth       *config.SubscriberAuth
+		tokens     *fakeTokens
+		wantHeader string
+		wantErr    bool
+	}{{
+		name:   "no subscriber auth",
+		tokens: &fakeTokens{},
+	}, {
+		name: "no tokens",
+		auth: &config.SubscriberAuth{Mode: config.SubscriberAuth_ACCESS_TOKEN},
+	}, {
+		name:       "ID token",
+		auth:       &config.SubscriberAuth{Mode: config.SubscriberAuth_ID_TOKEN, Audience: "https://subscriber.a.run.app"},
+		tokens:     &fakeTokens{},
+		wantHeader: "Bearer ID_TOKEN:https://subscriber.a.run.app",
+	}, {
+		name:       "access token",
+		auth:       &config.SubscriberAuth{Mode: config.SubscriberAuth_ACCESS_TOKEN},
+		tokens:     &fakeTokens{},
+		wantHeader: "Bearer ACCESS_TOKEN:",
+	}, {
+		name:    "token error",
+		auth:    &config.SubscriberAuth{Mode: config.SubscriberAuth_ACCESS_TOKEN},
+		tokens:  &fakeTokens{err: errors.New("no credentials")},
+		wantErr: true,
+	}}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			headers := make(chan string, 1)
+			targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				headers <- req.Header.Get("Authorization")
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer targetSvr.Close()
+
+			broker := &config.Broker{Namespace: "ns", Name: "broker"}
+			target := &config.Target{Namespace: "ns", Name: "target", Broker: "broker", Address: targetSvr.URL, SubscriberAuth: tc.auth}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &Processor{
+				DeliverClient: http.DefaultClient,
+				Targets:       testTargets,
+				StatsReporter: r,
+			}
+			if tc.tokens != nil {
+				p.Tokens = tc.tokens
+			}
+
+			err = p.Process(ctx, newSampleEvent())
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Process error got=%v, wantErr=%v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if len(headers) != 0 {
+					t.Error("target received a request without a token")
+				}
+				return
+			}
+			if got := <-headers; got != tc.wantHeader {
+				t.Errorf("target Authorization header got=%q, want=%q", got, tc.wantHeader)
+			}
+		})
+	}
+}
+
+func TestDeliverHeadersAndExtensions(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	type request struct {
+		header http.Header
+		event  *event.Event
+	}
+	requests := make(chan request, 1)
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		e, err := binding.ToEvent(req.Context(), cehttp.NewMessageFromHttpRequest(req))
+		if err != nil {
+			t.Errorf("target received message cannot be converted to an event: %v", err)
+		}
+		requests <- request{header: req.Header, event: e}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer targetSvr.Close()
+
+	secretsDir, err := ioutil.TempDir("", "secrets-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(secretsDir)
+	if err := ioutil.WriteFile(filepath.Join(secretsDir, "ns_keys_api"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Namespace: "ns",
+		Name:      "target",
+		Broker:    "broker",
+		Address:   targetSvr.URL,
+		Headers:   map[string]string{"X-Route": "a"},
+		SecretHeaders: map[string]*config.SecretKeyRef{
+			"X-Api-Key": {Name: "keys", Key: "api"},
+			"X-Team":    {Name: "keys", Key: "team", Optional: true},
+		},
+		Extensions: map[string]string{"knativetrigger": "target", "team": "b"},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient:    http.DefaultClient,
+		Targets:          testTargets,
+		StatsReporter:    r,
+		HeaderSecretsDir: secretsDir,
+	}
+
+	origin := newSampleEvent()
+	origin.SetExtension("team", "a")
+	if err := p.Process(ctx, origin); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+
+	got := <-requests
+	if v := got.header.Get("X-Route"); v != "a" {
+		t.Errorf("target X-Route header got=%q, want=%q", v, "a")
+	}
+	if v := got.header.Get("X-Api-Key"); v != "secret" {
+		t.Errorf("target X-Api-Key header got=%q, want=%q", v, "secret")
+	}
+	// The optional header is left out, as its secret key doesn't exist.
+	if _, ok := got.header["X-Team"]; ok {
+		t.Errorf("target X-Team header got=%q, want none", got.header.Get("X-Team"))
+	}
+	wantExtensions := map[string]interface{}{"knativetrigger": "target", "team": "b"}
+	if diff := cmp.Diff(wantExtensions, got.event.Extensions()); diff != "" {
+		t.Errorf("target received event extensions (-want,+got): %v", diff)
+	}
+	// The original event isn't modified, as it's sent to the retry queue on failure.
+	if v := origin.Extensions()["team"]; v != "a" {
+		t.Errorf("original event team extension got=%v, want=%q", v, "a")
+	}
+}
+
+func TestDeliverClaimCheck(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	requests := make(chan *event.Event, 1)
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		e, err := binding.ToEvent(req.Context(), cehttp.NewMessageFromHttpRequest(req))
+		if err != nil {
+			t.Errorf("target received message cannot be converted to an event: %v", err)
+		}
+		requests <- e
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer targetSvr.Close()
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Namespace: "ns",
+		Name:      "target",
+		Broker:    "broker",
+		Address:   targetSvr.URL,
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects := gstorage.NewObjects()
+	client, err := gstorage.TestClientCreator(gstorage.TestClientData{Objects: objects})(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := claimcheck.NewStore(client, "test-bucket", 0)
+	p := &Processor{
+		DeliverClient: http.DefaultClient,
+		Targets:       testTargets,
+		StatsReporter: r,
+		ClaimChecks:   store,
+	}
+
+	origin := newSampleEvent()
+	origin.SetData("text/plain", []byte("offloaded data"))
+	if err := store.Offload(ctx, types.NamespacedName{Namespace: "ns", Name: "broker"}, origin); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Process(ctx, origin); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	got := <-requests
+	if string(got.Data()) != "offloaded data" {
+		t.Errorf("target received event data got=%q, want=%q", got.Data(), "offloaded data")
+	}
+	if _, ok := got.Extensions()[claimcheck.Extension]; ok {
+		t.Error("target received event with a claim check")
+	}
+	// The original event isn't modified, as it's sent to the retry queue on failure.
+	if _, ok := origin.Extensions()[claimcheck.Extension]; !ok {
+		t.Error("original event claim check was removed")
+	}
+
+	// The event isn't delivered if its data can't be loaded.
+	if err := store.Delete(ctx, origin); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Process(ctx, origin); err == nil {
+		t.Error("Process got no error for an event whose data failed to be loaded")
+	}
+	select {
+	case e := <-requests:
+		t.Errorf("target received event %v whose data failed to be loaded", e)
+	default:
+	}
+}
+
 type targetWithFailureHandler struct {
 	t              *testing.T
 	delay          time.Duration
@@ -229,6 +627,7 @@ func TestDeliverFailure(t *testing.T) {
 		name          string
 		withRetry     bool
 		targetHandler *targetWithFailureHandler
+		targetTimeout time.Duration
 		failRetry     bool
 		wantErr       bool
 	}{{
@@ -259,6 +658,15 @@ func TestDeliverFailure(t *testing.T) {
 		targetHandler: &targetWithFailureHandler{delay: time.Second, respCode: http.StatusOK},
 		failRetry:     true,
 		wantErr:       true,
+	}, {
+		name:          "target timeout overrides deliver timeout",
+		targetHandler: &targetWithFailureHandler{delay: time.Second, respCode: http.StatusOK},
+		targetTimeout: 5 * time.Second,
+	}, {
+		name:          "target timeout no retry",
+		targetHandler: &targetWithFailureHandler{delay: 300 * time.Millisecond, respCode: http.StatusOK},
+		targetTimeout: 100 * time.Millisecond,
+		wantErr:       true,
 	}, {
 		name: "malformed reply failure",
 		// Return 2xx but with a malformed event should be considered error.
@@ -303,6 +711,9 @@ func TestDeliverFailure(t *testing.T) {
 					Topic: "test-retry-topic",
 				},
 			}
+			if tc.targetTimeout > 0 {
+				target.DeliverySpec = &config.DeliverySpec{Timeout: durationpb.New(tc.targetTimeout)}
+			}
 			testTargets := memory.NewEmptyTargets()
 			testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
 				bm.UpsertTargets(target)
@@ -332,6 +743,551 @@ func TestDeliverFailure(t *testing.T) {
 	}
 }
 
+func TestDeliverOrderedTarget(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		t.Error("unexpected delivery to the ordered target from fanout")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer targetSvr.Close()
+
+	psSrv, c, close := testPubsubClient(ctx, t, "test-project")
+	defer close()
+	if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+		t.Fatalf("failed to create test pubsub topc: %v", err)
+	}
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Namespace: "ns",
+		Name:      "target",
+		Broker:    "broker",
+		Address:   targetSvr.URL,
+		RetryQueue: &config.Queue{
+			Topic: "test-retry-topic",
+		},
+		OrderingKeyAttribute: "subject",
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient:         http.DefaultClient,
+		Targets:               testTargets,
+		RetryOnFailure:        true,
+		OrderedRetryPublisher: NewOrderedPublisher(c),
+		StatsReporter:         r,
+	}
+
+	origin := newSampleEvent()
+	if err := p.Process(ctx, origin); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+
+	msgs := psSrv.Messages()
+	if len(msgs) != 1 {
+		t.Fatalf("retry topic got %d messages, want 1", len(msgs))
+	}
+	if msgs[0].OrderingKey != "subject" {
+		t.Errorf("retry message ordering key got=%q, want=%q", msgs[0].OrderingKey, "subject")
+	}
+	if got := msgs[0].Attributes["ce-id"]; got != origin.ID() {
+		t.Errorf("retry message event ID got=%q, want=%q", got, origin.ID())
+	}
+}
+
+func TestDeliverPausedTarget(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		t.Error("unexpected delivery to the paused target")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer targetSvr.Close()
+
+	psSrv, c, close := testPubsubClient(ctx, t, "test-project")
+	defer close()
+	if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+		t.Fatalf("failed to create test pubsub topc: %v", err)
+	}
+	ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+	if err != nil {
+		t.Fatalf("failed to create pubsub protocol: %v", err)
+	}
+	deliverRetryClient, err := ceclient.New(ps)
+	if err != nil {
+		t.Fatalf("failed to create cloudevents client: %v", err)
+	}
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Namespace: "ns",
+		Name:      "target",
+		Broker:    "broker",
+		Address:   targetSvr.URL,
+		RetryQueue: &config.Queue{
+			Topic: "test-retry-topic",
+		},
+		State: config.State_PAUSED,
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("fanout enqueues the event for retry", func(t *testing.T) {
+		p := &Processor{
+			DeliverClient:      http.DefaultClient,
+			Targets:            testTargets,
+			RetryOnFailure:     true,
+			DeliverRetryClient: deliverRetryClient,
+			StatsReporter:      r,
+		}
+		origin := newSampleEvent()
+		if err := p.Process(ctx, origin); err != nil {
+			t.Fatalf("unexpected error from processing: %v", err)
+		}
+		msgs := psSrv.Messages()
+		if len(msgs) != 1 {
+			t.Fatalf("retry topic got %d messages, want 1", len(msgs))
+		}
+		if got := msgs[0].Attributes["ce-id"]; got != origin.ID() {
+			t.Errorf("retry message event ID got=%q, want=%q", got, origin.ID())
+		}
+	})
+
+	t.Run("retry keeps the event queued", func(t *testing.T) {
+		p := &Processor{
+			DeliverClient: http.DefaultClient,
+			Targets:       testTargets,
+			StatsReporter: r,
+		}
+		if err := p.Process(ctx, newSampleEvent()); err != errTargetPaused {
+			t.Errorf("processing got error=%v, want=%v", err, errTargetPaused)
+		}
+	})
+}
+
+func TestDeliverCircuitBreaker(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	deliveries := 0
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		deliveries++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer targetSvr.Close()
+
+	psSrv, c, close := testPubsubClient(ctx, t, "test-project")
+	defer close()
+	if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+		t.Fatalf("failed to create test pubsub topc: %v", err)
+	}
+	ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+	if err != nil {
+		t.Fatalf("failed to create pubsub protocol: %v", err)
+	}
+	deliverRetryClient, err := ceclient.New(ps)
+	if err != nil {
+		t.Fatalf("failed to create cloudevents client: %v", err)
+	}
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Id:        "uid",
+		Namespace: "ns",
+		Name:      "target",
+		Broker:    "broker",
+		Address:   targetSvr.URL,
+		RetryQueue: &config.Queue{
+			Topic: "test-retry-topic",
+		},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = r.AddTags(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = metrics.AddTargetTags(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	breakers := breaker.New(2, time.Hour)
+	p := &Processor{
+		DeliverClient:      http.DefaultClient,
+		Targets:            testTargets,
+		RetryOnFailure:     true,
+		DeliverRetryClient: deliverRetryClient,
+		StatsReporter:      r,
+		CircuitBreakers:    breakers,
+	}
+	for i := 0; i < 4; i++ {
+		if err := p.Process(ctx, newSampleEvent()); err != nil {
+			t.Fatalf("unexpected error from processing: %v", err)
+		}
+	}
+	// The breaker is opened after 2 failed deliveries, then the events are enqueued
+	// for retry without being delivered.
+	if deliveries != 2 {
+		t.Errorf("target got %d deliveries, want 2", deliveries)
+	}
+	if msgs := psSrv.Messages(); len(msgs) != 4 {
+		t.Errorf("retry topic got %d messages, want 4", len(msgs))
+	}
+	if got := breakers.States()[target.Id].State; got != breaker.Open {
+		t.Errorf("breaker state got=%v, want=%v", got, breaker.Open)
+	}
+	metricstest.CheckLastValueData(t, "circuit_breaker_state", map[string]string{
+		metricskey.LabelFilterType: "any",
+		metricskey.PodName:         "pod",
+		metricskey.ContainerName:   "container",
+	}, float64(breaker.Open))
+}
+
+func TestOutcome(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want breaker.Outcome
+	}{{
+		name: "success",
+		want: breaker.Success,
+	}, {
+		name: "client error",
+		err:  &statusCodeError{code: http.StatusBadRequest},
+		want: breaker.Success,
+	}, {
+		name: "too many requests",
+		err:  &statusCodeError{code: http.StatusTooManyRequests},
+		want: breaker.Failure,
+	}, {
+		name: "server error",
+		err:  &statusCodeError{code: http.StatusBadGateway},
+		want: breaker.Failure,
+	}, {
+		name: "unreachable",
+		err:  fmt.Errorf("wrapped: %w", &unreachableError{err: errors.New("connection refused")}),
+		want: breaker.Failure,
+	}, {
+		name: "other error",
+		err:  errors.New("failed to authenticate to target"),
+		want: breaker.Ignored,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Outcome(tc.err); got != tc.want {
+				t.Errorf("Outcome got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRecordDelivery(t *testing.T) {
+	target := &config.Target{Id: "uid", Namespace: "ns", Name: "target"}
+	cases := []struct {
+		name        string
+		err         error
+		wantSuccess bool
+		wantError   bool
+		wantCode    int
+	}{{
+		name:        "success",
+		wantSuccess: true,
+	}, {
+		name:      "client error",
+		err:       &statusCodeError{code: http.StatusBadRequest},
+		wantError: true,
+		wantCode:  http.StatusBadRequest,
+	}, {
+		name:      "server error",
+		err:       &statusCodeError{code: http.StatusBadGateway},
+		wantError: true,
+		wantCode:  http.StatusBadGateway,
+	}, {
+		name:      "unreachable",
+		err:       &unreachableError{err: errors.New("connection refused")},
+		wantError: true,
+	}, {
+		name: "circuit open",
+		err:  errCircuitOpen,
+	}, {
+		name: "other error",
+		err:  errors.New("failed to send reply"),
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &Processor{Deliveries: status.NewDeliveries()}
+			p.recordDelivery(target, tc.err)
+			got, recorded := p.Deliveries.Status()[target.Id]
+			if recorded != (tc.wantSuccess || tc.wantError) {
+				t.Fatalf("delivery recorded got=%v, want=%v", recorded, !recorded)
+			}
+			if (got.LastSuccessTime != nil) != tc.wantSuccess {
+				t.Errorf("LastSuccessTime got=%v, want set=%v", got.LastSuccessTime, tc.wantSuccess)
+			}
+			if (got.LastFailureTime != nil) != tc.wantError {
+				t.Errorf("LastFailureTime got=%v, want set=%v", got.LastFailureTime, tc.wantError)
+			}
+			if got.LastErrorCode != tc.wantCode {
+				t.Errorf("LastErrorCode got=%v, want=%v", got.LastErrorCode, tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestDeliverClaimCheckDeadLetter(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	targetSvr := httptest.NewServer(&targetWithFailureHandler{t: t, respCode: http.StatusServiceUnavailable})
+	defer targetSvr.Close()
+
+	deadLetterCh := make(chan *event.Event, 1)
+	deadLetterSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		e, err := binding.ToEvent(req.Context(), cehttp.NewMessageFromHttpRequest(req))
+		if err != nil {
+			t.Errorf("dead letter received message cannot be converted to an event: %v", err)
+		}
+		deadLetterCh <- e
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer deadLetterSvr.Close()
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Namespace:    "ns",
+		Name:         "target",
+		Broker:       "broker",
+		Address:      targetSvr.URL,
+		DeliverySpec: &config.DeliverySpec{DeadLetterAddress: deadLetterSvr.URL},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+	ctx = handlerctx.WithDeliveryAttempt(ctx, 1)
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects := gstorage.NewObjects()
+	client, err := gstorage.TestClientCreator(gstorage.TestClientData{Objects: objects})(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := claimcheck.NewStore(client, "test-bucket", 0)
+	p := &Processor{
+		DeliverClient: http.DefaultClient,
+		Targets:       testTargets,
+		StatsReporter: r,
+		ClaimChecks:   store,
+	}
+
+	origin := newSampleEvent()
+	origin.SetData("text/plain", []byte("offloaded data"))
+	if err := store.Offload(ctx, types.NamespacedName{Namespace: "ns", Name: "broker"}, origin); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Process(ctx, origin); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	got := <-deadLetterCh
+	if string(got.Data()) != "offloaded data" {
+		t.Errorf("dead letter received event data got=%q, want=%q", got.Data(), "offloaded data")
+	}
+	if _, ok := got.Extensions()[claimcheck.Extension]; ok {
+		t.Error("dead letter received event with a claim check")
+	}
+
+	// The claim check is sent to the dead letter address if the data can't be loaded.
+	if err := store.Delete(ctx, origin); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Process(ctx, origin); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	got = <-deadLetterCh
+	if _, ok := got.Extensions()[claimcheck.Extension]; !ok {
+		t.Error("dead letter received event without its claim check")
+	}
+}
+
+func TestDeliverDeadLetter(t *testing.T) {
+	cases := []struct {
+		name           string
+		withRetry      bool
+		attempt        int
+		retry          int32
+		noDeadLetter   bool
+		deadLetterCode int
+		wantDeadLetter bool
+		wantErr        bool
+	}{{
+		name:    "attempts left",
+		attempt: 1,
+		retry:   3,
+		wantErr: true,
+	}, {
+		name:           "attempts exhausted",
+		attempt:        3,
+		retry:          3,
+		deadLetterCode: http.StatusAccepted,
+		wantDeadLetter: true,
+	}, {
+		name:           "no retry",
+		attempt:        1,
+		deadLetterCode: http.StatusAccepted,
+		wantDeadLetter: true,
+	}, {
+		name:           "fanout no retry",
+		withRetry:      true,
+		deadLetterCode: http.StatusAccepted,
+		wantDeadLetter: true,
+	}, {
+		name:         "attempts exhausted without dead letter",
+		attempt:      3,
+		retry:        3,
+		noDeadLetter: true,
+	}, {
+		name:         "unlimited retries without dead letter",
+		attempt:      10,
+		noDeadLetter: true,
+		wantErr:      true,
+	}, {
+		name:           "dead letter failure",
+		attempt:        3,
+		retry:          3,
+		deadLetterCode: http.StatusInternalServerError,
+		wantDeadLetter: true,
+		wantErr:        true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			targetSvr := httptest.NewServer(&targetWithFailureHandler{t: t, respCode: http.StatusServiceUnavailable})
+			defer targetSvr.Close()
+
+			deadLetterCh := make(chan *event.Event, 1)
+			deadLetterSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				e, err := binding.ToEvent(req.Context(), cehttp.NewMessageFromHttpRequest(req))
+				if err != nil {
+					t.Errorf("dead letter received message cannot be converted to an event: %v", err)
+				}
+				deadLetterCh <- e
+				w.WriteHeader(tc.deadLetterCode)
+			}))
+			defer deadLetterSvr.Close()
+
+			broker := &config.Broker{Namespace: "ns", Name: "broker"}
+			target := &config.Target{
+				Namespace: "ns",
+				Name:      "target",
+				Broker:    "broker",
+				Address:   targetSvr.URL,
+				DeliverySpec: &config.DeliverySpec{
+					DeadLetterAddress: deadLetterSvr.URL,
+					Retry:             tc.retry,
+				},
+			}
+			if tc.noDeadLetter {
+				target.DeliverySpec.DeadLetterAddress = ""
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+			if !tc.withRetry {
+				ctx = handlerctx.WithDeliveryAttempt(ctx, tc.attempt)
+			}
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &Processor{
+				DeliverClient:  http.DefaultClient,
+				Targets:        testTargets,
+				RetryOnFailure: tc.withRetry,
+				StatsReporter:  r,
+			}
+
+			origin := newSampleEvent()
+			eventutil.UpdateRemainingHops(ctx, origin, 5)
+			err = p.Process(ctx, origin)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("processing got error=%v, want=%v", err, tc.wantErr)
+			}
+
+			var gotEvent *event.Event
+			select {
+			case gotEvent = <-deadLetterCh:
+			default:
+			}
+			if !tc.wantDeadLetter {
+				if gotEvent != nil {
+					t.Errorf("unexpected event sent to dead letter address: %v", gotEvent)
+				}
+				return
+			}
+			if gotEvent == nil {
+				t.Fatal("event wasn't sent to dead letter address")
+			}
+			wantEvent := newSampleEvent()
+			wantEvent.SetTime(origin.Time())
+			wantEvent.SetExtension(errorDestExtension, targetSvr.URL)
+			wantEvent.SetExtension(errorCodeExtension, http.StatusServiceUnavailable)
+			wantEvent.SetExtension(errorDataExtension, "event delivery failed: HTTP status code 503")
+			// HTTP transport changes the internal type of the extensions to string.
+			toStrings := func(exts map[string]interface{}) map[string]string {
+				m := make(map[string]string, len(exts))
+				for k, v := range exts {
+					m[k] = fmt.Sprint(v)
+				}
+				return m
+			}
+			if diff := cmp.Diff(toStrings(wantEvent.Extensions()), toStrings(gotEvent.Extensions())); diff != "" {
+				t.Errorf("dead letter event extensions (-want,+got): %v", diff)
+			}
+			if gotEvent.ID() != origin.ID() {
+				t.Errorf("dead letter event ID got=%q, want=%q", gotEvent.ID(), origin.ID())
+			}
+		})
+	}
+}
+
 type NoReplyHandler struct{}
 
 func (NoReplyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
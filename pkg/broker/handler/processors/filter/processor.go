@@ -31,6 +31,7 @@ import (
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
 	"github.com/google/knative-gcp/pkg/tracing"
+	"github.com/google/knative-gcp/pkg/utils/eventfilter"
 )
 
 // Processor is the processor to filter events based on trigger filters.
@@ -64,17 +65,37 @@ func (p *Processor) Process(ctx context.Context, event *event.Event) error {
 	ctx, span := startSpan(ctx, trigger, event)
 	defer span.End()
 
-	if target.FilterAttributes == nil {
-		return p.Next().Process(ctx, event)
-	}
-
-	if PassFilter(ctx, target.FilterAttributes, event) {
+	if PassTarget(ctx, target, event) && p.passCELFilter(ctx, target, event) {
 		return p.Next().Process(ctx, event)
 	}
 	logging.FromContext(ctx).Debug("event does not pass filter for target", zap.Any("target", target))
 	return nil
 }
 
+// passCELFilter checks given event against the CEL filter of the target, if any.
+// An event doesn't pass if the filter is invalid or fails to evaluate.
+func (p *Processor) passCELFilter(ctx context.Context, target *config.Target, event *event.Event) bool {
+	prg, err := p.Targets.GetCELFilter(target)
+	if err != nil {
+		logging.FromContext(ctx).Error("Invalid CEL filter for target", zap.String("target", target.Key()), zap.Error(err))
+		trace.FromContext(ctx).Annotatef(nil, "invalid CEL filter: %v", err)
+		return false
+	}
+	if prg == nil {
+		return true
+	}
+	pass, err := prg.Eval(event)
+	if err != nil {
+		logging.FromContext(ctx).Debug("CEL filter evaluation failed", zap.String("filter", prg.Expression()), zap.Error(err))
+		trace.FromContext(ctx).Annotatef(nil, "CEL filter evaluation failed: %v", err)
+		return false
+	}
+	if !pass {
+		trace.FromContext(ctx).Annotatef(nil, "event does not pass CEL filter %q", prg.Expression())
+	}
+	return pass
+}
+
 func startSpan(ctx context.Context, trigger types.NamespacedName, event *event.Event) (context.Context, *trace.Span) {
 	var span *trace.Span
 	if dt, ok := extensions.GetDistributedTracingExtension(*event); ok {
@@ -93,45 +114,8 @@ func startSpan(ctx context.Context, trigger types.NamespacedName, event *event.E
 	return tracing.WithLogging(ctx, span), span
 }
 
-// PassFilter checks given event against attributes available in the attrs map to determine
-// if the event should pass or not.
-func PassFilter(ctx context.Context, attrs map[string]string, event *event.Event) bool {
-	// Set standard context attributes. The attributes available may not be
-	// exactly the same as the attributes defined in the current version of the
-	// CloudEvents spec.
-	ce := map[string]interface{}{
-		"specversion":     event.SpecVersion(),
-		"type":            event.Type(),
-		"source":          event.Source(),
-		"subject":         event.Subject(),
-		"id":              event.ID(),
-		"time":            event.Time().String(),
-		"schemaurl":       event.DataSchema(),
-		"datacontenttype": event.DataContentType(),
-		"datamediatype":   event.DataMediaType(),
-		// TODO: use data_base64 when SDK supports it.
-		"datacontentencoding": event.DeprecatedDataContentEncoding(),
-	}
-	ext := event.Extensions()
-	for k, v := range ext {
-		ce[k] = v
-	}
-
-	for k, v := range attrs {
-		var value interface{}
-		value, ok := ce[k]
-		// If the attribute does not exist in the event, return false.
-		if !ok {
-			logging.FromContext(ctx).Debug("Attribute not found", zap.String("attribute", k))
-			trace.FromContext(ctx).Annotatef(nil, "event missing filter attribute %q", k)
-			return false
-		}
-		// If the attribute is not set to any and is different than the one from the event, return false.
-		if v != "" && v != value {
-			logging.FromContext(ctx).Debug("Attribute had non-matching value", zap.String("attribute", k), zap.String("filter", v), zap.Any("received", value))
-			trace.FromContext(ctx).Annotatef(nil, "event attribute %q does not match filter value %q", k, v)
-			return false
-		}
-	}
-	return true
+// PassTarget checks given event against both the filter attributes and the filter
+// expressions of the target to determine if the event should pass or not.
+func PassTarget(ctx context.Context, target *config.Target, event *event.Event) bool {
+	return eventfilter.PassFilter(ctx, target.FilterAttributes, event) && PassFilters(ctx, target.Filters, event)
 }
@@ -325,3 +325,68 @@ func newTestTargets(filter map[string]string) (context.Context, config.Targets)
 	ctx := handlerctx.WithTargetKey(context.Background(), testTarget.Key())
 	return ctx, testTargets
 }
+
+func TestCELFilterProcessor(t *testing.T) {
+	cases := []struct {
+		name       string
+		celFilter  string
+		shouldPass bool
+	}{{
+		name:       "no filter",
+		shouldPass: true,
+	}, {
+		name:       "pass",
+		celFilter:  `ce.type == "foo" && data.bucket == "prod-uploads"`,
+		shouldPass: true,
+	}, {
+		name:       "not pass",
+		celFilter:  `data.bucket == "dev-uploads"`,
+		shouldPass: false,
+	}, {
+		name:       "evaluation error",
+		celFilter:  `data.missing == "foo"`,
+		shouldPass: false,
+	}, {
+		name:       "invalid filter",
+		celFilter:  `data.bucket ==`,
+		shouldPass: false,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := event.New()
+			e.SetType("foo")
+			e.SetData(event.ApplicationJSON, map[string]string{"bucket": "prod-uploads"})
+
+			testTarget := &config.Target{
+				Name:      "target",
+				Broker:    "broker",
+				Namespace: "ns",
+				CelFilter: tc.celFilter,
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+				bm.UpsertTargets(testTarget)
+			})
+			ctx := handlerctx.WithTargetKey(context.Background(), testTarget.Key())
+
+			next := &processors.FakeProcessor{}
+			p := &Processor{Targets: testTargets}
+			p.WithNext(next)
+			ch := make(chan *event.Event, 1)
+			next.PrevEventsCh = ch
+
+			if err := p.Process(ctx, &e); err != nil {
+				t.Errorf("unexpected error from processing: %v", err)
+			}
+			close(ch)
+			gotEvent := <-ch
+			if tc.shouldPass && gotEvent == nil {
+				t.Errorf("event didn't pass CEL filter %q", tc.celFilter)
+			}
+			if !tc.shouldPass && gotEvent != nil {
+				t.Errorf("unexpected event %v passed CEL filter %q", gotEvent, tc.celFilter)
+			}
+		})
+	}
+}
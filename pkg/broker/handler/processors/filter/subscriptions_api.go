@@ -0,0 +1,106 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/utils/eventfilter"
+)
+
+// PassFilters checks given event against the CloudEvents Subscriptions API filter
+// expressions to determine if the event should pass or not. The event passes only
+// if it passes all the filters.
+func PassFilters(ctx context.Context, filters []*config.Filter, event *event.Event) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	return passAll(ctx, filters, eventfilter.Attributes(event))
+}
+
+func passAll(ctx context.Context, filters []*config.Filter, attrs map[string]interface{}) bool {
+	for _, f := range filters {
+		if !passExpression(ctx, f, attrs) {
+			return false
+		}
+	}
+	return true
+}
+
+func passAny(ctx context.Context, filters []*config.Filter, attrs map[string]interface{}) bool {
+	for _, f := range filters {
+		if passExpression(ctx, f, attrs) {
+			return true
+		}
+	}
+	return false
+}
+
+// passExpression evaluates a single filter expression. Every dialect set in the
+// expression must match; an expression without any dialect matches all events.
+func passExpression(ctx context.Context, f *config.Filter, attrs map[string]interface{}) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.Exact) > 0 && !matchAttributes(ctx, "exact", f.Exact, attrs, func(v, want string) bool { return v == want }) {
+		return false
+	}
+	if len(f.Prefix) > 0 && !matchAttributes(ctx, "prefix", f.Prefix, attrs, strings.HasPrefix) {
+		return false
+	}
+	if len(f.Suffix) > 0 && !matchAttributes(ctx, "suffix", f.Suffix, attrs, strings.HasSuffix) {
+		return false
+	}
+	if len(f.All) > 0 && !passAll(ctx, f.All, attrs) {
+		return false
+	}
+	if len(f.Any) > 0 && !passAny(ctx, f.Any, attrs) {
+		return false
+	}
+	if f.Not != nil && passExpression(ctx, f.Not, attrs) {
+		return false
+	}
+	return true
+}
+
+// matchAttributes checks that every attribute in want exists in the event and that
+// its string value matches according to the given match function.
+func matchAttributes(ctx context.Context, dialect string, want map[string]string, attrs map[string]interface{}, match func(v, want string) bool) bool {
+	for k, w := range want {
+		value, ok := attrs[k]
+		if !ok {
+			logging.FromContext(ctx).Debug("Attribute not found", zap.String("attribute", k), zap.String("dialect", dialect))
+			trace.FromContext(ctx).Annotatef(nil, "event missing %s filter attribute %q", dialect, k)
+			return false
+		}
+		v, err := types.Format(value)
+		if err != nil || !match(v, w) {
+			logging.FromContext(ctx).Debug("Attribute had non-matching value", zap.String("attribute", k), zap.String("dialect", dialect), zap.String("filter", w), zap.Any("received", value))
+			trace.FromContext(ctx).Annotatef(nil, "event attribute %q does not match %s filter value %q", k, dialect, w)
+			return false
+		}
+	}
+	return true
+}
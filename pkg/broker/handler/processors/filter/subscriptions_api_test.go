@@ -0,0 +1,217 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func TestPassFilters(t *testing.T) {
+	e := event.New()
+	e.SetID("id")
+	e.SetType("com.google.cloud.storage.object.v1.finalized")
+	e.SetSource("//storage.googleapis.com/projects/_/buckets/my-bucket")
+	e.SetSubject("objects/image.jpg")
+	e.SetExtension("priority", 5)
+
+	cases := []struct {
+		name    string
+		filters []*config.Filter
+		want    bool
+	}{{
+		name: "no filters",
+		want: true,
+	}, {
+		name:    "empty expression",
+		filters: []*config.Filter{{}},
+		want:    true,
+	}, {
+		name: "exact pass",
+		filters: []*config.Filter{{
+			Exact: map[string]string{"id": "id", "type": "com.google.cloud.storage.object.v1.finalized"},
+		}},
+		want: true,
+	}, {
+		name: "exact not pass",
+		filters: []*config.Filter{{
+			Exact: map[string]string{"type": "com.google.cloud.storage."},
+		}},
+		want: false,
+	}, {
+		name: "exact empty value not pass",
+		filters: []*config.Filter{{
+			Exact: map[string]string{"type": ""},
+		}},
+		want: false,
+	}, {
+		name: "prefix pass",
+		filters: []*config.Filter{{
+			Prefix: map[string]string{"type": "com.google.cloud.storage."},
+		}},
+		want: true,
+	}, {
+		name: "prefix not pass",
+		filters: []*config.Filter{{
+			Prefix: map[string]string{"type": "com.google.cloud.pubsub."},
+		}},
+		want: false,
+	}, {
+		name: "suffix pass",
+		filters: []*config.Filter{{
+			Suffix: map[string]string{"subject": ".jpg"},
+		}},
+		want: true,
+	}, {
+		name: "suffix not pass",
+		filters: []*config.Filter{{
+			Suffix: map[string]string{"subject": ".png"},
+		}},
+		want: false,
+	}, {
+		name: "extension pass",
+		filters: []*config.Filter{{
+			Exact: map[string]string{"priority": "5"},
+		}},
+		want: true,
+	}, {
+		name: "missing attribute not pass",
+		filters: []*config.Filter{{
+			Prefix: map[string]string{"missing": ""},
+		}},
+		want: false,
+	}, {
+		name: "all pass",
+		filters: []*config.Filter{{
+			All: []*config.Filter{
+				{Prefix: map[string]string{"type": "com.google.cloud.storage."}},
+				{Suffix: map[string]string{"subject": ".jpg"}},
+			},
+		}},
+		want: true,
+	}, {
+		name: "all not pass",
+		filters: []*config.Filter{{
+			All: []*config.Filter{
+				{Prefix: map[string]string{"type": "com.google.cloud.storage."}},
+				{Suffix: map[string]string{"subject": ".png"}},
+			},
+		}},
+		want: false,
+	}, {
+		name: "any pass",
+		filters: []*config.Filter{{
+			Any: []*config.Filter{
+				{Suffix: map[string]string{"subject": ".png"}},
+				{Suffix: map[string]string{"subject": ".jpg"}},
+			},
+		}},
+		want: true,
+	}, {
+		name: "any not pass",
+		filters: []*config.Filter{{
+			Any: []*config.Filter{
+				{Suffix: map[string]string{"subject": ".png"}},
+				{Suffix: map[string]string{"subject": ".gif"}},
+			},
+		}},
+		want: false,
+	}, {
+		name: "not pass",
+		filters: []*config.Filter{{
+			Not: &config.Filter{Suffix: map[string]string{"subject": ".png"}},
+		}},
+		want: true,
+	}, {
+		name: "not not pass",
+		filters: []*config.Filter{{
+			Not: &config.Filter{Suffix: map[string]string{"subject": ".jpg"}},
+		}},
+		want: false,
+	}, {
+		name: "multiple filters all pass",
+		filters: []*config.Filter{
+			{Prefix: map[string]string{"type": "com.google.cloud.storage."}},
+			{Not: &config.Filter{Exact: map[string]string{"subject": "objects/other.jpg"}}},
+		},
+		want: true,
+	}, {
+		name: "multiple filters one not pass",
+		filters: []*config.Filter{
+			{Prefix: map[string]string{"type": "com.google.cloud.storage."}},
+			{Exact: map[string]string{"subject": "objects/other.jpg"}},
+		},
+		want: false,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := PassFilters(context.Background(), tc.filters, &e); got != tc.want {
+				t.Errorf("PassFilters got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPassTarget(t *testing.T) {
+	e := event.New()
+	e.SetType("com.google.cloud.storage.object.v1.finalized")
+	e.SetSource("source")
+
+	cases := []struct {
+		name   string
+		target *config.Target
+		want   bool
+	}{{
+		name:   "no filters",
+		target: &config.Target{},
+		want:   true,
+	}, {
+		name: "both pass",
+		target: &config.Target{
+			FilterAttributes: map[string]string{"source": "source"},
+			Filters:          []*config.Filter{{Prefix: map[string]string{"type": "com.google.cloud.storage."}}},
+		},
+		want: true,
+	}, {
+		name: "filter attributes not pass",
+		target: &config.Target{
+			FilterAttributes: map[string]string{"source": "other"},
+			Filters:          []*config.Filter{{Prefix: map[string]string{"type": "com.google.cloud.storage."}}},
+		},
+		want: false,
+	}, {
+		name: "filter expressions not pass",
+		target: &config.Target{
+			FilterAttributes: map[string]string{"source": "source"},
+			Filters:          []*config.Filter{{Prefix: map[string]string{"type": "com.google.cloud.pubsub."}}},
+		},
+		want: false,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := PassTarget(context.Background(), tc.target, &e); got != tc.want {
+				t.Errorf("PassTarget got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
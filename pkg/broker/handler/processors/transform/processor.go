@@ -0,0 +1,131 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	ceclient "github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// Processor sends each event to the transformer of the broker in the context, if any,
+// and passes the transformed event to the next processor in place of the original one.
+// An event is dropped if the transformer replies without an event.
+type Processor struct {
+	processors.BaseProcessor
+
+	// DeliverClient is the client to send events to the transformers.
+	DeliverClient *http.Client
+
+	// Targets is the targets from config.
+	Targets config.ReadonlyTargets
+
+	// DeliverTimeout is the timeout applied to cancel the transformation. It doesn't apply
+	// to the next processors. If zero, no additional timeout is applied.
+	DeliverTimeout time.Duration
+}
+
+var _ processors.Interface = (*Processor)(nil)
+
+// Process transforms the event with the transformer of the broker in the context.
+func (p *Processor) Process(ctx context.Context, e *event.Event) error {
+	bk, err := handlerctx.GetBrokerKey(ctx)
+	if err != nil {
+		return err
+	}
+	broker, ok := p.Targets.GetBrokerByKey(bk)
+	if !ok {
+		// If the broker no longer exists, then there is nothing to process.
+		logging.FromContext(ctx).Warn("broker no longer exist in the config", zap.String("broker", bk))
+		return nil
+	}
+	if broker.TransformerAddress == "" {
+		return p.Next().Process(ctx, e)
+	}
+
+	transformed, err := p.transform(ctx, broker.TransformerAddress, e)
+	if err != nil {
+		// The event is nacked and delivered again to the transformer.
+		return fmt.Errorf("failed to transform event: %w", err)
+	}
+	if transformed == nil {
+		logging.FromContext(ctx).Debug("transformer replied without an event, dropping event", zap.String("broker", bk), zap.String("event.id", e.ID()))
+		trace.FromContext(ctx).Annotate(ceclient.EventTraceAttributes(e), "event dropped: transformer replied without an event")
+		return nil
+	}
+	// Transforming an event doesn't count as a hop, so the transformed event keeps the
+	// remaining hops of the original event.
+	if hops, ok := eventutil.GetRemainingHops(ctx, e); ok {
+		transformed.SetExtension(eventutil.HopsAttribute, hops)
+	}
+	return p.Next().Process(ctx, transformed)
+}
+
+// transform sends the event to the transformer and returns its reply, or nil if it
+// replied without an event.
+func (p *Processor) transform(ctx context.Context, address string, e *event.Event) (*event.Event, error) {
+	if p.DeliverTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.DeliverTimeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := cehttp.WriteRequest(ctx, eventutil.NewImmutableEventMessage(e), req, eventutil.DeleteRemainingHopsTransformer); err != nil {
+		return nil, err
+	}
+	resp, err := p.DeliverClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.FromContext(ctx).Warn("failed to close response body", zap.Error(err))
+		}
+	}()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("HTTP status code %d", resp.StatusCode)
+	}
+
+	respMsg := cehttp.NewMessageFromHttpResponse(resp)
+	if respMsg.ReadEncoding() == binding.EncodingUnknown {
+		// A body without a known encoding is a malformed reply, as in the deliver processor.
+		body := make([]byte, 1)
+		if n, _ := respMsg.BodyReader.Read(body); n != 0 {
+			return nil, errors.New("received a malformed event in reply")
+		}
+		return nil, nil
+	}
+	return binding.ToEvent(ctx, respMsg)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/go-cmp/cmp"
+	logtest "knative.dev/pkg/logging/testing"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+)
+
+func TestInvalidContext(t *testing.T) {
+	p := &Processor{Targets: memory.NewEmptyTargets()}
+	e := event.New()
+	err := p.Process(context.Background(), &e)
+	if err != handlerctx.ErrBrokerKeyNotPresent {
+		t.Errorf("Process error got=%v, want=%v", err, handlerctx.ErrBrokerKeyNotPresent)
+	}
+}
+
+// fakeTransformer replies to each event with reply, or with the status code if reply is nil.
+type fakeTransformer struct {
+	t        *testing.T
+	reply    *event.Event
+	status   int
+	received *event.Event
+}
+
+func (h *fakeTransformer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	e, err := binding.ToEvent(req.Context(), cehttp.NewMessageFromHttpRequest(req))
+	if err != nil {
+		h.t.Errorf("transformer received an invalid event: %v", err)
+	}
+	h.received = e
+	if h.reply == nil {
+		w.WriteHeader(h.status)
+		return
+	}
+	if err := cehttp.WriteResponseWriter(req.Context(), binding.ToMessage(h.reply), http.StatusOK, w); err != nil {
+		h.t.Errorf("failed to write reply: %v", err)
+	}
+}
+
+func TestProcess(t *testing.T) {
+	newEvent := func(eventType string) event.Event {
+		e := event.New()
+		e.SetID("id")
+		e.SetSource("source")
+		e.SetType(eventType)
+		return e
+	}
+	origin := newEvent("type")
+	origin.SetExtension(eventutil.HopsAttribute, int32(10))
+
+	reply := newEvent("type.enriched")
+	// The transformer doesn't receive the hops, which are restored on its reply.
+	wantOrigin := newEvent("type")
+	wantReply := newEvent("type.enriched")
+	wantReply.SetExtension(eventutil.HopsAttribute, int32(10))
+
+	cases := []struct {
+		name            string
+		noTransformer   bool
+		transformer     *fakeTransformer
+		wantTransformed *event.Event
+		wantNext        *event.Event
+		wantErr         bool
+	}{{
+		name:          "no transformer",
+		noTransformer: true,
+		wantNext:      &origin,
+	}, {
+		name:            "event transformed",
+		transformer:     &fakeTransformer{reply: &reply},
+		wantTransformed: &wantOrigin,
+		wantNext:        &wantReply,
+	}, {
+		name:            "event dropped",
+		transformer:     &fakeTransformer{status: http.StatusAccepted},
+		wantTransformed: &wantOrigin,
+	}, {
+		name:            "transformation failure",
+		transformer:     &fakeTransformer{status: http.StatusInternalServerError},
+		wantTransformed: &wantOrigin,
+		wantErr:         true,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtest.TestContextWithLogger(t)
+			address := ""
+			if !tc.noTransformer {
+				tc.transformer.t = t
+				svr := httptest.NewServer(tc.transformer)
+				defer svr.Close()
+				address = svr.URL
+			}
+
+			broker := &config.Broker{Namespace: "ns", Name: "broker"}
+			targets := memory.NewEmptyTargets()
+			targets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+				bm.SetTransformerAddress(address)
+			})
+
+			next := &processors.FakeProcessor{PrevEventsCh: make(chan *event.Event, 1)}
+			p := processors.ChainProcessors(&Processor{
+				DeliverClient:  http.DefaultClient,
+				Targets:        targets,
+				DeliverTimeout: time.Second,
+			}, next)
+
+			e := origin.Clone()
+			err := p.Process(handlerctx.WithBrokerKey(ctx, broker.Key()), &e)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Process got error=%v, want=%v", err, tc.wantErr)
+			}
+			if !tc.noTransformer {
+				if diff := cmp.Diff(tc.wantTransformed, tc.transformer.received); diff != "" {
+					t.Errorf("transformer received event (-want,+got): %v", diff)
+				}
+			}
+			var gotNext *event.Event
+			select {
+			case gotNext = <-next.PrevEventsCh:
+			default:
+			}
+			if diff := cmp.Diff(tc.wantNext, gotNext); diff != "" {
+				t.Errorf("next processor received event (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
+// The timeout of the transformation must not apply to the fanout and delivery of the
+// transformed event.
+func TestProcessTimeoutOnlyAppliesToTransformation(t *testing.T) {
+	ctx := logtest.TestContextWithLogger(t)
+	reply := event.New()
+	reply.SetID("id")
+	reply.SetSource("source")
+	reply.SetType("type.enriched")
+	svr := httptest.NewServer(&fakeTransformer{t: t, reply: &reply})
+	defer svr.Close()
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	targets := memory.NewEmptyTargets()
+	targets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.SetTransformerAddress(svr.URL)
+	})
+
+	var nextDeadline bool
+	next := &processors.FakeProcessor{
+		PrevEventsCh: make(chan *event.Event, 1),
+		InterceptFunc: func(ctx context.Context, e *event.Event) *event.Event {
+			_, nextDeadline = ctx.Deadline()
+			return e
+		},
+	}
+	p := processors.ChainProcessors(&Processor{
+		DeliverClient:  http.DefaultClient,
+		Targets:        targets,
+		DeliverTimeout: time.Second,
+	}, next)
+
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	if err := p.Process(handlerctx.WithBrokerKey(ctx, broker.Key()), &e); err != nil {
+		t.Fatalf("Process got error=%v", err)
+	}
+	if nextDeadline {
+		t.Error("next processor got the deadline of the transformation")
+	}
+}
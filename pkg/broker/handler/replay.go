@@ -0,0 +1,147 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/metrics"
+)
+
+// publishWindow is the range of publish times of the messages replayed to a target.
+type publishWindow struct {
+	from, until time.Time
+}
+
+// contains returns true if a message published at t is within the window.
+func (w *publishWindow) contains(t time.Time) bool {
+	return !t.Before(w.from) && !t.After(w.until)
+}
+
+// newPublishWindow returns the publish window of the replay. The events published
+// after the window were already delivered by the fanout handlers. A replay without
+// a window doesn't deliver any event, so that its subscription is drained until a
+// replay is requested.
+func newPublishWindow(r *config.Replay) *publishWindow {
+	if r.From == nil || r.Until == nil {
+		return &publishWindow{}
+	}
+	return &publishWindow{
+		from:  r.From.AsTime(),
+		until: r.Until.AsTime(),
+	}
+}
+
+type replayHandlerCache struct {
+	Handler
+	r *config.Replay
+}
+
+// shouldRenew returns true if the replay subscription or window of the target changed.
+func (hc *replayHandlerCache) shouldRenew(r *config.Replay) bool {
+	if !hc.IsAlive() {
+		return true
+	}
+	return r.Queue.Subscription != hc.r.Queue.Subscription ||
+		!proto.Equal(r.From, hc.r.From) ||
+		!proto.Equal(r.Until, hc.r.Until)
+}
+
+// replaying returns true if events are replayed to the target. Replays don't start until
+// the replay subscription is ready, and are suspended while the target is paused.
+func replaying(t *config.Target) bool {
+	return t.Replay != nil && t.Replay.Queue != nil && t.Replay.Queue.State == config.State_READY && t.State != config.State_PAUSED
+}
+
+// syncReplays starts a replay handler for each target which replays events, pulling the
+// events of its broker from its replay subscription. Only the events published within
+// the replay window are delivered to the target, the others are acked.
+func (p *RetryPool) syncReplays(ctx context.Context) {
+	p.replays.Range(func(key, value interface{}) bool {
+		t, ok := p.targets.GetTargetByKey(key.(string))
+		if !ok || !replaying(t) {
+			value.(*replayHandlerCache).Stop()
+			p.replays.Delete(key)
+		}
+		return true
+	})
+
+	p.targets.RangeAllTargets(func(t *config.Target) bool {
+		if !replaying(t) {
+			return true
+		}
+		if value, ok := p.replays.Load(t.Key()); ok {
+			if !value.(*replayHandlerCache).shouldRenew(t.Replay) {
+				return true
+			}
+			value.(*replayHandlerCache).Stop()
+			p.replays.Delete(t.Key())
+		}
+
+		sub := p.pubsubClient.Subscription(t.Replay.Queue.Subscription)
+		sub.ReceiveSettings = p.options.PubsubReceiveSettings
+
+		h := NewHandler(
+			sub,
+			processors.ChainProcessors(
+				&filter.Processor{Targets: p.targets},
+				&deliver.Processor{
+					DeliverClient:    p.deliverClient,
+					Targets:          p.targets,
+					StatsReporter:    p.statsReporter,
+					Tokens:           p.options.SubscriberTokens,
+					Deliveries:       p.options.Deliveries,
+					ClaimChecks:      p.options.ClaimChecks,
+					HeaderSecretsDir: p.options.HeaderSecretsDir,
+				},
+			),
+			p.options.TimeoutPerEvent,
+		)
+		h.window = newPublishWindow(t.Replay)
+		hc := &replayHandlerCache{
+			Handler: *h,
+			r:       t.Replay,
+		}
+
+		ctx, err := metrics.AddTargetTags(ctx, t)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to add target tags to context", zap.Error(err))
+		}
+		ctx = handlerctx.WithBrokerKey(ctx, config.BrokerKey(t.Namespace, t.Broker))
+		ctx = handlerctx.WithTargetKey(ctx, t.Key())
+		hc.Start(ctx, func(err error) {
+			if err != nil {
+				logging.FromContext(ctx).Error("replay handler for trigger has stopped with error", zap.String("trigger", t.Key()), zap.Error(err))
+			} else {
+				logging.FromContext(ctx).Info("replay handler for trigger has stopped", zap.String("trigger", t.Key()))
+			}
+		})
+
+		p.replays.Store(t.Key(), hc)
+		return true
+	})
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+)
+
+func TestNewPublishWindow(t *testing.T) {
+	from := time.Date(2020, 10, 1, 8, 0, 0, 0, time.UTC)
+	until := time.Date(2020, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	w := newPublishWindow(&config.Replay{From: timestamppb.New(from), Until: timestamppb.New(until)})
+	for _, tc := range []struct {
+		t    time.Time
+		want bool
+	}{
+		{t: from.Add(-time.Second), want: false},
+		{t: from, want: true},
+		{t: from.Add(30 * time.Minute), want: true},
+		{t: until, want: true},
+		{t: until.Add(time.Second), want: false},
+	} {
+		if got := w.contains(tc.t); got != tc.want {
+			t.Errorf("contains(%v) got=%v, want=%v", tc.t, got, tc.want)
+		}
+	}
+
+	// A replay without a window doesn't deliver any event.
+	if newPublishWindow(&config.Replay{}).contains(time.Now()) {
+		t.Error("Empty replay window shouldn't contain any publish time")
+	}
+}
+
+func TestHandlerPublishWindow(t *testing.T) {
+	ctx := context.Background()
+	c, close := testPubsubClient(ctx, t, testProjectID)
+	defer close()
+
+	topic, err := c.CreateTopic(ctx, testTopic)
+	if err != nil {
+		t.Fatalf("failed to create topic: %v", err)
+	}
+	newSub := func(t *testing.T, id string) *pubsub.Subscription {
+		sub, err := c.CreateSubscription(ctx, id, pubsub.SubscriptionConfig{
+			Topic: topic,
+		})
+		if err != nil {
+			t.Fatalf("failed to create subscription: %v", err)
+		}
+		return sub
+	}
+
+	p, err := cepubsub.New(context.Background(),
+		cepubsub.WithClient(c),
+		cepubsub.WithProjectID(testProjectID),
+		cepubsub.WithTopicID(testTopic),
+	)
+	if err != nil {
+		t.Fatalf("failed to create cloudevents pubsub protocol: %v", err)
+	}
+
+	eventCh := make(chan *event.Event)
+	processor := &processors.FakeProcessor{PrevEventsCh: eventCh}
+	testEvent := event.New()
+	testEvent.SetID("id")
+	testEvent.SetSource("source")
+	testEvent.SetType("type")
+
+	t.Run("event published outside of the window is acked", func(t *testing.T) {
+		h := NewHandler(newSub(t, "outside"), processor, time.Second)
+		h.window = &publishWindow{from: time.Now().Add(-time.Hour), until: time.Now().Add(-time.Minute)}
+		h.Start(ctx, func(err error) {})
+		defer h.Stop()
+
+		if err := p.Send(ctx, binding.ToMessage(&testEvent)); err != nil {
+			t.Fatalf("failed to seed event to pubsub: %v", err)
+		}
+		if gotEvent := nextEventWithTimeout(eventCh); gotEvent != nil {
+			t.Errorf("processor should receive 0 events but got: %+v", gotEvent)
+		}
+	})
+
+	t.Run("event published within the window is processed", func(t *testing.T) {
+		h := NewHandler(newSub(t, "within"), processor, time.Second)
+		h.window = &publishWindow{from: time.Now().Add(-time.Hour), until: time.Now().Add(time.Hour)}
+		h.Start(ctx, func(err error) {})
+		defer h.Stop()
+
+		if err := p.Send(ctx, binding.ToMessage(&testEvent)); err != nil {
+			t.Fatalf("failed to seed event to pubsub: %v", err)
+		}
+		gotEvent := nextEventWithTimeout(eventCh)
+		if diff := cmp.Diff(&testEvent, gotEvent); diff != "" {
+			t.Errorf("processed event (-want,+got): %v", diff)
+		}
+	})
+}
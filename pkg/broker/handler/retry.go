@@ -28,6 +28,7 @@ import (
 
 	"github.com/google/knative-gcp/pkg/broker/config"
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/limiter"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
@@ -42,6 +43,8 @@ type RetryPool struct {
 	options *Options
 	targets config.ReadonlyTargets
 	pool    sync.Map
+	// replays holds the replay handlers, keyed by target.
+	replays sync.Map
 	// Pubsub client used to pull events from decoupling topics.
 	pubsubClient *pubsub.Client
 	// For initial events delivery. We only need a shared client.
@@ -95,6 +98,11 @@ func NewRetryPool(
 	return p, nil
 }
 
+// Targets returns the targets config the pool syncs with.
+func (p *RetryPool) Targets() config.ReadonlyTargets {
+	return p.targets
+}
+
 // SyncOnce syncs once the handler pool based on the targets config.
 func (p *RetryPool) SyncOnce(ctx context.Context) error {
 	ctx, err := p.statsReporter.AddTags(ctx)
@@ -113,8 +121,9 @@ func (p *RetryPool) SyncOnce(ctx context.Context) error {
 
 	p.targets.RangeAllTargets(func(t *config.Target) bool {
 		if value, ok := p.pool.Load(t.Key()); ok {
-			// Skip if we don't need to renew the handler.
-			if !value.(*retryHandlerCache).shouldRenew(t) {
+			// Skip if we don't need to renew the handler. The handler of a paused
+			// target is stopped so that its events stay queued until it's resumed.
+			if t.State != config.State_PAUSED && !value.(*retryHandlerCache).shouldRenew(t) {
 				return true
 			}
 			// Stop and clean up the old handler before we start a new one.
@@ -131,28 +140,33 @@ func (p *RetryPool) SyncOnce(ctx context.Context) error {
 		sub := p.pubsubClient.Subscription(t.RetryQueue.Subscription)
 		sub.ReceiveSettings = p.options.PubsubReceiveSettings
 
+		ctx, err := metrics.AddTargetTags(ctx, t)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to add target tags to context", zap.Error(err))
+		}
+
 		h := NewHandler(
 			sub,
 			processors.ChainProcessors(
 				&filter.Processor{Targets: p.targets},
 				&deliver.Processor{
-					DeliverClient: p.deliverClient,
-					Targets:       p.targets,
-					StatsReporter: p.statsReporter,
+					DeliverClient:    p.deliverClient,
+					Targets:          p.targets,
+					StatsReporter:    p.statsReporter,
+					Tokens:           p.options.SubscriberTokens,
+					Deliveries:       p.options.Deliveries,
+					ClaimChecks:      p.options.ClaimChecks,
+					HeaderSecretsDir: p.options.HeaderSecretsDir,
 				},
 			),
 			p.options.TimeoutPerEvent,
 		)
+		h.limiter = p.newLimiter(ctx)
 		hc := &retryHandlerCache{
 			Handler: *h,
 			t:       t,
 		}
 
-		ctx, err := metrics.AddTargetTags(ctx, t)
-		if err != nil {
-			logging.FromContext(ctx).Error("failed to add target tags to context", zap.Error(err))
-		}
-
 		// Deliver processor needs the broker in the context for reply.
 		ctx = handlerctx.WithBrokerKey(ctx, config.BrokerKey(t.Namespace, t.Broker))
 		ctx = handlerctx.WithTargetKey(ctx, t.Key())
@@ -170,5 +184,33 @@ func (p *RetryPool) SyncOnce(ctx context.Context) error {
 		return true
 	})
 
+	if p.options.Deliveries != nil {
+		// Forget the deliveries of deleted targets.
+		targetIDs := make(map[string]bool)
+		p.targets.RangeAllTargets(func(t *config.Target) bool {
+			targetIDs[t.Id] = true
+			return true
+		})
+		p.options.Deliveries.Retain(targetIDs)
+	}
+
+	p.syncReplays(ctx)
 	return nil
 }
+
+// newLimiter returns the adaptive concurrency limiter of a retry handler, or nil if
+// the concurrency isn't adaptive. The limit is reported with the tags of ctx.
+func (p *RetryPool) newLimiter(ctx context.Context) *limiter.Limiter {
+	if p.options.AdaptiveConcurrency == nil {
+		return nil
+	}
+	settings := *p.options.AdaptiveConcurrency
+	if settings.MaxLimit == 0 {
+		settings.MaxLimit = p.options.PubsubReceiveSettings.MaxOutstandingMessages
+	}
+	l := limiter.New(settings, func(limit int) {
+		p.statsReporter.ReportConcurrencyLimit(ctx, limit)
+	})
+	p.statsReporter.ReportConcurrencyLimit(ctx, l.Limit())
+	return l
+}
@@ -21,13 +21,17 @@ import (
 	"testing"
 	"time"
 
+	"cloud.google.com/go/pubsub"
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/google/go-cmp/cmp"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
 	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	"github.com/google/knative-gcp/pkg/broker/handler/limiter"
 	handlertesting "github.com/google/knative-gcp/pkg/broker/handler/testing"
+	"github.com/google/knative-gcp/pkg/metrics"
 	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
 
 	_ "knative.dev/pkg/metrics/testing"
@@ -109,6 +113,64 @@ func TestRetryWatchAndSync(t *testing.T) {
 		assertRetryHandlers(t, syncPool, helper.Targets)
 	})
 
+	t.Run("replay handler created for target with ready replay queue", func(t *testing.T) {
+		b := helper.GenerateBroker(ctx, t, "ns")
+		target := helper.GenerateTarget(ctx, t, b.Key(), nil)
+		bs = append(bs, b)
+		replay := &config.Replay{
+			Queue: &config.Queue{
+				Topic:        b.DecoupleQueue.Topic,
+				Subscription: b.DecoupleQueue.Subscription,
+				State:        config.State_READY,
+			},
+		}
+		helper.Targets.MutateBroker(b.Namespace, b.Name, func(bm config.BrokerMutation) {
+			target.Replay = replay
+			bm.UpsertTargets(target)
+		})
+		signal <- struct{}{}
+		// Wait a short period for the handlers to be updated.
+		<-time.After(time.Second)
+		assertReplayHandlers(t, syncPool, target.Key())
+
+		helper.Targets.MutateBroker(b.Namespace, b.Name, func(bm config.BrokerMutation) {
+			target.Replay = nil
+			bm.UpsertTargets(target)
+		})
+		signal <- struct{}{}
+		<-time.After(time.Second)
+		assertReplayHandlers(t, syncPool)
+	})
+
+	t.Run("handler stopped for paused target until resumed", func(t *testing.T) {
+		b := helper.GenerateBroker(ctx, t, "ns")
+		target := helper.GenerateTarget(ctx, t, b.Key(), nil)
+		bs = append(bs, b)
+		signal <- struct{}{}
+		// Wait a short period for the handlers to be updated.
+		<-time.After(time.Second)
+		assertRetryHandlers(t, syncPool, helper.Targets)
+
+		helper.Targets.MutateBroker(b.Namespace, b.Name, func(bm config.BrokerMutation) {
+			target.State = config.State_PAUSED
+			bm.UpsertTargets(target)
+		})
+		signal <- struct{}{}
+		<-time.After(time.Second)
+		assertRetryHandlers(t, syncPool, helper.Targets)
+		if _, ok := syncPool.pool.Load(target.Key()); ok {
+			t.Errorf("handler for paused target %q wasn't stopped", target.Key())
+		}
+
+		helper.Targets.MutateBroker(b.Namespace, b.Name, func(bm config.BrokerMutation) {
+			target.State = config.State_READY
+			bm.UpsertTargets(target)
+		})
+		signal <- struct{}{}
+		<-time.After(time.Second)
+		assertRetryHandlers(t, syncPool, helper.Targets)
+	})
+
 	t.Run("deleting all brokers with their targets", func(t *testing.T) {
 		// clean up all brokers
 		for _, b := range bs {
@@ -320,6 +382,37 @@ func TestRetrySyncPoolE2E(t *testing.T) {
 	})
 }
 
+func TestRetryPoolLimiter(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := context.Background()
+	r, err := metrics.NewDeliveryReporter(retryPod, retryContainer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := pubsub.ReceiveSettings{MaxOutstandingMessages: 3}
+
+	p, err := NewRetryPool(memory.NewEmptyTargets(), nil, nil, r, WithPubsubReceiveSettings(rs))
+	if err != nil {
+		t.Fatalf("unexpected error from creating retry pool: %v", err)
+	}
+	if l := p.newLimiter(ctx); l != nil {
+		t.Errorf("limiter got=%v, want nil", l)
+	}
+
+	// The limit is bounded by the max outstanding messages.
+	p, err = NewRetryPool(memory.NewEmptyTargets(), nil, nil, r, WithPubsubReceiveSettings(rs), WithAdaptiveConcurrency(limiter.Settings{InitialLimit: 10}))
+	if err != nil {
+		t.Fatalf("unexpected error from creating retry pool: %v", err)
+	}
+	l := p.newLimiter(ctx)
+	if l == nil {
+		t.Fatal("limiter got nil")
+	}
+	if got := l.Limit(); got != 3 {
+		t.Errorf("limit got=%d, want=3", got)
+	}
+}
+
 func assertRetryHandlers(t *testing.T, p *RetryPool, targets config.Targets) {
 	t.Helper()
 	gotHandlers := make(map[string]bool)
@@ -342,6 +435,24 @@ func assertRetryHandlers(t *testing.T, p *RetryPool, targets config.Targets) {
 	}
 }
 
+func assertReplayHandlers(t *testing.T, p *RetryPool, wantKeys ...string) {
+	t.Helper()
+	gotHandlers := make(map[string]bool)
+	wantHandlers := make(map[string]bool)
+
+	p.replays.Range(func(key, value interface{}) bool {
+		gotHandlers[key.(string)] = true
+		return true
+	})
+	for _, k := range wantKeys {
+		wantHandlers[k] = true
+	}
+
+	if diff := cmp.Diff(wantHandlers, gotHandlers); diff != "" {
+		t.Errorf("replay handlers map (-want,+got): %v", diff)
+	}
+}
+
 func genTestEvent(subject, t, id, source string) event.Event {
 	e := event.New()
 	e.SetSubject(subject)
@@ -0,0 +1,233 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/api/idtoken"
+	"google.golang.org/api/option"
+	authv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+const (
+	// The rejected reasons of the events rejected by the sender authorization.
+	rejectedReasonUnauthenticated = "unauthenticated"
+	rejectedReasonUnauthorized    = "unauthorized"
+
+	// identityCacheSize is the maximum number of authenticated tokens whose identity is cached.
+	identityCacheSize = 10000
+	// identityCacheTTL is how long the identity of an authenticated token is cached. Tokens are
+	// authenticated again after that, so revoked or expired tokens are rejected at most this late.
+	identityCacheTTL = time.Minute
+
+	bearerPrefix = "bearer "
+)
+
+// AuthenticationMode is the kind of tokens the ingress authenticates senders with.
+type AuthenticationMode string
+
+const (
+	// AuthenticationNone only authenticates the senders of the brokers with allowed senders,
+	// using Kubernetes service account tokens. Events sent to other brokers are accepted
+	// without a token.
+	AuthenticationNone AuthenticationMode = ""
+	// AuthenticationKubernetes requires every sender to present a Kubernetes service account
+	// token, verified with the TokenReview API. The identity of the sender is the username of
+	// the service account, e.g. system:serviceaccount:<namespace>:<name>.
+	AuthenticationKubernetes AuthenticationMode = "kubernetes"
+	// AuthenticationOIDC requires every sender to present a Google-signed OIDC ID token. The
+	// identity of the sender is the email claim of the token, or its subject if it has none.
+	AuthenticationOIDC AuthenticationMode = "oidc"
+)
+
+// AuthenticationAudience is the audience the tokens of the senders must be issued for. Any
+// audience is accepted if it's empty.
+type AuthenticationAudience string
+
+// Authenticator verifies the token of a sender.
+type Authenticator interface {
+	// Authenticate returns the identity of the sender of the token. It returns an
+	// ErrUnauthenticated error if the token isn't valid.
+	Authenticate(ctx context.Context, token string) (string, error)
+}
+
+// Authorizer authenticates the senders of events and checks them against the allowed senders
+// of their broker.
+type Authorizer struct {
+	// required is whether every sender must be authenticated, as opposed to only the senders
+	// of the brokers with allowed senders.
+	required      bool
+	authenticator Authenticator
+	// brokerConfig holds the allowed senders of the brokers.
+	brokerConfig config.ReadonlyTargets
+	// identities caches the identities of the authenticated tokens, keyed by token hash.
+	identities *cache.LRUExpireCache
+}
+
+// NewAuthorizer creates an Authorizer authenticating the senders with the tokens of the mode.
+func NewAuthorizer(ctx context.Context, mode AuthenticationMode, audience AuthenticationAudience, kubeClient kubernetes.Interface, brokerConfig config.ReadonlyTargets) (*Authorizer, error) {
+	var authenticator Authenticator
+	switch mode {
+	case AuthenticationNone, AuthenticationKubernetes:
+		authenticator = &tokenReviewAuthenticator{
+			kubeClient: kubeClient,
+			audience:   string(audience),
+		}
+	case AuthenticationOIDC:
+		validator, err := idtoken.NewValidator(ctx, option.WithoutAuthentication())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ID token validator: %w", err)
+		}
+		authenticator = &idTokenAuthenticator{
+			validate: validator.Validate,
+			audience: string(audience),
+		}
+	default:
+		return nil, fmt.Errorf("unknown authentication mode %q", mode)
+	}
+	return newAuthorizer(mode != AuthenticationNone, authenticator, brokerConfig), nil
+}
+
+func newAuthorizer(required bool, authenticator Authenticator, brokerConfig config.ReadonlyTargets) *Authorizer {
+	return &Authorizer{
+		required:      required,
+		authenticator: authenticator,
+		brokerConfig:  brokerConfig,
+		identities:    cache.NewLRUExpireCache(identityCacheSize),
+	}
+}
+
+// Authorize checks that the sender of the Authorization header may send events to the broker.
+// It returns an ErrUnauthenticated error if the sender must be authenticated but has no valid
+// token, and an ErrUnauthorized error if the sender isn't an allowed sender of the broker. A nil
+// Authorizer accepts every sender.
+func (a *Authorizer) Authorize(ctx context.Context, broker types.NamespacedName, authorization string) error {
+	if a == nil {
+		return nil
+	}
+	var allowedSenders []string
+	// Brokers missing from the config are rejected by the decouple sink.
+	if b, ok := a.brokerConfig.GetBroker(broker.Namespace, broker.Name); ok {
+		if b.DenyAllSenders {
+			return fmt.Errorf("%w: %q doesn't allow any sender", ErrUnauthorized, broker)
+		}
+		allowedSenders = b.AllowedSenders
+	}
+	if !a.required && len(allowedSenders) == 0 {
+		return nil
+	}
+
+	token, ok := bearerToken(authorization)
+	if !ok {
+		return fmt.Errorf("%w: missing bearer token", ErrUnauthenticated)
+	}
+	identity, err := a.authenticate(ctx, token)
+	if err != nil {
+		return err
+	}
+	if len(allowedSenders) == 0 {
+		return nil
+	}
+	for _, sender := range allowedSenders {
+		if sender == identity {
+			return nil
+		}
+	}
+	logging.FromContext(ctx).Debug("Sender is not allowed", zap.String("identity", identity))
+	return fmt.Errorf("%w: %q is not an allowed sender of %q", ErrUnauthorized, identity, broker)
+}
+
+// authenticate returns the identity of the sender of the token, from the cache if the token was
+// authenticated recently.
+func (a *Authorizer) authenticate(ctx context.Context, token string) (string, error) {
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+	if identity, ok := a.identities.Get(key); ok {
+		return identity.(string), nil
+	}
+	identity, err := a.authenticator.Authenticate(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	a.identities.Add(key, identity, identityCacheTTL)
+	return identity, nil
+}
+
+// bearerToken returns the token of a bearer Authorization header.
+func bearerToken(authorization string) (string, bool) {
+	if len(authorization) <= len(bearerPrefix) || !strings.EqualFold(authorization[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+	token := strings.TrimSpace(authorization[len(bearerPrefix):])
+	return token, token != ""
+}
+
+// tokenReviewAuthenticator authenticates Kubernetes service account tokens with the TokenReview API.
+type tokenReviewAuthenticator struct {
+	kubeClient kubernetes.Interface
+	audience   string
+}
+
+func (a *tokenReviewAuthenticator) Authenticate(ctx context.Context, token string) (string, error) {
+	review := &authv1.TokenReview{
+		Spec: authv1.TokenReviewSpec{
+			Token: token,
+		},
+	}
+	if a.audience != "" {
+		review.Spec.Audiences = []string{a.audience}
+	}
+	result, err := a.kubeClient.AuthenticationV1().TokenReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to review token: %w", err)
+	}
+	if !result.Status.Authenticated {
+		return "", fmt.Errorf("%w: %s", ErrUnauthenticated, result.Status.Error)
+	}
+	return result.Status.User.Username, nil
+}
+
+// idTokenAuthenticator authenticates Google-signed OIDC ID tokens.
+type idTokenAuthenticator struct {
+	validate func(ctx context.Context, token, audience string) (*idtoken.Payload, error)
+	audience string
+}
+
+func (a *idTokenAuthenticator) Authenticate(ctx context.Context, token string) (string, error) {
+	payload, err := a.validate(ctx, token, a.audience)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+	if email, ok := payload.Claims["email"].(string); ok && email != "" {
+		return email, nil
+	}
+	return payload.Subject, nil
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mime"
+	nethttp "net/http"
+	"sync"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+	"google.golang.org/api/support/bundler"
+	"k8s.io/apimachinery/pkg/types"
+	kntracing "knative.dev/eventing/pkg/tracing"
+
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+const (
+	// The events of a batch are sent to the decouple sink concurrently, up to this limit.
+	maxBatchConcurrency = 100
+
+	// The results of the events of a batch.
+	batchResultAccepted  = "accepted"
+	batchResultInvalid   = "invalid"
+	batchResultThrottled = "throttled"
+	batchResultFailed    = "failed"
+)
+
+// batchResponse is the multi-status response to a batch of events. It lists the
+// results of the events in the order of the batch.
+type batchResponse struct {
+	Results []batchEventResult `json:"results"`
+}
+
+// batchEventResult is the result of one event of a batch.
+type batchEventResult struct {
+	// ID is the ID of the event, if it could be parsed.
+	ID string `json:"id,omitempty"`
+	// Status is the HTTP status code the event would get if it was sent on its own.
+	Status int `json:"status"`
+	// Result is one of accepted, invalid, throttled or failed.
+	Result string `json:"result"`
+	// Error is the reason the event wasn't accepted.
+	Error string `json:"error,omitempty"`
+	// RetryAfter is the number of seconds after which a rate limited event may be sent again.
+	RetryAfter int `json:"retryAfter,omitempty"`
+}
+
+// isBatch returns true if the request is in the CloudEvents batched content mode.
+func isBatch(request *nethttp.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	return err == nil && mediaType == event.ApplicationCloudEventsBatchJSON
+}
+
+// serveBatch sends each event of a batch to the decouple sink and responds with the
+// result of each event. A malformed batch is rejected as a whole.
+func (h *Handler) serveBatch(ctx context.Context, response nethttp.ResponseWriter, request *nethttp.Request, broker types.NamespacedName) {
+	trace.FromContext(ctx).SetName(kntracing.BrokerMessagingDestination(broker))
+
+	var batch []json.RawMessage
+	if err := json.NewDecoder(request.Body).Decode(&batch); err != nil {
+		httpStatus := nethttp.StatusBadRequest
+		if err.Error() == "http: request body too large" {
+			httpStatus = nethttp.StatusRequestEntityTooLarge
+		}
+		logging.FromContext(ctx).Debug("Malformed batch", zap.Error(err))
+		nethttp.Error(response, err.Error(), httpStatus)
+		h.reportMetrics(ctx, "_invalid_cloud_event_", httpStatus, "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, decoupleSinkTimeout)
+	defer cancel()
+	results := make([]batchEventResult, len(batch))
+	sem := make(chan struct{}, maxBatchConcurrency)
+	var wg sync.WaitGroup
+	for i, raw := range batch {
+		e, err := toBatchEvent(raw)
+		if err != nil {
+			results[i] = batchEventResult{
+				Status: nethttp.StatusBadRequest,
+				Result: batchResultInvalid,
+				Error:  err.Error(),
+			}
+			if e != nil {
+				results[i].ID = e.ID()
+			}
+			h.reportMetrics(ctx, "_invalid_cloud_event_", nethttp.StatusBadRequest, "")
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, e *cev2.Event) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = h.sendBatchEvent(ctx, broker, e)
+		}(i, e)
+	}
+	wg.Wait()
+
+	body, err := json.Marshal(batchResponse{Results: results})
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to marshal batch response", zap.Error(err))
+		nethttp.Error(response, err.Error(), nethttp.StatusInternalServerError)
+		return
+	}
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(nethttp.StatusMultiStatus)
+	if _, err := response.Write(body); err != nil {
+		logging.FromContext(ctx).Warn("Failed to write batch response", zap.Error(err))
+	}
+}
+
+// toBatchEvent parses and validates an event of a batch. The event is returned
+// along with the validation error if it could be parsed.
+func toBatchEvent(raw json.RawMessage) (*cev2.Event, error) {
+	e := cev2.NewEvent()
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, err
+	}
+	if e.Time().IsZero() {
+		e.SetTime(time.Now())
+	}
+	if err := e.Validate(); err != nil {
+		return &e, err
+	}
+	return &e, nil
+}
+
+// sendBatchEvent sends an event of a batch to the decouple sink.
+func (h *Handler) sendBatchEvent(ctx context.Context, broker types.NamespacedName, e *cev2.Event) batchEventResult {
+	e.SetExtension(EventArrivalTime, cev2.Timestamp{Time: time.Now()})
+	result := batchEventResult{
+		ID:     e.ID(),
+		Status: nethttp.StatusAccepted,
+		Result: batchResultAccepted,
+	}
+	var reason string
+	if res := h.decouple.Send(ctx, broker, *e); !cev2.IsACK(res) {
+		result.Status = sendErrorStatusCode(res)
+		var rateLimitErr *RateLimitError
+		if errors.As(res, &rateLimitErr) {
+			logging.FromContext(ctx).Debug("Rate limited event", zap.String("event.id", e.ID()), zap.Error(res))
+			reason = rateLimitErr.Reason
+			result.Result = batchResultThrottled
+			result.Error = ErrRateLimited.Error()
+			result.RetryAfter = retryAfterSeconds(rateLimitErr.RetryAfter)
+		} else {
+			logging.FromContext(ctx).Error("Error publishing to PubSub", zap.String("event.id", e.ID()), zap.Error(res))
+			result.Result = batchResultFailed
+			result.Error = "Failed to publish to PubSub"
+			if errors.Is(res, bundler.ErrOverflow) {
+				result.Result = batchResultThrottled
+			}
+		}
+	}
+	h.reportMetrics(ctx, e.Type(), result.Status, reason)
+	return result
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/support/bundler"
+	"k8s.io/apimachinery/pkg/types"
+	logtest "knative.dev/pkg/logging/testing"
+
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+// fakeBatchDecoupleSink records the events it receives and fails the events
+// with a result set for their ID.
+type fakeBatchDecoupleSink struct {
+	results map[string]protocol.Result
+
+	mu     sync.Mutex
+	events map[string]cev2.Event
+}
+
+func (s *fakeBatchDecoupleSink) Send(_ context.Context, _ types.NamespacedName, event cev2.Event) protocol.Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events[event.ID()] = event
+	return s.results[event.ID()]
+}
+
+func TestHandlerBatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		results     map[string]protocol.Result
+		wantCode    int
+		wantResults []batchEventResult
+		wantSent    []string
+	}{{
+		name:     "malformed batch",
+		body:     `{"id":"1"}`,
+		wantCode: nethttp.StatusBadRequest,
+	}, {
+		name:        "empty batch",
+		body:        `[]`,
+		wantCode:    nethttp.StatusMultiStatus,
+		wantResults: []batchEventResult{},
+	}, {
+		name: "all accepted",
+		body: `[{"specversion":"1.0","id":"1","source":"test-source","type":"test-type"},` +
+			`{"specversion":"1.0","id":"2","source":"test-source","type":"test-type","data":{"a":"b"}}]`,
+		wantCode: nethttp.StatusMultiStatus,
+		wantResults: []batchEventResult{
+			{ID: "1", Status: nethttp.StatusAccepted, Result: batchResultAccepted},
+			{ID: "2", Status: nethttp.StatusAccepted, Result: batchResultAccepted},
+		},
+		wantSent: []string{"1", "2"},
+	}, {
+		name: "mixed results",
+		body: `[{"specversion":"1.0","id":"1","source":"test-source","type":"test-type"},` +
+			`{"specversion":"1.0","id":"2","source":"test-source"},` +
+			`"not an event",` +
+			`{"specversion":"1.0","id":"4","source":"test-source","type":"test-type"},` +
+			`{"specversion":"1.0","id":"5","source":"test-source","type":"test-type"}]`,
+		results: map[string]protocol.Result{
+			"4": bundler.ErrOverflow,
+			"5": ErrNotReady,
+		},
+		wantCode: nethttp.StatusMultiStatus,
+		wantResults: []batchEventResult{
+			{ID: "1", Status: nethttp.StatusAccepted, Result: batchResultAccepted},
+			{ID: "2", Status: nethttp.StatusBadRequest, Result: batchResultInvalid},
+			{Status: nethttp.StatusBadRequest, Result: batchResultInvalid},
+			{ID: "4", Status: nethttp.StatusTooManyRequests, Result: batchResultThrottled, Error: "Failed to publish to PubSub"},
+			{ID: "5", Status: nethttp.StatusServiceUnavailable, Result: batchResultFailed, Error: "Failed to publish to PubSub"},
+		},
+		wantSent: []string{"1", "4", "5"},
+	}, {
+		name: "rate limited",
+		body: `[{"specversion":"1.0","id":"1","source":"test-source","type":"test-type"},` +
+			`{"specversion":"1.0","id":"2","source":"test-source","type":"test-type"}]`,
+		results: map[string]protocol.Result{
+			"2": &RateLimitError{Reason: rejectedReasonNamespaceRateLimit, RetryAfter: 1500 * time.Millisecond},
+		},
+		wantCode: nethttp.StatusMultiStatus,
+		wantResults: []batchEventResult{
+			{ID: "1", Status: nethttp.StatusAccepted, Result: batchResultAccepted},
+			{ID: "2", Status: nethttp.StatusTooManyRequests, Result: batchResultThrottled, Error: "rate limit exceeded", RetryAfter: 2},
+		},
+		wantSent: []string{"1", "2"},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetIngressMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sink := &fakeBatchDecoupleSink{results: tc.results, events: make(map[string]cev2.Event)}
+			h := NewHandler(ctx, nil, sink, nil, statsReporter, DefaultMaxRequestBodyBytes)
+
+			req := httptest.NewRequest(nethttp.MethodPost, "/ns1/broker1", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/cloudevents-batch+json; charset=utf-8")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantCode {
+				t.Errorf("StatusCode mismatch. got: %v, want: %v", rec.Code, tc.wantCode)
+			}
+			if tc.wantCode != nethttp.StatusMultiStatus {
+				return
+			}
+			var got batchResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Failed to unmarshal batch response %q: %v", rec.Body.String(), err)
+			}
+			// Validation errors come from the CloudEvents SDK, so only check that one is set.
+			for i := range got.Results {
+				if got.Results[i].Result != batchResultInvalid {
+					continue
+				}
+				if got.Results[i].Error == "" {
+					t.Errorf("Invalid event at index %d is missing an error", i)
+				}
+				got.Results[i].Error = ""
+			}
+			if diff := cmp.Diff(tc.wantResults, got.Results); diff != "" {
+				t.Errorf("Batch results (-want,+got): %v", diff)
+			}
+			if len(sink.events) != len(tc.wantSent) {
+				t.Errorf("Sent events got=%d, want=%d", len(sink.events), len(tc.wantSent))
+			}
+			for _, id := range tc.wantSent {
+				e, ok := sink.events[id]
+				if !ok {
+					t.Errorf("Event %q wasn't sent to the decouple sink", id)
+					continue
+				}
+				if _, ok := e.Extensions()[EventArrivalTime]; !ok {
+					t.Errorf("Event %q is missing the %s extension", id, EventArrivalTime)
+				}
+				if e.Time().IsZero() {
+					t.Errorf("Event %q should be decorated with timestamp, got zero.", id)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// claimCheckDecoupleSink is a DecoupleSink which offloads the data of the large events to
+// Cloud Storage before sending them, so that they fit in a Pub/Sub message.
+type claimCheckDecoupleSink struct {
+	sink DecoupleSink
+	// store offloads the data of the events. If nil, the events are sent as is.
+	store *claimcheck.Store
+}
+
+// NewClaimCheckDecoupleSink creates a DecoupleSink which offloads the data of the large events
+// sent to the multiTopicDecoupleSink to the store, if not nil.
+func NewClaimCheckDecoupleSink(sink *multiTopicDecoupleSink, store *claimcheck.Store) *claimCheckDecoupleSink {
+	return &claimCheckDecoupleSink{
+		sink:  sink,
+		store: store,
+	}
+}
+
+// Send sends the event to the decouple sink, after offloading its data if it's too large.
+func (s *claimCheckDecoupleSink) Send(ctx context.Context, broker types.NamespacedName, event cev2.Event) protocol.Result {
+	// The claim checks are only set by the ingress, so that the senders can't get the data of
+	// other events delivered.
+	if _, ok := event.Extensions()[claimcheck.Extension]; ok {
+		event.SetExtension(claimcheck.Extension, nil)
+	}
+	if s.store == nil {
+		return s.sink.Send(ctx, broker, event)
+	}
+	if err := s.store.Offload(ctx, broker, &event); err != nil {
+		logging.FromContext(ctx).Error("Failed to offload the data of the event", zap.String("event.id", event.ID()), zap.Error(err))
+		return err
+	}
+	res := s.sink.Send(ctx, broker, event)
+	if !cev2.IsACK(res) {
+		if err := s.store.Delete(ctx, &event); err != nil {
+			logging.FromContext(ctx).Warn("Failed to delete the offloaded data of the event", zap.String("event.id", event.ID()), zap.Error(err))
+		}
+	}
+	return res
+}
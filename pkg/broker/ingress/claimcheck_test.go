@@ -0,0 +1,115 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	gstorage "github.com/google/knative-gcp/pkg/gclient/storage/testing"
+)
+
+func TestClaimCheckDecoupleSink(t *testing.T) {
+	ctx := context.Background()
+	broker := types.NamespacedName{Namespace: "ns", Name: "broker"}
+	objects := gstorage.NewObjects()
+	client, err := gstorage.TestClientCreator(gstorage.TestClientData{Objects: objects})(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := claimcheck.NewStore(client, "test-bucket", 5)
+
+	newEvent := func(id, data string) cev2.Event {
+		e := cev2.NewEvent()
+		e.SetID(id)
+		e.SetSource("source")
+		e.SetType("type")
+		e.SetData("text/plain", []byte(data))
+		return e
+	}
+	forged := newEvent("forged", "data")
+	forged.SetExtension(claimcheck.Extension, "gs://test-bucket/ns/broker/other")
+
+	tests := []struct {
+		name          string
+		store         *claimcheck.Store
+		event         cev2.Event
+		result        protocol.Result
+		wantOffloaded bool
+		wantData      string
+	}{{
+		name:     "small event",
+		store:    store,
+		event:    newEvent("small", "data"),
+		wantData: "data",
+	}, {
+		name:          "large event",
+		store:         store,
+		event:         newEvent("large", "large data"),
+		wantOffloaded: true,
+	}, {
+		name:          "large event failed to be published",
+		store:         store,
+		event:         newEvent("failed", "large data"),
+		result:        errors.New("publish error"),
+		wantOffloaded: true,
+	}, {
+		name:     "claim check disabled",
+		event:    newEvent("disabled", "large data"),
+		wantData: "large data",
+	}, {
+		name:     "forged claim check",
+		store:    store,
+		event:    forged,
+		wantData: "data",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sink := &fakeBatchDecoupleSink{
+				results: map[string]protocol.Result{test.event.ID(): test.result},
+				events:  make(map[string]cev2.Event),
+			}
+			s := &claimCheckDecoupleSink{sink: sink, store: test.store}
+			if res := s.Send(ctx, broker, test.event); res != test.result {
+				t.Errorf("Send got result %v, want %v", res, test.result)
+			}
+
+			got := sink.events[test.event.ID()]
+			ref, offloaded := got.Extensions()[claimcheck.Extension].(string)
+			if offloaded != test.wantOffloaded {
+				t.Fatalf("Sent event claim check got=%q, want offloaded=%v", ref, test.wantOffloaded)
+			}
+			if string(got.Data()) != test.wantData {
+				t.Errorf("Sent event data got=%q, want %q", got.Data(), test.wantData)
+			}
+			if !offloaded {
+				return
+			}
+			_, stored := objects.Get("test-bucket", strings.TrimPrefix(ref, "gs://test-bucket/"))
+			if wantStored := test.result == nil; stored != wantStored {
+				t.Errorf("Offloaded object stored got=%v, want %v", stored, wantStored)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/cache"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/metrics"
+)
+
+// DeduplicationCacheSize is the maximum number of event keys kept by the in-memory deduplication store.
+type DeduplicationCacheSize int
+
+// DeduplicationStore keeps the keys of the events sent to brokers with a deduplication window.
+type DeduplicationStore interface {
+	// Reserve reserves the key of an event before it's published. It returns
+	// ErrDuplicateEvent if the event was published within the window, or
+	// ErrDuplicateInProgress if the event is being published.
+	Reserve(ctx context.Context, key string) error
+	// Commit records that the event of a reserved key was published. The key
+	// is kept for the window.
+	Commit(ctx context.Context, key string, window time.Duration) error
+	// Release releases a reserved key, because its event failed to be published.
+	Release(ctx context.Context, key string) error
+}
+
+// deduplicatingDecoupleSink is a DecoupleSink which doesn't send an event again if it
+// was sent to a broker within the broker's deduplication window.
+type deduplicatingDecoupleSink struct {
+	sink DecoupleSink
+	// brokerConfig holds configurations for all brokers.
+	brokerConfig config.ReadonlyTargets
+	store        DeduplicationStore
+	reporter     *metrics.IngressReporter
+}
+
+// NewDeduplicatingDecoupleSink creates a DecoupleSink which deduplicates the events sent to
+// the claimCheckDecoupleSink.
+func NewDeduplicatingDecoupleSink(
+	sink *claimCheckDecoupleSink,
+	brokerConfig config.ReadonlyTargets,
+	store DeduplicationStore,
+	reporter *metrics.IngressReporter) *deduplicatingDecoupleSink {
+
+	return &deduplicatingDecoupleSink{
+		sink:         sink,
+		brokerConfig: brokerConfig,
+		store:        store,
+		reporter:     reporter,
+	}
+}
+
+// Send sends the event to the decouple sink, unless the event was already sent to the broker
+// within its deduplication window.
+func (d *deduplicatingDecoupleSink) Send(ctx context.Context, broker types.NamespacedName, event cev2.Event) protocol.Result {
+	window := d.deduplicationWindow(broker)
+	if window <= 0 {
+		return d.sink.Send(ctx, broker, event)
+	}
+
+	key := deduplicationKey(broker, &event)
+	switch err := d.store.Reserve(ctx, key); {
+	case errors.Is(err, ErrDuplicateEvent):
+		logging.FromContext(ctx).Debug("Deduplicated event", zap.String("event.id", event.ID()), zap.String("event.source", event.Source()))
+		d.reportDeduplicated(ctx, &event)
+		return nil
+	case errors.Is(err, ErrDuplicateInProgress):
+		return err
+	case err != nil:
+		// Rather publish a duplicate than reject the event if the store is unavailable.
+		logging.FromContext(ctx).Warn("Failed to reserve event in deduplication store", zap.String("event.id", event.ID()), zap.Error(err))
+		return d.sink.Send(ctx, broker, event)
+	}
+
+	res := d.sink.Send(ctx, broker, event)
+	if cev2.IsACK(res) {
+		if err := d.store.Commit(ctx, key, window); err != nil {
+			logging.FromContext(ctx).Warn("Failed to commit event in deduplication store", zap.String("event.id", event.ID()), zap.Error(err))
+		}
+	} else if err := d.store.Release(ctx, key); err != nil {
+		logging.FromContext(ctx).Warn("Failed to release event in deduplication store", zap.String("event.id", event.ID()), zap.Error(err))
+	}
+	return res
+}
+
+func (d *deduplicatingDecoupleSink) deduplicationWindow(broker types.NamespacedName) time.Duration {
+	b, ok := d.brokerConfig.GetBroker(broker.Namespace, broker.Name)
+	if !ok || b.GetDeduplicationWindow() == nil {
+		return 0
+	}
+	return b.GetDeduplicationWindow().AsDuration()
+}
+
+func (d *deduplicatingDecoupleSink) reportDeduplicated(ctx context.Context, event *cev2.Event) {
+	args := metrics.IngressReportArgs{EventType: event.Type()}
+	if err := d.reporter.ReportDeduplicatedEventCount(ctx, args); err != nil {
+		logging.FromContext(ctx).Warn("Failed to record metrics.", zap.Error(err))
+	}
+}
+
+// deduplicationKey returns the key identifying an event sent to a broker. Events are identified
+// by their source and id, as required by the CloudEvents spec.
+func deduplicationKey(broker types.NamespacedName, event *cev2.Event) string {
+	// The NUL separator can't be part of the names, the source or the id.
+	return broker.Namespace + "\x00" + broker.Name + "\x00" + event.Source() + "\x00" + event.ID()
+}
+
+type deduplicationState int
+
+const (
+	deduplicationReserved deduplicationState = iota
+	deduplicationCommitted
+)
+
+// lruDeduplicationStore is an in-memory DeduplicationStore which evicts the least
+// recently used keys when it's full. The keys are local to the ingress pod.
+type lruDeduplicationStore struct {
+	// mu makes reserving a key atomic.
+	mu    sync.Mutex
+	cache *cache.LRUExpireCache
+}
+
+// NewLRUDeduplicationStore creates an in-memory DeduplicationStore which keeps at most size keys.
+func NewLRUDeduplicationStore(size DeduplicationCacheSize) *lruDeduplicationStore {
+	return &lruDeduplicationStore{cache: cache.NewLRUExpireCache(int(size))}
+}
+
+func newLRUDeduplicationStoreWithClock(size DeduplicationCacheSize, clock cache.Clock) *lruDeduplicationStore {
+	return &lruDeduplicationStore{cache: cache.NewLRUExpireCacheWithClock(int(size), clock)}
+}
+
+func (s *lruDeduplicationStore) Reserve(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state, ok := s.cache.Get(key); ok {
+		if state == deduplicationCommitted {
+			return ErrDuplicateEvent
+		}
+		return ErrDuplicateInProgress
+	}
+	// A reservation expires in case the event is never committed or released.
+	s.cache.Add(key, deduplicationReserved, decoupleSinkTimeout)
+	return nil
+}
+
+func (s *lruDeduplicationStore) Commit(_ context.Context, key string, window time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache.Add(key, deduplicationCommitted, window)
+	return nil
+}
+
+func (s *lruDeduplicationStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache.Remove(key)
+	return nil
+}
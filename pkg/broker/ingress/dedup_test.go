@@ -0,0 +1,165 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	logtest "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/metrics/metricskey"
+	"knative.dev/pkg/metrics/metricstest"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+// fakeCountingDecoupleSink counts the events it receives and returns the next result.
+type fakeCountingDecoupleSink struct {
+	results []protocol.Result
+	count   int
+}
+
+func (s *fakeCountingDecoupleSink) Send(context.Context, types.NamespacedName, cev2.Event) protocol.Result {
+	s.count++
+	if len(s.results) == 0 {
+		return nil
+	}
+	res := s.results[0]
+	s.results = s.results[1:]
+	return res
+}
+
+func TestLRUDeduplicationStore(t *testing.T) {
+	ctx := context.Background()
+	fakeClock := clock.NewFakeClock(time.Now())
+	s := newLRUDeduplicationStoreWithClock(10, fakeClock)
+
+	if err := s.Reserve(ctx, "key"); err != nil {
+		t.Fatalf("Reserve() got error: %v", err)
+	}
+	if err := s.Reserve(ctx, "key"); !errors.Is(err, ErrDuplicateInProgress) {
+		t.Errorf("Reserve() of reserved key got error %v, want %v", err, ErrDuplicateInProgress)
+	}
+	if err := s.Release(ctx, "key"); err != nil {
+		t.Fatalf("Release() got error: %v", err)
+	}
+	if err := s.Reserve(ctx, "key"); err != nil {
+		t.Fatalf("Reserve() of released key got error: %v", err)
+	}
+	if err := s.Commit(ctx, "key", time.Minute); err != nil {
+		t.Fatalf("Commit() got error: %v", err)
+	}
+	if err := s.Reserve(ctx, "key"); !errors.Is(err, ErrDuplicateEvent) {
+		t.Errorf("Reserve() of committed key got error %v, want %v", err, ErrDuplicateEvent)
+	}
+	if err := s.Reserve(ctx, "other"); err != nil {
+		t.Errorf("Reserve() of other key got error: %v", err)
+	}
+
+	fakeClock.Step(time.Minute + time.Second)
+	if err := s.Reserve(ctx, "key"); err != nil {
+		t.Errorf("Reserve() of expired key got error: %v", err)
+	}
+}
+
+func TestDeduplicatingDecoupleSink(t *testing.T) {
+	tests := []struct {
+		name             string
+		window           time.Duration
+		results          []protocol.Result
+		wantResults      []protocol.Result
+		wantCount        int
+		wantDeduplicated int64
+	}{{
+		name:        "no deduplication window",
+		wantResults: []protocol.Result{nil, nil},
+		wantCount:   2,
+	}, {
+		name:             "duplicate within window",
+		window:           time.Minute,
+		wantResults:      []protocol.Result{nil, nil},
+		wantCount:        1,
+		wantDeduplicated: 1,
+	}, {
+		name:        "failed event is sent again",
+		window:      time.Minute,
+		results:     []protocol.Result{ErrNotReady},
+		wantResults: []protocol.Result{ErrNotReady, nil},
+		wantCount:   2,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetIngressMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			reporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+			if err != nil {
+				t.Fatal(err)
+			}
+			targets := memory.NewEmptyTargets()
+			targets.MutateBroker("ns1", "broker1", func(m config.BrokerMutation) {
+				m.SetDeduplicationWindow(tc.window)
+			})
+			inner := &fakeCountingDecoupleSink{results: tc.results}
+			sink := &deduplicatingDecoupleSink{
+				sink:         inner,
+				brokerConfig: targets,
+				store:        NewLRUDeduplicationStore(10),
+				reporter:     reporter,
+			}
+			broker := types.NamespacedName{Namespace: "ns1", Name: "broker1"}
+			for i, want := range tc.wantResults {
+				if got := sink.Send(ctx, broker, *createTestEvent("test-event")); !errors.Is(got, want) {
+					t.Errorf("Send() #%d got result %v, want %v", i, got, want)
+				}
+			}
+			if inner.count != tc.wantCount {
+				t.Errorf("Sent events got=%d, want=%d", inner.count, tc.wantCount)
+			}
+			if tc.wantDeduplicated == 0 {
+				metricstest.CheckStatsNotReported(t, "deduplicated_event_count")
+			} else {
+				metricstest.CheckCountData(t, "deduplicated_event_count", map[string]string{
+					metricskey.LabelEventType: eventType,
+					metricskey.PodName:        pod,
+					metricskey.ContainerName:  container,
+				}, tc.wantDeduplicated)
+			}
+		})
+	}
+}
+
+func TestDeduplicationKey(t *testing.T) {
+	broker := types.NamespacedName{Namespace: "ns1", Name: "broker1"}
+	e1 := cev2.NewEvent()
+	e1.SetSource("a")
+	e1.SetID("bc")
+	e2 := cev2.NewEvent()
+	e2.SetSource("ab")
+	e2.SetID("c")
+	if deduplicationKey(broker, &e1) == deduplicationKey(broker, &e2) {
+		t.Errorf("Events with different sources and ids got the same key %q", deduplicationKey(broker, &e1))
+	}
+}
@@ -16,7 +16,11 @@ limitations under the License.
 
 package ingress
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 // ErrNotFound is the error when a broker doesn't exist in the configmap.
 // This can happen if the clients specifies invalid broker in the path, or the configmap volume hasn't been updated.
@@ -28,3 +32,35 @@ var ErrIncomplete = errors.New("incomplete config")
 
 // ErrNotReady is the error when a broker is not ready.
 var ErrNotReady = errors.New("not ready")
+
+// ErrDuplicateEvent is the error when an event was already published to a broker within its deduplication window.
+var ErrDuplicateEvent = errors.New("duplicate event")
+
+// ErrDuplicateInProgress is the error when an event sent to a broker with a deduplication window is being published
+// by another request. The sender should retry the event later.
+var ErrDuplicateInProgress = errors.New("duplicate event is being published")
+
+// ErrRateLimited is the error when an event is rejected because its broker or namespace exceeded its rate limit.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimitError is the ErrRateLimited error of an event, along with the limit it exceeded.
+type RateLimitError struct {
+	// Reason is the rejected reason of the event, identifying the exceeded limit.
+	Reason string
+	// RetryAfter is the time after which the limit accepts an event again.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%v (%s), retry after %v", ErrRateLimited, e.Reason, e.RetryAfter)
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// ErrUnauthenticated is the error when the sender of an event must be authenticated but didn't present a valid token.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// ErrUnauthorized is the error when the authenticated sender of an event isn't an allowed sender of its broker.
+var ErrUnauthorized = errors.New("unauthorized")
@@ -0,0 +1,269 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	nethttp "net/http"
+	"sync"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"go.opencensus.io/plugin/ocgrpc"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+	"google.golang.org/api/support/bundler"
+	"google.golang.org/grpc"
+	grpccode "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	grpcstatus "google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/types"
+	kntracing "knative.dev/eventing/pkg/tracing"
+
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/metrics"
+	"github.com/google/knative-gcp/pkg/utils/clients"
+)
+
+const (
+	// maxStreamConcurrency is the maximum number of events of a publish stream sent to the decouple sink at once.
+	maxStreamConcurrency = 100
+
+	// grpcShutdownTimeout is how long in-flight streams are given to finish when the server stops.
+	grpcShutdownTimeout = 30 * time.Second
+)
+
+// GRPCServer receives events over gRPC and persists them to storage (pubsub).
+type GRPCServer struct {
+	port int
+	// decouple is the client to send events to a decouple sink.
+	decouple DecoupleSink
+	// authorizer checks that the senders may send events to the brokers.
+	authorizer *Authorizer
+	logger     *zap.Logger
+	reporter   *metrics.IngressReporter
+	// maxBodyBytes is the max size of the requests.
+	maxBodyBytes int
+}
+
+var _ IngressServer = (*GRPCServer)(nil)
+
+// NewGRPCServer creates a new gRPC ingress server.
+func NewGRPCServer(ctx context.Context, port clients.GRPCPort, decouple DecoupleSink, authorizer *Authorizer, reporter *metrics.IngressReporter, maxBodyBytes MaxRequestBodyBytes) *GRPCServer {
+	return &GRPCServer{
+		port:         int(port),
+		decouple:     decouple,
+		authorizer:   authorizer,
+		reporter:     reporter,
+		logger:       logging.FromContext(ctx),
+		maxBodyBytes: int(maxBodyBytes),
+	}
+}
+
+// Start blocks to receive events over gRPC.
+func (s *GRPCServer) Start(ctx context.Context) error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", s.port))
+	if err != nil {
+		return err
+	}
+	return s.serve(ctx, lis)
+}
+
+func (s *GRPCServer) serve(ctx context.Context, lis net.Listener) error {
+	server := grpc.NewServer(
+		grpc.StatsHandler(&ocgrpc.ServerHandler{}),
+		grpc.MaxRecvMsgSize(s.maxBodyBytes),
+	)
+	RegisterIngressServer(server, s)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.Serve(lis)
+	}()
+
+	select {
+	case <-ctx.Done():
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(grpcShutdownTimeout):
+			server.Stop()
+		}
+		return <-errChan
+	case err := <-errChan:
+		return err
+	}
+}
+
+// Publish implements IngressServer. It sends the events of the stream to the decouple sink
+// concurrently and acknowledges every event once it's sent. The sender of the stream is
+// authorized with the authorization metadata of the stream, for the broker of every event.
+func (s *GRPCServer) Publish(stream Ingress_PublishServer) error {
+	ctx := logging.WithLogger(stream.Context(), s.logger)
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+	var (
+		wg     sync.WaitGroup
+		sendMu sync.Mutex
+	)
+	sem := make(chan struct{}, maxStreamConcurrency)
+	defer wg.Wait()
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(req *PublishRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			res := s.publish(ctx, req, authorization)
+			sendMu.Lock()
+			defer sendMu.Unlock()
+			if err := stream.Send(res); err != nil {
+				logging.FromContext(ctx).Warn("Failed to acknowledge event", zap.String("event.id", res.GetId()), zap.Error(err))
+			}
+		}(req)
+	}
+}
+
+// publish sends the event of the request to the decouple sink, if its sender is authorized.
+func (s *GRPCServer) publish(ctx context.Context, req *PublishRequest, authorization string) *PublishResponse {
+	res := &PublishResponse{
+		Id:     req.GetEvent().GetId(),
+		Source: req.GetEvent().GetSource(),
+	}
+	broker := types.NamespacedName{Namespace: req.GetNamespace(), Name: req.GetBroker()}
+	if broker.Namespace == "" || broker.Name == "" {
+		res.Code = int32(grpccode.InvalidArgument)
+		res.Message = "namespace and broker are required"
+		return res
+	}
+	ctx = withBroker(ctx, broker)
+
+	if err := s.authorizer.Authorize(ctx, broker, authorization); err != nil {
+		statusCode, reason := authorizationErrorStatusCode(err)
+		res.Code = int32(authorizationErrorCode(err))
+		if reason == "" {
+			logging.FromContext(ctx).Error("Failed to authorize sender", zap.Error(err))
+			res.Message = "Failed to authorize sender"
+		} else {
+			logging.FromContext(ctx).Debug("Rejected sender", zap.String("event.id", res.GetId()), zap.Error(err))
+			res.Message = err.Error()
+		}
+		s.reportMetrics(ctx, "", statusCode, reason)
+		return res
+	}
+
+	event, err := EventFromProto(req.GetEvent())
+	if err != nil {
+		logging.FromContext(ctx).Debug("Invalid event", zap.String("event.id", res.GetId()), zap.Error(err))
+		res.Code = int32(grpccode.InvalidArgument)
+		res.Message = err.Error()
+		s.reportMetrics(ctx, "_invalid_cloud_event_", nethttp.StatusBadRequest, "")
+		return res
+	}
+	event.SetExtension(EventArrivalTime, cev2.Timestamp{Time: time.Now()})
+
+	ctx, span := trace.StartSpan(ctx, kntracing.BrokerMessagingDestination(broker), trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+	addEventSpanAttributes(span, broker, event)
+
+	// Metrics are reported with the status code the HTTP ingress would have responded with.
+	statusCode := nethttp.StatusAccepted
+	ctx, cancel := context.WithTimeout(ctx, decoupleSinkTimeout)
+	defer cancel()
+	var reason string
+	defer func() { s.reportMetrics(ctx, event.Type(), statusCode, reason) }()
+	if result := s.decouple.Send(ctx, broker, *event); !cev2.IsACK(result) {
+		statusCode = sendErrorStatusCode(result)
+		res.Code = int32(sendErrorCode(result))
+		var rateLimitErr *RateLimitError
+		if errors.As(result, &rateLimitErr) {
+			logging.FromContext(ctx).Debug("Rate limited event", zap.String("event.id", event.ID()), zap.Error(result))
+			reason = rateLimitErr.Reason
+			res.Message = rateLimitErr.Error()
+			return res
+		}
+		logging.FromContext(ctx).Error("Error publishing to PubSub", zap.String("event.id", event.ID()), zap.Error(result))
+		res.Message = "Failed to publish to PubSub"
+		if grpcstatus.Code(result) == grpccode.PermissionDenied {
+			res.Message = deniedErrMsg
+		}
+		return res
+	}
+	res.Code = int32(grpccode.OK)
+	return res
+}
+
+// sendErrorCode returns the gRPC code for an event the decouple sink failed to send.
+func sendErrorCode(res protocol.Result) grpccode.Code {
+	switch {
+	case errors.Is(res, ErrNotFound):
+		return grpccode.NotFound
+	case errors.Is(res, ErrNotReady):
+		return grpccode.Unavailable
+	case errors.Is(res, bundler.ErrOverflow), errors.Is(res, ErrRateLimited):
+		return grpccode.ResourceExhausted
+	case errors.Is(res, ErrDuplicateInProgress):
+		return grpccode.Aborted
+	case grpcstatus.Code(res) == grpccode.PermissionDenied:
+		return grpccode.PermissionDenied
+	default:
+		return grpccode.Internal
+	}
+}
+
+// authorizationErrorCode returns the gRPC code for an event whose sender failed to be authorized.
+func authorizationErrorCode(err error) grpccode.Code {
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		return grpccode.Unauthenticated
+	case errors.Is(err, ErrUnauthorized):
+		return grpccode.PermissionDenied
+	default:
+		return grpccode.Internal
+	}
+}
+
+func (s *GRPCServer) reportMetrics(ctx context.Context, eventType string, statusCode int, rejectedReason string) {
+	args := metrics.IngressReportArgs{
+		EventType:      eventType,
+		ResponseCode:   statusCode,
+		RejectedReason: rejectedReason,
+	}
+	if err := s.reporter.ReportEventCount(ctx, args); err != nil {
+		logging.FromContext(ctx).Warn("Failed to record metrics.", zap.Error(err))
+	}
+}
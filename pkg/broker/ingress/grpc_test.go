@@ -0,0 +1,162 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/support/bundler"
+	"google.golang.org/grpc"
+	grpccode "google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/testing/protocmp"
+	logtest "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/metrics/metricskey"
+	"knative.dev/pkg/metrics/metricstest"
+
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+func TestGRPCServerPublish(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+	ctx, cancel := context.WithCancel(logtest.TestContextWithLogger(t))
+	statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &fakeBatchDecoupleSink{
+		results: map[string]protocol.Result{
+			"throttled": bundler.ErrOverflow,
+			"not-found": ErrNotFound,
+			"rate-limited": &RateLimitError{
+				Reason:     rejectedReasonBrokerRateLimit,
+				RetryAfter: time.Second,
+			},
+		},
+		events: make(map[string]cev2.Event),
+	}
+	s := NewGRPCServer(ctx, 0, sink, nil, statsReporter, DefaultMaxRequestBodyBytes)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- s.serve(ctx, lis) }()
+	defer func() {
+		cancel()
+		if err := <-served; err != nil {
+			t.Errorf("Server stopped with error: %v", err)
+		}
+	}()
+
+	conn, err := grpc.DialContext(ctx, lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	stream, err := NewIngressClient(conn).Publish(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := []*PublishRequest{
+		{Namespace: "ns1", Broker: "broker1", Event: createTestProtoEvent("accepted")},
+		{Namespace: "ns1", Broker: "broker1", Event: createTestProtoEvent("throttled")},
+		{Namespace: "ns1", Broker: "broker1", Event: createTestProtoEvent("not-found")},
+		{Namespace: "ns1", Broker: "broker1", Event: createTestProtoEvent("rate-limited")},
+		{Namespace: "ns1", Event: createTestProtoEvent("no-broker")},
+		{Namespace: "ns1", Broker: "broker1", Event: &CloudEvent{Id: "invalid", Source: "test-source", SpecVersion: "1.0"}},
+	}
+	for _, req := range requests {
+		if err := stream.Send(req); err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]*PublishResponse)
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to receive response: %v", err)
+		}
+		res.Message = ""
+		got[res.GetId()] = res
+	}
+
+	want := map[string]*PublishResponse{
+		"accepted":     {Id: "accepted", Source: "test-source", Code: int32(grpccode.OK)},
+		"throttled":    {Id: "throttled", Source: "test-source", Code: int32(grpccode.ResourceExhausted)},
+		"not-found":    {Id: "not-found", Source: "test-source", Code: int32(grpccode.NotFound)},
+		"rate-limited": {Id: "rate-limited", Source: "test-source", Code: int32(grpccode.ResourceExhausted)},
+		"no-broker":    {Id: "no-broker", Source: "test-source", Code: int32(grpccode.InvalidArgument)},
+		"invalid":      {Id: "invalid", Source: "test-source", Code: int32(grpccode.InvalidArgument)},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("Publish responses (-want,+got): %v", diff)
+	}
+	if len(sink.events) != 4 {
+		t.Errorf("Sent events got=%d, want=4", len(sink.events))
+	}
+	if e, ok := sink.events["accepted"]; !ok {
+		t.Error("Event accepted wasn't sent to the decouple sink")
+	} else if _, ok := e.Extensions()[EventArrivalTime]; !ok {
+		t.Errorf("Event is missing the %s extension", EventArrivalTime)
+	}
+
+	metricstest.EnsureRecorded()
+	gotCounts := make(map[string]int64)
+	for _, m := range metricstest.GetMetric("event_count") {
+		for _, v := range m.Values {
+			gotCounts[v.Tags[metricskey.LabelEventType]+"/"+v.Tags[metricskey.LabelResponseCode]+"/"+v.Tags["rejected_reason"]] += *v.Int64
+		}
+	}
+	wantCounts := map[string]int64{
+		eventType + "/202/": 1,
+		eventType + "/429/": 1,
+		eventType + "/429/" + rejectedReasonBrokerRateLimit: 1,
+		eventType + "/404/":          1,
+		"_invalid_cloud_event_/400/": 1,
+	}
+	if diff := cmp.Diff(wantCounts, gotCounts); diff != "" {
+		t.Errorf("Event counts by type and response code (-want,+got): %v", diff)
+	}
+}
+
+func createTestProtoEvent(id string) *CloudEvent {
+	return &CloudEvent{
+		Id:          id,
+		Source:      "test-source",
+		SpecVersion: cev2.VersionV1,
+		Type:        eventType,
+		Data:        &CloudEvent_TextData{TextData: `{"key":"value"}`},
+		Attributes: map[string]*CloudEvent_CloudEventAttributeValue{
+			"datacontenttype": stringAttribute(cev2.ApplicationJSON),
+		},
+	}
+}
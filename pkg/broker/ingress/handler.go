@@ -19,7 +19,9 @@ package ingress
 import (
 	"context"
 	"errors"
+	"math"
 	nethttp "net/http"
+	"strconv"
 	"time"
 
 	cev2 "github.com/cloudevents/sdk-go/v2"
@@ -49,8 +51,10 @@ const (
 	// TODO(liu-cong) configurable timeout
 	decoupleSinkTimeout = 30 * time.Second
 
-	// Limit for request payload in bytes (10Mb -- corresponds to message size limit on PubSub as of 09/2020)
-	maxRequestBodyBytes = 10000000
+	// DefaultMaxRequestBodyBytes is the default limit for request payload in bytes (10Mb --
+	// corresponds to message size limit on PubSub as of 09/2020). Larger events are only
+	// accepted if their data is offloaded to Cloud Storage.
+	DefaultMaxRequestBodyBytes MaxRequestBodyBytes = 10000000
 
 	// EventArrivalTime is used to access the metadata stored on a
 	// CloudEvent to measure the time difference between when an event is
@@ -67,17 +71,29 @@ const (
 Please refer to "Configure the Authentication Mechanism for GCP" at https://github.com/google/knative-gcp/blob/master/docs/install/install-gcp-broker.md`
 )
 
-// HandlerSet provides a handler with a real HTTPMessageReceiver and pubsub MultiTopicDecoupleSink.
+// HandlerSet provides a handler with a real HTTPMessageReceiver and pubsub MultiTopicDecoupleSink
+// which deduplicates events with an in-memory DeduplicationStore, after enforcing the rate limits,
+// and offloads the data of large events with a claimcheck.Store if provided.
+// The handler authorizes the senders of events with an Authorizer.
 var HandlerSet wire.ProviderSet = wire.NewSet(
 	NewHandler,
+	NewAuthorizer,
 	clients.NewHTTPMessageReceiver,
 	wire.Bind(new(HttpMessageReceiver), new(*kncloudevents.HTTPMessageReceiver)),
 	NewMultiTopicDecoupleSink,
-	wire.Bind(new(DecoupleSink), new(*multiTopicDecoupleSink)),
+	NewClaimCheckDecoupleSink,
+	NewDeduplicatingDecoupleSink,
+	NewRateLimitingDecoupleSink,
+	wire.Bind(new(DecoupleSink), new(*rateLimitingDecoupleSink)),
+	NewLRUDeduplicationStore,
+	wire.Bind(new(DeduplicationStore), new(*lruDeduplicationStore)),
 	clients.NewPubsubClient,
 	metrics.NewIngressReporter,
 )
 
+// MaxRequestBodyBytes is the max size of the requests received by the ingress.
+type MaxRequestBodyBytes int64
+
 // DecoupleSink is an interface to send events to a decoupling sink (e.g., pubsub).
 type DecoupleSink interface {
 	// Send sends the event from a broker to the corresponding decoupling sink.
@@ -95,17 +111,23 @@ type Handler struct {
 	httpReceiver HttpMessageReceiver
 	// decouple is the client to send events to a decouple sink.
 	decouple DecoupleSink
-	logger   *zap.Logger
-	reporter *metrics.IngressReporter
+	// authorizer checks that the senders may send events to the brokers.
+	authorizer *Authorizer
+	logger     *zap.Logger
+	reporter   *metrics.IngressReporter
+	// maxBodyBytes is the max size of the requests.
+	maxBodyBytes int64
 }
 
 // NewHandler creates a new ingress handler.
-func NewHandler(ctx context.Context, httpReceiver HttpMessageReceiver, decouple DecoupleSink, reporter *metrics.IngressReporter) *Handler {
+func NewHandler(ctx context.Context, httpReceiver HttpMessageReceiver, decouple DecoupleSink, authorizer *Authorizer, reporter *metrics.IngressReporter, maxBodyBytes MaxRequestBodyBytes) *Handler {
 	return &Handler{
 		httpReceiver: httpReceiver,
 		decouple:     decouple,
+		authorizer:   authorizer,
 		reporter:     reporter,
 		logger:       logging.FromContext(ctx),
+		maxBodyBytes: int64(maxBodyBytes),
 	}
 }
 
@@ -117,8 +139,9 @@ func (h *Handler) Start(ctx context.Context) error {
 // ServeHTTP implements net/http Handler interface method.
 // 1. Performs basic validation of the request.
 // 2. Parse request URL to get namespace and broker.
-// 3. Convert request to event.
-// 4. Send event to decouple sink.
+// 3. Authorize the sender of the request.
+// 4. Convert request to event, or to events in the batched content mode.
+// 5. Send event to decouple sink.
 func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Request) {
 	if request.URL.Path == heathCheckPath {
 		response.WriteHeader(nethttp.StatusOK)
@@ -133,11 +156,11 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 		return
 	}
 
-	if request.ContentLength > maxRequestBodyBytes {
+	if request.ContentLength > h.maxBodyBytes {
 		response.WriteHeader(nethttp.StatusRequestEntityTooLarge)
 		return
 	}
-	request.Body = nethttp.MaxBytesReader(nil, request.Body, maxRequestBodyBytes)
+	request.Body = nethttp.MaxBytesReader(nil, request.Body, h.maxBodyBytes)
 
 	broker, err := ConvertPathToNamespacedName(request.URL.Path)
 	if err != nil {
@@ -145,14 +168,28 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 		nethttp.Error(response, err.Error(), nethttp.StatusNotFound)
 		return
 	}
-	ctx = logging.With(ctx, zap.Stringer("broker", broker))
-	ctx = metricskey.WithResource(ctx, resource.Resource{
-		Type: metricskey.ResourceTypeKnativeBroker,
-		Labels: map[string]string{
-			metricskey.LabelNamespaceName: broker.Namespace,
-			metricskey.LabelBrokerName:    broker.Name,
-		},
-	})
+	ctx = withBroker(ctx, broker)
+
+	if err := h.authorizer.Authorize(ctx, broker, request.Header.Get("Authorization")); err != nil {
+		statusCode, reason := authorizationErrorStatusCode(err)
+		if statusCode == nethttp.StatusUnauthorized {
+			response.Header().Set("WWW-Authenticate", "Bearer")
+		}
+		if reason == "" {
+			logging.FromContext(ctx).Error("Failed to authorize sender", zap.Error(err))
+			nethttp.Error(response, "Failed to authorize sender", statusCode)
+		} else {
+			logging.FromContext(ctx).Debug("Rejected sender", zap.Error(err))
+			nethttp.Error(response, err.Error(), statusCode)
+		}
+		h.reportMetrics(ctx, "", statusCode, reason)
+		return
+	}
+
+	if isBatch(request) {
+		h.serveBatch(ctx, response, request, broker)
+		return
+	}
 
 	event, err := h.toEvent(ctx, request)
 	if err != nil {
@@ -161,7 +198,7 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 			httpStatus = nethttp.StatusRequestEntityTooLarge
 		}
 		nethttp.Error(response, err.Error(), httpStatus)
-		h.reportMetrics(ctx, "_invalid_cloud_event_", httpStatus)
+		h.reportMetrics(ctx, "_invalid_cloud_event_", httpStatus, "")
 		return
 	}
 
@@ -169,37 +206,28 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 
 	span := trace.FromContext(ctx)
 	span.SetName(kntracing.BrokerMessagingDestination(broker))
-	if span.IsRecordingEvents() {
-		span.AddAttributes(
-			append(
-				ceclient.EventTraceAttributes(event),
-				kntracing.MessagingSystemAttribute,
-				tracing.PubSubProtocolAttribute,
-				kntracing.BrokerMessagingDestinationAttribute(broker),
-				kntracing.MessagingMessageIDAttribute(event.ID()),
-			)...,
-		)
-	}
+	addEventSpanAttributes(span, broker, event)
 
 	// Optimistically set status code to StatusAccepted. It will be updated if there is an error.
 	// According to the data plane spec (https://github.com/knative/eventing/blob/master/docs/spec/data-plane.md), a
 	// non-callable SINK (which broker is) MUST respond with 202 Accepted if the request is accepted.
 	statusCode := nethttp.StatusAccepted
+	var reason string
 	ctx, cancel := context.WithTimeout(ctx, decoupleSinkTimeout)
 	defer cancel()
-	defer func() { h.reportMetrics(ctx, event.Type(), statusCode) }()
+	defer func() { h.reportMetrics(ctx, event.Type(), statusCode, reason) }()
 	if res := h.decouple.Send(ctx, broker, *event); !cev2.IsACK(res) {
+		statusCode = sendErrorStatusCode(res)
+		var rateLimitErr *RateLimitError
+		if errors.As(res, &rateLimitErr) {
+			logging.FromContext(ctx).Debug("Rate limited event", zap.String("event.id", event.ID()), zap.Error(res))
+			reason = rateLimitErr.Reason
+			response.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(rateLimitErr.RetryAfter)))
+			nethttp.Error(response, ErrRateLimited.Error(), statusCode)
+			return
+		}
 		logging.FromContext(ctx).Error("Error publishing to PubSub", zap.Error(res))
-		statusCode = nethttp.StatusInternalServerError
-
-		switch {
-		case errors.Is(res, ErrNotFound):
-			statusCode = nethttp.StatusNotFound
-		case errors.Is(res, ErrNotReady):
-			statusCode = nethttp.StatusServiceUnavailable
-		case errors.Is(res, bundler.ErrOverflow):
-			statusCode = nethttp.StatusTooManyRequests
-		case grpcstatus.Code(res) == grpccode.PermissionDenied:
+		if grpcstatus.Code(res) == grpccode.PermissionDenied {
 			nethttp.Error(response, deniedErrMsg, statusCode)
 			return
 		}
@@ -210,6 +238,62 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 	response.WriteHeader(statusCode)
 }
 
+// withBroker adds the broker to the logger and the metrics resource of the context.
+func withBroker(ctx context.Context, broker types.NamespacedName) context.Context {
+	ctx = logging.With(ctx, zap.Stringer("broker", broker))
+	return metricskey.WithResource(ctx, resource.Resource{
+		Type: metricskey.ResourceTypeKnativeBroker,
+		Labels: map[string]string{
+			metricskey.LabelNamespaceName: broker.Namespace,
+			metricskey.LabelBrokerName:    broker.Name,
+		},
+	})
+}
+
+// addEventSpanAttributes adds the attributes of an event sent to the broker to the span.
+func addEventSpanAttributes(span *trace.Span, broker types.NamespacedName, event *cev2.Event) {
+	if span.IsRecordingEvents() {
+		span.AddAttributes(
+			append(
+				ceclient.EventTraceAttributes(event),
+				kntracing.MessagingSystemAttribute,
+				tracing.PubSubProtocolAttribute,
+				kntracing.BrokerMessagingDestinationAttribute(broker),
+				kntracing.MessagingMessageIDAttribute(event.ID()),
+			)...,
+		)
+	}
+}
+
+// sendErrorStatusCode returns the HTTP status code for an event the decouple sink failed to send.
+func sendErrorStatusCode(res protocol.Result) int {
+	switch {
+	case errors.Is(res, ErrNotFound):
+		return nethttp.StatusNotFound
+	case errors.Is(res, ErrNotReady):
+		return nethttp.StatusServiceUnavailable
+	case errors.Is(res, bundler.ErrOverflow), errors.Is(res, ErrRateLimited):
+		return nethttp.StatusTooManyRequests
+	case errors.Is(res, ErrDuplicateInProgress):
+		return nethttp.StatusConflict
+	default:
+		return nethttp.StatusInternalServerError
+	}
+}
+
+// authorizationErrorStatusCode returns the HTTP status code and the rejected reason of a request
+// whose sender failed to be authorized.
+func authorizationErrorStatusCode(err error) (int, string) {
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		return nethttp.StatusUnauthorized, rejectedReasonUnauthenticated
+	case errors.Is(err, ErrUnauthorized):
+		return nethttp.StatusForbidden, rejectedReasonUnauthorized
+	default:
+		return nethttp.StatusInternalServerError, ""
+	}
+}
+
 // toEvent converts an http request to an event.
 func (h *Handler) toEvent(ctx context.Context, request *nethttp.Request) (*cev2.Event, error) {
 	message := http.NewMessageFromHttpRequest(request)
@@ -231,10 +315,16 @@ func (h *Handler) toEvent(ctx context.Context, request *nethttp.Request) (*cev2.
 	return event, nil
 }
 
-func (h *Handler) reportMetrics(ctx context.Context, eventType string, statusCode int) {
+// retryAfterSeconds returns the number of seconds of the Retry-After header for a delay.
+func retryAfterSeconds(delay time.Duration) int {
+	return int(math.Max(1, math.Ceil(delay.Seconds())))
+}
+
+func (h *Handler) reportMetrics(ctx context.Context, eventType string, statusCode int, rejectedReason string) {
 	args := metrics.IngressReportArgs{
-		EventType:    eventType,
-		ResponseCode: statusCode,
+		EventType:      eventType,
+		ResponseCode:   statusCode,
+		RejectedReason: rejectedReason,
 	}
 	if err := h.reporter.ReportEventCount(ctx, args); err != nil {
 		logging.FromContext(ctx).Warn("Failed to record metrics.", zap.Error(err))
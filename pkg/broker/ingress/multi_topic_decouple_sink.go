@@ -33,6 +33,7 @@ import (
 	"github.com/cloudevents/sdk-go/v2/extensions"
 	"github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
 	"github.com/google/knative-gcp/pkg/logging"
 )
@@ -75,7 +76,7 @@ type multiTopicDecoupleSink struct {
 
 // Send sends incoming event to its corresponding pubsub topic based on which broker it belongs to.
 func (m *multiTopicDecoupleSink) Send(ctx context.Context, broker types.NamespacedName, event cev2.Event) protocol.Result {
-	topic, err := m.getTopicForBroker(ctx, broker)
+	topic, orderingKeyAttribute, err := m.getTopicForBroker(ctx, broker)
 	if err != nil {
 		trace.FromContext(ctx).Annotate(
 			[]trace.Attribute{
@@ -100,13 +101,23 @@ func (m *multiTopicDecoupleSink) Send(ctx context.Context, broker types.Namespac
 		return err
 	}
 
+	if topic.EnableMessageOrdering {
+		msg.OrderingKey = eventutil.OrderingKey(&event, orderingKeyAttribute)
+	}
+
 	_, err = topic.Publish(ctx, msg).Get(ctx)
+	if err != nil && msg.OrderingKey != "" {
+		// Publishing with the ordering key is paused after a failure so that later events
+		// don't overtake this one. The sender gets the error and is expected to resend the
+		// event, so resume publishing.
+		topic.ResumePublish(msg.OrderingKey)
+	}
 	return err
 }
 
 // eventFilterFunc is used to see if a target is interested in an event.
 // It is used as a vaiable to allow stubbing out in unit tests.
-var eventFilterFunc = filter.PassFilter
+var eventFilterFunc = filter.PassTarget
 
 // enableEventFilterFunc is a temporary function to control enabling and
 // disabling trigger-less event filtering in ingress.
@@ -123,7 +134,7 @@ func isEventFilteringEnabled() bool {
 func (m *multiTopicDecoupleSink) hasTrigger(ctx context.Context, event *cev2.Event) bool {
 	hasTrigger := false
 	m.brokerConfig.RangeAllTargets(func(target *config.Target) bool {
-		if eventFilterFunc(ctx, target.FilterAttributes, event) {
+		if eventFilterFunc(ctx, target, event) {
 			hasTrigger = true
 			return false
 		}
@@ -135,16 +146,17 @@ func (m *multiTopicDecoupleSink) hasTrigger(ctx context.Context, event *cev2.Eve
 }
 
 // getTopicForBroker finds the corresponding decouple topic for the broker from the mounted broker configmap volume.
-func (m *multiTopicDecoupleSink) getTopicForBroker(ctx context.Context, broker types.NamespacedName) (*pubsub.Topic, error) {
-	topicID, err := m.getTopicIDForBroker(ctx, broker)
+// It also returns the attribute the ordering key of the broker's events is derived from, if any.
+func (m *multiTopicDecoupleSink) getTopicForBroker(ctx context.Context, broker types.NamespacedName) (*pubsub.Topic, string, error) {
+	brokerConfig, err := m.getBrokerConfig(ctx, broker)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	if topic, ok := m.getExistingTopic(broker); ok {
-		// Check that the broker's topic ID hasn't changed.
-		if topic.ID() == topicID {
-			return topic, nil
+		// Check that the broker's topic ID and ordering haven't changed.
+		if isTopicForBroker(topic, brokerConfig) {
+			return topic, brokerConfig.OrderingKeyAttribute, nil
 		}
 	}
 
@@ -152,46 +164,53 @@ func (m *multiTopicDecoupleSink) getTopicForBroker(ctx context.Context, broker t
 	return m.updateTopicForBroker(ctx, broker)
 }
 
-func (m *multiTopicDecoupleSink) updateTopicForBroker(ctx context.Context, broker types.NamespacedName) (*pubsub.Topic, error) {
+func (m *multiTopicDecoupleSink) updateTopicForBroker(ctx context.Context, broker types.NamespacedName) (*pubsub.Topic, string, error) {
 	m.topicsMut.Lock()
 	defer m.topicsMut.Unlock()
 	// Fetch latest decouple topic ID under lock.
-	topicID, err := m.getTopicIDForBroker(ctx, broker)
+	brokerConfig, err := m.getBrokerConfig(ctx, broker)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	if topic, ok := m.topics[broker]; ok {
-		if topic.ID() == topicID {
+		if isTopicForBroker(topic, brokerConfig) {
 			// Topic already updated.
-			return topic, nil
+			return topic, brokerConfig.OrderingKeyAttribute, nil
 		}
 		// Stop old topic.
 		m.topics[broker].Stop()
 	}
-	topic := m.pubsub.Topic(topicID)
+	topic := m.pubsub.Topic(brokerConfig.DecoupleQueue.Topic)
+	topic.EnableMessageOrdering = brokerConfig.OrderingKeyAttribute != ""
 	m.topics[broker] = topic
-	return topic, nil
+	return topic, brokerConfig.OrderingKeyAttribute, nil
+}
+
+// isTopicForBroker checks that the topic publishes to the decouple topic of the broker with the
+// broker's ordering.
+func isTopicForBroker(topic *pubsub.Topic, brokerConfig *config.Broker) bool {
+	return topic.ID() == brokerConfig.DecoupleQueue.Topic && topic.EnableMessageOrdering == (brokerConfig.OrderingKeyAttribute != "")
 }
 
-func (m *multiTopicDecoupleSink) getTopicIDForBroker(ctx context.Context, broker types.NamespacedName) (string, error) {
+func (m *multiTopicDecoupleSink) getBrokerConfig(ctx context.Context, broker types.NamespacedName) (*config.Broker, error) {
 	brokerConfig, ok := m.brokerConfig.GetBroker(broker.Namespace, broker.Name)
 	if !ok {
 		// There is an propagation delay between the controller reconciles the broker config and
 		// the config being pushed to the configmap volume in the ingress pod. So sometimes we return
 		// an error even if the request is valid.
 		logging.FromContext(ctx).Warn("config is not found for")
-		return "", fmt.Errorf("%q: %w", broker, ErrNotFound)
+		return nil, fmt.Errorf("%q: %w", broker, ErrNotFound)
 	}
 	if brokerConfig.DecoupleQueue == nil || brokerConfig.DecoupleQueue.Topic == "" {
 		logging.FromContext(ctx).Error("DecoupleQueue or topic missing for broker, this should NOT happen.", zap.Any("brokerConfig", brokerConfig))
-		return "", fmt.Errorf("decouple queue of %q: %w", broker, ErrIncomplete)
+		return nil, fmt.Errorf("decouple queue of %q: %w", broker, ErrIncomplete)
 	}
 	if brokerConfig.DecoupleQueue.State != config.State_READY {
 		logging.FromContext(ctx).Debug("decouple queue is not ready")
-		return "", fmt.Errorf("%q: %w", broker, ErrNotReady)
+		return nil, fmt.Errorf("%q: %w", broker, ErrNotReady)
 	}
-	return brokerConfig.DecoupleQueue.Topic, nil
+	return brokerConfig, nil
 }
 
 func (m *multiTopicDecoupleSink) getExistingTopic(broker types.NamespacedName) (*pubsub.Topic, bool) {
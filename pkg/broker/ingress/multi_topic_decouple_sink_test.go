@@ -53,9 +53,10 @@ func TestMultiTopicDecoupleSink(t *testing.T) {
 	brokerTargets := map[string]*config.Target{"target": {}}
 
 	type brokerTestCase struct {
-		broker  types.NamespacedName
-		topic   string
-		wantErr bool
+		broker          types.NamespacedName
+		topic           string
+		wantOrderingKey string
+		wantErr         bool
 	}
 	tests := []struct {
 		name         string
@@ -104,6 +105,24 @@ func TestMultiTopicDecoupleSink(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "ordered broker",
+			brokerConfig: &config.TargetsConfig{
+				Brokers: map[string]*config.Broker{
+					"test_ns_1/test_broker_1": {DecoupleQueue: &config.Queue{Topic: "test_topic_1", State: config.State_READY}, Targets: brokerTargets, OrderingKeyAttribute: "source"},
+				},
+			},
+			cases: []brokerTestCase{
+				{
+					broker: types.NamespacedName{
+						Namespace: "test_ns_1",
+						Name:      "test_broker_1",
+					},
+					topic:           "test_topic_1",
+					wantOrderingKey: "test-source",
+				},
+			},
+		},
 		{
 			name: "broker doesn't exist in config",
 			brokerConfig: &config.TargetsConfig{
@@ -193,7 +212,7 @@ func TestMultiTopicDecoupleSink(t *testing.T) {
 					}
 				}
 				subscription, err := psClient.CreateSubscription(
-					ctx, fmt.Sprintf("test-sub-%d", i), pubsub.SubscriptionConfig{Topic: topic})
+					ctx, fmt.Sprintf("test-sub-%d", i), pubsub.SubscriptionConfig{Topic: topic, EnableMessageOrdering: testCase.wantOrderingKey != ""})
 				if err != nil {
 					t.Fatal(err)
 				}
@@ -224,6 +243,9 @@ func TestMultiTopicDecoupleSink(t *testing.T) {
 						},
 					)
 					msg := <-msgCh
+					if msg.OrderingKey != testCase.wantOrderingKey {
+						t.Errorf("Message ordering key got=%q, want=%q", msg.OrderingKey, testCase.wantOrderingKey)
+					}
 					if got, err := binding.ToEvent(ctx, cepubsub.NewMessage(msg)); err != nil {
 						t.Error(err)
 					} else if diff := cmp.Diff(event, got); diff != "" {
@@ -519,6 +541,31 @@ func TestHasTrigger(t *testing.T) {
 			},
 			hasTrigger: false,
 		},
+		{
+			name: "broker with target with matching filter expression",
+			brokerTargets: map[string]*config.Target{
+				"target_1": {
+					Filters: []*config.Filter{{
+						Prefix: map[string]string{"type": "google.cloud."},
+					}},
+				},
+			},
+			hasTrigger: true,
+		},
+		{
+			name: "broker with target with non-matching filter expression",
+			brokerTargets: map[string]*config.Target{
+				"target_1": {
+					FilterAttributes: map[string]string{
+						"type": eventType,
+					},
+					Filters: []*config.Filter{{
+						Not: &config.Filter{Exact: map[string]string{"type": eventType}},
+					}},
+				},
+			},
+			hasTrigger: false,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -560,7 +607,7 @@ func TestMultiTopicDecoupleSinkSendChecksFilter(t *testing.T) {
 	filterCalled := false
 	origEventFilterFunc := eventFilterFunc
 	defer func() { eventFilterFunc = origEventFilterFunc }()
-	eventFilterFunc = func(ctx context.Context, attrs map[string]string, event *event.Event) bool {
+	eventFilterFunc = func(ctx context.Context, target *config.Target, event *event.Event) bool {
 		filterCalled = true
 		return true
 	}
@@ -617,7 +664,7 @@ func TestMultiTopicDecoupleSinkSendDoesNotChecksFilterWhenFeatureDisabled(t *tes
 	filterCalled := false
 	origEventFilterFunc := eventFilterFunc
 	defer func() { eventFilterFunc = origEventFilterFunc }()
-	eventFilterFunc = func(ctx context.Context, attrs map[string]string, event *event.Event) bool {
+	eventFilterFunc = func(ctx context.Context, target *config.Target, event *event.Event) bool {
 		filterCalled = true
 		return true
 	}
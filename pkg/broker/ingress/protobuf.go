@@ -0,0 +1,233 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// ApplicationProtobuf is the data content type of events with proto data.
+	ApplicationProtobuf = "application/protobuf"
+
+	datacontenttype = "datacontenttype"
+	dataschema      = "dataschema"
+	subject         = "subject"
+	eventTime       = "time"
+)
+
+// EventFromProto converts an event in the CloudEvents protobuf format to an
+// event. The time of the event is set to now if it's missing.
+func EventFromProto(pe *CloudEvent) (*cev2.Event, error) {
+	if pe == nil {
+		return nil, errors.New("event is required")
+	}
+	e := cev2.NewEvent(pe.GetSpecVersion())
+	if e.Context == nil {
+		return nil, e.Validate()
+	}
+	e.SetID(pe.GetId())
+	e.SetSource(pe.GetSource())
+	e.SetType(pe.GetType())
+	for name, attr := range pe.GetAttributes() {
+		value, err := attributeValueFromProto(attr)
+		if err != nil {
+			return &e, fmt.Errorf("attribute %q: %w", name, err)
+		}
+		switch name {
+		case datacontenttype, subject:
+			s, err := cetypes.ToString(value)
+			if err != nil {
+				return &e, fmt.Errorf("attribute %q: %w", name, err)
+			}
+			if name == datacontenttype {
+				e.SetDataContentType(s)
+			} else {
+				e.SetSubject(s)
+			}
+		case dataschema:
+			u, err := cetypes.ToURL(value)
+			if err != nil {
+				return &e, fmt.Errorf("attribute %q: %w", name, err)
+			}
+			e.SetDataSchema(u.String())
+		case eventTime:
+			t, err := cetypes.ToTime(value)
+			if err != nil {
+				return &e, fmt.Errorf("attribute %q: %w", name, err)
+			}
+			e.SetTime(t)
+		default:
+			e.SetExtension(name, value)
+		}
+	}
+
+	switch data := pe.GetData().(type) {
+	case *CloudEvent_BinaryData:
+		e.DataEncoded = data.BinaryData
+		e.DataBase64 = true
+	case *CloudEvent_TextData:
+		e.DataEncoded = []byte(data.TextData)
+	case *CloudEvent_ProtoData:
+		e.DataEncoded = data.ProtoData.GetValue()
+		e.DataBase64 = true
+		if e.DataContentType() == "" {
+			e.SetDataContentType(ApplicationProtobuf)
+		}
+	}
+
+	if e.Time().IsZero() {
+		e.SetTime(time.Now())
+	}
+	if err := e.Validate(); err != nil {
+		return &e, err
+	}
+	return &e, nil
+}
+
+// attributeValueFromProto converts an attribute value to one of the
+// CloudEvents attribute types.
+func attributeValueFromProto(attr *CloudEvent_CloudEventAttributeValue) (interface{}, error) {
+	switch v := attr.GetAttr().(type) {
+	case *CloudEvent_CloudEventAttributeValue_CeBoolean:
+		return v.CeBoolean, nil
+	case *CloudEvent_CloudEventAttributeValue_CeInteger:
+		return v.CeInteger, nil
+	case *CloudEvent_CloudEventAttributeValue_CeString:
+		return v.CeString, nil
+	case *CloudEvent_CloudEventAttributeValue_CeBytes:
+		return v.CeBytes, nil
+	case *CloudEvent_CloudEventAttributeValue_CeUri:
+		return cetypes.ToURL(v.CeUri)
+	case *CloudEvent_CloudEventAttributeValue_CeUriRef:
+		u, err := cetypes.ToURL(v.CeUriRef)
+		if err != nil {
+			return nil, err
+		}
+		return cetypes.URIRef{URL: *u}, nil
+	case *CloudEvent_CloudEventAttributeValue_CeTimestamp:
+		if err := v.CeTimestamp.CheckValid(); err != nil {
+			return nil, err
+		}
+		return v.CeTimestamp.AsTime(), nil
+	default:
+		return nil, errors.New("value is required")
+	}
+}
+
+// EventToProto converts an event to the CloudEvents protobuf format.
+func EventToProto(e *cev2.Event) (*CloudEvent, error) {
+	pe := &CloudEvent{
+		Id:          e.ID(),
+		Source:      e.Source(),
+		SpecVersion: e.SpecVersion(),
+		Type:        e.Type(),
+		Attributes:  make(map[string]*CloudEvent_CloudEventAttributeValue),
+	}
+	if ct := e.DataContentType(); ct != "" {
+		pe.Attributes[datacontenttype] = stringAttribute(ct)
+	}
+	if ds := e.DataSchema(); ds != "" {
+		pe.Attributes[dataschema] = &CloudEvent_CloudEventAttributeValue{
+			Attr: &CloudEvent_CloudEventAttributeValue_CeUri{CeUri: ds},
+		}
+	}
+	if s := e.Subject(); s != "" {
+		pe.Attributes[subject] = stringAttribute(s)
+	}
+	if t := e.Time(); !t.IsZero() {
+		pe.Attributes[eventTime] = &CloudEvent_CloudEventAttributeValue{
+			Attr: &CloudEvent_CloudEventAttributeValue_CeTimestamp{CeTimestamp: timestamppb.New(t)},
+		}
+	}
+	for name, value := range e.Extensions() {
+		attr, err := attributeValueToProto(value)
+		if err != nil {
+			return nil, fmt.Errorf("extension %q: %w", name, err)
+		}
+		pe.Attributes[name] = attr
+	}
+
+	if data := e.Data(); data != nil {
+		if !e.DataBase64 && isTextContentType(e.DataContentType()) {
+			pe.Data = &CloudEvent_TextData{TextData: string(data)}
+		} else {
+			pe.Data = &CloudEvent_BinaryData{BinaryData: data}
+		}
+	}
+	return pe, nil
+}
+
+// attributeValueToProto converts a CloudEvents attribute value to the
+// protobuf format.
+func attributeValueToProto(value interface{}) (*CloudEvent_CloudEventAttributeValue, error) {
+	value, err := cetypes.Validate(value)
+	if err != nil {
+		return nil, err
+	}
+	attr := &CloudEvent_CloudEventAttributeValue{}
+	switch v := value.(type) {
+	case bool:
+		attr.Attr = &CloudEvent_CloudEventAttributeValue_CeBoolean{CeBoolean: v}
+	case int32:
+		attr.Attr = &CloudEvent_CloudEventAttributeValue_CeInteger{CeInteger: v}
+	case string:
+		attr.Attr = &CloudEvent_CloudEventAttributeValue_CeString{CeString: v}
+	case []byte:
+		attr.Attr = &CloudEvent_CloudEventAttributeValue_CeBytes{CeBytes: v}
+	case cetypes.URI:
+		attr.Attr = &CloudEvent_CloudEventAttributeValue_CeUri{CeUri: v.String()}
+	case cetypes.URIRef:
+		attr.Attr = &CloudEvent_CloudEventAttributeValue_CeUriRef{CeUriRef: v.String()}
+	case cetypes.Timestamp:
+		attr.Attr = &CloudEvent_CloudEventAttributeValue_CeTimestamp{CeTimestamp: timestamppb.New(v.Time)}
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+	return attr, nil
+}
+
+func stringAttribute(s string) *CloudEvent_CloudEventAttributeValue {
+	return &CloudEvent_CloudEventAttributeValue{
+		Attr: &CloudEvent_CloudEventAttributeValue_CeString{CeString: s},
+	}
+}
+
+// isTextContentType returns true if data of the content type is text.
+func isTextContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	case mediaType == event.ApplicationJSON, mediaType == event.ApplicationXML:
+		return true
+	default:
+		return strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml")
+	}
+}
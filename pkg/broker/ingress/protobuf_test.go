@@ -0,0 +1,198 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"testing"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestEventProtoRoundTrip(t *testing.T) {
+	eventTime := time.Date(2020, 10, 1, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		event func() cev2.Event
+	}{{
+		name: "json data",
+		event: func() cev2.Event {
+			e := cev2.NewEvent()
+			e.SetID("id")
+			e.SetSource("source")
+			e.SetType("type")
+			e.SetTime(eventTime)
+			e.SetSubject("subject")
+			e.SetDataSchema("https://example.com/schema")
+			if err := e.SetData(cev2.ApplicationJSON, map[string]string{"key": "value"}); err != nil {
+				t.Fatal(err)
+			}
+			return e
+		},
+	}, {
+		name: "binary data",
+		event: func() cev2.Event {
+			e := cev2.NewEvent()
+			e.SetID("id")
+			e.SetSource("source")
+			e.SetType("type")
+			e.SetTime(eventTime)
+			if err := e.SetData("application/octet-stream", []byte{0, 1, 2}); err != nil {
+				t.Fatal(err)
+			}
+			return e
+		},
+	}, {
+		name: "extensions",
+		event: func() cev2.Event {
+			e := cev2.NewEvent()
+			e.SetID("id")
+			e.SetSource("source")
+			e.SetType("type")
+			e.SetTime(eventTime)
+			e.SetExtension("boolext", true)
+			e.SetExtension("intext", 42)
+			e.SetExtension("stringext", "value")
+			e.SetExtension("timeext", eventTime)
+			return e
+		},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			want := tc.event()
+			pe, err := EventToProto(&want)
+			if err != nil {
+				t.Fatalf("EventToProto() failed: %v", err)
+			}
+			got, err := EventFromProto(pe)
+			if err != nil {
+				t.Fatalf("EventFromProto() failed: %v", err)
+			}
+			if diff := cmp.Diff(want.String(), got.String()); diff != "" {
+				t.Errorf("Round trip event (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestEventToProtoText(t *testing.T) {
+	e := cev2.NewEvent()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	if err := e.SetData("text/plain", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := EventToProto(&e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &CloudEvent{
+		Id:          "id",
+		Source:      "source",
+		SpecVersion: cev2.VersionV1,
+		Type:        "type",
+		Attributes: map[string]*CloudEvent_CloudEventAttributeValue{
+			"datacontenttype": stringAttribute("text/plain"),
+		},
+		Data: &CloudEvent_TextData{TextData: "hello"},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("EventToProto (-want,+got): %v", diff)
+	}
+}
+
+func TestEventFromProto(t *testing.T) {
+	uriRef := &CloudEvent_CloudEventAttributeValue{
+		Attr: &CloudEvent_CloudEventAttributeValue_CeUriRef{CeUriRef: "/relative"},
+	}
+	tests := []struct {
+		name    string
+		pe      *CloudEvent
+		wantErr bool
+		check   func(t *testing.T, e *cev2.Event)
+	}{{
+		name:    "nil event",
+		wantErr: true,
+	}, {
+		name:    "unknown spec version",
+		pe:      &CloudEvent{Id: "id", Source: "source", Type: "type", SpecVersion: "2.0"},
+		wantErr: true,
+	}, {
+		name:    "missing type",
+		pe:      &CloudEvent{Id: "id", Source: "source", SpecVersion: cev2.VersionV1},
+		wantErr: true,
+	}, {
+		name: "attribute without value",
+		pe: &CloudEvent{Id: "id", Source: "source", Type: "type", SpecVersion: cev2.VersionV1,
+			Attributes: map[string]*CloudEvent_CloudEventAttributeValue{"ext": {}}},
+		wantErr: true,
+	}, {
+		name: "time is set",
+		pe:   &CloudEvent{Id: "id", Source: "source", Type: "type", SpecVersion: cev2.VersionV1},
+		check: func(t *testing.T, e *cev2.Event) {
+			if e.Time().IsZero() {
+				t.Error("Event should be decorated with timestamp, got zero.")
+			}
+		},
+	}, {
+		name: "proto data",
+		pe: &CloudEvent{Id: "id", Source: "source", Type: "type", SpecVersion: cev2.VersionV1,
+			Data: &CloudEvent_ProtoData{ProtoData: &anypb.Any{TypeUrl: "type.googleapis.com/test", Value: []byte{1, 2}}}},
+		check: func(t *testing.T, e *cev2.Event) {
+			if e.DataContentType() != ApplicationProtobuf {
+				t.Errorf("DataContentType got=%q, want=%q", e.DataContentType(), ApplicationProtobuf)
+			}
+			if diff := cmp.Diff([]byte{1, 2}, e.Data()); diff != "" {
+				t.Errorf("Data (-want,+got): %v", diff)
+			}
+		},
+	}, {
+		name: "typed extensions",
+		pe: &CloudEvent{Id: "id", Source: "source", Type: "type", SpecVersion: cev2.VersionV1,
+			Attributes: map[string]*CloudEvent_CloudEventAttributeValue{
+				"refext": uriRef,
+				"time": {Attr: &CloudEvent_CloudEventAttributeValue_CeTimestamp{
+					CeTimestamp: timestamppb.New(time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)),
+				}},
+			}},
+		check: func(t *testing.T, e *cev2.Event) {
+			if got, ok := e.Extensions()["refext"].(cetypes.URIRef); !ok || got.String() != "/relative" {
+				t.Errorf("Extension refext got=%v, want=/relative", e.Extensions()["refext"])
+			}
+			if want := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC); !e.Time().Equal(want) {
+				t.Errorf("Time got=%v, want=%v", e.Time(), want)
+			}
+		},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := EventFromProto(tc.pe)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("EventFromProto() error got=%v, wantErr=%v", err, tc.wantErr)
+			}
+			if tc.check != nil {
+				tc.check(t, e)
+			}
+		})
+	}
+}
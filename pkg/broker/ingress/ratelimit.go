@@ -0,0 +1,148 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/configmap"
+
+	"github.com/google/knative-gcp/pkg/apis/configs/ratelimit"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+const (
+	// The rejected reasons of the events rejected by the rate limits.
+	rejectedReasonBrokerRateLimit    = "broker_rate_limit"
+	rejectedReasonNamespaceRateLimit = "namespace_rate_limit"
+)
+
+// rateLimitingDecoupleSink is a DecoupleSink which rejects the events exceeding the rate
+// limits of their broker or namespace before sending them.
+type rateLimitingDecoupleSink struct {
+	sink    DecoupleSink
+	limiter *rateLimiter
+}
+
+// NewRateLimitingDecoupleSink creates a DecoupleSink which enforces the rate limits of the
+// config-ingress-rate-limit ConfigMap on the events sent to the deduplicatingDecoupleSink.
+// Nothing is rate limited if the ConfigMap doesn't exist.
+func NewRateLimitingDecoupleSink(ctx context.Context, sink *deduplicatingDecoupleSink, cmw configmap.DefaultingWatcher) *rateLimitingDecoupleSink {
+	limiter := newRateLimiter()
+	store := ratelimit.NewStore(logging.FromContext(ctx).Sugar().Named("config-ingress-rate-limit-store"), func(_ string, value interface{}) {
+		limiter.setLimits(value.(*ratelimit.Limits))
+	})
+	cmw.WatchWithDefault(corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: ratelimit.ConfigMapName()}}, store.OnConfigChanged)
+	return &rateLimitingDecoupleSink{
+		sink:    sink,
+		limiter: limiter,
+	}
+}
+
+// Send sends the event to the decouple sink, unless it exceeds a rate limit. In that case it
+// returns a *RateLimitError.
+func (s *rateLimitingDecoupleSink) Send(ctx context.Context, broker types.NamespacedName, event cev2.Event) protocol.Result {
+	if err := s.limiter.reserve(broker, time.Now()); err != nil {
+		return err
+	}
+	return s.sink.Send(ctx, broker, event)
+}
+
+// rateLimiter keeps a token bucket for each limited broker and namespace.
+type rateLimiter struct {
+	mu     sync.Mutex
+	limits *ratelimit.Limits
+	// brokers are the buckets of the brokers, keyed by namespace/name.
+	brokers map[string]*rate.Limiter
+	// namespaces are the buckets shared by the brokers of the namespaces.
+	namespaces map[string]*rate.Limiter
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		limits:     &ratelimit.Limits{},
+		brokers:    make(map[string]*rate.Limiter),
+		namespaces: make(map[string]*rate.Limiter),
+	}
+}
+
+// setLimits replaces the limits. The buckets are refilled.
+func (r *rateLimiter) setLimits(limits *ratelimit.Limits) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.limits = limits
+	r.brokers = make(map[string]*rate.Limiter)
+	r.namespaces = make(map[string]*rate.Limiter)
+}
+
+// reserve takes a token from the buckets of the broker and its namespace. No token is taken if
+// either bucket is empty, and a *RateLimitError is returned instead.
+func (r *rateLimiter) reserve(broker types.NamespacedName, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	brokerLimiter := limiterFor(r.brokers, broker.String(), r.limits.BrokerLimit(broker.Namespace, broker.Name))
+	namespaceLimiter := limiterFor(r.namespaces, broker.Namespace, r.limits.NamespaceLimit(broker.Namespace))
+
+	brokerReservation, err := reserve(brokerLimiter, now, rejectedReasonBrokerRateLimit)
+	if err != nil {
+		return err
+	}
+	if _, err := reserve(namespaceLimiter, now, rejectedReasonNamespaceRateLimit); err != nil {
+		if brokerReservation != nil {
+			brokerReservation.CancelAt(now)
+		}
+		return err
+	}
+	return nil
+}
+
+// limiterFor returns the bucket of a key with the given limits, or nil if the key is unlimited.
+func limiterFor(limiters map[string]*rate.Limiter, key string, limits *ratelimit.ScopedLimits) *rate.Limiter {
+	if limits == nil {
+		return nil
+	}
+	l, ok := limiters[key]
+	if !ok {
+		l = rate.NewLimiter(rate.Limit(limits.EventsPerSecond), limits.BurstOrDefault())
+		limiters[key] = l
+	}
+	return l
+}
+
+// reserve takes a token from the bucket if the bucket isn't empty.
+func reserve(limiter *rate.Limiter, now time.Time, reason string) (*rate.Reservation, error) {
+	if limiter == nil {
+		return nil, nil
+	}
+	reservation := limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return nil, &RateLimitError{Reason: reason}
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return nil, &RateLimitError{Reason: reason, RetryAfter: delay}
+	}
+	return reservation, nil
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"bytes"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/configmap"
+	logtest "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/metrics/metricskey"
+	"knative.dev/pkg/metrics/metricstest"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
+
+	"github.com/google/knative-gcp/pkg/apis/configs/ratelimit"
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	broker1 := types.NamespacedName{Namespace: "ns1", Name: "broker1"}
+	broker2 := types.NamespacedName{Namespace: "ns1", Name: "broker2"}
+	unlimited := types.NamespacedName{Namespace: "ns2", Name: "broker1"}
+
+	l := newRateLimiter()
+	for i := 0; i < 10; i++ {
+		if err := l.reserve(broker1, now); err != nil {
+			t.Fatalf("reserve() without limits got error: %v", err)
+		}
+	}
+
+	l.setLimits(&ratelimit.Limits{
+		BrokerLimits: map[string]ratelimit.ScopedLimits{
+			"ns1/broker1": {EventsPerSecond: 0.5, Burst: 1},
+		},
+		NamespaceLimits: map[string]ratelimit.ScopedLimits{
+			"ns2": {},
+		},
+		ClusterLimits: ratelimit.ScopedLimits{EventsPerSecond: 2, Burst: 3},
+	})
+	tests := []struct {
+		broker types.NamespacedName
+		at     time.Duration
+		want   error
+	}{
+		{broker: broker2},
+		{broker: broker2},
+		{broker: broker2},
+		// The bucket of ns1 is empty.
+		{broker: broker1, want: &RateLimitError{Reason: rejectedReasonNamespaceRateLimit, RetryAfter: 500 * time.Millisecond}},
+		// The token of broker1 was given back when the namespace rejected the event.
+		{broker: broker1, at: 500 * time.Millisecond},
+		// The bucket of broker1 is empty.
+		{broker: broker1, at: time.Second, want: &RateLimitError{Reason: rejectedReasonBrokerRateLimit, RetryAfter: 1500 * time.Millisecond}},
+		{broker: unlimited},
+		{broker: unlimited},
+		{broker: unlimited},
+		{broker: unlimited},
+	}
+	for i, tc := range tests {
+		err := l.reserve(tc.broker, now.Add(tc.at))
+		if diff := cmp.Diff(tc.want, err); diff != "" {
+			t.Errorf("reserve() #%d of %v unexpected error (-want,+got): %v", i, tc.broker, diff)
+		}
+	}
+
+	// New limits refill the buckets.
+	l.setLimits(&ratelimit.Limits{ClusterLimits: ratelimit.ScopedLimits{EventsPerSecond: 1}})
+	if err := l.reserve(broker1, now); err != nil {
+		t.Errorf("reserve() after new limits got error: %v", err)
+	}
+}
+
+func TestRateLimitingDecoupleSink(t *testing.T) {
+	tests := []struct {
+		name        string
+		configMap   *corev1.ConfigMap
+		wantResults []error
+		wantCount   int
+	}{{
+		name:        "no configmap",
+		wantResults: []error{nil, nil, nil},
+		wantCount:   3,
+	}, {
+		name: "namespace limit",
+		configMap: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: system.Namespace(), Name: ratelimit.ConfigMapName()},
+			Data: map[string]string{
+				"ingress-rate-limit-config": `
+namespaceLimits:
+  ns1:
+    eventsPerSecond: 0.1
+    burst: 2`,
+			},
+		},
+		wantResults: []error{nil, nil, ErrRateLimited},
+		wantCount:   2,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtest.TestContextWithLogger(t)
+			kubeClient := fake.NewSimpleClientset()
+			if tc.configMap != nil {
+				kubeClient = fake.NewSimpleClientset(tc.configMap)
+			}
+			cmw := configmap.NewInformedWatcher(kubeClient, system.Namespace())
+			inner := &fakeCountingDecoupleSink{}
+			sink := NewRateLimitingDecoupleSink(ctx, nil, cmw)
+			sink.sink = inner
+			if err := cmw.Start(ctx.Done()); err != nil {
+				t.Fatalf("Failed to start ConfigMap watcher: %v", err)
+			}
+
+			broker := types.NamespacedName{Namespace: "ns1", Name: "broker1"}
+			for i, want := range tc.wantResults {
+				if got := sink.Send(ctx, broker, *createTestEvent("test-event")); !errors.Is(got, want) {
+					t.Errorf("Send() #%d got result %v, want %v", i, got, want)
+				}
+			}
+			if inner.count != tc.wantCount {
+				t.Errorf("Sent events got=%d, want=%d", inner.count, tc.wantCount)
+			}
+		})
+	}
+}
+
+func TestHandlerRateLimited(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &fakeCountingDecoupleSink{results: []protocol.Result{
+		&RateLimitError{Reason: rejectedReasonBrokerRateLimit, RetryAfter: 2500 * time.Millisecond},
+	}}
+	h := NewHandler(ctx, nil, sink, nil, statsReporter, DefaultMaxRequestBodyBytes)
+
+	req := httptest.NewRequest(nethttp.MethodPost, "/ns1/broker1", &bytes.Buffer{})
+	if err := cehttp.WriteRequest(ctx, binding.ToMessage(createTestEvent("test-event")), req); err != nil {
+		t.Fatalf("Failed to write event to request: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != nethttp.StatusTooManyRequests {
+		t.Errorf("StatusCode mismatch. got: %v, want: %v", rec.Code, nethttp.StatusTooManyRequests)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "3" {
+		t.Errorf("Retry-After header got=%q, want=%q", got, "3")
+	}
+	metricstest.CheckCountData(t, "event_count", map[string]string{
+		metricskey.LabelEventType:         eventType,
+		metricskey.LabelResponseCode:      "429",
+		metricskey.LabelResponseCodeClass: "4xx",
+		"rejected_reason":                 rejectedReasonBrokerRateLimit,
+		metricskey.PodName:                pod,
+		metricskey.ContainerName:          container,
+	}, 1)
+}
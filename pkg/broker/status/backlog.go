@@ -0,0 +1,124 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+const (
+	undeliveredMessagesMetric = "pubsub.googleapis.com/subscription/num_undelivered_messages"
+	// backlogWindow is how far back the backlog is looked for. The metric is sampled
+	// every minute, and is available a few minutes later.
+	backlogWindow = 5 * time.Minute
+)
+
+// Backlogs gets the number of undelivered messages of Pub/Sub subscriptions from
+// Cloud Monitoring.
+type Backlogs struct {
+	client    *monitoring.MetricClient
+	projectID string
+	// prefix is the prefix of the IDs of the subscriptions.
+	prefix string
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+// NewBacklogs creates Backlogs of the subscriptions of the project whose ID starts with prefix.
+func NewBacklogs(ctx context.Context, projectID, prefix string, opts ...option.ClientOption) (*Backlogs, error) {
+	client, err := monitoring.NewMetricClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create monitoring client: %w", err)
+	}
+	return &Backlogs{
+		client:    client,
+		projectID: projectID,
+		prefix:    prefix,
+		now:       time.Now,
+	}, nil
+}
+
+// Get returns the latest number of undelivered messages of the subscriptions, keyed by
+// subscription ID.
+func (b *Backlogs) Get(ctx context.Context) (map[string]int64, error) {
+	now := b.now()
+	it := b.client.ListTimeSeries(ctx, &monitoringpb.ListTimeSeriesRequest{
+		Name: "projects/" + b.projectID,
+		Filter: fmt.Sprintf(`metric.type = %q AND resource.type = "pubsub_subscription" AND resource.labels.subscription_id = starts_with(%q)`,
+			undeliveredMessagesMetric, b.prefix),
+		Interval: &monitoringpb.TimeInterval{
+			StartTime: timestamppb.New(now.Add(-backlogWindow)),
+			EndTime:   timestamppb.New(now),
+		},
+		View: monitoringpb.ListTimeSeriesRequest_FULL,
+	})
+	backlogs := make(map[string]int64)
+	for {
+		ts, err := it.Next()
+		if err == iterator.Done {
+			return backlogs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the backlog of the subscriptions: %w", err)
+		}
+		// The points are ordered from the most recent.
+		if points := ts.GetPoints(); len(points) > 0 {
+			backlogs[ts.GetResource().GetLabels()["subscription_id"]] = points[0].GetValue().GetInt64Value()
+		}
+	}
+}
+
+// AddRetryBacklogs adds the backlog of the retry subscriptions of the targets to their
+// status, keyed by target ID.
+func (b *Backlogs) AddRetryBacklogs(ctx context.Context, targets config.ReadonlyTargets, status map[string]TargetStatus) error {
+	backlogs, err := b.Get(ctx)
+	if err != nil {
+		return err
+	}
+	targets.RangeAllTargets(func(t *config.Target) bool {
+		if t.RetryQueue == nil {
+			return true
+		}
+		backlog, ok := backlogs[t.RetryQueue.Subscription]
+		if !ok {
+			return true
+		}
+		ts, ok := status[t.Id]
+		if !ok {
+			ts = TargetStatus{Namespace: t.Namespace, Name: t.Name}
+		}
+		ts.RetryBacklog = &backlog
+		status[t.Id] = ts
+		return true
+	})
+	return nil
+}
+
+// Close closes the monitoring client.
+func (b *Backlogs) Close() error {
+	return b.client.Close()
+}
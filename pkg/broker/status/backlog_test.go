@@ -0,0 +1,165 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/option"
+	"google.golang.org/genproto/googleapis/api/monitoredres"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/grpc"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+)
+
+type fakeMetricServer struct {
+	monitoringpb.UnimplementedMetricServiceServer
+	req    *monitoringpb.ListTimeSeriesRequest
+	series []*monitoringpb.TimeSeries
+}
+
+func (s *fakeMetricServer) ListTimeSeries(_ context.Context, req *monitoringpb.ListTimeSeriesRequest) (*monitoringpb.ListTimeSeriesResponse, error) {
+	s.req = req
+	return &monitoringpb.ListTimeSeriesResponse{TimeSeries: s.series}, nil
+}
+
+func subscriptionSeries(id string, values ...int64) *monitoringpb.TimeSeries {
+	ts := &monitoringpb.TimeSeries{
+		Resource: &monitoredres.MonitoredResource{
+			Type:   "pubsub_subscription",
+			Labels: map[string]string{"subscription_id": id},
+		},
+	}
+	for _, v := range values {
+		ts.Points = append(ts.Points, &monitoringpb.Point{
+			Value: &monitoringpb.TypedValue{Value: &monitoringpb.TypedValue_Int64Value{Int64Value: v}},
+		})
+	}
+	return ts
+}
+
+func testBacklogs(ctx context.Context, t *testing.T, srv *fakeMetricServer) (*Backlogs, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	s := grpc.NewServer()
+	monitoringpb.RegisterMetricServiceServer(s, srv)
+	go s.Serve(lis)
+
+	b, err := NewBacklogs(ctx, "test-project", "cre-tgr",
+		option.WithEndpoint(lis.Addr().String()),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithInsecure()))
+	if err != nil {
+		s.Stop()
+		t.Fatalf("NewBacklogs() = %v", err)
+	}
+	return b, func() {
+		b.Close()
+		s.Stop()
+	}
+}
+
+func TestBacklogs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := &fakeMetricServer{
+		series: []*monitoringpb.TimeSeries{
+			subscriptionSeries("cre-tgr_ns_t1_uid1", 12, 30),
+			subscriptionSeries("cre-tgr_ns_t2_uid2", 0),
+			subscriptionSeries("cre-tgr_ns_t3_uid3"),
+		},
+	}
+	b, close := testBacklogs(ctx, t, srv)
+	defer close()
+	now := time.Unix(1600000000, 0)
+	b.now = func() time.Time { return now }
+
+	got, err := b.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	want := map[string]int64{
+		"cre-tgr_ns_t1_uid1": 12,
+		"cre-tgr_ns_t2_uid2": 0,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Get() (-want,+got): %v", diff)
+	}
+
+	if srv.req.GetName() != "projects/test-project" {
+		t.Errorf("request name got %q, want %q", srv.req.GetName(), "projects/test-project")
+	}
+	if !strings.Contains(srv.req.GetFilter(), `starts_with("cre-tgr")`) {
+		t.Errorf("request filter %q doesn't filter the subscriptions by prefix", srv.req.GetFilter())
+	}
+	if got, want := srv.req.GetInterval().GetEndTime().AsTime(), now; !got.Equal(want) {
+		t.Errorf("request end time got %v, want %v", got, want)
+	}
+	if got, want := srv.req.GetInterval().GetStartTime().AsTime(), now.Add(-backlogWindow); !got.Equal(want) {
+		t.Errorf("request start time got %v, want %v", got, want)
+	}
+}
+
+func TestAddRetryBacklogs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := &fakeMetricServer{
+		series: []*monitoringpb.TimeSeries{
+			subscriptionSeries("cre-tgr_ns_t1_uid1", 12),
+			subscriptionSeries("cre-tgr_ns_t2_uid2", 3),
+		},
+	}
+	b, close := testBacklogs(ctx, t, srv)
+	defer close()
+
+	targets := memory.NewEmptyTargets()
+	targets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.UpsertTargets(
+			&config.Target{Id: "uid1", Namespace: "ns", Name: "t1", RetryQueue: &config.Queue{Subscription: "cre-tgr_ns_t1_uid1"}},
+			&config.Target{Id: "uid2", Namespace: "ns", Name: "t2", RetryQueue: &config.Queue{Subscription: "cre-tgr_ns_t2_uid2"}},
+			&config.Target{Id: "uid3", Namespace: "ns", Name: "t3", RetryQueue: &config.Queue{Subscription: "cre-tgr_ns_t3_uid3"}},
+			&config.Target{Id: "uid4", Namespace: "ns", Name: "t4"},
+		)
+	})
+	failure := time.Unix(1600000000, 0)
+	status := map[string]TargetStatus{
+		"uid1": {Namespace: "ns", Name: "t1", LastFailureTime: &failure, LastErrorCode: 503},
+	}
+	if err := b.AddRetryBacklogs(ctx, targets, status); err != nil {
+		t.Fatalf("AddRetryBacklogs() = %v", err)
+	}
+
+	backlog1, backlog2 := int64(12), int64(3)
+	want := map[string]TargetStatus{
+		"uid1": {Namespace: "ns", Name: "t1", LastFailureTime: &failure, LastErrorCode: 503, RetryBacklog: &backlog1},
+		"uid2": {Namespace: "ns", Name: "t2", RetryBacklog: &backlog2},
+	}
+	if diff := cmp.Diff(want, status); diff != "" {
+		t.Errorf("AddRetryBacklogs() (-want,+got): %v", diff)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// maxErrorLength is the max length of the errors reported, so that the reports of
+// many failing targets fit in a ConfigMap.
+const maxErrorLength = 256
+
+// Deliveries records the last successful and failed deliveries to the targets,
+// keyed by target ID.
+type Deliveries struct {
+	mu      sync.Mutex
+	targets map[string]*TargetStatus
+}
+
+// NewDeliveries creates empty Deliveries.
+func NewDeliveries() *Deliveries {
+	return &Deliveries{targets: make(map[string]*TargetStatus)}
+}
+
+// RecordSuccess records a successful delivery to the target at t.
+func (d *Deliveries) RecordSuccess(target *config.Target, t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.target(target).LastSuccessTime = &t
+}
+
+// RecordFailure records a delivery to the target which failed at t with err, and the
+// HTTP status code of the response if any.
+func (d *Deliveries) RecordFailure(target *config.Target, t time.Time, err error, code int) {
+	msg := err.Error()
+	if len(msg) > maxErrorLength {
+		msg = msg[:maxErrorLength]
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	ts := d.target(target)
+	ts.LastFailureTime = &t
+	ts.LastError = msg
+	ts.LastErrorCode = code
+}
+
+func (d *Deliveries) target(target *config.Target) *TargetStatus {
+	ts, ok := d.targets[target.Id]
+	if !ok {
+		ts = &TargetStatus{Namespace: target.Namespace, Name: target.Name}
+		d.targets[target.Id] = ts
+	}
+	return ts
+}
+
+// Status returns the status of the targets with recorded deliveries, keyed by target ID.
+func (d *Deliveries) Status() map[string]TargetStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	status := make(map[string]TargetStatus, len(d.targets))
+	for id, ts := range d.targets {
+		status[id] = *ts
+	}
+	return status
+}
+
+// Retain removes the deliveries of the targets which aren't in ids, e.g. because the
+// targets were deleted.
+func (d *Deliveries) Retain(ids map[string]bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for id := range d.targets {
+		if !ids[id] {
+			delete(d.targets, id)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func TestDeliveries(t *testing.T) {
+	target := &config.Target{Id: "uid", Namespace: "ns", Name: "trigger"}
+	other := &config.Target{Id: "other-uid", Namespace: "ns", Name: "other"}
+	success := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	failure := success.Add(time.Minute)
+
+	d := NewDeliveries()
+	d.RecordSuccess(target, success)
+	d.RecordFailure(target, failure, errors.New("event delivery failed: HTTP status code 503"), 503)
+	d.RecordFailure(other, failure, errors.New(strings.Repeat("x", 300)), 0)
+
+	want := map[string]TargetStatus{
+		"uid": {
+			Namespace:       "ns",
+			Name:            "trigger",
+			LastSuccessTime: &success,
+			LastFailureTime: &failure,
+			LastError:       "event delivery failed: HTTP status code 503",
+			LastErrorCode:   503,
+		},
+		"other-uid": {
+			Namespace:       "ns",
+			Name:            "other",
+			LastFailureTime: &failure,
+			LastError:       strings.Repeat("x", maxErrorLength),
+		},
+	}
+	if diff := cmp.Diff(want, d.Status()); diff != "" {
+		t.Errorf("Status (-want,+got): %v", diff)
+	}
+
+	d.Retain(map[string]bool{"uid": true})
+	delete(want, "other-uid")
+	if diff := cmp.Diff(want, d.Status()); diff != "" {
+		t.Errorf("Status after Retain (-want,+got): %v", diff)
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package status provides the status reports of the targets the broker data plane
+// pods deliver events to. Each pod writes its report to a ConfigMap in the status
+// namespace, which the trigger controller surfaces on the Triggers.
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/system"
+
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+const (
+	// ReportLabelKey is the label of the ConfigMaps holding the status reports.
+	ReportLabelKey = "events.cloud.google.com/broker-status-report"
+	// ReportDataKey is the key of the report in the data of the ConfigMaps.
+	ReportDataKey = "report.json"
+
+	configMapPrefix = "broker-status-"
+	namespaceSuffix = "-broker-status"
+)
+
+// Namespace returns the namespace of the ConfigMaps holding the status reports. It's
+// dedicated to the reports, so that the data plane pods which write them can't write
+// the other ConfigMaps of the system namespace.
+func Namespace() string {
+	return system.Namespace() + namespaceSuffix
+}
+
+// Report is the status of the targets reported by a data plane pod.
+type Report struct {
+	// Pod is the name of the reporting pod.
+	Pod string `json:"pod"`
+	// PodUID is the UID of the reporting pod.
+	PodUID types.UID `json:"podUID,omitempty"`
+	// Time is when the report was made.
+	Time time.Time `json:"time"`
+	// Targets is the status of the targets, keyed by target ID. Only the targets
+	// with something to report are included.
+	Targets map[string]TargetStatus `json:"targets,omitempty"`
+}
+
+// TargetStatus is the status of a target reported by a data plane pod.
+type TargetStatus struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// CircuitBreaker is the state of the circuit breaker of the target, if not closed.
+	CircuitBreaker string `json:"circuitBreaker,omitempty"`
+	// LastSuccessTime is the time of the last successful delivery to the target.
+	LastSuccessTime *time.Time `json:"lastSuccessTime,omitempty"`
+	// LastFailureTime is the time of the last failed delivery to the target.
+	LastFailureTime *time.Time `json:"lastFailureTime,omitempty"`
+	// LastError is the error of the last failed delivery.
+	LastError string `json:"lastError,omitempty"`
+	// LastErrorCode is the HTTP status code of the last failed delivery, zero if the
+	// target didn't respond.
+	LastErrorCode int `json:"lastErrorCode,omitempty"`
+	// RetryBacklog is the number of undelivered events in the retry queue of the target.
+	RetryBacklog *int64 `json:"retryBacklog,omitempty"`
+}
+
+// ConfigMapName returns the name of the ConfigMap holding the report of the pod.
+func ConfigMapName(pod string) string {
+	return configMapPrefix + pod
+}
+
+// ParseReport parses the report held by the ConfigMap.
+func ParseReport(cm *corev1.ConfigMap) (*Report, error) {
+	data, ok := cm.Data[ReportDataKey]
+	if !ok {
+		return nil, fmt.Errorf("configmap %s/%s has no %s", cm.Namespace, cm.Name, ReportDataKey)
+	}
+	var report Report
+	if err := json.Unmarshal([]byte(data), &report); err != nil {
+		return nil, fmt.Errorf("failed to parse the report of configmap %s/%s: %w", cm.Namespace, cm.Name, err)
+	}
+	return &report, nil
+}
+
+// Reporter periodically writes the status of the targets to the ConfigMap of the
+// pod. The ConfigMap can't be owned by the pod, which is in another namespace, so
+// the broker cell deletes it once the pod is gone.
+type Reporter struct {
+	client    kubernetes.Interface
+	namespace string
+	pod       string
+	podUID    types.UID
+	// collect returns the status of the targets to report.
+	collect func(ctx context.Context) map[string]TargetStatus
+	// now is replaced in tests.
+	now func() time.Time
+
+	// last is the last status written, nil until the first report is written.
+	last map[string]TargetStatus
+}
+
+// NewReporter creates a Reporter writing the status returned by collect to the
+// ConfigMap of the pod in the namespace.
+func NewReporter(client kubernetes.Interface, namespace, pod string, podUID types.UID, collect func(ctx context.Context) map[string]TargetStatus) *Reporter {
+	return &Reporter{
+		client:    client,
+		namespace: namespace,
+		pod:       pod,
+		podUID:    podUID,
+		collect:   collect,
+		now:       time.Now,
+	}
+}
+
+// Run writes the report every period, if the status changed, until ctx is done.
+func (r *Reporter) Run(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Report(ctx); err != nil {
+				logging.FromContext(ctx).Error("failed to write the status report", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Report writes the status of the targets, unless it didn't change since the last report.
+func (r *Reporter) Report(ctx context.Context) error {
+	targets := r.collect(ctx)
+	if targets == nil {
+		targets = make(map[string]TargetStatus)
+	}
+	if r.last != nil && reflect.DeepEqual(targets, r.last) {
+		return nil
+	}
+	data, err := json.Marshal(&Report{Pod: r.pod, PodUID: r.podUID, Time: r.now().UTC(), Targets: targets})
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigMapName(r.pod),
+			Namespace: r.namespace,
+			Labels:    map[string]string{ReportLabelKey: "true"},
+		},
+		Data: map[string]string{ReportDataKey: string(data)},
+	}
+	configMaps := r.client.CoreV1().ConfigMaps(r.namespace)
+	existing, err := configMaps.Get(ctx, cm.Name, metav1.GetOptions{})
+	switch {
+	case apierrs.IsNotFound(err):
+		_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+	case err == nil:
+		existing = existing.DeepCopy()
+		existing.Labels = cm.Labels
+		existing.Data = cm.Data
+		_, err = configMaps.Update(ctx, existing, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write configmap %s/%s: %w", cm.Namespace, cm.Name, err)
+	}
+	r.last = targets
+	return nil
+}
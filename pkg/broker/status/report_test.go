@@ -0,0 +1,121 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/system"
+)
+
+func TestReporter(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	targets := map[string]TargetStatus{
+		"uid": {Namespace: "ns", Name: "trigger", CircuitBreaker: "Open"},
+	}
+	r := NewReporter(client, "cloud-run-events", "fanout-abc", "pod-uid", func(context.Context) map[string]TargetStatus {
+		return targets
+	})
+	now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	if err := r.Report(ctx); err != nil {
+		t.Fatalf("Report got unexpected error: %v", err)
+	}
+	cm, err := client.CoreV1().ConfigMaps("cloud-run-events").Get(ctx, "broker-status-fanout-abc", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get the report configmap: %v", err)
+	}
+	if cm.Labels[ReportLabelKey] != "true" {
+		t.Errorf("configmap labels got=%v, want the %s label", cm.Labels, ReportLabelKey)
+	}
+	got, err := ParseReport(cm)
+	if err != nil {
+		t.Fatalf("ParseReport got unexpected error: %v", err)
+	}
+	want := &Report{Pod: "fanout-abc", PodUID: "pod-uid", Time: now, Targets: targets}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("report (-want,+got): %v", diff)
+	}
+
+	// The report isn't written again if the status didn't change.
+	client.ClearActions()
+	now = now.Add(time.Minute)
+	if err := r.Report(ctx); err != nil {
+		t.Fatalf("Report got unexpected error: %v", err)
+	}
+	if actions := client.Actions(); len(actions) != 0 {
+		t.Errorf("unchanged report got actions %v, want none", actions)
+	}
+
+	targets = nil
+	if err := r.Report(ctx); err != nil {
+		t.Fatalf("Report got unexpected error: %v", err)
+	}
+	var updated bool
+	for _, a := range client.Actions() {
+		if a.Matches("update", "configmaps") {
+			cm = a.(clienttesting.UpdateAction).GetObject().(*corev1.ConfigMap)
+			updated = true
+		}
+	}
+	if !updated {
+		t.Fatal("changed report wasn't updated")
+	}
+	got, err = ParseReport(cm)
+	if err != nil {
+		t.Fatalf("ParseReport got unexpected error: %v", err)
+	}
+	want = &Report{Pod: "fanout-abc", PodUID: "pod-uid", Time: now}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("updated report (-want,+got): %v", diff)
+	}
+}
+
+func TestParseReportError(t *testing.T) {
+	for name, data := range map[string]map[string]string{
+		"no report":      {},
+		"invalid report": {ReportDataKey: "{"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "cloud-run-events", Name: "broker-status-fanout-abc"},
+				Data:       data,
+			}
+			if _, err := ParseReport(cm); err == nil {
+				t.Error("ParseReport got no error")
+			}
+		})
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	os.Setenv(system.NamespaceEnvKey, "cloud-run-events")
+	defer os.Unsetenv(system.NamespaceEnvKey)
+	if got, want := Namespace(), "cloud-run-events-broker-status"; got != want {
+		t.Errorf("Namespace got=%q, want=%q", got, want)
+	}
+}
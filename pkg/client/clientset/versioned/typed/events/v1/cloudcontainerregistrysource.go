@@ -0,0 +1,195 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	scheme "github.com/google/knative-gcp/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// CloudContainerRegistrySourcesGetter has a method to return a CloudContainerRegistrySourceInterface.
+// A group's client should implement this interface.
+type CloudContainerRegistrySourcesGetter interface {
+	CloudContainerRegistrySources(namespace string) CloudContainerRegistrySourceInterface
+}
+
+// CloudContainerRegistrySourceInterface has methods to work with CloudContainerRegistrySource resources.
+type CloudContainerRegistrySourceInterface interface {
+	Create(ctx context.Context, cloudContainerRegistrySource *v1.CloudContainerRegistrySource, opts metav1.CreateOptions) (*v1.CloudContainerRegistrySource, error)
+	Update(ctx context.Context, cloudContainerRegistrySource *v1.CloudContainerRegistrySource, opts metav1.UpdateOptions) (*v1.CloudContainerRegistrySource, error)
+	UpdateStatus(ctx context.Context, cloudContainerRegistrySource *v1.CloudContainerRegistrySource, opts metav1.UpdateOptions) (*v1.CloudContainerRegistrySource, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.CloudContainerRegistrySource, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.CloudContainerRegistrySourceList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.CloudContainerRegistrySource, err error)
+	CloudContainerRegistrySourceExpansion
+}
+
+// cloudContainerRegistrySources implements CloudContainerRegistrySourceInterface
+type cloudContainerRegistrySources struct {
+	client rest.Interface
+	ns     string
+}
+
+// newCloudContainerRegistrySources returns a CloudContainerRegistrySources
+func newCloudContainerRegistrySources(c *EventsV1Client, namespace string) *cloudContainerRegistrySources {
+	return &cloudContainerRegistrySources{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the cloudContainerRegistrySource, and returns the corresponding cloudContainerRegistrySource object, and an error if there is any.
+func (c *cloudContainerRegistrySources) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.CloudContainerRegistrySource, err error) {
+	result = &v1.CloudContainerRegistrySource{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cloudcontainerregistrysources").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of CloudContainerRegistrySources that match those selectors.
+func (c *cloudContainerRegistrySources) List(ctx context.Context, opts metav1.ListOptions) (result *v1.CloudContainerRegistrySourceList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.CloudContainerRegistrySourceList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cloudcontainerregistrysources").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested cloudContainerRegistrySources.
+func (c *cloudContainerRegistrySources) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("cloudcontainerregistrysources").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a cloudContainerRegistrySource and creates it.  Returns the server's representation of the cloudContainerRegistrySource, and an error, if there is any.
+func (c *cloudContainerRegistrySources) Create(ctx context.Context, cloudContainerRegistrySource *v1.CloudContainerRegistrySource, opts metav1.CreateOptions) (result *v1.CloudContainerRegistrySource, err error) {
+	result = &v1.CloudContainerRegistrySource{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("cloudcontainerregistrysources").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(cloudContainerRegistrySource).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a cloudContainerRegistrySource and updates it. Returns the server's representation of the cloudContainerRegistrySource, and an error, if there is any.
+func (c *cloudContainerRegistrySources) Update(ctx context.Context, cloudContainerRegistrySource *v1.CloudContainerRegistrySource, opts metav1.UpdateOptions) (result *v1.CloudContainerRegistrySource, err error) {
+	result = &v1.CloudContainerRegistrySource{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("cloudcontainerregistrysources").
+		Name(cloudContainerRegistrySource.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(cloudContainerRegistrySource).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *cloudContainerRegistrySources) UpdateStatus(ctx context.Context, cloudContainerRegistrySource *v1.CloudContainerRegistrySource, opts metav1.UpdateOptions) (result *v1.CloudContainerRegistrySource, err error) {
+	result = &v1.CloudContainerRegistrySource{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("cloudcontainerregistrysources").
+		Name(cloudContainerRegistrySource.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(cloudContainerRegistrySource).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the cloudContainerRegistrySource and deletes it. Returns an error if one occurs.
+func (c *cloudContainerRegistrySources) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cloudcontainerregistrysources").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *cloudContainerRegistrySources) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cloudcontainerregistrysources").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched cloudContainerRegistrySource.
+func (c *cloudContainerRegistrySources) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.CloudContainerRegistrySource, err error) {
+	result = &v1.CloudContainerRegistrySource{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("cloudcontainerregistrysources").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	scheme "github.com/google/knative-gcp/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// CloudMonitoringAlertSourcesGetter has a method to return a CloudMonitoringAlertSourceInterface.
+// A group's client should implement this interface.
+type CloudMonitoringAlertSourcesGetter interface {
+	CloudMonitoringAlertSources(namespace string) CloudMonitoringAlertSourceInterface
+}
+
+// CloudMonitoringAlertSourceInterface has methods to work with CloudMonitoringAlertSource resources.
+type CloudMonitoringAlertSourceInterface interface {
+	Create(ctx context.Context, cloudMonitoringAlertSource *v1.CloudMonitoringAlertSource, opts metav1.CreateOptions) (*v1.CloudMonitoringAlertSource, error)
+	Update(ctx context.Context, cloudMonitoringAlertSource *v1.CloudMonitoringAlertSource, opts metav1.UpdateOptions) (*v1.CloudMonitoringAlertSource, error)
+	UpdateStatus(ctx context.Context, cloudMonitoringAlertSource *v1.CloudMonitoringAlertSource, opts metav1.UpdateOptions) (*v1.CloudMonitoringAlertSource, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.CloudMonitoringAlertSource, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.CloudMonitoringAlertSourceList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.CloudMonitoringAlertSource, err error)
+	CloudMonitoringAlertSourceExpansion
+}
+
+// cloudMonitoringAlertSources implements CloudMonitoringAlertSourceInterface
+type cloudMonitoringAlertSources struct {
+	client rest.Interface
+	ns     string
+}
+
+// newCloudMonitoringAlertSources returns a CloudMonitoringAlertSources
+func newCloudMonitoringAlertSources(c *EventsV1Client, namespace string) *cloudMonitoringAlertSources {
+	return &cloudMonitoringAlertSources{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the cloudMonitoringAlertSource, and returns the corresponding cloudMonitoringAlertSource object, and an error if there is any.
+func (c *cloudMonitoringAlertSources) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.CloudMonitoringAlertSource, err error) {
+	result = &v1.CloudMonitoringAlertSource{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cloudmonitoringalertsources").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of CloudMonitoringAlertSources that match those selectors.
+func (c *cloudMonitoringAlertSources) List(ctx context.Context, opts metav1.ListOptions) (result *v1.CloudMonitoringAlertSourceList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.CloudMonitoringAlertSourceList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cloudmonitoringalertsources").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested cloudMonitoringAlertSources.
+func (c *cloudMonitoringAlertSources) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("cloudmonitoringalertsources").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a cloudMonitoringAlertSource and creates it.  Returns the server's representation of the cloudMonitoringAlertSource, and an error, if there is any.
+func (c *cloudMonitoringAlertSources) Create(ctx context.Context, cloudMonitoringAlertSource *v1.CloudMonitoringAlertSource, opts metav1.CreateOptions) (result *v1.CloudMonitoringAlertSource, err error) {
+	result = &v1.CloudMonitoringAlertSource{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("cloudmonitoringalertsources").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(cloudMonitoringAlertSource).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a cloudMonitoringAlertSource and updates it. Returns the server's representation of the cloudMonitoringAlertSource, and an error, if there is any.
+func (c *cloudMonitoringAlertSources) Update(ctx context.Context, cloudMonitoringAlertSource *v1.CloudMonitoringAlertSource, opts metav1.UpdateOptions) (result *v1.CloudMonitoringAlertSource, err error) {
+	result = &v1.CloudMonitoringAlertSource{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("cloudmonitoringalertsources").
+		Name(cloudMonitoringAlertSource.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(cloudMonitoringAlertSource).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *cloudMonitoringAlertSources) UpdateStatus(ctx context.Context, cloudMonitoringAlertSource *v1.CloudMonitoringAlertSource, opts metav1.UpdateOptions) (result *v1.CloudMonitoringAlertSource, err error) {
+	result = &v1.CloudMonitoringAlertSource{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("cloudmonitoringalertsources").
+		Name(cloudMonitoringAlertSource.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(cloudMonitoringAlertSource).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the cloudMonitoringAlertSource and deletes it. Returns an error if one occurs.
+func (c *cloudMonitoringAlertSources) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cloudmonitoringalertsources").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *cloudMonitoringAlertSources) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cloudmonitoringalertsources").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched cloudMonitoringAlertSource.
+func (c *cloudMonitoringAlertSources) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.CloudMonitoringAlertSource, err error) {
+	result = &v1.CloudMonitoringAlertSource{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("cloudmonitoringalertsources").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
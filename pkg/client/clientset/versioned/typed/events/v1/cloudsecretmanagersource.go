@@ -0,0 +1,195 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	scheme "github.com/google/knative-gcp/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// CloudSecretManagerSourcesGetter has a method to return a CloudSecretManagerSourceInterface.
+// A group's client should implement this interface.
+type CloudSecretManagerSourcesGetter interface {
+	CloudSecretManagerSources(namespace string) CloudSecretManagerSourceInterface
+}
+
+// CloudSecretManagerSourceInterface has methods to work with CloudSecretManagerSource resources.
+type CloudSecretManagerSourceInterface interface {
+	Create(ctx context.Context, cloudSecretManagerSource *v1.CloudSecretManagerSource, opts metav1.CreateOptions) (*v1.CloudSecretManagerSource, error)
+	Update(ctx context.Context, cloudSecretManagerSource *v1.CloudSecretManagerSource, opts metav1.UpdateOptions) (*v1.CloudSecretManagerSource, error)
+	UpdateStatus(ctx context.Context, cloudSecretManagerSource *v1.CloudSecretManagerSource, opts metav1.UpdateOptions) (*v1.CloudSecretManagerSource, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.CloudSecretManagerSource, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.CloudSecretManagerSourceList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.CloudSecretManagerSource, err error)
+	CloudSecretManagerSourceExpansion
+}
+
+// cloudSecretManagerSources implements CloudSecretManagerSourceInterface
+type cloudSecretManagerSources struct {
+	client rest.Interface
+	ns     string
+}
+
+// newCloudSecretManagerSources returns a CloudSecretManagerSources
+func newCloudSecretManagerSources(c *EventsV1Client, namespace string) *cloudSecretManagerSources {
+	return &cloudSecretManagerSources{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the cloudSecretManagerSource, and returns the corresponding cloudSecretManagerSource object, and an error if there is any.
+func (c *cloudSecretManagerSources) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.CloudSecretManagerSource, err error) {
+	result = &v1.CloudSecretManagerSource{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cloudsecretmanagersources").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of CloudSecretManagerSources that match those selectors.
+func (c *cloudSecretManagerSources) List(ctx context.Context, opts metav1.ListOptions) (result *v1.CloudSecretManagerSourceList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.CloudSecretManagerSourceList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cloudsecretmanagersources").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested cloudSecretManagerSources.
+func (c *cloudSecretManagerSources) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("cloudsecretmanagersources").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a cloudSecretManagerSource and creates it.  Returns the server's representation of the cloudSecretManagerSource, and an error, if there is any.
+func (c *cloudSecretManagerSources) Create(ctx context.Context, cloudSecretManagerSource *v1.CloudSecretManagerSource, opts metav1.CreateOptions) (result *v1.CloudSecretManagerSource, err error) {
+	result = &v1.CloudSecretManagerSource{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("cloudsecretmanagersources").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(cloudSecretManagerSource).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a cloudSecretManagerSource and updates it. Returns the server's representation of the cloudSecretManagerSource, and an error, if there is any.
+func (c *cloudSecretManagerSources) Update(ctx context.Context, cloudSecretManagerSource *v1.CloudSecretManagerSource, opts metav1.UpdateOptions) (result *v1.CloudSecretManagerSource, err error) {
+	result = &v1.CloudSecretManagerSource{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("cloudsecretmanagersources").
+		Name(cloudSecretManagerSource.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(cloudSecretManagerSource).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *cloudSecretManagerSources) UpdateStatus(ctx context.Context, cloudSecretManagerSource *v1.CloudSecretManagerSource, opts metav1.UpdateOptions) (result *v1.CloudSecretManagerSource, err error) {
+	result = &v1.CloudSecretManagerSource{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("cloudsecretmanagersources").
+		Name(cloudSecretManagerSource.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(cloudSecretManagerSource).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the cloudSecretManagerSource and deletes it. Returns an error if one occurs.
+func (c *cloudSecretManagerSources) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cloudsecretmanagersources").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *cloudSecretManagerSources) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cloudsecretmanagersources").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched cloudSecretManagerSource.
+func (c *cloudSecretManagerSources) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.CloudSecretManagerSource, err error) {
+	result = &v1.CloudSecretManagerSource{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("cloudsecretmanagersources").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
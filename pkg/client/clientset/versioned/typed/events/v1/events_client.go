@@ -27,9 +27,14 @@ import (
 type EventsV1Interface interface {
 	RESTClient() rest.Interface
 	CloudAuditLogsSourcesGetter
+	CloudBillingBudgetSourcesGetter
 	CloudBuildSourcesGetter
+	CloudContainerRegistrySourcesGetter
+	CloudFirestoreSourcesGetter
+	CloudMonitoringAlertSourcesGetter
 	CloudPubSubSourcesGetter
 	CloudSchedulerSourcesGetter
+	CloudSecretManagerSourcesGetter
 	CloudStorageSourcesGetter
 }
 
@@ -42,10 +47,26 @@ func (c *EventsV1Client) CloudAuditLogsSources(namespace string) CloudAuditLogsS
 	return newCloudAuditLogsSources(c, namespace)
 }
 
+func (c *EventsV1Client) CloudBillingBudgetSources(namespace string) CloudBillingBudgetSourceInterface {
+	return newCloudBillingBudgetSources(c, namespace)
+}
+
 func (c *EventsV1Client) CloudBuildSources(namespace string) CloudBuildSourceInterface {
 	return newCloudBuildSources(c, namespace)
 }
 
+func (c *EventsV1Client) CloudContainerRegistrySources(namespace string) CloudContainerRegistrySourceInterface {
+	return newCloudContainerRegistrySources(c, namespace)
+}
+
+func (c *EventsV1Client) CloudFirestoreSources(namespace string) CloudFirestoreSourceInterface {
+	return newCloudFirestoreSources(c, namespace)
+}
+
+func (c *EventsV1Client) CloudMonitoringAlertSources(namespace string) CloudMonitoringAlertSourceInterface {
+	return newCloudMonitoringAlertSources(c, namespace)
+}
+
 func (c *EventsV1Client) CloudPubSubSources(namespace string) CloudPubSubSourceInterface {
 	return newCloudPubSubSources(c, namespace)
 }
@@ -54,6 +75,10 @@ func (c *EventsV1Client) CloudSchedulerSources(namespace string) CloudSchedulerS
 	return newCloudSchedulerSources(c, namespace)
 }
 
+func (c *EventsV1Client) CloudSecretManagerSources(namespace string) CloudSecretManagerSourceInterface {
+	return newCloudSecretManagerSources(c, namespace)
+}
+
 func (c *EventsV1Client) CloudStorageSources(namespace string) CloudStorageSourceInterface {
 	return newCloudStorageSources(c, namespace)
 }
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	eventsv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeCloudContainerRegistrySources implements CloudContainerRegistrySourceInterface
+type FakeCloudContainerRegistrySources struct {
+	Fake *FakeEventsV1
+	ns   string
+}
+
+var cloudcontainerregistrysourcesResource = schema.GroupVersionResource{Group: "events.cloud.google.com", Version: "v1", Resource: "cloudcontainerregistrysources"}
+
+var cloudcontainerregistrysourcesKind = schema.GroupVersionKind{Group: "events.cloud.google.com", Version: "v1", Kind: "CloudContainerRegistrySource"}
+
+// Get takes name of the cloudContainerRegistrySource, and returns the corresponding cloudContainerRegistrySource object, and an error if there is any.
+func (c *FakeCloudContainerRegistrySources) Get(ctx context.Context, name string, options v1.GetOptions) (result *eventsv1.CloudContainerRegistrySource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(cloudcontainerregistrysourcesResource, c.ns, name), &eventsv1.CloudContainerRegistrySource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudContainerRegistrySource), err
+}
+
+// List takes label and field selectors, and returns the list of CloudContainerRegistrySources that match those selectors.
+func (c *FakeCloudContainerRegistrySources) List(ctx context.Context, opts v1.ListOptions) (result *eventsv1.CloudContainerRegistrySourceList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(cloudcontainerregistrysourcesResource, cloudcontainerregistrysourcesKind, c.ns, opts), &eventsv1.CloudContainerRegistrySourceList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &eventsv1.CloudContainerRegistrySourceList{ListMeta: obj.(*eventsv1.CloudContainerRegistrySourceList).ListMeta}
+	for _, item := range obj.(*eventsv1.CloudContainerRegistrySourceList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested cloudContainerRegistrySources.
+func (c *FakeCloudContainerRegistrySources) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(cloudcontainerregistrysourcesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a cloudContainerRegistrySource and creates it.  Returns the server's representation of the cloudContainerRegistrySource, and an error, if there is any.
+func (c *FakeCloudContainerRegistrySources) Create(ctx context.Context, cloudContainerRegistrySource *eventsv1.CloudContainerRegistrySource, opts v1.CreateOptions) (result *eventsv1.CloudContainerRegistrySource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(cloudcontainerregistrysourcesResource, c.ns, cloudContainerRegistrySource), &eventsv1.CloudContainerRegistrySource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudContainerRegistrySource), err
+}
+
+// Update takes the representation of a cloudContainerRegistrySource and updates it. Returns the server's representation of the cloudContainerRegistrySource, and an error, if there is any.
+func (c *FakeCloudContainerRegistrySources) Update(ctx context.Context, cloudContainerRegistrySource *eventsv1.CloudContainerRegistrySource, opts v1.UpdateOptions) (result *eventsv1.CloudContainerRegistrySource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(cloudcontainerregistrysourcesResource, c.ns, cloudContainerRegistrySource), &eventsv1.CloudContainerRegistrySource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudContainerRegistrySource), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeCloudContainerRegistrySources) UpdateStatus(ctx context.Context, cloudContainerRegistrySource *eventsv1.CloudContainerRegistrySource, opts v1.UpdateOptions) (*eventsv1.CloudContainerRegistrySource, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(cloudcontainerregistrysourcesResource, "status", c.ns, cloudContainerRegistrySource), &eventsv1.CloudContainerRegistrySource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudContainerRegistrySource), err
+}
+
+// Delete takes name of the cloudContainerRegistrySource and deletes it. Returns an error if one occurs.
+func (c *FakeCloudContainerRegistrySources) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(cloudcontainerregistrysourcesResource, c.ns, name), &eventsv1.CloudContainerRegistrySource{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeCloudContainerRegistrySources) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(cloudcontainerregistrysourcesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &eventsv1.CloudContainerRegistrySourceList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched cloudContainerRegistrySource.
+func (c *FakeCloudContainerRegistrySources) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *eventsv1.CloudContainerRegistrySource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(cloudcontainerregistrysourcesResource, c.ns, name, pt, data, subresources...), &eventsv1.CloudContainerRegistrySource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudContainerRegistrySource), err
+}
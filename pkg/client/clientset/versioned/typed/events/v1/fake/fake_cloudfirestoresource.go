@@ -0,0 +1,142 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	eventsv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeCloudFirestoreSources implements CloudFirestoreSourceInterface
+type FakeCloudFirestoreSources struct {
+	Fake *FakeEventsV1
+	ns   string
+}
+
+var cloudfirestoresourcesResource = schema.GroupVersionResource{Group: "events.cloud.google.com", Version: "v1", Resource: "cloudfirestoresources"}
+
+var cloudfirestoresourcesKind = schema.GroupVersionKind{Group: "events.cloud.google.com", Version: "v1", Kind: "CloudFirestoreSource"}
+
+// Get takes name of the cloudFirestoreSource, and returns the corresponding cloudFirestoreSource object, and an error if there is any.
+func (c *FakeCloudFirestoreSources) Get(ctx context.Context, name string, options v1.GetOptions) (result *eventsv1.CloudFirestoreSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(cloudfirestoresourcesResource, c.ns, name), &eventsv1.CloudFirestoreSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudFirestoreSource), err
+}
+
+// List takes label and field selectors, and returns the list of CloudFirestoreSources that match those selectors.
+func (c *FakeCloudFirestoreSources) List(ctx context.Context, opts v1.ListOptions) (result *eventsv1.CloudFirestoreSourceList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(cloudfirestoresourcesResource, cloudfirestoresourcesKind, c.ns, opts), &eventsv1.CloudFirestoreSourceList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &eventsv1.CloudFirestoreSourceList{ListMeta: obj.(*eventsv1.CloudFirestoreSourceList).ListMeta}
+	for _, item := range obj.(*eventsv1.CloudFirestoreSourceList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested cloudFirestoreSources.
+func (c *FakeCloudFirestoreSources) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(cloudfirestoresourcesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a cloudFirestoreSource and creates it.  Returns the server's representation of the cloudFirestoreSource, and an error, if there is any.
+func (c *FakeCloudFirestoreSources) Create(ctx context.Context, cloudFirestoreSource *eventsv1.CloudFirestoreSource, opts v1.CreateOptions) (result *eventsv1.CloudFirestoreSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(cloudfirestoresourcesResource, c.ns, cloudFirestoreSource), &eventsv1.CloudFirestoreSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudFirestoreSource), err
+}
+
+// Update takes the representation of a cloudFirestoreSource and updates it. Returns the server's representation of the cloudFirestoreSource, and an error, if there is any.
+func (c *FakeCloudFirestoreSources) Update(ctx context.Context, cloudFirestoreSource *eventsv1.CloudFirestoreSource, opts v1.UpdateOptions) (result *eventsv1.CloudFirestoreSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(cloudfirestoresourcesResource, c.ns, cloudFirestoreSource), &eventsv1.CloudFirestoreSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudFirestoreSource), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeCloudFirestoreSources) UpdateStatus(ctx context.Context, cloudFirestoreSource *eventsv1.CloudFirestoreSource, opts v1.UpdateOptions) (*eventsv1.CloudFirestoreSource, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(cloudfirestoresourcesResource, "status", c.ns, cloudFirestoreSource), &eventsv1.CloudFirestoreSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudFirestoreSource), err
+}
+
+// Delete takes name of the cloudFirestoreSource and deletes it. Returns an error if one occurs.
+func (c *FakeCloudFirestoreSources) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(cloudfirestoresourcesResource, c.ns, name), &eventsv1.CloudFirestoreSource{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeCloudFirestoreSources) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(cloudfirestoresourcesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &eventsv1.CloudFirestoreSourceList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched cloudFirestoreSource.
+func (c *FakeCloudFirestoreSources) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *eventsv1.CloudFirestoreSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(cloudfirestoresourcesResource, c.ns, name, pt, data, subresources...), &eventsv1.CloudFirestoreSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudFirestoreSource), err
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	eventsv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeCloudMonitoringAlertSources implements CloudMonitoringAlertSourceInterface
+type FakeCloudMonitoringAlertSources struct {
+	Fake *FakeEventsV1
+	ns   string
+}
+
+var cloudmonitoringalertsourcesResource = schema.GroupVersionResource{Group: "events.cloud.google.com", Version: "v1", Resource: "cloudmonitoringalertsources"}
+
+var cloudmonitoringalertsourcesKind = schema.GroupVersionKind{Group: "events.cloud.google.com", Version: "v1", Kind: "CloudMonitoringAlertSource"}
+
+// Get takes name of the cloudMonitoringAlertSource, and returns the corresponding cloudMonitoringAlertSource object, and an error if there is any.
+func (c *FakeCloudMonitoringAlertSources) Get(ctx context.Context, name string, options v1.GetOptions) (result *eventsv1.CloudMonitoringAlertSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(cloudmonitoringalertsourcesResource, c.ns, name), &eventsv1.CloudMonitoringAlertSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudMonitoringAlertSource), err
+}
+
+// List takes label and field selectors, and returns the list of CloudMonitoringAlertSources that match those selectors.
+func (c *FakeCloudMonitoringAlertSources) List(ctx context.Context, opts v1.ListOptions) (result *eventsv1.CloudMonitoringAlertSourceList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(cloudmonitoringalertsourcesResource, cloudmonitoringalertsourcesKind, c.ns, opts), &eventsv1.CloudMonitoringAlertSourceList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &eventsv1.CloudMonitoringAlertSourceList{ListMeta: obj.(*eventsv1.CloudMonitoringAlertSourceList).ListMeta}
+	for _, item := range obj.(*eventsv1.CloudMonitoringAlertSourceList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested cloudMonitoringAlertSources.
+func (c *FakeCloudMonitoringAlertSources) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(cloudmonitoringalertsourcesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a cloudMonitoringAlertSource and creates it.  Returns the server's representation of the cloudMonitoringAlertSource, and an error, if there is any.
+func (c *FakeCloudMonitoringAlertSources) Create(ctx context.Context, cloudMonitoringAlertSource *eventsv1.CloudMonitoringAlertSource, opts v1.CreateOptions) (result *eventsv1.CloudMonitoringAlertSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(cloudmonitoringalertsourcesResource, c.ns, cloudMonitoringAlertSource), &eventsv1.CloudMonitoringAlertSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudMonitoringAlertSource), err
+}
+
+// Update takes the representation of a cloudMonitoringAlertSource and updates it. Returns the server's representation of the cloudMonitoringAlertSource, and an error, if there is any.
+func (c *FakeCloudMonitoringAlertSources) Update(ctx context.Context, cloudMonitoringAlertSource *eventsv1.CloudMonitoringAlertSource, opts v1.UpdateOptions) (result *eventsv1.CloudMonitoringAlertSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(cloudmonitoringalertsourcesResource, c.ns, cloudMonitoringAlertSource), &eventsv1.CloudMonitoringAlertSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudMonitoringAlertSource), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeCloudMonitoringAlertSources) UpdateStatus(ctx context.Context, cloudMonitoringAlertSource *eventsv1.CloudMonitoringAlertSource, opts v1.UpdateOptions) (*eventsv1.CloudMonitoringAlertSource, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(cloudmonitoringalertsourcesResource, "status", c.ns, cloudMonitoringAlertSource), &eventsv1.CloudMonitoringAlertSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudMonitoringAlertSource), err
+}
+
+// Delete takes name of the cloudMonitoringAlertSource and deletes it. Returns an error if one occurs.
+func (c *FakeCloudMonitoringAlertSources) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(cloudmonitoringalertsourcesResource, c.ns, name), &eventsv1.CloudMonitoringAlertSource{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeCloudMonitoringAlertSources) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(cloudmonitoringalertsourcesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &eventsv1.CloudMonitoringAlertSourceList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched cloudMonitoringAlertSource.
+func (c *FakeCloudMonitoringAlertSources) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *eventsv1.CloudMonitoringAlertSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(cloudmonitoringalertsourcesResource, c.ns, name, pt, data, subresources...), &eventsv1.CloudMonitoringAlertSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudMonitoringAlertSource), err
+}
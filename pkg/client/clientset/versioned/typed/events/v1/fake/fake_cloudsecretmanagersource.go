@@ -0,0 +1,142 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	eventsv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeCloudSecretManagerSources implements CloudSecretManagerSourceInterface
+type FakeCloudSecretManagerSources struct {
+	Fake *FakeEventsV1
+	ns   string
+}
+
+var cloudsecretmanagersourcesResource = schema.GroupVersionResource{Group: "events.cloud.google.com", Version: "v1", Resource: "cloudsecretmanagersources"}
+
+var cloudsecretmanagersourcesKind = schema.GroupVersionKind{Group: "events.cloud.google.com", Version: "v1", Kind: "CloudSecretManagerSource"}
+
+// Get takes name of the cloudSecretManagerSource, and returns the corresponding cloudSecretManagerSource object, and an error if there is any.
+func (c *FakeCloudSecretManagerSources) Get(ctx context.Context, name string, options v1.GetOptions) (result *eventsv1.CloudSecretManagerSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(cloudsecretmanagersourcesResource, c.ns, name), &eventsv1.CloudSecretManagerSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudSecretManagerSource), err
+}
+
+// List takes label and field selectors, and returns the list of CloudSecretManagerSources that match those selectors.
+func (c *FakeCloudSecretManagerSources) List(ctx context.Context, opts v1.ListOptions) (result *eventsv1.CloudSecretManagerSourceList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(cloudsecretmanagersourcesResource, cloudsecretmanagersourcesKind, c.ns, opts), &eventsv1.CloudSecretManagerSourceList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &eventsv1.CloudSecretManagerSourceList{ListMeta: obj.(*eventsv1.CloudSecretManagerSourceList).ListMeta}
+	for _, item := range obj.(*eventsv1.CloudSecretManagerSourceList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested cloudSecretManagerSources.
+func (c *FakeCloudSecretManagerSources) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(cloudsecretmanagersourcesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a cloudSecretManagerSource and creates it.  Returns the server's representation of the cloudSecretManagerSource, and an error, if there is any.
+func (c *FakeCloudSecretManagerSources) Create(ctx context.Context, cloudSecretManagerSource *eventsv1.CloudSecretManagerSource, opts v1.CreateOptions) (result *eventsv1.CloudSecretManagerSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(cloudsecretmanagersourcesResource, c.ns, cloudSecretManagerSource), &eventsv1.CloudSecretManagerSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudSecretManagerSource), err
+}
+
+// Update takes the representation of a cloudSecretManagerSource and updates it. Returns the server's representation of the cloudSecretManagerSource, and an error, if there is any.
+func (c *FakeCloudSecretManagerSources) Update(ctx context.Context, cloudSecretManagerSource *eventsv1.CloudSecretManagerSource, opts v1.UpdateOptions) (result *eventsv1.CloudSecretManagerSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(cloudsecretmanagersourcesResource, c.ns, cloudSecretManagerSource), &eventsv1.CloudSecretManagerSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudSecretManagerSource), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeCloudSecretManagerSources) UpdateStatus(ctx context.Context, cloudSecretManagerSource *eventsv1.CloudSecretManagerSource, opts v1.UpdateOptions) (*eventsv1.CloudSecretManagerSource, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(cloudsecretmanagersourcesResource, "status", c.ns, cloudSecretManagerSource), &eventsv1.CloudSecretManagerSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudSecretManagerSource), err
+}
+
+// Delete takes name of the cloudSecretManagerSource and deletes it. Returns an error if one occurs.
+func (c *FakeCloudSecretManagerSources) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(cloudsecretmanagersourcesResource, c.ns, name), &eventsv1.CloudSecretManagerSource{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeCloudSecretManagerSources) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(cloudsecretmanagersourcesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &eventsv1.CloudSecretManagerSourceList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched cloudSecretManagerSource.
+func (c *FakeCloudSecretManagerSources) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *eventsv1.CloudSecretManagerSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(cloudsecretmanagersourcesResource, c.ns, name, pt, data, subresources...), &eventsv1.CloudSecretManagerSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*eventsv1.CloudSecretManagerSource), err
+}
@@ -32,10 +32,26 @@ func (c *FakeEventsV1) CloudAuditLogsSources(namespace string) v1.CloudAuditLogs
 	return &FakeCloudAuditLogsSources{c, namespace}
 }
 
+func (c *FakeEventsV1) CloudBillingBudgetSources(namespace string) v1.CloudBillingBudgetSourceInterface {
+	return &FakeCloudBillingBudgetSources{c, namespace}
+}
+
 func (c *FakeEventsV1) CloudBuildSources(namespace string) v1.CloudBuildSourceInterface {
 	return &FakeCloudBuildSources{c, namespace}
 }
 
+func (c *FakeEventsV1) CloudContainerRegistrySources(namespace string) v1.CloudContainerRegistrySourceInterface {
+	return &FakeCloudContainerRegistrySources{c, namespace}
+}
+
+func (c *FakeEventsV1) CloudFirestoreSources(namespace string) v1.CloudFirestoreSourceInterface {
+	return &FakeCloudFirestoreSources{c, namespace}
+}
+
+func (c *FakeEventsV1) CloudMonitoringAlertSources(namespace string) v1.CloudMonitoringAlertSourceInterface {
+	return &FakeCloudMonitoringAlertSources{c, namespace}
+}
+
 func (c *FakeEventsV1) CloudPubSubSources(namespace string) v1.CloudPubSubSourceInterface {
 	return &FakeCloudPubSubSources{c, namespace}
 }
@@ -44,6 +60,10 @@ func (c *FakeEventsV1) CloudSchedulerSources(namespace string) v1.CloudScheduler
 	return &FakeCloudSchedulerSources{c, namespace}
 }
 
+func (c *FakeEventsV1) CloudSecretManagerSources(namespace string) v1.CloudSecretManagerSourceInterface {
+	return &FakeCloudSecretManagerSources{c, namespace}
+}
+
 func (c *FakeEventsV1) CloudStorageSources(namespace string) v1.CloudStorageSourceInterface {
 	return &FakeCloudStorageSources{c, namespace}
 }
@@ -20,10 +20,20 @@ package v1
 
 type CloudAuditLogsSourceExpansion interface{}
 
+type CloudBillingBudgetSourceExpansion interface{}
+
 type CloudBuildSourceExpansion interface{}
 
+type CloudContainerRegistrySourceExpansion interface{}
+
+type CloudFirestoreSourceExpansion interface{}
+
+type CloudMonitoringAlertSourceExpansion interface{}
+
 type CloudPubSubSourceExpansion interface{}
 
 type CloudSchedulerSourceExpansion interface{}
 
+type CloudSecretManagerSourceExpansion interface{}
+
 type CloudStorageSourceExpansion interface{}
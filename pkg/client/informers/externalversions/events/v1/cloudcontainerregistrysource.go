@@ -0,0 +1,90 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	time "time"
+
+	eventsv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	versioned "github.com/google/knative-gcp/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/google/knative-gcp/pkg/client/informers/externalversions/internalinterfaces"
+	v1 "github.com/google/knative-gcp/pkg/client/listers/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// CloudContainerRegistrySourceInformer provides access to a shared informer and lister for
+// CloudContainerRegistrySources.
+type CloudContainerRegistrySourceInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.CloudContainerRegistrySourceLister
+}
+
+type cloudContainerRegistrySourceInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewCloudContainerRegistrySourceInformer constructs a new informer for CloudContainerRegistrySource type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewCloudContainerRegistrySourceInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredCloudContainerRegistrySourceInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredCloudContainerRegistrySourceInformer constructs a new informer for CloudContainerRegistrySource type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredCloudContainerRegistrySourceInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.EventsV1().CloudContainerRegistrySources(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.EventsV1().CloudContainerRegistrySources(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&eventsv1.CloudContainerRegistrySource{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *cloudContainerRegistrySourceInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredCloudContainerRegistrySourceInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *cloudContainerRegistrySourceInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&eventsv1.CloudContainerRegistrySource{}, f.defaultInformer)
+}
+
+func (f *cloudContainerRegistrySourceInformer) Lister() v1.CloudContainerRegistrySourceLister {
+	return v1.NewCloudContainerRegistrySourceLister(f.Informer().GetIndexer())
+}
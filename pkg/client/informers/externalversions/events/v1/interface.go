@@ -26,12 +26,22 @@ import (
 type Interface interface {
 	// CloudAuditLogsSources returns a CloudAuditLogsSourceInformer.
 	CloudAuditLogsSources() CloudAuditLogsSourceInformer
+	// CloudBillingBudgetSources returns a CloudBillingBudgetSourceInformer.
+	CloudBillingBudgetSources() CloudBillingBudgetSourceInformer
 	// CloudBuildSources returns a CloudBuildSourceInformer.
 	CloudBuildSources() CloudBuildSourceInformer
+	// CloudContainerRegistrySources returns a CloudContainerRegistrySourceInformer.
+	CloudContainerRegistrySources() CloudContainerRegistrySourceInformer
+	// CloudFirestoreSources returns a CloudFirestoreSourceInformer.
+	CloudFirestoreSources() CloudFirestoreSourceInformer
+	// CloudMonitoringAlertSources returns a CloudMonitoringAlertSourceInformer.
+	CloudMonitoringAlertSources() CloudMonitoringAlertSourceInformer
 	// CloudPubSubSources returns a CloudPubSubSourceInformer.
 	CloudPubSubSources() CloudPubSubSourceInformer
 	// CloudSchedulerSources returns a CloudSchedulerSourceInformer.
 	CloudSchedulerSources() CloudSchedulerSourceInformer
+	// CloudSecretManagerSources returns a CloudSecretManagerSourceInformer.
+	CloudSecretManagerSources() CloudSecretManagerSourceInformer
 	// CloudStorageSources returns a CloudStorageSourceInformer.
 	CloudStorageSources() CloudStorageSourceInformer
 }
@@ -52,11 +62,31 @@ func (v *version) CloudAuditLogsSources() CloudAuditLogsSourceInformer {
 	return &cloudAuditLogsSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CloudBillingBudgetSources returns a CloudBillingBudgetSourceInformer.
+func (v *version) CloudBillingBudgetSources() CloudBillingBudgetSourceInformer {
+	return &cloudBillingBudgetSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CloudBuildSources returns a CloudBuildSourceInformer.
 func (v *version) CloudBuildSources() CloudBuildSourceInformer {
 	return &cloudBuildSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CloudContainerRegistrySources returns a CloudContainerRegistrySourceInformer.
+func (v *version) CloudContainerRegistrySources() CloudContainerRegistrySourceInformer {
+	return &cloudContainerRegistrySourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// CloudFirestoreSources returns a CloudFirestoreSourceInformer.
+func (v *version) CloudFirestoreSources() CloudFirestoreSourceInformer {
+	return &cloudFirestoreSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// CloudMonitoringAlertSources returns a CloudMonitoringAlertSourceInformer.
+func (v *version) CloudMonitoringAlertSources() CloudMonitoringAlertSourceInformer {
+	return &cloudMonitoringAlertSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CloudPubSubSources returns a CloudPubSubSourceInformer.
 func (v *version) CloudPubSubSources() CloudPubSubSourceInformer {
 	return &cloudPubSubSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -67,6 +97,11 @@ func (v *version) CloudSchedulerSources() CloudSchedulerSourceInformer {
 	return &cloudSchedulerSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CloudSecretManagerSources returns a CloudSecretManagerSourceInformer.
+func (v *version) CloudSecretManagerSources() CloudSecretManagerSourceInformer {
+	return &cloudSecretManagerSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CloudStorageSources returns a CloudStorageSourceInformer.
 func (v *version) CloudStorageSources() CloudStorageSourceInformer {
 	return &cloudStorageSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -67,12 +67,22 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		// Group=events.cloud.google.com, Version=v1
 	case v1.SchemeGroupVersion.WithResource("cloudauditlogssources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1().CloudAuditLogsSources().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cloudbillingbudgetsources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1().CloudBillingBudgetSources().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cloudbuildsources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1().CloudBuildSources().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cloudcontainerregistrysources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1().CloudContainerRegistrySources().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cloudfirestoresources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1().CloudFirestoreSources().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cloudmonitoringalertsources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1().CloudMonitoringAlertSources().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cloudpubsubsources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1().CloudPubSubSources().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cloudschedulersources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1().CloudSchedulerSources().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cloudsecretmanagersources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1().CloudSecretManagerSources().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cloudstoragesources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1().CloudStorageSources().Informer()}, nil
 
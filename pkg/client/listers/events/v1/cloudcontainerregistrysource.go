@@ -0,0 +1,94 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CloudContainerRegistrySourceLister helps list CloudContainerRegistrySources.
+type CloudContainerRegistrySourceLister interface {
+	// List lists all CloudContainerRegistrySources in the indexer.
+	List(selector labels.Selector) (ret []*v1.CloudContainerRegistrySource, err error)
+	// CloudContainerRegistrySources returns an object that can list and get CloudContainerRegistrySources.
+	CloudContainerRegistrySources(namespace string) CloudContainerRegistrySourceNamespaceLister
+	CloudContainerRegistrySourceListerExpansion
+}
+
+// cloudContainerRegistrySourceLister implements the CloudContainerRegistrySourceLister interface.
+type cloudContainerRegistrySourceLister struct {
+	indexer cache.Indexer
+}
+
+// NewCloudContainerRegistrySourceLister returns a new CloudContainerRegistrySourceLister.
+func NewCloudContainerRegistrySourceLister(indexer cache.Indexer) CloudContainerRegistrySourceLister {
+	return &cloudContainerRegistrySourceLister{indexer: indexer}
+}
+
+// List lists all CloudContainerRegistrySources in the indexer.
+func (s *cloudContainerRegistrySourceLister) List(selector labels.Selector) (ret []*v1.CloudContainerRegistrySource, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CloudContainerRegistrySource))
+	})
+	return ret, err
+}
+
+// CloudContainerRegistrySources returns an object that can list and get CloudContainerRegistrySources.
+func (s *cloudContainerRegistrySourceLister) CloudContainerRegistrySources(namespace string) CloudContainerRegistrySourceNamespaceLister {
+	return cloudContainerRegistrySourceNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// CloudContainerRegistrySourceNamespaceLister helps list and get CloudContainerRegistrySources.
+type CloudContainerRegistrySourceNamespaceLister interface {
+	// List lists all CloudContainerRegistrySources in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1.CloudContainerRegistrySource, err error)
+	// Get retrieves the CloudContainerRegistrySource from the indexer for a given namespace and name.
+	Get(name string) (*v1.CloudContainerRegistrySource, error)
+	CloudContainerRegistrySourceNamespaceListerExpansion
+}
+
+// cloudContainerRegistrySourceNamespaceLister implements the CloudContainerRegistrySourceNamespaceLister
+// interface.
+type cloudContainerRegistrySourceNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all CloudContainerRegistrySources in the indexer for a given namespace.
+func (s cloudContainerRegistrySourceNamespaceLister) List(selector labels.Selector) (ret []*v1.CloudContainerRegistrySource, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CloudContainerRegistrySource))
+	})
+	return ret, err
+}
+
+// Get retrieves the CloudContainerRegistrySource from the indexer for a given namespace and name.
+func (s cloudContainerRegistrySourceNamespaceLister) Get(name string) (*v1.CloudContainerRegistrySource, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("cloudcontainerregistrysource"), name)
+	}
+	return obj.(*v1.CloudContainerRegistrySource), nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CloudSecretManagerSourceLister helps list CloudSecretManagerSources.
+type CloudSecretManagerSourceLister interface {
+	// List lists all CloudSecretManagerSources in the indexer.
+	List(selector labels.Selector) (ret []*v1.CloudSecretManagerSource, err error)
+	// CloudSecretManagerSources returns an object that can list and get CloudSecretManagerSources.
+	CloudSecretManagerSources(namespace string) CloudSecretManagerSourceNamespaceLister
+	CloudSecretManagerSourceListerExpansion
+}
+
+// cloudSecretManagerSourceLister implements the CloudSecretManagerSourceLister interface.
+type cloudSecretManagerSourceLister struct {
+	indexer cache.Indexer
+}
+
+// NewCloudSecretManagerSourceLister returns a new CloudSecretManagerSourceLister.
+func NewCloudSecretManagerSourceLister(indexer cache.Indexer) CloudSecretManagerSourceLister {
+	return &cloudSecretManagerSourceLister{indexer: indexer}
+}
+
+// List lists all CloudSecretManagerSources in the indexer.
+func (s *cloudSecretManagerSourceLister) List(selector labels.Selector) (ret []*v1.CloudSecretManagerSource, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CloudSecretManagerSource))
+	})
+	return ret, err
+}
+
+// CloudSecretManagerSources returns an object that can list and get CloudSecretManagerSources.
+func (s *cloudSecretManagerSourceLister) CloudSecretManagerSources(namespace string) CloudSecretManagerSourceNamespaceLister {
+	return cloudSecretManagerSourceNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// CloudSecretManagerSourceNamespaceLister helps list and get CloudSecretManagerSources.
+type CloudSecretManagerSourceNamespaceLister interface {
+	// List lists all CloudSecretManagerSources in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1.CloudSecretManagerSource, err error)
+	// Get retrieves the CloudSecretManagerSource from the indexer for a given namespace and name.
+	Get(name string) (*v1.CloudSecretManagerSource, error)
+	CloudSecretManagerSourceNamespaceListerExpansion
+}
+
+// cloudSecretManagerSourceNamespaceLister implements the CloudSecretManagerSourceNamespaceLister
+// interface.
+type cloudSecretManagerSourceNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all CloudSecretManagerSources in the indexer for a given namespace.
+func (s cloudSecretManagerSourceNamespaceLister) List(selector labels.Selector) (ret []*v1.CloudSecretManagerSource, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CloudSecretManagerSource))
+	})
+	return ret, err
+}
+
+// Get retrieves the CloudSecretManagerSource from the indexer for a given namespace and name.
+func (s cloudSecretManagerSourceNamespaceLister) Get(name string) (*v1.CloudSecretManagerSource, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("cloudsecretmanagersource"), name)
+	}
+	return obj.(*v1.CloudSecretManagerSource), nil
+}
@@ -26,6 +26,14 @@ type CloudAuditLogsSourceListerExpansion interface{}
 // CloudAuditLogsSourceNamespaceLister.
 type CloudAuditLogsSourceNamespaceListerExpansion interface{}
 
+// CloudBillingBudgetSourceListerExpansion allows custom methods to be added to
+// CloudBillingBudgetSourceLister.
+type CloudBillingBudgetSourceListerExpansion interface{}
+
+// CloudBillingBudgetSourceNamespaceListerExpansion allows custom methods to be added to
+// CloudBillingBudgetSourceNamespaceLister.
+type CloudBillingBudgetSourceNamespaceListerExpansion interface{}
+
 // CloudBuildSourceListerExpansion allows custom methods to be added to
 // CloudBuildSourceLister.
 type CloudBuildSourceListerExpansion interface{}
@@ -34,6 +42,30 @@ type CloudBuildSourceListerExpansion interface{}
 // CloudBuildSourceNamespaceLister.
 type CloudBuildSourceNamespaceListerExpansion interface{}
 
+// CloudContainerRegistrySourceListerExpansion allows custom methods to be added to
+// CloudContainerRegistrySourceLister.
+type CloudContainerRegistrySourceListerExpansion interface{}
+
+// CloudContainerRegistrySourceNamespaceListerExpansion allows custom methods to be added to
+// CloudContainerRegistrySourceNamespaceLister.
+type CloudContainerRegistrySourceNamespaceListerExpansion interface{}
+
+// CloudFirestoreSourceListerExpansion allows custom methods to be added to
+// CloudFirestoreSourceLister.
+type CloudFirestoreSourceListerExpansion interface{}
+
+// CloudFirestoreSourceNamespaceListerExpansion allows custom methods to be added to
+// CloudFirestoreSourceNamespaceLister.
+type CloudFirestoreSourceNamespaceListerExpansion interface{}
+
+// CloudMonitoringAlertSourceListerExpansion allows custom methods to be added to
+// CloudMonitoringAlertSourceLister.
+type CloudMonitoringAlertSourceListerExpansion interface{}
+
+// CloudMonitoringAlertSourceNamespaceListerExpansion allows custom methods to be added to
+// CloudMonitoringAlertSourceNamespaceLister.
+type CloudMonitoringAlertSourceNamespaceListerExpansion interface{}
+
 // CloudPubSubSourceListerExpansion allows custom methods to be added to
 // CloudPubSubSourceLister.
 type CloudPubSubSourceListerExpansion interface{}
@@ -50,6 +82,14 @@ type CloudSchedulerSourceListerExpansion interface{}
 // CloudSchedulerSourceNamespaceLister.
 type CloudSchedulerSourceNamespaceListerExpansion interface{}
 
+// CloudSecretManagerSourceListerExpansion allows custom methods to be added to
+// CloudSecretManagerSourceLister.
+type CloudSecretManagerSourceListerExpansion interface{}
+
+// CloudSecretManagerSourceNamespaceListerExpansion allows custom methods to be added to
+// CloudSecretManagerSourceNamespaceLister.
+type CloudSecretManagerSourceNamespaceListerExpansion interface{}
+
 // CloudStorageSourceListerExpansion allows custom methods to be added to
 // CloudStorageSourceLister.
 type CloudStorageSourceListerExpansion interface{}
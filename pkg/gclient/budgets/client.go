@@ -0,0 +1,63 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package budgets
+
+import (
+	"context"
+
+	budgets "cloud.google.com/go/billing/budgets/apiv1beta1"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/api/option"
+	budgetspb "google.golang.org/genproto/googleapis/cloud/billing/budgets/v1beta1"
+)
+
+// CreateFn is a factory function to create a Cloud Billing budget client.
+type CreateFn func(ctx context.Context, opts ...option.ClientOption) (BudgetClient, error)
+
+// NewBudgetClient creates a new wrapped Cloud Billing budget client.
+func NewBudgetClient(ctx context.Context, opts ...option.ClientOption) (BudgetClient, error) {
+	client, err := budgets.NewBudgetClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &budgetClient{
+		client: client,
+	}, nil
+}
+
+// budgetClient wraps budgets.BudgetClient. Is the client that will be used everywhere except unit tests.
+type budgetClient struct {
+	client *budgets.BudgetClient
+}
+
+// Verify that it satisfies the BudgetClient interface.
+var _ BudgetClient = &budgetClient{}
+
+// Close implements budgets.BudgetClient.Close
+func (c *budgetClient) Close() error {
+	return c.client.Close()
+}
+
+// GetBudget implements budgets.BudgetClient.GetBudget
+func (c *budgetClient) GetBudget(ctx context.Context, req *budgetspb.GetBudgetRequest, opts ...gax.CallOption) (*budgetspb.Budget, error) {
+	return c.client.GetBudget(ctx, req, opts...)
+}
+
+// UpdateBudget implements budgets.BudgetClient.UpdateBudget
+func (c *budgetClient) UpdateBudget(ctx context.Context, req *budgetspb.UpdateBudgetRequest, opts ...gax.CallOption) (*budgetspb.Budget, error) {
+	return c.client.UpdateBudget(ctx, req, opts...)
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package budgets
+
+import (
+	"context"
+
+	"github.com/googleapis/gax-go/v2"
+	budgetspb "google.golang.org/genproto/googleapis/cloud/billing/budgets/v1beta1"
+)
+
+// BudgetClient matches the interface exposed by budgets.BudgetClient
+// see https://godoc.org/cloud.google.com/go/billing/budgets/apiv1beta1#BudgetClient
+type BudgetClient interface {
+	// Close see https://godoc.org/cloud.google.com/go/billing/budgets/apiv1beta1#BudgetClient.Close
+	Close() error
+	// GetBudget see https://godoc.org/cloud.google.com/go/billing/budgets/apiv1beta1#BudgetClient.GetBudget
+	GetBudget(ctx context.Context, req *budgetspb.GetBudgetRequest, opts ...gax.CallOption) (*budgetspb.Budget, error)
+	// UpdateBudget see https://godoc.org/cloud.google.com/go/billing/budgets/apiv1beta1#BudgetClient.UpdateBudget
+	UpdateBudget(ctx context.Context, req *budgetspb.UpdateBudgetRequest, opts ...gax.CallOption) (*budgetspb.Budget, error)
+}
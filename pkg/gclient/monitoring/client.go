@@ -0,0 +1,68 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/api/option"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+)
+
+// CreateFn is a factory function to create a Cloud Monitoring notification channel client.
+type CreateFn func(ctx context.Context, opts ...option.ClientOption) (NotificationChannelClient, error)
+
+// NewNotificationChannelClient creates a new wrapped Cloud Monitoring notification channel client.
+func NewNotificationChannelClient(ctx context.Context, opts ...option.ClientOption) (NotificationChannelClient, error) {
+	client, err := monitoring.NewNotificationChannelClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &notificationChannelClient{
+		client: client,
+	}, nil
+}
+
+// notificationChannelClient wraps monitoring.NotificationChannelClient. Is the client that will be used everywhere except unit tests.
+type notificationChannelClient struct {
+	client *monitoring.NotificationChannelClient
+}
+
+// Verify that it satisfies the NotificationChannelClient interface.
+var _ NotificationChannelClient = &notificationChannelClient{}
+
+// Close implements monitoring.NotificationChannelClient.Close
+func (c *notificationChannelClient) Close() error {
+	return c.client.Close()
+}
+
+// CreateNotificationChannel implements monitoring.NotificationChannelClient.CreateNotificationChannel
+func (c *notificationChannelClient) CreateNotificationChannel(ctx context.Context, req *monitoringpb.CreateNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error) {
+	return c.client.CreateNotificationChannel(ctx, req, opts...)
+}
+
+// GetNotificationChannel implements monitoring.NotificationChannelClient.GetNotificationChannel
+func (c *notificationChannelClient) GetNotificationChannel(ctx context.Context, req *monitoringpb.GetNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error) {
+	return c.client.GetNotificationChannel(ctx, req, opts...)
+}
+
+// DeleteNotificationChannel implements monitoring.NotificationChannelClient.DeleteNotificationChannel
+func (c *notificationChannelClient) DeleteNotificationChannel(ctx context.Context, req *monitoringpb.DeleteNotificationChannelRequest, opts ...gax.CallOption) error {
+	return c.client.DeleteNotificationChannel(ctx, req, opts...)
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+
+	"github.com/googleapis/gax-go/v2"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+)
+
+// NotificationChannelClient matches the interface exposed by monitoring.NotificationChannelClient
+// see https://godoc.org/cloud.google.com/go/monitoring/apiv3/v2#NotificationChannelClient
+type NotificationChannelClient interface {
+	// Close see https://godoc.org/cloud.google.com/go/monitoring/apiv3/v2#NotificationChannelClient.Close
+	Close() error
+	// CreateNotificationChannel see https://godoc.org/cloud.google.com/go/monitoring/apiv3/v2#NotificationChannelClient.CreateNotificationChannel
+	CreateNotificationChannel(ctx context.Context, req *monitoringpb.CreateNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error)
+	// GetNotificationChannel see https://godoc.org/cloud.google.com/go/monitoring/apiv3/v2#NotificationChannelClient.GetNotificationChannel
+	GetNotificationChannel(ctx context.Context, req *monitoringpb.GetNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error)
+	// DeleteNotificationChannel see https://godoc.org/cloud.google.com/go/monitoring/apiv3/v2#NotificationChannelClient.DeleteNotificationChannel
+	DeleteNotificationChannel(ctx context.Context, req *monitoringpb.DeleteNotificationChannelRequest, opts ...gax.CallOption) error
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+)
+
+const (
+	defaultEndpoint    = "https://secretmanager.googleapis.com/"
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// CreateFn is a factory function to create a Secret Manager client.
+type CreateFn func(ctx context.Context, opts ...option.ClientOption) (Client, error)
+
+// NewClient creates a new Secret Manager client.
+func NewClient(ctx context.Context, opts ...option.ClientOption) (Client, error) {
+	// Options given by the caller come last so they can override the endpoint.
+	opts = append([]option.ClientOption{
+		option.WithEndpoint(defaultEndpoint),
+		option.WithScopes(cloudPlatformScope),
+	}, opts...)
+	client, endpoint, err := htransport.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &secretManagerClient{
+		client:   client,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+	}, nil
+}
+
+// secretManagerClient calls the Secret Manager REST API. Is the client that will be used everywhere except unit tests.
+type secretManagerClient struct {
+	client   *http.Client
+	endpoint string
+}
+
+// Verify that it satisfies the Client interface.
+var _ Client = &secretManagerClient{}
+
+// Close implements Client.Close
+func (c *secretManagerClient) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+// GetSecret implements Client.GetSecret
+func (c *secretManagerClient) GetSecret(ctx context.Context, name string) (*Secret, error) {
+	return c.do(ctx, http.MethodGet, name, nil)
+}
+
+// UpdateSecretTopics implements Client.UpdateSecretTopics
+func (c *secretManagerClient) UpdateSecretTopics(ctx context.Context, secret *Secret) (*Secret, error) {
+	return c.do(ctx, http.MethodPatch, secret.Name+"?updateMask=topics", secret)
+}
+
+// do sends a request about a secret and decodes the secret returned. Errors
+// returned by the API are *googleapi.Error.
+func (c *secretManagerClient) do(ctx context.Context, method, path string, secret *Secret) (*Secret, error) {
+	var body io.Reader
+	if secret != nil {
+		b, err := json.Marshal(secret)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+"/v1/"+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if secret != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if err := googleapi.CheckResponse(res); err != nil {
+		return nil, err
+	}
+	var got Secret
+	if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+		return nil, err
+	}
+	return &got, nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+func TestClient(t *testing.T) {
+	var gotMethod, gotPath, gotQuery, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath, gotQuery = r.Method, r.URL.Path, r.URL.RawQuery
+		b, _ := ioutil.ReadAll(r.Body)
+		gotBody = string(b)
+		if r.URL.Path == "/v1/projects/my-project/secrets/missing" {
+			http.Error(w, `{"error":{"code":404,"message":"not found"}}`, http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(&Secret{
+			Name:   "projects/my-project/secrets/my-secret",
+			Topics: []*Topic{{Name: "projects/my-project/topics/my-topic"}},
+			Etag:   `"1"`,
+		})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client, err := NewClient(ctx, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewClient() = %v", err)
+	}
+	defer client.Close()
+
+	want := &Secret{
+		Name:   "projects/my-project/secrets/my-secret",
+		Topics: []*Topic{{Name: "projects/my-project/topics/my-topic"}},
+		Etag:   `"1"`,
+	}
+	got, err := client.GetSecret(ctx, "projects/my-project/secrets/my-secret")
+	if err != nil {
+		t.Fatalf("GetSecret() = %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected secret (-want, +got) = %v", diff)
+	}
+	if gotMethod != http.MethodGet || gotPath != "/v1/projects/my-project/secrets/my-secret" {
+		t.Errorf("unexpected request %s %s", gotMethod, gotPath)
+	}
+
+	if _, err := client.UpdateSecretTopics(ctx, &Secret{Name: "projects/my-project/secrets/my-secret", Etag: `"1"`}); err != nil {
+		t.Fatalf("UpdateSecretTopics() = %v", err)
+	}
+	if gotMethod != http.MethodPatch || gotPath != "/v1/projects/my-project/secrets/my-secret" || gotQuery != "updateMask=topics" {
+		t.Errorf("unexpected request %s %s?%s", gotMethod, gotPath, gotQuery)
+	}
+	if want := `{"name":"projects/my-project/secrets/my-secret","etag":"\"1\""}`; gotBody != want {
+		t.Errorf("unexpected body %s, want %s", gotBody, want)
+	}
+
+	_, err = client.GetSecret(ctx, "projects/my-project/secrets/missing")
+	var gerr *googleapi.Error
+	if !errors.As(err, &gerr) || gerr.Code != http.StatusNotFound {
+		t.Errorf("GetSecret() = %v, want a NotFound googleapi.Error", err)
+	}
+}
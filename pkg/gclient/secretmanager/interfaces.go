@@ -0,0 +1,52 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretmanager
+
+import (
+	"context"
+)
+
+// Client manages the Pub/Sub topics secrets publish their events to.
+// No Secret Manager client library is vendored in this repo, and the
+// google.golang.org/genproto version it pins has no topics in its Secret
+// Manager protos, so it is a thin wrapper of the REST API.
+// see https://cloud.google.com/secret-manager/docs/reference/rest/v1/projects.secrets
+type Client interface {
+	// Close releases the connections of the client.
+	Close() error
+	// GetSecret see https://cloud.google.com/secret-manager/docs/reference/rest/v1/projects.secrets/get
+	GetSecret(ctx context.Context, name string) (*Secret, error)
+	// UpdateSecretTopics replaces the topics of the secret with secret.Topics.
+	// see https://cloud.google.com/secret-manager/docs/reference/rest/v1/projects.secrets/patch
+	UpdateSecretTopics(ctx context.Context, secret *Secret) (*Secret, error)
+}
+
+// Secret holds the fields of a Secret Manager secret used by the sources.
+type Secret struct {
+	// Name is the resource name of the secret, e.g. projects/my-project/secrets/my-secret.
+	Name string `json:"name,omitempty"`
+	// Topics are the Pub/Sub topics the secret publishes its events to.
+	Topics []*Topic `json:"topics,omitempty"`
+	// Etag is used to avoid overwriting concurrent updates of the secret.
+	Etag string `json:"etag,omitempty"`
+}
+
+// Topic is a Pub/Sub topic a secret publishes its events to.
+type Topic struct {
+	// Name is the resource name of the topic, e.g. projects/my-project/topics/my-topic.
+	Name string `json:"name"`
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+
+	"google.golang.org/api/option"
+
+	"github.com/google/knative-gcp/pkg/gclient/secretmanager"
+)
+
+// TestClientCreator returns a secretmanager.CreateFn used to construct the test Secret Manager client.
+func TestClientCreator(value interface{}) secretmanager.CreateFn {
+	var data TestClientData
+	var ok bool
+	if data, ok = value.(TestClientData); !ok {
+		data = TestClientData{}
+	}
+	if data.CreateClientErr != nil {
+		return func(_ context.Context, _ ...option.ClientOption) (secretmanager.Client, error) {
+			return nil, data.CreateClientErr
+		}
+	}
+
+	return func(_ context.Context, _ ...option.ClientOption) (secretmanager.Client, error) {
+		return &testClient{
+			data: data,
+		}, nil
+	}
+}
+
+// TestClientData is the data used to configure the test Secret Manager client.
+type TestClientData struct {
+	CreateClientErr error
+	// Topics are the topics the secrets returned by GetSecret publish their
+	// events to.
+	Topics                []string
+	GetSecretErr          error
+	UpdateSecretTopicsErr error
+	CloseErr              error
+}
+
+// testClient is the test Secret Manager client.
+type testClient struct {
+	data TestClientData
+}
+
+// Verify that it satisfies the secretmanager.Client interface.
+var _ secretmanager.Client = &testClient{}
+
+// Close implements client.Close
+func (c *testClient) Close() error {
+	return c.data.CloseErr
+}
+
+// GetSecret implements client.GetSecret
+func (c *testClient) GetSecret(ctx context.Context, name string) (*secretmanager.Secret, error) {
+	if c.data.GetSecretErr != nil {
+		return nil, c.data.GetSecretErr
+	}
+	secret := &secretmanager.Secret{
+		Name: name,
+	}
+	for _, topic := range c.data.Topics {
+		secret.Topics = append(secret.Topics, &secretmanager.Topic{Name: topic})
+	}
+	return secret, nil
+}
+
+// UpdateSecretTopics implements client.UpdateSecretTopics
+func (c *testClient) UpdateSecretTopics(ctx context.Context, secret *secretmanager.Secret) (*secretmanager.Secret, error) {
+	if c.data.UpdateSecretTopicsErr != nil {
+		return nil, c.data.UpdateSecretTopicsErr
+	}
+	return secret, nil
+}
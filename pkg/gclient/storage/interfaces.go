@@ -18,6 +18,7 @@ package storage
 
 import (
 	"context"
+	"io"
 
 	"cloud.google.com/go/storage"
 )
@@ -42,4 +43,17 @@ type Bucket interface {
 	DeleteNotification(ctx context.Context, id string) error
 	// Attrs see https://godoc.org/cloud.google.com/go/storage#BucketHandle.Attrs
 	Attrs(ctx context.Context) (*storage.BucketAttrs, error)
+	// Object see https://godoc.org/cloud.google.com/go/storage#BucketHandle.Object
+	Object(name string) Object
+}
+
+// Object matches the interface exposed by storage.ObjectHandle
+// see https://godoc.org/cloud.google.com/go/storage#ObjectHandle
+type Object interface {
+	// NewReader see https://godoc.org/cloud.google.com/go/storage#ObjectHandle.NewReader
+	NewReader(ctx context.Context) (io.ReadCloser, error)
+	// NewWriter see https://godoc.org/cloud.google.com/go/storage#ObjectHandle.NewWriter
+	NewWriter(ctx context.Context) io.WriteCloser
+	// Delete see https://godoc.org/cloud.google.com/go/storage#ObjectHandle.Delete
+	Delete(ctx context.Context) error
 }
@@ -25,7 +25,9 @@ import (
 
 // testBucket is a test Storage bucket.
 type testBucket struct {
-	data TestBucketData
+	data    TestBucketData
+	name    string
+	objects *Objects
 }
 
 // TestBucketData is the data used to configure the test Bucket.
@@ -37,6 +39,8 @@ type TestBucketData struct {
 	DeleteErr          error
 	Attrs              *BucketAttrs
 	AttrsError         error
+	// ObjectErr is returned when reading, writing or deleting the objects of the bucket.
+	ObjectErr error
 }
 
 // Verify that it satisfies the storage.Bucket interface.
@@ -65,3 +69,8 @@ func (b *testBucket) DeleteNotification(ctx context.Context, id string) error {
 func (b *testBucket) Attrs(ctx context.Context) (*BucketAttrs, error) {
 	return b.data.Attrs, b.data.AttrsError
 }
+
+// Object implements bucket.Object
+func (b *testBucket) Object(name string) storage.Object {
+	return &testObject{bucket: b.name, name: name, objects: b.objects, err: b.data.ObjectErr}
+}
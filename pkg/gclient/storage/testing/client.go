@@ -36,9 +36,14 @@ func TestClientCreator(value interface{}) storage.CreateFn {
 		}
 	}
 
+	objects := data.Objects
+	if objects == nil {
+		objects = NewObjects()
+	}
 	return func(ctx context.Context, opts ...option.ClientOption) (storage.Client, error) {
 		return &testClient{
-			data: data,
+			data:    data,
+			objects: objects,
 		}, nil
 	}
 }
@@ -50,11 +55,14 @@ type TestClientData struct {
 	CreateTopicErr        error
 	CloseErr              error
 	BucketData            TestBucketData
+	// Objects holds the objects of the buckets. If nil, the buckets are empty.
+	Objects *Objects
 }
 
 // testClient is a test Storage client.
 type testClient struct {
-	data TestClientData
+	data    TestClientData
+	objects *Objects
 }
 
 // Verify that it satisfies the storage.Client interface.
@@ -67,5 +75,5 @@ func (c *testClient) Close() error {
 
 // Bucket implements client.Bucket
 func (c *testClient) Bucket(name string) storage.Bucket {
-	return &testBucket{data: c.data.BucketData}
+	return &testBucket{data: c.data.BucketData, name: name, objects: c.objects}
 }
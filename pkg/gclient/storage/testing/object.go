@@ -0,0 +1,122 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	. "cloud.google.com/go/storage"
+	"github.com/google/knative-gcp/pkg/gclient/storage"
+)
+
+// Objects is an in-memory store of the objects of the test buckets.
+type Objects struct {
+	mu sync.Mutex
+	// data holds the content of the objects, keyed by bucket and object name.
+	data map[string]map[string][]byte
+}
+
+// NewObjects creates an empty in-memory store of objects.
+func NewObjects() *Objects {
+	return &Objects{data: make(map[string]map[string][]byte)}
+}
+
+// Get returns the content of an object, if it exists.
+func (o *Objects) Get(bucket, name string) ([]byte, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	content, ok := o.data[bucket][name]
+	return content, ok
+}
+
+// Put sets the content of an object.
+func (o *Objects) Put(bucket, name string, content []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.data[bucket] == nil {
+		o.data[bucket] = make(map[string][]byte)
+	}
+	o.data[bucket][name] = content
+}
+
+// Delete removes an object, and returns false if it doesn't exist.
+func (o *Objects) Delete(bucket, name string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.data[bucket][name]; !ok {
+		return false
+	}
+	delete(o.data[bucket], name)
+	return true
+}
+
+// testObject is a test Storage object.
+type testObject struct {
+	bucket  string
+	name    string
+	objects *Objects
+	err     error
+}
+
+// Verify that it satisfies the storage.Object interface.
+var _ storage.Object = &testObject{}
+
+// NewReader implements object.NewReader
+func (o *testObject) NewReader(ctx context.Context) (io.ReadCloser, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	content, ok := o.objects.Get(o.bucket, o.name)
+	if !ok {
+		return nil, ErrObjectNotExist
+	}
+	return ioutil.NopCloser(bytes.NewReader(content)), nil
+}
+
+// NewWriter implements object.NewWriter. The object is stored when the writer is closed.
+func (o *testObject) NewWriter(ctx context.Context) io.WriteCloser {
+	return &testWriter{object: o}
+}
+
+// Delete implements object.Delete
+func (o *testObject) Delete(ctx context.Context) error {
+	if o.err != nil {
+		return o.err
+	}
+	if !o.objects.Delete(o.bucket, o.name) {
+		return ErrObjectNotExist
+	}
+	return nil
+}
+
+type testWriter struct {
+	bytes.Buffer
+	object *testObject
+}
+
+// Close stores the written content in the object.
+func (w *testWriter) Close() error {
+	if w.object.err != nil {
+		return w.object.err
+	}
+	w.object.objects.Put(w.object.bucket, w.object.name, w.Bytes())
+	return nil
+}
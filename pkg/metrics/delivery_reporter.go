@@ -45,6 +45,8 @@ type DeliveryReporter struct {
 	containerName         ContainerName
 	dispatchTimeInMsecM   *stats.Float64Measure
 	processingTimeInMsecM *stats.Float64Measure
+	breakerStateM         *stats.Int64Measure
+	concurrencyLimitM     *stats.Int64Measure
 }
 
 func (r *DeliveryReporter) register() error {
@@ -95,6 +97,34 @@ func (r *DeliveryReporter) register() error {
 				ContainerNameKey,
 			},
 		},
+		&view.View{
+			Name:        r.breakerStateM.Name(),
+			Description: r.breakerStateM.Description(),
+			Measure:     r.breakerStateM,
+			Aggregation: view.LastValue(),
+			TagKeys: []tag.Key{
+				NamespaceNameKey,
+				BrokerNameKey,
+				TriggerNameKey,
+				TriggerFilterTypeKey,
+				PodNameKey,
+				ContainerNameKey,
+			},
+		},
+		&view.View{
+			Name:        r.concurrencyLimitM.Name(),
+			Description: r.concurrencyLimitM.Description(),
+			Measure:     r.concurrencyLimitM,
+			Aggregation: view.LastValue(),
+			TagKeys: []tag.Key{
+				NamespaceNameKey,
+				BrokerNameKey,
+				TriggerNameKey,
+				TriggerFilterTypeKey,
+				PodNameKey,
+				ContainerNameKey,
+			},
+		},
 	)
 }
 
@@ -117,6 +147,20 @@ func NewDeliveryReporter(podName PodName, containerName ContainerName) (*Deliver
 			"The time spent processing an event before it is dispatched to a Trigger subscriber",
 			stats.UnitMilliseconds,
 		),
+		// breakerStateM records the state of the circuit breaker of a Trigger
+		// subscriber: 0 if closed, 1 if open and 2 if half-open.
+		breakerStateM: stats.Int64(
+			"circuit_breaker_state",
+			"The state of the circuit breaker of a Trigger subscriber: 0 if closed, 1 if open and 2 if half-open",
+			stats.UnitDimensionless,
+		),
+		// concurrencyLimitM records the adaptive limit of the events delivered
+		// concurrently to a Trigger subscriber from its retry queue.
+		concurrencyLimitM: stats.Int64(
+			"retry_concurrency_limit",
+			"The limit of the events delivered concurrently to a Trigger subscriber from its retry queue",
+			stats.UnitDimensionless,
+		),
 	}
 
 	if err := r.register(); err != nil {
@@ -132,6 +176,18 @@ func (r *DeliveryReporter) ReportEventDispatchTime(ctx context.Context, d time.D
 	metrics.Record(ctx, r.dispatchTimeInMsecM.M(float64(d/time.Millisecond)), stats.WithAttachments(attachments))
 }
 
+// ReportCircuitBreakerState captures the state of the circuit breaker of the target
+// in the context. The state is an enum value, 0 being closed.
+func (r *DeliveryReporter) ReportCircuitBreakerState(ctx context.Context, state int) {
+	metrics.Record(ctx, r.breakerStateM.M(int64(state)))
+}
+
+// ReportConcurrencyLimit captures the concurrency limit of the retry handler of the
+// target in the context.
+func (r *DeliveryReporter) ReportConcurrencyLimit(ctx context.Context, limit int) {
+	metrics.Record(ctx, r.concurrencyLimitM.M(int64(limit)))
+}
+
 // StartEventProcessing records the start of event processing for delivery within the given context.
 func StartEventProcessing(ctx context.Context) context.Context {
 	return context.WithValue(ctx, startDeliveryProcessingTime, time.Now())
@@ -128,6 +128,85 @@ func TestReportEventProcessingTime(t *testing.T) {
 	metricstest.CheckDistributionData(t, "event_dispatch_latencies", wantTags, 2, 1100.0, 9100.0)
 }
 
+func TestReportCircuitBreakerState(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+
+	wantTags := map[string]string{
+		metricskey.LabelFilterType: "testeventtype",
+		metricskey.PodName:         "testpod",
+		metricskey.ContainerName:   "testcontainer",
+	}
+
+	r, err := NewDeliveryReporter("testpod", "testcontainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := r.AddTags(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = AddTargetTags(ctx, &config.Target{
+		Namespace: "testns",
+		Broker:    "testbroker",
+		Name:      "testtrigger",
+		FilterAttributes: map[string]string{
+			"type": "testeventtype",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportCircuitBreakerState(ctx, 1)
+		return nil
+	})
+	metricstest.CheckLastValueData(t, "circuit_breaker_state", wantTags, 1)
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportCircuitBreakerState(ctx, 0)
+		return nil
+	})
+	metricstest.CheckLastValueData(t, "circuit_breaker_state", wantTags, 0)
+}
+
+func TestReportConcurrencyLimit(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+
+	wantTags := map[string]string{
+		metricskey.LabelFilterType: "testeventtype",
+		metricskey.PodName:         "testpod",
+		metricskey.ContainerName:   "testcontainer",
+	}
+
+	r, err := NewDeliveryReporter("testpod", "testcontainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := r.AddTags(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = AddTargetTags(ctx, &config.Target{
+		Namespace: "testns",
+		Broker:    "testbroker",
+		Name:      "testtrigger",
+		FilterAttributes: map[string]string{
+			"type": "testeventtype",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportConcurrencyLimit(ctx, 10)
+		return nil
+	})
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportConcurrencyLimit(ctx, 9)
+		return nil
+	})
+	metricstest.CheckLastValueData(t, "retry_concurrency_limit", wantTags, 9)
+}
+
 func TestMetricsWithEmptySourceAndTypeFilter(t *testing.T) {
 	reportertest.ResetDeliveryMetrics()
 
@@ -35,6 +35,9 @@ import (
 type IngressReportArgs struct {
 	EventType    string
 	ResponseCode int
+	// RejectedReason is the reason the ingress rejected the event without trying
+	// to publish it, if any.
+	RejectedReason string
 }
 
 func (r *IngressReporter) register() error {
@@ -42,6 +45,7 @@ func (r *IngressReporter) register() error {
 		EventTypeKey,
 		ResponseCodeKey,
 		ResponseCodeClassKey,
+		RejectedReasonKey,
 		PodNameKey,
 		ContainerNameKey,
 	}
@@ -55,6 +59,17 @@ func (r *IngressReporter) register() error {
 			Aggregation: view.Count(),
 			TagKeys:     tagKeys,
 		},
+		&view.View{
+			Name:        r.deduplicatedEventCountM.Name(),
+			Description: r.deduplicatedEventCountM.Description(),
+			Measure:     r.deduplicatedEventCountM,
+			Aggregation: view.Count(),
+			TagKeys: []tag.Key{
+				EventTypeKey,
+				PodNameKey,
+				ContainerNameKey,
+			},
+		},
 	)
 }
 
@@ -68,6 +83,11 @@ func NewIngressReporter(podName PodName, containerName ContainerName) (*IngressR
 			"Number of events received by a Broker",
 			stats.UnitDimensionless,
 		),
+		deduplicatedEventCountM: stats.Int64(
+			"deduplicated_event_count",
+			"Number of events received by a Broker again within its deduplication window",
+			stats.UnitDimensionless,
+		),
 	}
 	if err := r.register(); err != nil {
 		return nil, fmt.Errorf("failed to register ingress stats: %w", err)
@@ -77,23 +97,43 @@ func NewIngressReporter(podName PodName, containerName ContainerName) (*IngressR
 
 // IngressReporter reports ingress metrics.
 type IngressReporter struct {
-	podName       PodName
-	containerName ContainerName
-	eventCountM   *stats.Int64Measure
+	podName                 PodName
+	containerName           ContainerName
+	eventCountM             *stats.Int64Measure
+	deduplicatedEventCountM *stats.Int64Measure
 }
 
 func (r *IngressReporter) ReportEventCount(ctx context.Context, args IngressReportArgs) error {
 	// Count does not support exemplar currently, but keeping this anyway for future support.
 	attachments := getSpanContextAttachments(ctx)
+	mutators := []tag.Mutator{
+		tag.Insert(PodNameKey, string(r.podName)),
+		tag.Insert(ContainerNameKey, string(r.containerName)),
+		tag.Insert(EventTypeKey, EventTypeMetricValue(args.EventType)),
+		tag.Insert(ResponseCodeKey, strconv.Itoa(args.ResponseCode)),
+		tag.Insert(ResponseCodeClassKey, metrics.ResponseCodeClass(args.ResponseCode)),
+	}
+	if args.RejectedReason != "" {
+		mutators = append(mutators, tag.Insert(RejectedReasonKey, args.RejectedReason))
+	}
 	metrics.Record(
 		ctx, r.eventCountM.M(1),
 		stats.WithAttachments(attachments),
+		stats.WithTags(mutators...),
+	)
+	return nil
+}
+
+// ReportDeduplicatedEventCount records an event that wasn't published because it was
+// received again within the deduplication window of its broker. The response code of
+// the args is ignored.
+func (r *IngressReporter) ReportDeduplicatedEventCount(ctx context.Context, args IngressReportArgs) error {
+	metrics.Record(
+		ctx, r.deduplicatedEventCountM.M(1),
 		stats.WithTags(
 			tag.Insert(PodNameKey, string(r.podName)),
 			tag.Insert(ContainerNameKey, string(r.containerName)),
 			tag.Insert(EventTypeKey, EventTypeMetricValue(args.EventType)),
-			tag.Insert(ResponseCodeKey, strconv.Itoa(args.ResponseCode)),
-			tag.Insert(ResponseCodeClassKey, metrics.ResponseCodeClass(args.ResponseCode)),
 		),
 	)
 	return nil
@@ -86,3 +86,55 @@ func TestStatsReporterWithCustomEventType(t *testing.T) {
 	})
 	metricstest.CheckCountData(t, "event_count", wantTags, 2)
 }
+
+func TestStatsReporterWithRejectedReason(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+
+	args := IngressReportArgs{
+		EventType:      "google.cloud.scheduler.job.v1.executed",
+		ResponseCode:   429,
+		RejectedReason: "namespace_rate_limit",
+	}
+	wantTags := map[string]string{
+		metricskey.LabelEventType:         "google.cloud.scheduler.job.v1.executed",
+		metricskey.LabelResponseCode:      "429",
+		metricskey.LabelResponseCodeClass: "4xx",
+		"rejected_reason":                 "namespace_rate_limit",
+		metricskey.ContainerName:          "testcontainer",
+		metricskey.PodName:                "testpod",
+	}
+
+	r, err := NewIngressReporter(PodName("testpod"), ContainerName("testcontainer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reportertest.ExpectMetrics(t, func() error {
+		return r.ReportEventCount(context.Background(), args)
+	})
+	metricstest.CheckCountData(t, "event_count", wantTags, 1)
+}
+
+func TestStatsReporterDeduplicatedEventCount(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+
+	args := IngressReportArgs{
+		EventType: "google.cloud.scheduler.job.v1.executed",
+	}
+	wantTags := map[string]string{
+		metricskey.LabelEventType: "google.cloud.scheduler.job.v1.executed",
+		metricskey.ContainerName:  "testcontainer",
+		metricskey.PodName:        "testpod",
+	}
+
+	r, err := NewIngressReporter(PodName("testpod"), ContainerName("testcontainer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reportertest.ExpectMetrics(t, func() error {
+		return r.ReportDeduplicatedEventCount(context.Background(), args)
+	})
+	metricstest.CheckCountData(t, "deduplicated_event_count", wantTags, 1)
+	metricstest.CheckStatsNotReported(t, "event_count")
+}
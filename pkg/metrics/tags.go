@@ -24,9 +24,10 @@ import (
 )
 
 const (
-	defaultEventType  = "custom"
-	labelResourceKind = "resource_kind"
-	labelResourceName = "resource_name"
+	defaultEventType    = "custom"
+	labelResourceKind   = "resource_kind"
+	labelResourceName   = "resource_name"
+	labelRejectedReason = "rejected_reason"
 )
 
 type PodName string
@@ -49,6 +50,7 @@ var (
 
 	ResponseCodeKey      = tag.MustNewKey(metricskey.LabelResponseCode)
 	ResponseCodeClassKey = tag.MustNewKey(metricskey.LabelResponseCodeClass)
+	RejectedReasonKey    = tag.MustNewKey(labelRejectedReason)
 
 	PodNameKey       = tag.MustNewKey(metricskey.PodName)
 	ContainerNameKey = tag.MustNewKey(metricskey.ContainerName)
@@ -33,6 +33,7 @@ import (
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
 	"github.com/google/knative-gcp/pkg/tracing"
 	"github.com/google/knative-gcp/pkg/utils/clients"
+	"github.com/google/knative-gcp/pkg/utils/eventfilter"
 	"go.opencensus.io/trace"
 	"k8s.io/apimachinery/pkg/types"
 	kntracing "knative.dev/eventing/pkg/tracing"
@@ -55,6 +56,14 @@ type AdapterArgs struct {
 
 	// ConverterType use to select which converter to use.
 	ConverterType converters.ConverterType
+
+	// EventFilter holds the CloudEvents attributes, or extensions, that the
+	// converted events must have to be sent to the sink.
+	EventFilter map[string]string
+
+	// ConverterArgs holds the arguments of the converter selected by
+	// ConverterType.
+	ConverterArgs map[string]string
 }
 
 // Adapter implements the Pub/Sub adapter to deliver Pub/Sub messages from a
@@ -122,6 +131,7 @@ func (a *Adapter) Start(ctx context.Context) error {
 	ctx = WithProjectKey(ctx, a.projectID)
 	ctx = WithTopicKey(ctx, a.args.TopicID)
 	ctx = WithSubscriptionKey(ctx, a.subscription.ID())
+	ctx = WithConverterArgs(ctx, a.args.ConverterArgs)
 
 	return a.subscription.Receive(ctx, a.receive)
 }
@@ -142,6 +152,12 @@ func (a *Adapter) receive(ctx context.Context, msg *pubsub.Message) {
 		return
 	}
 
+	if !eventfilter.PassFilter(ctx, a.args.EventFilter, event) {
+		a.logger.Debug("Event does not match the event filter, dropping it", zap.String("id", event.ID()))
+		msg.Ack()
+		return
+	}
+
 	ctx, span := a.startSpan(ctx, event)
 	defer span.End()
 
@@ -111,6 +111,8 @@ func TestAdapter(t *testing.T) {
 		original         *event.Event
 		converted        *event.Event
 		reply            *event.Event
+		eventFilter      map[string]string
+		filtered         bool
 		wantMetricLabels []metricLabels
 	}{{
 		name:     "converter fails",
@@ -138,6 +140,22 @@ func TestAdapter(t *testing.T) {
 			CeSource:   replyEvent.Source(),
 			StatusCode: http.StatusOK,
 		}},
+	}, {
+		name:        "successful with matching event filter",
+		original:    sampleEvent,
+		converted:   &convertedEvent,
+		eventFilter: map[string]string{"type": convertedEvent.Type(), "subject": ""},
+		wantMetricLabels: []metricLabels{{
+			CeType:     convertedEvent.Type(),
+			CeSource:   convertedEvent.Source(),
+			StatusCode: http.StatusOK,
+		}},
+	}, {
+		name:        "event filtered out",
+		original:    sampleEvent,
+		converted:   &convertedEvent,
+		eventFilter: map[string]string{"type": "other-type"},
+		filtered:    true,
 	}}
 
 	// TODO add reply failures and other cases
@@ -190,6 +208,7 @@ func TestAdapter(t *testing.T) {
 				SinkURI:       sinkSvr.URL,
 				Extensions:    map[string]string{},
 				ConverterType: converters.ConverterType(testConverterType),
+				EventFilter:   tc.eventFilter,
 			}
 
 			if tc.reply != nil {
@@ -251,6 +270,9 @@ func TestAdapter(t *testing.T) {
 				}
 
 				defer msg.Finish(nil)
+				if tc.filtered {
+					return errors.New("sink received an event that does not match the event filter")
+				}
 				gotEvent, err := binding.ToEvent(rctx, msg)
 				if err != nil {
 					return fmt.Errorf("sink received message that cannot be converted to an event: %v", err)
@@ -0,0 +1,36 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+)
+
+// The key used to store/retrieve the converter arguments in the context.
+type converterArgsKey struct{}
+
+// WithConverterArgs sets the converter arguments in the context.
+func WithConverterArgs(ctx context.Context, args map[string]string) context.Context {
+	return context.WithValue(ctx, converterArgsKey{}, args)
+}
+
+// GetConverterArgs gets the converter arguments from the context. It returns
+// nil if the context has no converter arguments.
+func GetConverterArgs(ctx context.Context) map[string]string {
+	args, _ := ctx.Value(converterArgsKey{}).(map[string]string)
+	return args
+}
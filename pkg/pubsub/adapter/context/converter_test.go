@@ -0,0 +1,36 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConverterArgs(t *testing.T) {
+	if got := GetConverterArgs(context.Background()); got != nil {
+		t.Errorf("converter arguments from empty context got=%v, want=nil", got)
+	}
+
+	want := map[string]string{"key": "value"}
+	ctx := WithConverterArgs(context.Background(), want)
+	if diff := cmp.Diff(want, GetConverterArgs(ctx)); diff != "" {
+		t.Errorf("converter arguments from context (-want,+got): %v", diff)
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+const (
+	billingAccountIDAttribute = "billingAccountId"
+	budgetIDAttribute         = "budgetId"
+)
+
+// billingBudgetNotification is the payload that Cloud Billing publishes to the
+// topic of a budget. Only the fields the converter needs are decoded, the
+// whole payload is kept as the event data.
+type billingBudgetNotification struct {
+	CostAmount             float64  `json:"costAmount"`
+	CurrencyCode           string   `json:"currencyCode"`
+	AlertThresholdExceeded *float64 `json:"alertThresholdExceeded,omitempty"`
+}
+
+func convertCloudBillingBudget(_ context.Context, msg *pubsub.Message) (*cev2.Event, error) {
+	billingAccount, ok := msg.Attributes[billingAccountIDAttribute]
+	if !ok {
+		return nil, fmt.Errorf("received event did not have %s", billingAccountIDAttribute)
+	}
+	budget, ok := msg.Attributes[budgetIDAttribute]
+	if !ok {
+		return nil, fmt.Errorf("received event did not have %s", budgetIDAttribute)
+	}
+	var n billingBudgetNotification
+	if err := json.Unmarshal(msg.Data, &n); err != nil {
+		return nil, fmt.Errorf("failed to decode budget notification: %w", err)
+	}
+
+	event := cev2.NewEvent(cev2.VersionV1)
+	event.SetID(msg.ID)
+	event.SetTime(msg.PublishTime)
+	event.SetType(schemasv1.CloudBillingBudgetCostUpdatedEventType)
+	event.SetSource(schemasv1.CloudBillingBudgetEventSource(billingAccount))
+	event.SetSubject(schemasv1.CloudBillingBudgetEventSubject(budget))
+	// CloudEvents extensions cannot hold floating point numbers, so the
+	// amounts are sent as their shortest decimal representation.
+	event.SetExtension(schemasv1.CloudBillingBudgetCostAmountExtension, strconv.FormatFloat(n.CostAmount, 'f', -1, 64))
+	event.SetExtension(schemasv1.CloudBillingBudgetCurrencyExtension, n.CurrencyCode)
+	// Notifications only include a threshold once one has been exceeded in
+	// the current budget period.
+	if n.AlertThresholdExceeded != nil {
+		event.SetExtension(schemasv1.CloudBillingBudgetThresholdExceededExtension, strconv.FormatFloat(*n.AlertThresholdExceeded, 'f', -1, 64))
+	}
+
+	if err := event.SetData(cev2.ApplicationJSON, msg.Data); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+var billingBudgetPublishTime = time.Date(2020, time.November, 10, 23, 0, 0, 0, time.UTC)
+
+func TestConvertCloudBillingBudget(t *testing.T) {
+	tests := []struct {
+		name                  string
+		attributes            map[string]string
+		data                  string
+		wantErr               bool
+		wantCostAmount        string
+		wantCurrency          string
+		wantThresholdExceeded string
+	}{{
+		name: "threshold exceeded",
+		attributes: map[string]string{
+			"billingAccountId": "012345-6789AB-CDEF01",
+			"budgetId":         "budget",
+			"schemaVersion":    "1.0",
+		},
+		data:                  `{"budgetDisplayName":"dev","alertThresholdExceeded":0.9,"costAmount":140.321,"costIntervalStart":"2020-11-01T07:00:00Z","budgetAmount":152.557,"budgetAmountType":"SPECIFIED_AMOUNT","currencyCode":"USD"}`,
+		wantCostAmount:        "140.321",
+		wantCurrency:          "USD",
+		wantThresholdExceeded: "0.9",
+	}, {
+		name: "no threshold exceeded",
+		attributes: map[string]string{
+			"billingAccountId": "012345-6789AB-CDEF01",
+			"budgetId":         "budget",
+			"schemaVersion":    "1.0",
+		},
+		data:           `{"budgetDisplayName":"dev","costAmount":12,"costIntervalStart":"2020-11-01T07:00:00Z","budgetAmount":152.557,"budgetAmountType":"SPECIFIED_AMOUNT","currencyCode":"EUR"}`,
+		wantCostAmount: "12",
+		wantCurrency:   "EUR",
+	}, {
+		name: "no billing account",
+		attributes: map[string]string{
+			"budgetId": "budget",
+		},
+		data:    `{"costAmount":12,"currencyCode":"EUR"}`,
+		wantErr: true,
+	}, {
+		name: "no budget",
+		attributes: map[string]string{
+			"billingAccountId": "012345-6789AB-CDEF01",
+		},
+		data:    `{"costAmount":12,"currencyCode":"EUR"}`,
+		wantErr: true,
+	}, {
+		name: "invalid payload",
+		attributes: map[string]string{
+			"billingAccountId": "012345-6789AB-CDEF01",
+			"budgetId":         "budget",
+		},
+		data:    "test data",
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msg := &pubsub.Message{
+				ID:          "id",
+				PublishTime: billingBudgetPublishTime,
+				Attributes:  test.attributes,
+				Data:        []byte(test.data),
+			}
+			gotEvent, err := NewPubSubConverter().Convert(context.Background(), msg, CloudBillingBudget)
+			if err != nil {
+				if !test.wantErr {
+					t.Errorf("converters.convertCloudBillingBudget got error %v want error=%v", err, test.wantErr)
+				}
+				return
+			}
+			if test.wantErr {
+				t.Fatalf("converters.convertCloudBillingBudget got event %v want error", gotEvent)
+			}
+			if gotEvent.ID() != "id" {
+				t.Errorf("ID '%s' != '%s'", gotEvent.ID(), "id")
+			}
+			if !gotEvent.Time().Equal(billingBudgetPublishTime) {
+				t.Errorf("Time '%v' != '%v'", gotEvent.Time(), billingBudgetPublishTime)
+			}
+			if want := schemasv1.CloudBillingBudgetEventSource("012345-6789AB-CDEF01"); gotEvent.Source() != want {
+				t.Errorf("Source %q != %q", gotEvent.Source(), want)
+			}
+			if gotEvent.Type() != schemasv1.CloudBillingBudgetCostUpdatedEventType {
+				t.Errorf("Type %q != %q", gotEvent.Type(), schemasv1.CloudBillingBudgetCostUpdatedEventType)
+			}
+			if want := "budgets/budget"; gotEvent.Subject() != want {
+				t.Errorf("Subject %q != %q", gotEvent.Subject(), want)
+			}
+			extensions := gotEvent.Extensions()
+			if got := extensions[schemasv1.CloudBillingBudgetCostAmountExtension]; got != test.wantCostAmount {
+				t.Errorf("CostAmount %q != %q", got, test.wantCostAmount)
+			}
+			if got := extensions[schemasv1.CloudBillingBudgetCurrencyExtension]; got != test.wantCurrency {
+				t.Errorf("Currency %q != %q", got, test.wantCurrency)
+			}
+			if got, _ := extensions[schemasv1.CloudBillingBudgetThresholdExceededExtension].(string); got != test.wantThresholdExceeded {
+				t.Errorf("ThresholdExceeded %q != %q", got, test.wantThresholdExceeded)
+			}
+			if !bytes.Equal(gotEvent.Data(), msg.Data) {
+				t.Errorf("Data %q != %q", gotEvent.Data(), msg.Data)
+			}
+		})
+	}
+}
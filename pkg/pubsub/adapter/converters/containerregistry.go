@@ -0,0 +1,110 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+const (
+	containerRegistryInsertAction = "INSERT"
+	containerRegistryDeleteAction = "DELETE"
+)
+
+// containerRegistryNotification is the payload that Container Registry and
+// Artifact Registry publish to the gcr topic. Digest and Tag are full image
+// references, e.g. gcr.io/my-project/hello-world@sha256:6ec128e26cd5 and
+// gcr.io/my-project/hello-world:latest.
+type containerRegistryNotification struct {
+	Action string `json:"action"`
+	Digest string `json:"digest,omitempty"`
+	Tag    string `json:"tag,omitempty"`
+}
+
+func convertCloudContainerRegistry(ctx context.Context, msg *pubsub.Message) (*cev2.Event, error) {
+	var n containerRegistryNotification
+	if err := json.Unmarshal(msg.Data, &n); err != nil {
+		return nil, fmt.Errorf("failed to decode container registry notification: %w", err)
+	}
+	if n.Digest == "" && n.Tag == "" {
+		return nil, fmt.Errorf("received event did not have digest or tag")
+	}
+
+	event := cev2.NewEvent(cev2.VersionV1)
+	event.SetID(msg.ID)
+	event.SetTime(msg.PublishTime)
+
+	switch n.Action {
+	case containerRegistryInsertAction:
+		event.SetType(schemasv1.CloudContainerRegistryPushedEventType)
+	case containerRegistryDeleteAction:
+		event.SetType(schemasv1.CloudContainerRegistryDeletedEventType)
+	default:
+		return nil, fmt.Errorf("unsupported container registry action: %q", n.Action)
+	}
+
+	project, err := GetProjectKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	event.SetSource(schemasv1.CloudContainerRegistryEventSource(project))
+
+	// The digest identifies the image, the tag is only there when the image
+	// was pushed or deleted by tag.
+	var repository string
+	if n.Digest != "" {
+		event.SetSubject(n.Digest)
+		repository = strings.SplitN(n.Digest, "@", 2)[0]
+	} else {
+		event.SetSubject(n.Tag)
+	}
+	if n.Tag != "" {
+		name, tag := splitImageTag(n.Tag)
+		if repository == "" {
+			repository = name
+		}
+		if tag != "" {
+			event.SetExtension(schemasv1.CloudContainerRegistryTagExtension, tag)
+		}
+	}
+	event.SetExtension(schemasv1.CloudContainerRegistryRepositoryExtension, repository)
+
+	if err := event.SetData(cev2.ApplicationJSON, msg.Data); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// splitImageTag splits an image reference into its name and tag. The tag
+// separator is the last colon after the last slash, as the registry host may
+// include a port.
+func splitImageTag(image string) (string, string) {
+	i := strings.LastIndex(image, ":")
+	if i < 0 || i < strings.LastIndex(image, "/") {
+		return image, ""
+	}
+	return image[:i], image[i+1:]
+}
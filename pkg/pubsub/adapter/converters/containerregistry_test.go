@@ -0,0 +1,135 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+const (
+	imageRepository = "us-docker.pkg.dev/my-project/my-repo/hello-world"
+	imageDigest     = imageRepository + "@sha256:6ec128e26cd5"
+	imageTag        = imageRepository + ":v1.0.2"
+)
+
+var containerRegistryPublishTime = time.Date(2020, time.November, 10, 23, 0, 0, 0, time.UTC)
+
+func TestConvertCloudContainerRegistry(t *testing.T) {
+	tests := []struct {
+		name           string
+		message        *pubsub.Message
+		wantErr        bool
+		wantType       string
+		wantSubject    string
+		wantRepository string
+		wantTag        string
+	}{{
+		name: "pushed with tag",
+		message: &pubsub.Message{
+			Data: []byte(`{"action":"INSERT","digest":"` + imageDigest + `","tag":"` + imageTag + `"}`),
+		},
+		wantType:       schemasv1.CloudContainerRegistryPushedEventType,
+		wantSubject:    imageDigest,
+		wantRepository: imageRepository,
+		wantTag:        "v1.0.2",
+	}, {
+		name: "pushed without tag",
+		message: &pubsub.Message{
+			Data: []byte(`{"action":"INSERT","digest":"` + imageDigest + `"}`),
+		},
+		wantType:       schemasv1.CloudContainerRegistryPushedEventType,
+		wantSubject:    imageDigest,
+		wantRepository: imageRepository,
+	}, {
+		name: "deleted by tag",
+		message: &pubsub.Message{
+			Data: []byte(`{"action":"DELETE","tag":"localhost:5000/hello-world:latest"}`),
+		},
+		wantType:       schemasv1.CloudContainerRegistryDeletedEventType,
+		wantSubject:    "localhost:5000/hello-world:latest",
+		wantRepository: "localhost:5000/hello-world",
+		wantTag:        "latest",
+	}, {
+		name: "unknown action",
+		message: &pubsub.Message{
+			Data: []byte(`{"action":"UPDATE","digest":"` + imageDigest + `"}`),
+		},
+		wantErr: true,
+	}, {
+		name: "no digest nor tag",
+		message: &pubsub.Message{
+			Data: []byte(`{"action":"INSERT"}`),
+		},
+		wantErr: true,
+	}, {
+		name: "invalid payload",
+		message: &pubsub.Message{
+			Data: []byte("test data"),
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.message.ID = "id"
+			test.message.PublishTime = containerRegistryPublishTime
+			ctx := WithProjectKey(context.Background(), "testproject")
+			gotEvent, err := NewPubSubConverter().Convert(ctx, test.message, CloudContainerRegistry)
+			if err != nil {
+				if !test.wantErr {
+					t.Errorf("converters.convertCloudContainerRegistry got error %v want error=%v", err, test.wantErr)
+				}
+				return
+			}
+			if test.wantErr {
+				t.Fatalf("converters.convertCloudContainerRegistry got event %v want error", gotEvent)
+			}
+			if gotEvent.ID() != "id" {
+				t.Errorf("ID '%s' != '%s'", gotEvent.ID(), "id")
+			}
+			if !gotEvent.Time().Equal(containerRegistryPublishTime) {
+				t.Errorf("Time '%v' != '%v'", gotEvent.Time(), containerRegistryPublishTime)
+			}
+			if want := schemasv1.CloudContainerRegistryEventSource("testproject"); gotEvent.Source() != want {
+				t.Errorf("Source %q != %q", gotEvent.Source(), want)
+			}
+			if gotEvent.Type() != test.wantType {
+				t.Errorf("Type %q != %q", gotEvent.Type(), test.wantType)
+			}
+			if gotEvent.Subject() != test.wantSubject {
+				t.Errorf("Subject %q != %q", gotEvent.Subject(), test.wantSubject)
+			}
+			extensions := gotEvent.Extensions()
+			if got := extensions[schemasv1.CloudContainerRegistryRepositoryExtension]; got != test.wantRepository {
+				t.Errorf("Repository %q != %q", got, test.wantRepository)
+			}
+			if got, _ := extensions[schemasv1.CloudContainerRegistryTagExtension].(string); got != test.wantTag {
+				t.Errorf("Tag %q != %q", got, test.wantTag)
+			}
+			if !bytes.Equal(gotEvent.Data(), test.message.Data) {
+				t.Errorf("Data %q != %q", gotEvent.Data(), test.message.Data)
+			}
+		})
+	}
+}
@@ -44,12 +44,17 @@ type ConverterType string
 
 const (
 	// The different type of Converters for the different sources.
-	CloudPubSub    ConverterType = "pubsub"
-	CloudStorage   ConverterType = "storage"
-	CloudAuditLogs ConverterType = "auditlogs"
-	CloudScheduler ConverterType = "scheduler"
-	CloudBuild     ConverterType = "build"
-	PubSubPull     ConverterType = "pubsub_pull"
+	CloudPubSub            ConverterType = "pubsub"
+	CloudStorage           ConverterType = "storage"
+	CloudAuditLogs         ConverterType = "auditlogs"
+	CloudScheduler         ConverterType = "scheduler"
+	CloudBuild             ConverterType = "build"
+	CloudFirestore         ConverterType = "firestore"
+	CloudContainerRegistry ConverterType = "containerregistry"
+	CloudMonitoring        ConverterType = "monitoring"
+	CloudBillingBudget     ConverterType = "billingbudget"
+	CloudSecretManager     ConverterType = "secretmanager"
+	PubSubPull             ConverterType = "pubsub_pull"
 )
 
 type converterFn func(context.Context, *pubsub.Message) (*cev2.Event, error)
@@ -71,12 +76,17 @@ type PubSubConverter struct {
 func NewPubSubConverter() Converter {
 	return &PubSubConverter{
 		converters: map[ConverterType]converterFn{
-			CloudPubSub:    convertCloudPubSub,
-			CloudAuditLogs: convertCloudAuditLogs,
-			CloudStorage:   convertCloudStorage,
-			CloudScheduler: convertCloudScheduler,
-			CloudBuild:     convertCloudBuild,
-			PubSubPull:     convertPubSubPull,
+			CloudPubSub:            convertCloudPubSub,
+			CloudAuditLogs:         convertCloudAuditLogs,
+			CloudStorage:           convertCloudStorage,
+			CloudScheduler:         convertCloudScheduler,
+			CloudBuild:             convertCloudBuild,
+			CloudFirestore:         convertCloudFirestore,
+			CloudContainerRegistry: convertCloudContainerRegistry,
+			CloudMonitoring:        convertCloudMonitoring,
+			CloudBillingBudget:     convertCloudBillingBudget,
+			CloudSecretManager:     convertCloudSecretManager,
+			PubSubPull:             convertPubSubPull,
 		},
 	}
 }
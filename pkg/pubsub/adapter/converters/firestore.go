@@ -0,0 +1,166 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/golang/protobuf/ptypes"
+	auditpb "google.golang.org/genproto/googleapis/cloud/audit"
+	logpb "google.golang.org/genproto/googleapis/logging/v2"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	eventsv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+var firestoreDocumentRegexp = regexp.MustCompile(`^projects/([^/]+)/databases/([^/]+)/documents/(.+)$`)
+
+// convertCloudFirestore converts the Data Access audit log entry of a
+// Firestore write into a document event. The entry is expected to be routed by
+// the Stackdriver sink of a CloudFirestoreSource. A commit with several writes
+// is reported once, for its first write which matches the source, as the sink
+// filter only guarantees that one of them matched it.
+func convertCloudFirestore(ctx context.Context, msg *pubsub.Message) (*cev2.Event, error) {
+	entry := logpb.LogEntry{}
+	if err := jsonpbUnmarshaller.Unmarshal(bytes.NewReader(msg.Data), &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode LogEntry: %w", err)
+	}
+
+	payload, ok := entry.Payload.(*logpb.LogEntry_ProtoPayload)
+	if !ok {
+		return nil, errors.New("non-AuditLog log entry")
+	}
+	var unpacked ptypes.DynamicAny
+	if err := ptypes.UnmarshalAny(payload.ProtoPayload, &unpacked); err != nil {
+		return nil, fmt.Errorf("unrecognized proto payload: %w", err)
+	}
+	auditLog, ok := unpacked.Message.(*auditpb.AuditLog)
+	if !ok {
+		return nil, fmt.Errorf("unhandled proto payload type: %T", unpacked.Message)
+	}
+
+	eventType, match, err := firestoreWrite(auditLog.Request, GetConverterArgs(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	event := cev2.NewEvent(cev2.VersionV1)
+	event.SetID(schemasv1.CloudAuditLogsEventID(entry.InsertId, entry.LogName, ptypes.TimestampString(entry.Timestamp)))
+	if timestamp, err := ptypes.Timestamp(entry.Timestamp); err != nil {
+		return nil, fmt.Errorf("invalid LogEntry timestamp: %w", err)
+	} else {
+		event.SetTime(timestamp)
+	}
+	event.SetType(eventType)
+	event.SetSource(schemasv1.CloudFirestoreEventSource(match[1], match[2]))
+	event.SetSubject(schemasv1.CloudFirestoreEventSubject(match[3]))
+	event.SetDataSchema(schemasv1.CloudAuditLogsEventDataSchema)
+	event.SetData(cev2.ApplicationJSON, msg.Data)
+	return &event, nil
+}
+
+// firestoreWrite returns the event type and the submatches of
+// firestoreDocumentRegexp in the document name of the first write of a Commit
+// or BatchWrite request which matches the database, collection and event types
+// of the converter arguments. Missing arguments match any write.
+func firestoreWrite(request *structpb.Struct, args map[string]string) (string, []string, error) {
+	if request == nil {
+		return "", nil, errors.New("missing request in AuditLog")
+	}
+	writes := request.GetFields()["writes"].GetListValue().GetValues()
+	if len(writes) == 0 {
+		return "", nil, errors.New("no writes in request")
+	}
+	var eventTypes []string
+	if types := args[eventsv1.CloudFirestoreSourceEventTypes]; types != "" {
+		eventTypes = strings.Split(types, ",")
+	}
+	for _, w := range writes {
+		eventType, name := firestoreWriteEvent(w.GetStructValue().GetFields())
+		if eventType == "" || !firestoreMatchEventType(eventTypes, eventType) {
+			continue
+		}
+		match := firestoreDocumentRegexp.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+		if database := args[eventsv1.CloudFirestoreSourceDatabase]; database != "" && database != match[2] {
+			continue
+		}
+		if collection := args[eventsv1.CloudFirestoreSourceCollection]; collection != "" && !firestoreMatchCollection(collection, match[3]) {
+			continue
+		}
+		return eventType, match, nil
+	}
+	return "", nil, errors.New("no write of the request matches the source")
+}
+
+// firestoreWriteEvent returns the event type and the document name of a write,
+// or empty strings if the write is neither an update nor a delete.
+func firestoreWriteEvent(write map[string]*structpb.Value) (string, string) {
+	if name := write["delete"].GetStringValue(); name != "" {
+		return schemasv1.CloudFirestoreDocumentDeletedEventType, name
+	}
+	name := write["update"].GetStructValue().GetFields()["name"].GetStringValue()
+	if name == "" {
+		return "", ""
+	}
+	if exists, ok := write["currentDocument"].GetStructValue().GetFields()["exists"].GetKind().(*structpb.Value_BoolValue); ok && !exists.BoolValue {
+		return schemasv1.CloudFirestoreDocumentCreatedEventType, name
+	}
+	return schemasv1.CloudFirestoreDocumentUpdatedEventType, name
+}
+
+// firestoreMatchEventType returns whether eventType is one of eventTypes, or
+// eventTypes is empty.
+func firestoreMatchEventType(eventTypes []string, eventType string) bool {
+	if len(eventTypes) == 0 {
+		return true
+	}
+	for _, t := range eventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// firestoreMatchCollection returns whether the document, relative to its
+// database, is a document of the collection. A "*" document ID of the
+// collection matches any document ID.
+func firestoreMatchCollection(collection, document string) bool {
+	want := strings.Split(collection, "/")
+	got := strings.Split(document, "/")
+	if len(got) != len(want)+1 {
+		return false
+	}
+	for i, s := range want {
+		if s != "*" && s != got[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	auditpb "google.golang.org/genproto/googleapis/cloud/audit"
+	logpb "google.golang.org/genproto/googleapis/logging/v2"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	eventsv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+const (
+	firestoreLogName  = "projects/test-project/logs/cloudaudit.googleapis.com%2Fdata_access"
+	firestoreDocument = "projects/test-project/databases/(default)/documents/users/alice"
+)
+
+func firestoreMessage(t *testing.T, request map[string]interface{}) *pubsub.Message {
+	t.Helper()
+	req, err := structpb.NewStruct(request)
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	payload, err := ptypes.MarshalAny(&auditpb.AuditLog{
+		ServiceName:  "firestore.googleapis.com",
+		MethodName:   "google.firestore.v1.Firestore.Commit",
+		ResourceName: "projects/test-project/databases/(default)",
+		Request:      req,
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal proto payload: %v", err)
+	}
+	testTime, err := time.Parse(time.RFC3339, testTs)
+	if err != nil {
+		t.Fatalf("Unable to parse test timestamp: %q", err)
+	}
+	ts, err := ptypes.TimestampProto(testTime)
+	if err != nil {
+		t.Fatalf("Invalid test timestamp: %q", err)
+	}
+	var buf bytes.Buffer
+	if err := new(jsonpb.Marshaler).Marshal(&buf, &logpb.LogEntry{
+		InsertId:  insertID,
+		LogName:   firestoreLogName,
+		Timestamp: ts,
+		Payload: &logpb.LogEntry_ProtoPayload{
+			ProtoPayload: payload,
+		},
+	}); err != nil {
+		t.Fatalf("Failed to marshal LogEntry pb: %v", err)
+	}
+	return &pubsub.Message{
+		Data: buf.Bytes(),
+	}
+}
+
+func TestConvertCloudFirestore(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     map[string]interface{}
+		args        map[string]string
+		wantType    string
+		wantSubject string
+		wantErr     bool
+	}{{
+		name: "created",
+		request: map[string]interface{}{
+			"writes": []interface{}{
+				map[string]interface{}{
+					"update":          map[string]interface{}{"name": firestoreDocument},
+					"currentDocument": map[string]interface{}{"exists": false},
+				},
+			},
+		},
+		wantType: schemasv1.CloudFirestoreDocumentCreatedEventType,
+	}, {
+		name: "updated",
+		request: map[string]interface{}{
+			"writes": []interface{}{
+				map[string]interface{}{
+					"update":          map[string]interface{}{"name": firestoreDocument},
+					"currentDocument": map[string]interface{}{"exists": true},
+				},
+			},
+		},
+		wantType: schemasv1.CloudFirestoreDocumentUpdatedEventType,
+	}, {
+		name: "updated without precondition",
+		request: map[string]interface{}{
+			"writes": []interface{}{
+				map[string]interface{}{
+					"update": map[string]interface{}{"name": firestoreDocument},
+				},
+			},
+		},
+		wantType: schemasv1.CloudFirestoreDocumentUpdatedEventType,
+	}, {
+		name: "deleted",
+		request: map[string]interface{}{
+			"writes": []interface{}{
+				map[string]interface{}{"delete": firestoreDocument},
+				map[string]interface{}{
+					"update": map[string]interface{}{"name": firestoreDocument + "/orders/1"},
+				},
+			},
+		},
+		wantType: schemasv1.CloudFirestoreDocumentDeletedEventType,
+	}, {
+		name: "first matching write",
+		request: map[string]interface{}{
+			"writes": []interface{}{
+				map[string]interface{}{"delete": firestoreDocument},
+				map[string]interface{}{
+					"update":          map[string]interface{}{"name": firestoreDocument + "/orders/1"},
+					"currentDocument": map[string]interface{}{"exists": false},
+				},
+				map[string]interface{}{
+					"update": map[string]interface{}{"name": firestoreDocument + "/orders/2"},
+				},
+			},
+		},
+		args: map[string]string{
+			eventsv1.CloudFirestoreSourceDatabase:   "(default)",
+			eventsv1.CloudFirestoreSourceCollection: "users/*/orders",
+		},
+		wantType:    schemasv1.CloudFirestoreDocumentCreatedEventType,
+		wantSubject: "users/alice/orders/1",
+	}, {
+		name: "first write of matching type",
+		request: map[string]interface{}{
+			"writes": []interface{}{
+				map[string]interface{}{"delete": firestoreDocument},
+				map[string]interface{}{
+					"update": map[string]interface{}{"name": "projects/test-project/databases/(default)/documents/users/bob"},
+				},
+			},
+		},
+		args: map[string]string{
+			eventsv1.CloudFirestoreSourceCollection: "users",
+			eventsv1.CloudFirestoreSourceEventTypes: schemasv1.CloudFirestoreDocumentCreatedEventType + "," + schemasv1.CloudFirestoreDocumentUpdatedEventType,
+		},
+		wantType:    schemasv1.CloudFirestoreDocumentUpdatedEventType,
+		wantSubject: "users/bob",
+	}, {
+		name: "no matching collection",
+		request: map[string]interface{}{
+			"writes": []interface{}{
+				map[string]interface{}{"delete": firestoreDocument},
+			},
+		},
+		args: map[string]string{
+			eventsv1.CloudFirestoreSourceCollection: "users/*/orders",
+		},
+		wantErr: true,
+	}, {
+		name: "no matching database",
+		request: map[string]interface{}{
+			"writes": []interface{}{
+				map[string]interface{}{"delete": firestoreDocument},
+			},
+		},
+		args: map[string]string{
+			eventsv1.CloudFirestoreSourceDatabase: "other",
+		},
+		wantErr: true,
+	}, {
+		name:    "no writes",
+		request: map[string]interface{}{},
+		wantErr: true,
+	}, {
+		name: "transform only",
+		request: map[string]interface{}{
+			"writes": []interface{}{
+				map[string]interface{}{"transform": map[string]interface{}{}},
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "invalid document name",
+		request: map[string]interface{}{
+			"writes": []interface{}{
+				map[string]interface{}{"delete": "users/alice"},
+			},
+		},
+		wantErr: true,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := firestoreMessage(t, tc.request)
+			ctx := WithConverterArgs(context.Background(), tc.args)
+			e, err := NewPubSubConverter().Convert(ctx, msg, CloudFirestore)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Convert() = %v, want error", e)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("conversion failed: %v", err)
+			}
+			if id := schemasv1.CloudAuditLogsEventID(insertID, firestoreLogName, testTs); e.ID() != id {
+				t.Errorf("ID %q != %q", e.ID(), id)
+			}
+			if e.Type() != tc.wantType {
+				t.Errorf("Type %q != %q", e.Type(), tc.wantType)
+			}
+			if want := schemasv1.CloudFirestoreEventSource("test-project", "(default)"); e.Source() != want {
+				t.Errorf("Source %q != %q", e.Source(), want)
+			}
+			wantSubject := "users/alice"
+			if tc.wantSubject != "" {
+				wantSubject = tc.wantSubject
+			}
+			if want := schemasv1.CloudFirestoreEventSubject(wantSubject); e.Subject() != want {
+				t.Errorf("Subject %q != %q", e.Subject(), want)
+			}
+			if e.DataSchema() != schemasv1.CloudAuditLogsEventDataSchema {
+				t.Errorf("DataSchema got=%s, want=%s", e.DataSchema(), schemasv1.CloudAuditLogsEventDataSchema)
+			}
+			if !bytes.Equal(e.Data(), msg.Data) {
+				t.Errorf("Data %s != %s", e.Data(), msg.Data)
+			}
+		})
+	}
+}
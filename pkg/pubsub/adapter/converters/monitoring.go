@@ -0,0 +1,87 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+const (
+	monitoringIncidentOpen   = "open"
+	monitoringIncidentClosed = "closed"
+)
+
+// monitoringNotification is the payload that Cloud Monitoring publishes to
+// Pub/Sub notification channels. Only the fields the converter needs are
+// decoded, the whole payload is kept as the event data.
+type monitoringNotification struct {
+	Incident *struct {
+		State      string `json:"state"`
+		PolicyName string `json:"policy_name"`
+		Severity   string `json:"severity,omitempty"`
+	} `json:"incident"`
+}
+
+func convertCloudMonitoring(ctx context.Context, msg *pubsub.Message) (*cev2.Event, error) {
+	var n monitoringNotification
+	if err := json.Unmarshal(msg.Data, &n); err != nil {
+		return nil, fmt.Errorf("failed to decode monitoring notification: %w", err)
+	}
+	if n.Incident == nil {
+		return nil, fmt.Errorf("received event did not have an incident")
+	}
+
+	event := cev2.NewEvent(cev2.VersionV1)
+	event.SetID(msg.ID)
+	event.SetTime(msg.PublishTime)
+
+	state := strings.ToLower(n.Incident.State)
+	switch state {
+	case monitoringIncidentOpen:
+		event.SetType(schemasv1.CloudMonitoringIncidentOpenedEventType)
+	case monitoringIncidentClosed:
+		event.SetType(schemasv1.CloudMonitoringIncidentClosedEventType)
+	default:
+		return nil, fmt.Errorf("unsupported incident state: %q", n.Incident.State)
+	}
+
+	project, err := GetProjectKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	event.SetSource(schemasv1.CloudMonitoringEventSource(project))
+	event.SetSubject(n.Incident.PolicyName)
+	event.SetExtension(schemasv1.CloudMonitoringStateExtension, state)
+	// Only the alert policies that have a severity set include it.
+	if n.Incident.Severity != "" {
+		event.SetExtension(schemasv1.CloudMonitoringSeverityExtension, strings.ToLower(n.Incident.Severity))
+	}
+
+	if err := event.SetData(cev2.ApplicationJSON, msg.Data); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
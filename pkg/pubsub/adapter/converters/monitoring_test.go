@@ -0,0 +1,113 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+var monitoringPublishTime = time.Date(2020, time.November, 10, 23, 0, 0, 0, time.UTC)
+
+func TestConvertCloudMonitoring(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		wantErr      bool
+		wantType     string
+		wantSubject  string
+		wantState    string
+		wantSeverity string
+	}{{
+		name:         "incident opened",
+		data:         `{"incident":{"incident_id":"0.opqiw61fsv7p","state":"open","policy_name":"high-cpu","severity":"Critical"},"version":"1.2"}`,
+		wantType:     schemasv1.CloudMonitoringIncidentOpenedEventType,
+		wantSubject:  "high-cpu",
+		wantState:    "open",
+		wantSeverity: "critical",
+	}, {
+		name:        "incident closed without severity",
+		data:        `{"incident":{"incident_id":"0.opqiw61fsv7p","state":"closed","policy_name":"high-cpu"},"version":"1.2"}`,
+		wantType:    schemasv1.CloudMonitoringIncidentClosedEventType,
+		wantSubject: "high-cpu",
+		wantState:   "closed",
+	}, {
+		name:    "unknown state",
+		data:    `{"incident":{"state":"acknowledged","policy_name":"high-cpu"}}`,
+		wantErr: true,
+	}, {
+		name:    "no incident",
+		data:    `{"version":"1.2"}`,
+		wantErr: true,
+	}, {
+		name:    "invalid payload",
+		data:    "test data",
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msg := &pubsub.Message{
+				ID:          "id",
+				PublishTime: monitoringPublishTime,
+				Data:        []byte(test.data),
+			}
+			ctx := WithProjectKey(context.Background(), "testproject")
+			gotEvent, err := NewPubSubConverter().Convert(ctx, msg, CloudMonitoring)
+			if err != nil {
+				if !test.wantErr {
+					t.Errorf("converters.convertCloudMonitoring got error %v want error=%v", err, test.wantErr)
+				}
+				return
+			}
+			if test.wantErr {
+				t.Fatalf("converters.convertCloudMonitoring got event %v want error", gotEvent)
+			}
+			if gotEvent.ID() != "id" {
+				t.Errorf("ID '%s' != '%s'", gotEvent.ID(), "id")
+			}
+			if !gotEvent.Time().Equal(monitoringPublishTime) {
+				t.Errorf("Time '%v' != '%v'", gotEvent.Time(), monitoringPublishTime)
+			}
+			if want := schemasv1.CloudMonitoringEventSource("testproject"); gotEvent.Source() != want {
+				t.Errorf("Source %q != %q", gotEvent.Source(), want)
+			}
+			if gotEvent.Type() != test.wantType {
+				t.Errorf("Type %q != %q", gotEvent.Type(), test.wantType)
+			}
+			if gotEvent.Subject() != test.wantSubject {
+				t.Errorf("Subject %q != %q", gotEvent.Subject(), test.wantSubject)
+			}
+			extensions := gotEvent.Extensions()
+			if got := extensions[schemasv1.CloudMonitoringStateExtension]; got != test.wantState {
+				t.Errorf("State %q != %q", got, test.wantState)
+			}
+			if got, _ := extensions[schemasv1.CloudMonitoringSeverityExtension].(string); got != test.wantSeverity {
+				t.Errorf("Severity %q != %q", got, test.wantSeverity)
+			}
+			if !bytes.Equal(gotEvent.Data(), msg.Data) {
+				t.Errorf("Data %q != %q", gotEvent.Data(), msg.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+var (
+	// Mapping of Secret Manager eventTypes to CloudEvent types.
+	secretManagerEventTypes = map[string]string{
+		"SECRET_CREATE":          schemasv1.CloudSecretManagerSecretCreatedEventType,
+		"SECRET_UPDATE":          schemasv1.CloudSecretManagerSecretUpdatedEventType,
+		"SECRET_DELETE":          schemasv1.CloudSecretManagerSecretDeletedEventType,
+		"SECRET_ROTATE":          schemasv1.CloudSecretManagerSecretRotatedEventType,
+		"SECRET_VERSION_ADD":     schemasv1.CloudSecretManagerSecretVersionAddedEventType,
+		"SECRET_VERSION_ENABLE":  schemasv1.CloudSecretManagerSecretVersionEnabledEventType,
+		"SECRET_VERSION_DISABLE": schemasv1.CloudSecretManagerSecretVersionDisabledEventType,
+		"SECRET_VERSION_DESTROY": schemasv1.CloudSecretManagerSecretVersionDestroyedEventType,
+	}
+)
+
+func convertCloudSecretManager(_ context.Context, msg *pubsub.Message) (*cev2.Event, error) {
+	event := cev2.NewEvent(cev2.VersionV1)
+	event.SetID(msg.ID)
+	event.SetTime(msg.PublishTime)
+
+	if val, ok := msg.Attributes["secretId"]; ok {
+		event.SetSource(schemasv1.CloudSecretManagerEventSource(val))
+	} else {
+		return nil, errors.New("received event did not have secretId")
+	}
+	// Only the events about a secret version carry its resource name, e.g.
+	// projects/my-project/secrets/my-secret/versions/1.
+	if val, ok := msg.Attributes["versionId"]; ok {
+		event.SetSubject(schemasv1.CloudSecretManagerEventSubject(path.Base(val)))
+	}
+
+	if val, ok := msg.Attributes["eventType"]; ok {
+		if eventType, ok := secretManagerEventTypes[val]; ok {
+			event.SetType(eventType)
+		} else {
+			return nil, fmt.Errorf("unknown event type %s", val)
+		}
+	} else {
+		return nil, errors.New("received event did not have eventType")
+	}
+
+	if err := event.SetData(cev2.ApplicationJSON, msg.Data); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
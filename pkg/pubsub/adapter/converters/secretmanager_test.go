@@ -0,0 +1,133 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+var secretManagerPublishTime = time.Date(2020, time.November, 10, 23, 0, 0, 0, time.UTC)
+
+func TestConvertCloudSecretManager(t *testing.T) {
+	tests := []struct {
+		name        string
+		attributes  map[string]string
+		wantErr     bool
+		wantType    string
+		wantSubject string
+	}{{
+		name: "secret created",
+		attributes: map[string]string{
+			"eventType":  "SECRET_CREATE",
+			"dataFormat": "JSON",
+			"secretId":   "projects/my-project/secrets/my-secret",
+		},
+		wantType: schemasv1.CloudSecretManagerSecretCreatedEventType,
+	}, {
+		name: "secret rotated",
+		attributes: map[string]string{
+			"eventType":  "SECRET_ROTATE",
+			"dataFormat": "JSON",
+			"secretId":   "projects/my-project/secrets/my-secret",
+		},
+		wantType: schemasv1.CloudSecretManagerSecretRotatedEventType,
+	}, {
+		name: "secret version added",
+		attributes: map[string]string{
+			"eventType":  "SECRET_VERSION_ADD",
+			"dataFormat": "JSON",
+			"secretId":   "projects/my-project/secrets/my-secret",
+			"versionId":  "projects/my-project/secrets/my-secret/versions/2",
+		},
+		wantType:    schemasv1.CloudSecretManagerSecretVersionAddedEventType,
+		wantSubject: "versions/2",
+	}, {
+		name: "secret version destroyed",
+		attributes: map[string]string{
+			"eventType":  "SECRET_VERSION_DESTROY",
+			"dataFormat": "JSON",
+			"secretId":   "projects/my-project/secrets/my-secret",
+			"versionId":  "projects/my-project/secrets/my-secret/versions/1",
+		},
+		wantType:    schemasv1.CloudSecretManagerSecretVersionDestroyedEventType,
+		wantSubject: "versions/1",
+	}, {
+		name: "no secretId",
+		attributes: map[string]string{
+			"eventType": "SECRET_CREATE",
+		},
+		wantErr: true,
+	}, {
+		name: "no eventType",
+		attributes: map[string]string{
+			"secretId": "projects/my-project/secrets/my-secret",
+		},
+		wantErr: true,
+	}, {
+		name: "unknown eventType",
+		attributes: map[string]string{
+			"eventType": "SECRET_UNKNOWN",
+			"secretId":  "projects/my-project/secrets/my-secret",
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msg := &pubsub.Message{
+				ID:          "id",
+				PublishTime: secretManagerPublishTime,
+				Attributes:  test.attributes,
+				Data:        []byte(`{"name":"projects/my-project/secrets/my-secret"}`),
+			}
+			gotEvent, err := NewPubSubConverter().Convert(context.Background(), msg, CloudSecretManager)
+			if err != nil {
+				if !test.wantErr {
+					t.Errorf("converters.convertCloudSecretManager got error %v want error=%v", err, test.wantErr)
+				}
+				return
+			}
+			if test.wantErr {
+				t.Fatalf("converters.convertCloudSecretManager got event %v want error", gotEvent)
+			}
+			if gotEvent.ID() != "id" {
+				t.Errorf("ID '%s' != '%s'", gotEvent.ID(), "id")
+			}
+			if !gotEvent.Time().Equal(secretManagerPublishTime) {
+				t.Errorf("Time '%v' != '%v'", gotEvent.Time(), secretManagerPublishTime)
+			}
+			if want := schemasv1.CloudSecretManagerEventSource("projects/my-project/secrets/my-secret"); gotEvent.Source() != want {
+				t.Errorf("Source %q != %q", gotEvent.Source(), want)
+			}
+			if gotEvent.Type() != test.wantType {
+				t.Errorf("Type %q != %q", gotEvent.Type(), test.wantType)
+			}
+			if gotEvent.Subject() != test.wantSubject {
+				t.Errorf("Subject %q != %q", gotEvent.Subject(), test.wantSubject)
+			}
+			if !bytes.Equal(gotEvent.Data(), msg.Data) {
+				t.Errorf("Data %q != %q", gotEvent.Data(), msg.Data)
+			}
+		})
+	}
+}
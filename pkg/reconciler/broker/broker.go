@@ -159,8 +159,9 @@ func (r *Reconciler) reconcileDecouplingTopicAndSubscription(ctx context.Context
 	// Check if PullSub exists, and if not, create it.
 	subID := resources.GenerateDecouplingSubscriptionName(b)
 	subConfig := pubsub.SubscriptionConfig{
-		Topic:  topic,
-		Labels: labels,
+		Topic:                 topic,
+		Labels:                labels,
+		EnableMessageOrdering: b.GetOrderingKeyAttribute() != "",
 		//TODO(grantr): configure these settings?
 		// AckDeadline
 		// RetentionDuration
@@ -60,3 +60,11 @@ func GenerateRetryTopicName(t *brokerv1beta1.Trigger) string {
 func GenerateRetrySubscriptionName(t *brokerv1beta1.Trigger) string {
 	return naming.TruncatedPubsubResourceName("cre-tgr", t.Namespace, t.Name, t.UID)
 }
+
+// GenerateReplaySubscriptionName generates a deterministic name for the
+// subscription of the Broker decoupling topic which retains the events to
+// replay to a Trigger. If the subscription name would be longer than allowed
+// by PubSub, the Trigger name is truncated to fit.
+func GenerateReplaySubscriptionName(t *brokerv1beta1.Trigger) string {
+	return naming.TruncatedPubsubResourceName("cre-rpl", t.Namespace, t.Name, t.UID)
+}
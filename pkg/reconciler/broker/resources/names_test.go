@@ -212,3 +212,37 @@ func trigger(ns, n, uid string) *brokerv1beta1.Trigger {
 		},
 	}
 }
+
+func TestGenerateReplaySubscriptionName(t *testing.T) {
+	testCases := []struct {
+		ns   string
+		n    string
+		uid  string
+		want string
+	}{{
+		ns:   "default",
+		n:    "default",
+		uid:  testUID,
+		want: fmt.Sprintf("cre-rpl_default_default_%s", testUID),
+	}, {
+		ns:   "with-dashes",
+		n:    "more-dashes",
+		uid:  testUID,
+		want: fmt.Sprintf("cre-rpl_with-dashes_more-dashes_%s", testUID),
+	}, {
+		ns:   maxNamespace,
+		n:    maxName,
+		uid:  testUID,
+		want: fmt.Sprintf("cre-rpl_%s_%s_%s", maxNamespace, strings.Repeat("n", truncatedNameMax), testUID),
+	}}
+
+	for _, tc := range testCases {
+		got := GenerateReplaySubscriptionName(trigger(tc.ns, tc.n, tc.uid))
+		if len(got) > naming.PubsubMax {
+			t.Errorf("name length %d is greater than %d", len(got), naming.PubsubMax)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("unexpected (want, +got) = %v", diff)
+		}
+	}
+}
@@ -22,9 +22,13 @@ import (
 
 	"github.com/google/knative-gcp/pkg/logging"
 	"go.uber.org/zap"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
 	"knative.dev/eventing/pkg/apis/eventing"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
@@ -53,6 +57,8 @@ func (r *Reconciler) reconcileConfig(ctx context.Context, bc *intv1alpha1.Broker
 	// however not efficient if there are too many triggers. If performance becomes an issue, we can consider
 	// maintaining 2 queues for updated brokers and triggers, and only update the config for updated brokers/triggers.
 	brokerTargets := memory.NewEmptyTargets()
+	// The values of the secret headers of the triggers, which are kept out of the targets config.
+	headerSecrets := make(map[string][]byte)
 	for _, broker := range brokers {
 		// Filter by `eventing.knative.dev/broker: <name>` here
 		// to get only the triggers for this broker. The trigger webhook will
@@ -63,7 +69,14 @@ func (r *Reconciler) reconcileConfig(ctx context.Context, bc *intv1alpha1.Broker
 			bc.Status.MarkTargetsConfigFailed(configFailed, "failed to list triggers for broker %v: %v", broker.Name, err)
 			return err
 		}
-		r.addToConfig(ctx, broker, triggers, brokerTargets)
+		r.addToConfig(ctx, broker, triggers, brokerTargets, headerSecrets)
+	}
+	// The Secret is updated before the config, so that the data plane finds the values of
+	// the secret headers of the targets once they're in the config.
+	if err := r.updateHeadersSecret(ctx, bc, headerSecrets); err != nil {
+		logging.FromContext(ctx).Error("Failed to update broker headers secret", zap.Error(err))
+		bc.Status.MarkTargetsConfigFailed(configFailed, "failed to update headers secret: %v", err)
+		return err
 	}
 	if err := r.updateTargetsConfig(ctx, bc, brokerTargets); err != nil {
 		logging.FromContext(ctx).Error("Failed to update broker targets configmap", zap.Error(err))
@@ -75,10 +88,13 @@ func (r *Reconciler) reconcileConfig(ctx context.Context, bc *intv1alpha1.Broker
 }
 
 // addToConfig reconstructs the data entry for the given broker and add it to targets-config.
-func (r *Reconciler) addToConfig(ctx context.Context, b *brokerv1beta1.Broker, triggers []*brokerv1beta1.Trigger, brokerTargets config.Targets) {
+// The values of the secret headers of its triggers are added to headerSecrets.
+func (r *Reconciler) addToConfig(ctx context.Context, b *brokerv1beta1.Broker, triggers []*brokerv1beta1.Trigger, brokerTargets config.Targets, headerSecrets map[string][]byte) {
 	// TODO Maybe get rid of BrokerMutation and add Delete() and Upsert(broker) methods to TargetsConfig. Now we always
 	//  delete or update the entire broker entry and we don't need partial updates per trigger.
 	// The code can be simplified to r.targetsConfig.Upsert(brokerConfigEntry)
+	deadLetterAddress := r.resolveDeadLetterAddress(ctx, b)
+	transformerAddress := r.resolveTransformerAddress(ctx, b)
 	brokerTargets.MutateBroker(b.Namespace, b.Name, func(m config.BrokerMutation) {
 		// First delete the broker entry.
 		m.Delete()
@@ -102,10 +118,26 @@ func (r *Reconciler) addToConfig(ctx context.Context, b *brokerv1beta1.Broker, t
 		} else {
 			m.SetState(config.State_UNKNOWN)
 		}
+		m.SetOrderingKeyAttribute(b.GetOrderingKeyAttribute())
+		m.SetDeduplicationWindow(b.GetDeduplicationWindow())
+		// A Broker with invalid allowed senders rejects every sender, rather than allowing any of them.
+		allowedSenders, err := b.GetAllowedSenders()
+		if err != nil {
+			logging.FromContext(ctx).Error("Invalid allowed senders, rejecting all senders", zap.String("broker", b.Name), zap.Error(err))
+		}
+		m.SetAllowedSenders(allowedSenders)
+		m.SetDenyAllSenders(err != nil)
+		m.SetTransformerAddress(transformerAddress)
 
 		// Insert each Trigger to the config.
 		for _, t := range triggers {
 			if t.Spec.Broker == b.Name {
+				// A Trigger with invalid annotations, e.g. invalid filters, is left out of the config so that it
+				// doesn't receive events it hasn't asked for.
+				if err := t.Validate(ctx); err != nil {
+					logging.FromContext(ctx).Error("Invalid trigger", zap.String("trigger", t.Name), zap.Error(err))
+					continue
+				}
 				target := &config.Target{
 					Id:        string(t.UID),
 					Name:      t.Name,
@@ -120,19 +152,167 @@ func (r *Reconciler) addToConfig(ctx context.Context, b *brokerv1beta1.Broker, t
 				if t.Spec.Filter != nil && t.Spec.Filter.Attributes != nil {
 					target.FilterAttributes = t.Spec.Filter.Attributes
 				}
+				// The filters were already validated above.
+				filters, _ := t.GetFilters()
+				target.Filters = resources.MakeTargetFilters(filters)
+				target.CelFilter = t.GetCELFilter()
+				target.OrderingKeyAttribute = t.GetOrderingKeyAttribute()
+				// The delivery annotation was already validated above.
+				triggerDelivery, _ := t.GetDelivery()
+				target.DeliverySpec = resources.MakeTargetDeliverySpec(b.Spec.Delivery, triggerDelivery, deadLetterAddress)
+				target.Replay = resources.MakeTargetReplay(b, t)
+				target.ReplyAddress, target.DropReplies = r.resolveReply(ctx, t)
+				target.ReplyToBroker = r.isBrokerAddress(target.ReplyAddress)
+				target.SubscriberAuth = resources.MakeTargetSubscriberAuth(t)
 				// TODO(#939) May need to use "data plane readiness" for trigger in stead of the
 				//  overall status, see https://github.com/google/knative-gcp/issues/939#issuecomment-644337937
-				if t.Status.IsReady() {
+				if t.Status.IsReady() && t.IsPaused() {
+					target.State = config.State_PAUSED
+				} else if t.Status.IsReady() {
 					target.State = config.State_READY
 				} else {
 					target.State = config.State_UNKNOWN
 				}
+				target.Extensions, _ = t.GetExtensions()
+				if err := r.resolveHeaders(t, target, headerSecrets); err != nil {
+					// The events of the trigger are queued in its retry queue until the headers can be
+					// resolved, rather than delivered without them.
+					logging.FromContext(ctx).Error("Unable to resolve the headers, pausing the trigger", zap.String("trigger", t.Name), zap.Error(err))
+					target.State = config.State_PAUSED
+				}
 				m.UpsertTargets(target)
 			}
 		}
 	})
 }
 
+// resolveDeadLetterAddress resolves the dead letter sink of the broker, which the data plane
+// sends events to once their delivery retries are exhausted. An empty address is returned
+// for a Pub/Sub topic dead letter sink, as it's set as the dead letter policy of the retry
+// subscriptions instead.
+func (r *Reconciler) resolveDeadLetterAddress(ctx context.Context, b *brokerv1beta1.Broker) string {
+	if b.Spec.Delivery == nil || b.Spec.Delivery.DeadLetterSink == nil || brokerv1beta1.IsPubsubDeadLetterSink(b.Spec.Delivery.DeadLetterSink) {
+		return ""
+	}
+	uri, err := r.resolveDestination(ctx, *b.Spec.Delivery.DeadLetterSink, b)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to resolve the dead letter sink", zap.String("broker", b.Name), zap.Error(err))
+		return ""
+	}
+	return uri
+}
+
+// resolveTransformerAddress resolves the transformer of the broker, which the data plane
+// sends events to before fanning them out. An empty address is returned if the broker
+// doesn't have a transformer.
+func (r *Reconciler) resolveTransformerAddress(ctx context.Context, b *brokerv1beta1.Broker) string {
+	// An invalid transformer annotation is rejected by the webhook.
+	transformer, _ := b.GetTransformer()
+	if transformer == nil {
+		return ""
+	}
+	uri, err := r.resolveDestination(ctx, *transformer, b)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to resolve the transformer", zap.String("broker", b.Name), zap.Error(err))
+		return ""
+	}
+	return uri
+}
+
+// resolveReply resolves where the data plane sends the replies of the trigger's subscriber.
+// An empty address means the replies are sent to the trigger's broker. The replies are
+// dropped if the reply destination can't be resolved, rather than sending them to a broker
+// the trigger hasn't asked for.
+func (r *Reconciler) resolveReply(ctx context.Context, t *brokerv1beta1.Trigger) (address string, drop bool) {
+	// The reply annotation was already validated.
+	reply, _ := t.GetReply()
+	if reply == nil {
+		return "", false
+	}
+	if reply.Destination == nil {
+		return "", reply.Drop
+	}
+	uri, err := r.resolveDestination(ctx, *reply.Destination, t)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to resolve the reply destination", zap.String("trigger", t.Name), zap.Error(err))
+		return "", true
+	}
+	return uri, false
+}
+
+// isBrokerAddress returns true if the address is the address of a Google Cloud Broker.
+func (r *Reconciler) isBrokerAddress(address string) bool {
+	if address == "" {
+		return false
+	}
+	brokers, err := r.brokerLister.List(labels.Everything())
+	if err != nil {
+		return false
+	}
+	for _, b := range brokers {
+		if b.GetAnnotations()[eventingv1beta1.BrokerClassAnnotationKey] == brokerv1beta1.BrokerClass && b.Status.Address.URL.String() == address {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveHeaders sets the headers the data plane sets on the requests to the trigger's
+// subscriber on the target. The values of the Secrets the headers reference are added to
+// headerSecrets rather than to the target, and the target only keeps the references.
+func (r *Reconciler) resolveHeaders(t *brokerv1beta1.Trigger, target *config.Target, headerSecrets map[string][]byte) error {
+	// The headers annotation was already validated.
+	headers, _ := t.GetHeaders()
+	for _, h := range headers {
+		if h.SecretKeyRef == nil {
+			if target.Headers == nil {
+				target.Headers = make(map[string]string)
+			}
+			target.Headers[h.Name] = h.Value
+			continue
+		}
+		ref := &config.SecretKeyRef{
+			Name:     h.SecretKeyRef.Name,
+			Key:      h.SecretKeyRef.Key,
+			Optional: h.SecretKeyRef.Optional != nil && *h.SecretKeyRef.Optional,
+		}
+		if target.SecretHeaders == nil {
+			target.SecretHeaders = make(map[string]*config.SecretKeyRef)
+		}
+		target.SecretHeaders[h.Name] = ref
+		secret, err := r.secretLister.Secrets(t.Namespace).Get(ref.Name)
+		if apierrs.IsNotFound(err) && ref.Optional {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get secret %q of header %q, it must have the label %s=true: %w", ref.Name, h.Name, brokerv1beta1.HeadersSecretLabelKey, err)
+		}
+		v, ok := secret.Data[ref.Key]
+		if !ok && ref.Optional {
+			continue
+		}
+		if !ok {
+			return fmt.Errorf("secret %q of header %q has no key %q", ref.Name, h.Name, ref.Key)
+		}
+		headerSecrets[config.SecretKey(t.Namespace, ref)] = v
+	}
+	return nil
+}
+
+func (r *Reconciler) resolveDestination(ctx context.Context, dest duckv1.Destination, parent metav1.Object) (string, error) {
+	if dest.Ref != nil && dest.Ref.Namespace == "" {
+		// To call URIFromDestinationV1, the ref must have a Namespace.
+		ref := *dest.Ref
+		ref.Namespace = parent.GetNamespace()
+		dest.Ref = &ref
+	}
+	uri, err := r.uriResolver.URIFromDestinationV1(ctx, dest, parent)
+	if err != nil {
+		return "", err
+	}
+	return uri.String(), nil
+}
+
 //TODO all this stuff should be in a configmap variant of the config object
 func (r *Reconciler) updateTargetsConfig(ctx context.Context, bc *intv1alpha1.BrokerCell, brokerTargets config.Targets) error {
 	desired, err := resources.MakeTargetsConfig(bc, brokerTargets)
@@ -151,6 +331,21 @@ func (r *Reconciler) updateTargetsConfig(ctx context.Context, bc *intv1alpha1.Br
 	return err
 }
 
+func (r *Reconciler) updateHeadersSecret(ctx context.Context, bc *intv1alpha1.BrokerCell, headerSecrets map[string][]byte) error {
+	desired := resources.MakeHeadersSecret(bc, headerSecrets)
+	// The Secret is only created once a trigger has secret headers, the data plane mounts it as optional.
+	if _, err := r.headersSecretLister.Secrets(desired.Namespace).Get(desired.Name); apierrs.IsNotFound(err) && len(headerSecrets) == 0 {
+		return nil
+	}
+	handlerFuncs := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { r.refreshPodVolume(ctx, bc) },
+		UpdateFunc: func(oldObj, newObj interface{}) { r.refreshPodVolume(ctx, bc) },
+		DeleteFunc: nil,
+	}
+	_, err := r.secretRec.ReconcileSecret(ctx, bc, desired, handlerFuncs)
+	return err
+}
+
 func (r *Reconciler) refreshPodVolume(ctx context.Context, bc *intv1alpha1.BrokerCell) {
 	if err := volume.UpdateVolumeGeneration(ctx, r.KubeClientSet, r.podLister, bc.Namespace, resources.CommonLabels(bc.Name)); err != nil {
 		// Failing to update the annotation on the data plane pods means there
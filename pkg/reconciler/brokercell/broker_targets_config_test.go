@@ -0,0 +1,105 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package brokercell
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+func TestResolveHeaders(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "gateway"},
+		Data:       map[string][]byte{"apiKey": []byte("secret-key")},
+	}
+	tests := []struct {
+		name        string
+		headers     string
+		want        *config.Target
+		wantSecrets map[string][]byte
+		wantErr     bool
+	}{{
+		name:        "no headers",
+		want:        &config.Target{},
+		wantSecrets: map[string][]byte{},
+	}, {
+		name:    "headers",
+		headers: `[{"name":"X-Route","value":"a"},{"name":"X-Api-Key","secretKeyRef":{"name":"gateway","key":"apiKey"}}]`,
+		want: &config.Target{
+			Headers:       map[string]string{"X-Route": "a"},
+			SecretHeaders: map[string]*config.SecretKeyRef{"X-Api-Key": {Name: "gateway", Key: "apiKey"}},
+		},
+		wantSecrets: map[string][]byte{testNS + "_gateway_apiKey": []byte("secret-key")},
+	}, {
+		name:    "missing secret",
+		headers: `[{"name":"X-Api-Key","secretKeyRef":{"name":"other","key":"apiKey"}}]`,
+		wantErr: true,
+	}, {
+		name:    "missing secret key",
+		headers: `[{"name":"X-Api-Key","secretKeyRef":{"name":"gateway","key":"other"}}]`,
+		wantErr: true,
+	}, {
+		name:    "missing optional secret",
+		headers: `[{"name":"X-Route","value":"a"},{"name":"X-Api-Key","secretKeyRef":{"name":"other","key":"apiKey","optional":true}}]`,
+		want: &config.Target{
+			Headers:       map[string]string{"X-Route": "a"},
+			SecretHeaders: map[string]*config.SecretKeyRef{"X-Api-Key": {Name: "other", Key: "apiKey", Optional: true}},
+		},
+		wantSecrets: map[string][]byte{},
+	}, {
+		name:    "missing optional secret key",
+		headers: `[{"name":"X-Api-Key","secretKeyRef":{"name":"gateway","key":"other","optional":true}}]`,
+		want: &config.Target{
+			SecretHeaders: map[string]*config.SecretKeyRef{"X-Api-Key": {Name: "gateway", Key: "other", Optional: true}},
+		},
+		wantSecrets: map[string][]byte{},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var opts []TriggerOption
+			if test.headers != "" {
+				opts = append(opts, WithTriggerHeaders(test.headers))
+			}
+			trig := NewTrigger("trigger", testNS, "broker", opts...)
+			testingListers := NewListers([]runtime.Object{secret})
+			r := &Reconciler{listers: listers{secretLister: testingListers.GetSecretLister()}}
+			got := &config.Target{}
+			gotSecrets := make(map[string][]byte)
+			err := r.resolveHeaders(trig, got, gotSecrets)
+			if (err != nil) != test.wantErr {
+				t.Errorf("resolveHeaders error got=%v, wantErr=%v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("resolveHeaders target (-want,+got): %v", diff)
+			}
+			if diff := cmp.Diff(test.wantSecrets, gotSecrets); diff != "" {
+				t.Errorf("resolveHeaders secrets (-want,+got): %v", diff)
+			}
+		})
+	}
+}
@@ -33,6 +33,7 @@ import (
 	hpav2beta2listers "k8s.io/client-go/listers/autoscaling/v2beta2"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"knative.dev/pkg/network"
+	"knative.dev/pkg/resolver"
 
 	pkgreconciler "knative.dev/pkg/reconciler"
 
@@ -50,6 +51,7 @@ type envConfig struct {
 	RetryImage             string `envconfig:"RETRY_IMAGE" required:"true"`
 	ServiceAccountName     string `envconfig:"SERVICE_ACCOUNT" default:"broker"`
 	IngressPort            int    `envconfig:"INGRESS_PORT" default:"8080"`
+	IngressGRPCPort        int    `envconfig:"INGRESS_GRPC_PORT" default:"8081"`
 	MetricsPort            int    `envconfig:"METRICS_PORT" default:"9090"`
 	InternalMetricsEnabled bool   `envconfig:"INTERNAL_METRICS_ENABLED" default:"false"`
 }
@@ -63,6 +65,11 @@ type listers struct {
 	endpointsLister  corev1listers.EndpointsLister
 	deploymentLister appsv1listers.DeploymentLister
 	podLister        corev1listers.PodLister
+	// secretLister only lists the Secrets labeled to be referenced by the headers of triggers.
+	secretLister corev1listers.SecretLister
+	// headersSecretLister lists the Secrets holding the values of the secret headers of
+	// the triggers in the data plane.
+	headersSecretLister corev1listers.SecretLister
 }
 
 // NewReconciler creates a new BrokerCell reconciler.
@@ -87,6 +94,11 @@ func NewReconciler(base *reconciler.Base, ls listers) (*Reconciler, error) {
 		Lister:     ls.configMapLister,
 		Recorder:   base.Recorder,
 	}
+	secretRec := &reconcilerutils.SecretReconciler{
+		KubeClient: base.KubeClientSet,
+		Lister:     ls.headersSecretLister,
+		Recorder:   base.Recorder,
+	}
 	r := &Reconciler{
 		Base:          base,
 		env:           env,
@@ -94,6 +106,7 @@ func NewReconciler(base *reconciler.Base, ls listers) (*Reconciler, error) {
 		svcRec:        svcRec,
 		deploymentRec: deploymentRec,
 		cmRec:         cmRec,
+		secretRec:     secretRec,
 	}
 	return r, nil
 }
@@ -107,6 +120,10 @@ type Reconciler struct {
 	svcRec        *reconcilerutils.ServiceReconciler
 	deploymentRec *reconcilerutils.DeploymentReconciler
 	cmRec         *reconcilerutils.ConfigMapReconciler
+	secretRec     *reconcilerutils.SecretReconciler
+
+	// uriResolver resolves the dead letter sinks of the brokers.
+	uriResolver *resolver.URIResolver
 
 	env envConfig
 }
@@ -194,6 +211,8 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, bc *intv1alpha1.BrokerCe
 	}
 	bc.Status.PropagateRetryAvailability(rd)
 
+	r.deleteStaleStatusReports(ctx, bc)
+
 	bc.Status.ObservedGeneration = bc.Generation
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "BrokerCellReconciled", "BrokerCell reconciled: \"%s/%s\"", bc.Namespace, bc.Name)
 }
@@ -242,10 +261,14 @@ func (r *Reconciler) makeIngressArgs(bc *intv1alpha1.BrokerCell) resources.Ingre
 			MemoryRequest:      bc.Spec.Components.Ingress.MemoryRequest,
 			MemoryLimit:        bc.Spec.Components.Ingress.MemoryLimit,
 			RolloutRestartTime: bc.GetAnnotations()[resources.IngressRestartTimeAnnotationKey],
+			ClaimCheckBucket:   bc.GetClaimCheckBucket(),
 		},
-		Port: r.env.IngressPort,
+		Port:     r.env.IngressPort,
+		GRPCPort: r.env.IngressGRPCPort,
 		// TODO(#1804): remove this arg when enabling the feature by default.
-		EnableIngressFilter: getIngressFilteringEnabled(bc),
+		EnableIngressFilter:    getIngressFilteringEnabled(bc),
+		Authentication:         bc.GetIngressAuthentication(),
+		AuthenticationAudience: bc.GetIngressAudience(),
 	}
 }
 
@@ -283,6 +306,7 @@ func (r *Reconciler) makeFanoutArgs(bc *intv1alpha1.BrokerCell) resources.Fanout
 			MemoryRequest:      bc.Spec.Components.Fanout.MemoryRequest,
 			MemoryLimit:        bc.Spec.Components.Fanout.MemoryLimit,
 			RolloutRestartTime: bc.GetAnnotations()[resources.FanoutRestartTimeAnnotationKey],
+			ClaimCheckBucket:   bc.GetClaimCheckBucket(),
 		},
 	}
 }
@@ -312,6 +336,7 @@ func (r *Reconciler) makeRetryArgs(bc *intv1alpha1.BrokerCell) resources.RetryAr
 			MemoryRequest:      bc.Spec.Components.Retry.MemoryRequest,
 			MemoryLimit:        bc.Spec.Components.Retry.MemoryLimit,
 			RolloutRestartTime: bc.GetAnnotations()[resources.RetryRestartTimeAnnotationKey],
+			ClaimCheckBucket:   bc.GetClaimCheckBucket(),
 		},
 	}
 }
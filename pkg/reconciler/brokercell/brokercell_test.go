@@ -17,8 +17,10 @@ limitations under the License.
 package brokercell
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -26,15 +28,22 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
 
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/client/injection/ducks/duck/v1/addressable"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	. "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/resolver"
 
 	"github.com/google/go-cmp/cmp"
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	bcreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1alpha1/brokercell"
@@ -62,11 +71,28 @@ var (
 		"events.cloud.google.com/fanoutRestartRequestedAt":  "2020-09-25T16:28:36-04:00",
 		"events.cloud.google.com/retryRestartRequestedAt":   "2020-09-25T16:28:36-04:00",
 	}
+	deadLetterRetry        int32 = 5
+	deadLetterDeliverySpec       = &eventingduckv1beta1.DeliverySpec{
+		DeadLetterSink: &duckv1.Destination{
+			URI: apis.HTTP("dead-letter.testnamespace.svc.cluster.local"),
+		},
+		Retry: &deadLetterRetry,
+	}
+
 	// TODO(1804): remove this variable when feature is enabled by default.
 	enableIngressFilteringAnnotation = map[string]string{
 		"events.cloud.google.com/ingressFilteringEnabled": "true",
 	}
 
+	ingressAuthenticationAnnotations = map[string]string{
+		"events.cloud.google.com/ingressAuthentication": "kubernetes",
+		"events.cloud.google.com/ingressAudience":       "test-audience",
+	}
+
+	claimCheckAnnotation = map[string]string{
+		"events.cloud.google.com/claimCheckBucket": "test-bucket",
+	}
+
 	brokerCellReconciledEvent     = Eventf(corev1.EventTypeNormal, "BrokerCellReconciled", `BrokerCell reconciled: "testnamespace/test-brokercell"`)
 	brokerCellGCEvent             = Eventf(corev1.EventTypeNormal, "BrokerCellGarbageCollected", `BrokerCell garbage collected: "testnamespace/test-brokercell"`)
 	brokerCellGCFailedEvent       = Eventf(corev1.EventTypeWarning, "InternalError", `failed to garbage collect brokercell: inducing failure for delete brokercells`)
@@ -844,6 +870,64 @@ func TestAllCases(t *testing.T) {
 				brokerCellReconciledEvent,
 			},
 		},
+		{
+			Name: "BrokerCell with ingress authentication created successfully",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults,
+					WithBrokerCellAnnotations(ingressAuthenticationAnnotations)),
+				testingdata.EmptyConfig(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)),
+				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
+					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+				testingdata.IngressDeploymentWithAuthenticationAnnotation(t),
+				testingdata.IngressServiceWithStatus(t),
+				testingdata.FanoutDeploymentWithStatus(t),
+				testingdata.RetryDeploymentWithStatus(t),
+				testingdata.IngressHPA(t),
+				testingdata.FanoutHPA(t),
+				testingdata.RetryHPA(t),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellReady,
+					WithBrokerCellAnnotations(ingressAuthenticationAnnotations),
+					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellSetDefaults,
+				)},
+			},
+			WantEvents: []string{
+				brokerCellReconciledEvent,
+			},
+		},
+		{
+			Name: "BrokerCell with claim check bucket created successfully",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults,
+					WithBrokerCellAnnotations(claimCheckAnnotation)),
+				testingdata.EmptyConfig(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)),
+				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
+					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+				testingdata.IngressDeploymentWithClaimCheckAnnotation(t),
+				testingdata.IngressServiceWithStatus(t),
+				testingdata.FanoutDeploymentWithClaimCheckAnnotation(t),
+				testingdata.RetryDeploymentWithClaimCheckAnnotation(t),
+				testingdata.IngressHPA(t),
+				testingdata.FanoutHPA(t),
+				testingdata.RetryHPA(t),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellReady,
+					WithBrokerCellAnnotations(claimCheckAnnotation),
+					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellSetDefaults,
+				)},
+			},
+			WantEvents: []string{
+				brokerCellReconciledEvent,
+			},
+		},
 		{
 			Name: "googlecloud created BrokerCell shouldn't be gc'ed because there are brokers",
 			Key:  testKey,
@@ -961,20 +1045,23 @@ func TestAllCases(t *testing.T) {
 		setReconcilerEnv()
 		base := reconciler.NewBase(ctx, controllerAgentName, cmw)
 		ls := listers{
-			brokerLister:     testingListers.GetBrokerLister(),
-			hpaLister:        testingListers.GetHPALister(),
-			triggerLister:    testingListers.GetTriggerLister(),
-			configMapLister:  testingListers.GetConfigMapLister(),
-			serviceLister:    testingListers.GetK8sServiceLister(),
-			endpointsLister:  testingListers.GetEndpointsLister(),
-			deploymentLister: testingListers.GetDeploymentLister(),
-			podLister:        testingListers.GetPodLister(),
+			brokerLister:        testingListers.GetBrokerLister(),
+			hpaLister:           testingListers.GetHPALister(),
+			triggerLister:       testingListers.GetTriggerLister(),
+			configMapLister:     testingListers.GetConfigMapLister(),
+			serviceLister:       testingListers.GetK8sServiceLister(),
+			endpointsLister:     testingListers.GetEndpointsLister(),
+			deploymentLister:    testingListers.GetDeploymentLister(),
+			podLister:           testingListers.GetPodLister(),
+			secretLister:        testingListers.GetSecretLister(),
+			headersSecretLister: testingListers.GetSecretLister(),
 		}
 
 		r, err := NewReconciler(base, ls)
 		if err != nil {
 			t.Fatalf("Failed to created BrokerCell reconciler: %v", err)
 		}
+		r.uriResolver = resolver.NewURIResolver(addressable.WithDuck(ctx), func(types.NamespacedName) {})
 		return bcreconciler.NewReconciler(ctx, r.Logger, r.RunClientSet, testingListers.GetBrokerCellLister(), r.Recorder, r)
 	}))
 }
@@ -992,9 +1079,26 @@ func TestBrokerTargetsReconcileConfig(t *testing.T) {
 	bc := NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)
 	objects := []runtime.Object{
 		bc,
-		NewBroker("broker", testNS, WithBrokerSetDefaults),
+		NewBroker("broker", testNS, WithBrokerDeliverySpec(deadLetterDeliverySpec), WithBrokerOrderedDelivery("subject"), WithBrokerDeduplicationWindow("PT5M"), WithBrokerAllowedSenders("system:serviceaccount:ns:sa"), WithBrokerTransformer(`{"uri":"http://transformer.testnamespace.svc.cluster.local"}`), WithBrokerAddress("broker-ingress.cloud-run-events.svc.cluster.local"), WithBrokerSetDefaults),
 		NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults),
-		NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults),
+		NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults, WithTriggerFiltersAnnotation(`[{"prefix":{"type":"com.google."}}]`)),
+		// trigger3 has invalid filters, so it's left out of the config.
+		NewTrigger("trigger3", testNS, "broker", WithTriggerSetDefaults, WithTriggerFiltersAnnotation(`[{}]`)),
+		NewTrigger("trigger4", testNS, "broker", WithTriggerSetDefaults, WithTriggerCELFilterAnnotation(`ce.type == "foo"`)),
+		// trigger5 has an invalid CEL filter, so it's left out of the config.
+		NewTrigger("trigger5", testNS, "broker", WithTriggerSetDefaults, WithTriggerCELFilterAnnotation(`ce.type ==`)),
+		NewTrigger("trigger6", testNS, "broker", WithTriggerSetDefaults, WithTriggerDeliveryAnnotation(`{"retry":2,"timeout":"PT10S"}`)),
+		NewTrigger("trigger7", testNS, "broker", WithTriggerSetDefaults, WithTriggerOrderedDelivery("orderid")),
+		NewTrigger("trigger8", testNS, "broker", WithTriggerSetDefaults, WithTriggerPaused, WithTriggerBrokerReady, WithTriggerSubscriptionReady, WithTriggerTopicReady, WithTriggerDependencyReady, WithTriggerSubscriberResolvedSucceeded, WithTriggerStatusSubscriberURI("http://example.com/subscriber/")),
+		NewTrigger("trigger9", testNS, "broker", WithTriggerSetDefaults, WithTriggerReply(`{"destination":{"uri":"http://broker-ingress.cloud-run-events.svc.cluster.local/other/default"}}`)),
+		NewTrigger("trigger10", testNS, "broker", WithTriggerSetDefaults, WithTriggerReply(`{"drop":true}`)),
+		NewTrigger("trigger11", testNS, "broker", WithTriggerSetDefaults, WithTriggerSubscriberAuth(`{"mode":"IDToken"}`), WithTriggerStatusSubscriberURI("https://subscriber-abc.a.run.app/events")),
+		NewTrigger("trigger12", testNS, "broker", WithTriggerSetDefaults, WithTriggerHeaders(`[{"name":"X-Route","value":"a"},{"name":"X-Api-Key","secretKeyRef":{"name":"gateway","key":"apiKey"}}]`), WithTriggerExtensions(`{"knativetrigger":"trigger12"}`)),
+		NewTrigger("trigger13", testNS, "broker", WithTriggerSetDefaults, WithTriggerReply(`{"destination":{"uri":"http://broker-ingress.cloud-run-events.svc.cluster.local"}}`)),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "gateway", Labels: map[string]string{brokerv1beta1.HeadersSecretLabelKey: "true"}},
+			Data:       map[string][]byte{"apiKey": []byte("secret-key")},
+		},
 	}
 	ctx, _ := SetupFakeContext(t)
 	cmw := configmap.NewStaticWatcher()
@@ -1002,25 +1106,37 @@ func TestBrokerTargetsReconcileConfig(t *testing.T) {
 	base := reconciler.NewBase(ctx, controllerAgentName, cmw)
 	testingListers := NewListers(objects)
 	ls := listers{
-		brokerLister:     testingListers.GetBrokerLister(),
-		hpaLister:        testingListers.GetHPALister(),
-		triggerLister:    testingListers.GetTriggerLister(),
-		configMapLister:  testingListers.GetConfigMapLister(),
-		serviceLister:    testingListers.GetK8sServiceLister(),
-		endpointsLister:  testingListers.GetEndpointsLister(),
-		deploymentLister: testingListers.GetDeploymentLister(),
-		podLister:        testingListers.GetPodLister(),
+		brokerLister:        testingListers.GetBrokerLister(),
+		hpaLister:           testingListers.GetHPALister(),
+		triggerLister:       testingListers.GetTriggerLister(),
+		configMapLister:     testingListers.GetConfigMapLister(),
+		serviceLister:       testingListers.GetK8sServiceLister(),
+		endpointsLister:     testingListers.GetEndpointsLister(),
+		deploymentLister:    testingListers.GetDeploymentLister(),
+		podLister:           testingListers.GetPodLister(),
+		secretLister:        testingListers.GetSecretLister(),
+		headersSecretLister: testingListers.GetSecretLister(),
 	}
 	r, err := NewReconciler(base, ls)
 	if err != nil {
 		t.Fatalf("Failed to create BrokerCell reconciler: %v", err)
 	}
+	r.uriResolver = resolver.NewURIResolver(addressable.WithDuck(ctx), func(types.NamespacedName) {})
 	// here we only want to test the functionality of the reconcileConfig that it should create a brokerTargets config successfully
 	r.reconcileConfig(ctx, bc)
 	wantMap := testingdata.Config(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
-		NewBroker("broker", testNS, WithBrokerSetDefaults),
+		NewBroker("broker", testNS, WithBrokerDeliverySpec(deadLetterDeliverySpec), WithBrokerOrderedDelivery("subject"), WithBrokerDeduplicationWindow("PT5M"), WithBrokerAllowedSenders("system:serviceaccount:ns:sa"), WithBrokerTransformer(`{"uri":"http://transformer.testnamespace.svc.cluster.local"}`), WithBrokerAddress("broker-ingress.cloud-run-events.svc.cluster.local"), WithBrokerSetDefaults),
 		NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults),
-		NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults))
+		NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults, WithTriggerFiltersAnnotation(`[{"prefix":{"type":"com.google."}}]`)),
+		NewTrigger("trigger4", testNS, "broker", WithTriggerSetDefaults, WithTriggerCELFilterAnnotation(`ce.type == "foo"`)),
+		NewTrigger("trigger6", testNS, "broker", WithTriggerSetDefaults, WithTriggerDeliveryAnnotation(`{"retry":2,"timeout":"PT10S"}`)),
+		NewTrigger("trigger7", testNS, "broker", WithTriggerSetDefaults, WithTriggerOrderedDelivery("orderid")),
+		NewTrigger("trigger8", testNS, "broker", WithTriggerSetDefaults, WithTriggerPaused, WithTriggerBrokerReady, WithTriggerSubscriptionReady, WithTriggerTopicReady, WithTriggerDependencyReady, WithTriggerSubscriberResolvedSucceeded, WithTriggerStatusSubscriberURI("http://example.com/subscriber/")),
+		NewTrigger("trigger9", testNS, "broker", WithTriggerSetDefaults, WithTriggerReply(`{"destination":{"uri":"http://broker-ingress.cloud-run-events.svc.cluster.local/other/default"}}`)),
+		NewTrigger("trigger10", testNS, "broker", WithTriggerSetDefaults, WithTriggerReply(`{"drop":true}`)),
+		NewTrigger("trigger11", testNS, "broker", WithTriggerSetDefaults, WithTriggerSubscriberAuth(`{"mode":"IDToken"}`), WithTriggerStatusSubscriberURI("https://subscriber-abc.a.run.app/events")),
+		NewTrigger("trigger12", testNS, "broker", WithTriggerSetDefaults, WithTriggerHeaders(`[{"name":"X-Route","value":"a"},{"name":"X-Api-Key","secretKeyRef":{"name":"gateway","key":"apiKey"}}]`), WithTriggerExtensions(`{"knativetrigger":"trigger12"}`)),
+		NewTrigger("trigger13", testNS, "broker", WithTriggerSetDefaults, WithTriggerReply(`{"destination":{"uri":"http://broker-ingress.cloud-run-events.svc.cluster.local"}}`)))
 	gotMap, err := client.CoreV1().ConfigMaps(testNS).Get(context.Background(), resources.Name(bc.Name, targetsCMName), metav1.GetOptions{})
 	if err != nil {
 		t.Fatalf("Failed to get ConfigMap from client: %v", err)
@@ -1042,4 +1158,66 @@ func TestBrokerTargetsReconcileConfig(t *testing.T) {
 	if diff := cmp.Diff(wantBrokerTargets.String(), gotBrokerTargets.String()); diff != "" {
 		t.Fatalf("Unexpected brokerTargets in ConfigMap(-want, +got): %s", diff)
 	}
+	// trigger13 replies to the address of the broker.
+	if !gotBrokerTargets.Brokers[testNS+"/broker"].Targets["trigger13"].GetReplyToBroker() {
+		t.Error("trigger13 doesn't reply to a broker")
+	}
+	// The value of the secret header of trigger12 is only in the headers secret.
+	if strings.Contains(gotMap.Data["targets.txt"], "secret-key") || bytes.Contains(gotMap.BinaryData[targetsCMKey], []byte("secret-key")) {
+		t.Error("The value of the secret header is in the ConfigMap")
+	}
+	gotSecret, err := client.CoreV1().Secrets(testNS).Get(context.Background(), resources.Name(bc.Name, "broker-headers"), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get Secret from client: %v", err)
+	}
+	wantSecret := resources.MakeHeadersSecret(bc, map[string][]byte{testNS + "_gateway_apiKey": []byte("secret-key")})
+	if diff := cmp.Diff(wantSecret, gotSecret); diff != "" {
+		t.Errorf("Unexpected headers Secret (-want, +got): %s", diff)
+	}
+}
+
+// A Broker with an invalid allowed senders annotation, e.g. one which predates the validation, must
+// reject every sender rather than allow any of them.
+func TestBrokerTargetsReconcileConfigInvalidAllowedSenders(t *testing.T) {
+	setReconcilerEnv()
+	bc := NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)
+	objects := []runtime.Object{
+		bc,
+		NewBroker("broker", testNS, WithBrokerAllowedSenders("system:serviceaccount:ns:sa,"), WithBrokerSetDefaults),
+	}
+	ctx, _ := SetupFakeContext(t)
+	ctx, client := fakekubeclient.With(ctx)
+	base := reconciler.NewBase(ctx, controllerAgentName, configmap.NewStaticWatcher())
+	testingListers := NewListers(objects)
+	r, err := NewReconciler(base, listers{
+		brokerLister:        testingListers.GetBrokerLister(),
+		hpaLister:           testingListers.GetHPALister(),
+		triggerLister:       testingListers.GetTriggerLister(),
+		configMapLister:     testingListers.GetConfigMapLister(),
+		serviceLister:       testingListers.GetK8sServiceLister(),
+		endpointsLister:     testingListers.GetEndpointsLister(),
+		deploymentLister:    testingListers.GetDeploymentLister(),
+		podLister:           testingListers.GetPodLister(),
+		secretLister:        testingListers.GetSecretLister(),
+		headersSecretLister: testingListers.GetSecretLister(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create BrokerCell reconciler: %v", err)
+	}
+	r.reconcileConfig(ctx, bc)
+	gotMap, err := client.CoreV1().ConfigMaps(testNS).Get(context.Background(), resources.Name(bc.Name, targetsCMName), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get ConfigMap from client: %v", err)
+	}
+	var gotBrokerTargets config.TargetsConfig
+	if err := proto.Unmarshal(gotMap.BinaryData[targetsCMKey], &gotBrokerTargets); err != nil {
+		t.Fatalf("Failed to deserialize the binary data in ConfigMap: %v", err)
+	}
+	b, ok := gotBrokerTargets.Brokers[testNS+"/broker"]
+	if !ok {
+		t.Fatalf("Broker missing from the config: %v", gotBrokerTargets.String())
+	}
+	if !b.DenyAllSenders || len(b.AllowedSenders) != 0 {
+		t.Errorf("Broker got DenyAllSenders=%v AllowedSenders=%v, want all senders denied", b.DenyAllSenders, b.AllowedSenders)
+	}
 }
@@ -37,8 +37,12 @@ import (
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
 	customresourceutil "github.com/google/knative-gcp/pkg/utils/customresource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	kubeinformers "k8s.io/client-go/informers"
+	corev1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	deploymentinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
 	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap"
 	endpointsinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/endpoints"
@@ -47,6 +51,7 @@ import (
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection"
+	"knative.dev/pkg/resolver"
 	"knative.dev/pkg/system"
 )
 
@@ -72,15 +77,22 @@ func NewController(
 ) *controller.Impl {
 	logger := logging.FromContext(ctx)
 
+	// Only the Secrets referenced by the headers of triggers and the Secrets holding their
+	// values in the data plane are watched, rather than every Secret of the cluster.
+	headerSecretInformer := newSecretInformer(ctx, labels.SelectorFromSet(map[string]string{brokerv1beta1.HeadersSecretLabelKey: "true"}))
+	headersSecretInformer := newSecretInformer(ctx, resources.HeadersSecretSelector())
+
 	ls := listers{
-		brokerLister:     brokerinformer.Get(ctx).Lister(),
-		hpaLister:        hpainformer.Get(ctx).Lister(),
-		triggerLister:    triggerinformer.Get(ctx).Lister(),
-		configMapLister:  configmapinformer.Get(ctx).Lister(),
-		serviceLister:    serviceinformer.Get(ctx).Lister(),
-		endpointsLister:  endpointsinformer.Get(ctx).Lister(),
-		deploymentLister: deploymentinformer.Get(ctx).Lister(),
-		podLister:        podinformer.Get(ctx).Lister(),
+		brokerLister:        brokerinformer.Get(ctx).Lister(),
+		hpaLister:           hpainformer.Get(ctx).Lister(),
+		triggerLister:       triggerinformer.Get(ctx).Lister(),
+		configMapLister:     configmapinformer.Get(ctx).Lister(),
+		serviceLister:       serviceinformer.Get(ctx).Lister(),
+		endpointsLister:     endpointsinformer.Get(ctx).Lister(),
+		deploymentLister:    deploymentinformer.Get(ctx).Lister(),
+		podLister:           podinformer.Get(ctx).Lister(),
+		secretLister:        headerSecretInformer.Lister(),
+		headersSecretLister: headersSecretInformer.Lister(),
 	}
 
 	base := reconciler.NewBase(ctx, controllerAgentName, cmw)
@@ -89,6 +101,10 @@ func NewController(
 		logger.Fatal("Failed to create BrokerCell reconciler", zap.Error(err))
 	}
 	impl := v1alpha1brokercell.NewImpl(ctx, r)
+	r.uriResolver = resolver.NewURIResolver(ctx, func(types.NamespacedName) {
+		// TODO(#866) Select the brokercell that's associated with the broker of the dead letter sink.
+		impl.EnqueueKey(types.NamespacedName{Namespace: system.Namespace(), Name: brokerresources.DefaultBrokerCellName})
+	})
 
 	var latencyReporter *metrics.BrokerCellLatencyReporter
 	if r.env.InternalMetricsEnabled {
@@ -122,6 +138,12 @@ func NewController(
 		},
 	))
 
+	// Watch the secrets referenced by the headers of triggers to update their headers.
+	headerSecretInformer.Informer().AddEventHandler(controller.HandleAll(func(interface{}) {
+		// TODO(#866) Select the brokercell that's associated with the triggers of the secret.
+		impl.EnqueueKey(types.NamespacedName{Namespace: system.Namespace(), Name: brokerresources.DefaultBrokerCellName})
+	}))
+
 	// Watch data plane components created by brokercell so we can update brokercell status immediately.
 	// 1. Watch deployments for ingress, fanout and retry
 	deploymentinformer.Get(ctx).Informer().AddEventHandler(handleResourceUpdate(impl))
@@ -131,6 +153,8 @@ func NewController(
 	hpainformer.Get(ctx).Informer().AddEventHandler(handleResourceUpdate(impl))
 	// 4. Watch the broker targets configmap.
 	configmapinformer.Get(ctx).Informer().AddEventHandler(handleResourceUpdate(impl))
+	// 5. Watch the broker headers secret.
+	headersSecretInformer.Informer().AddEventHandler(handleResourceUpdate(impl))
 
 	return impl
 }
@@ -159,3 +183,18 @@ func reportLatency(ctx context.Context, resourceObj metav1.ObjectMetaAccessor, l
 		logging.FromContext(ctx).Error("Failed to retrieve the resource update time", zap.Error(err))
 	}
 }
+
+// newSecretInformer starts an informer of the Secrets matching the selector and waits for
+// its cache to sync, as the injected Secret informer caches every Secret of the cluster.
+func newSecretInformer(ctx context.Context, selector labels.Selector) corev1informers.SecretInformer {
+	factory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeclient.Get(ctx), controller.GetResyncPeriod(ctx),
+		kubeinformers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = selector.String()
+		}))
+	informer := factory.Core().V1().Secrets()
+	// Register the informer before starting the factory.
+	informer.Informer()
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+	return informer
+}
@@ -60,14 +60,23 @@ type Args struct {
 	MemoryRequest      string
 	MemoryLimit        string
 	RolloutRestartTime string
+	// ClaimCheckBucket is the Cloud Storage bucket the data of the large events is
+	// offloaded to, if any.
+	ClaimCheckBucket string
 }
 
 // IngressArgs are the arguments to create a Broker's ingress Deployment.
 type IngressArgs struct {
 	Args
 	Port int
+	// GRPCPort is the port of the gRPC ingress.
+	GRPCPort int
 	// TODO(#1804): remove this field when enabling the feature by default.
 	EnableIngressFilter bool
+	// Authentication is the kind of bearer tokens required by the ingress, if any.
+	Authentication string
+	// AuthenticationAudience is the audience of the bearer tokens verified by the ingress.
+	AuthenticationAudience string
 }
 
 // FanoutArgs are the arguments to create a Broker's fanout Deployment.
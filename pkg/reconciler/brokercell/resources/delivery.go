@@ -0,0 +1,46 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"google.golang.org/protobuf/types/known/durationpb"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// MakeTargetDeliverySpec converts the Broker delivery spec, with the Trigger delivery
+// settings applied, to the targets config representation. deadLetterAddress is the
+// resolved dead letter sink of the Broker, if any.
+func MakeTargetDeliverySpec(spec *eventingduckv1beta1.DeliverySpec, triggerSpec *brokerv1beta1.TriggerDeliverySpec, deadLetterAddress string) *config.DeliverySpec {
+	spec = triggerSpec.ApplyTo(spec)
+	out := &config.DeliverySpec{
+		DeadLetterAddress: deadLetterAddress,
+	}
+	// The retries before sending an event to a Pub/Sub topic dead letter sink are
+	// counted by the dead letter policy of the retry subscription instead.
+	if spec.Retry != nil && !brokerv1beta1.IsPubsubDeadLetterSink(spec.DeadLetterSink) {
+		out.Retry = *spec.Retry
+	}
+	// The backoff between retries is applied by the retry policy of the retry
+	// subscription, see the trigger reconciler.
+	if timeout, ok := triggerSpec.GetTimeout(); ok {
+		out.Timeout = durationpb.New(timeout)
+	}
+	return out
+}
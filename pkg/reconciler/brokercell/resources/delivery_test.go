@@ -0,0 +1,93 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func TestMakeTargetDeliverySpec(t *testing.T) {
+	exponential := eventingduckv1beta1.BackoffPolicyExponential
+	linear := eventingduckv1beta1.BackoffPolicyLinear
+	brokerSpec := &eventingduckv1beta1.DeliverySpec{
+		DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dead-letter.ns.svc.cluster.local")},
+		Retry:          ptr.Int32(5),
+		BackoffPolicy:  &exponential,
+		BackoffDelay:   ptr.String("PT1S"),
+	}
+
+	tests := []struct {
+		name              string
+		spec              *eventingduckv1beta1.DeliverySpec
+		triggerSpec       *brokerv1beta1.TriggerDeliverySpec
+		deadLetterAddress string
+		want              *config.DeliverySpec
+	}{{
+		name: "no delivery spec",
+		want: &config.DeliverySpec{},
+	}, {
+		name:              "broker delivery spec",
+		spec:              brokerSpec,
+		deadLetterAddress: "http://dead-letter.ns.svc.cluster.local",
+		want: &config.DeliverySpec{
+			DeadLetterAddress: "http://dead-letter.ns.svc.cluster.local",
+			Retry:             5,
+		},
+	}, {
+		name: "trigger delivery settings",
+		spec: brokerSpec,
+		triggerSpec: &brokerv1beta1.TriggerDeliverySpec{
+			Retry:         ptr.Int32(2),
+			BackoffPolicy: &linear,
+			BackoffDelay:  ptr.String("PT5S"),
+			Timeout:       ptr.String("PT30S"),
+		},
+		deadLetterAddress: "http://dead-letter.ns.svc.cluster.local",
+		want: &config.DeliverySpec{
+			DeadLetterAddress: "http://dead-letter.ns.svc.cluster.local",
+			Retry:             2,
+			Timeout:           durationpb.New(30 * time.Second),
+		},
+	}, {
+		name: "pubsub dead letter sink",
+		spec: &eventingduckv1beta1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "pubsub", Host: "dead-letter-topic"}},
+			Retry:          ptr.Int32(5),
+		},
+		want: &config.DeliverySpec{},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := MakeTargetDeliverySpec(test.spec, test.triggerSpec, test.deadLetterAddress)
+			if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("MakeTargetDeliverySpec (-want,+got): %v", diff)
+			}
+		})
+	}
+}
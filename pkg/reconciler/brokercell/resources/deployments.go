@@ -34,7 +34,10 @@ import (
 func MakeIngressDeployment(args IngressArgs) *appsv1.Deployment {
 	container := containerTemplate(args.Args)
 	// Decorate the container template with ingress port.
-	container.Env = append(container.Env, corev1.EnvVar{Name: "PORT", Value: strconv.Itoa(args.Port)})
+	container.Env = append(container.Env,
+		corev1.EnvVar{Name: "PORT", Value: strconv.Itoa(args.Port)},
+		corev1.EnvVar{Name: "GRPC_PORT", Value: strconv.Itoa(args.GRPCPort)},
+	)
 
 	// TODO(#1804): remove this env variable when enabling the feature by default.
 	// Enable ingress filtering if necessary.
@@ -43,7 +46,18 @@ func MakeIngressDeployment(args IngressArgs) *appsv1.Deployment {
 		Value: strconv.FormatBool(args.EnableIngressFilter),
 	})
 
-	container.Ports = append(container.Ports, corev1.ContainerPort{Name: "http", ContainerPort: int32(args.Port)})
+	// Configure the verification of the senders' bearer tokens.
+	if args.Authentication != "" {
+		container.Env = append(container.Env, corev1.EnvVar{Name: "AUTHENTICATION_MODE", Value: args.Authentication})
+	}
+	if args.AuthenticationAudience != "" {
+		container.Env = append(container.Env, corev1.EnvVar{Name: "AUTHENTICATION_AUDIENCE", Value: args.AuthenticationAudience})
+	}
+
+	container.Ports = append(container.Ports,
+		corev1.ContainerPort{Name: "http", ContainerPort: int32(args.Port)},
+		corev1.ContainerPort{Name: "grpc", ContainerPort: int32(args.GRPCPort)},
+	)
 	container.ReadinessProbe = &corev1.Probe{
 		Handler: corev1.Handler{
 			HTTPGet: &corev1.HTTPGetAction{
@@ -103,7 +117,7 @@ func MakeFanoutDeployment(args FanoutArgs) *appsv1.Deployment {
 		SuccessThreshold:    1,
 		TimeoutSeconds:      5,
 	}
-	return deploymentTemplate(args.Args, []corev1.Container{container})
+	return withHeadersSecret(args.Args, deploymentTemplate(args.Args, []corev1.Container{container}))
 }
 
 // MakeRetryDeployment creates the retry Deployment object.
@@ -130,7 +144,27 @@ func MakeRetryDeployment(args RetryArgs) *appsv1.Deployment {
 		SuccessThreshold:    1,
 		TimeoutSeconds:      5,
 	}
-	return deploymentTemplate(args.Args, []corev1.Container{container})
+	return withHeadersSecret(args.Args, deploymentTemplate(args.Args, []corev1.Container{container}))
+}
+
+// withHeadersSecret mounts the Secret holding the values of the secret headers of the
+// triggers in the containers of the deployment, which deliver the events to the triggers.
+func withHeadersSecret(args Args, d *appsv1.Deployment) *appsv1.Deployment {
+	// The Secret is optional so that the pods start before the brokercell creates it.
+	optional := true
+	spec := &d.Spec.Template.Spec
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name:         headersSecretName,
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: Name(args.BrokerCell.Name, headersSecretName), Optional: &optional}},
+	})
+	for i := range spec.Containers {
+		spec.Containers[i].VolumeMounts = append(spec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      headersSecretName,
+			MountPath: headersSecretMountPath,
+			ReadOnly:  true,
+		})
+	}
+	return d
 }
 
 // deploymentTemplate creates a template for data plane deployments.
@@ -184,7 +218,7 @@ func deploymentTemplate(args Args, containers []corev1.Container) *appsv1.Deploy
 
 // containerTemplate returns a common template for broker data plane containers.
 func containerTemplate(args Args) corev1.Container {
-	return corev1.Container{
+	container := corev1.Container{
 		Image: args.Image,
 		Name:  args.ComponentName,
 		Env: []corev1.EnvVar{
@@ -204,6 +238,14 @@ func containerTemplate(args Args) corev1.Container {
 					},
 				},
 			},
+			{
+				Name: "POD_UID",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "metadata.uid",
+					},
+				},
+			},
 			{
 				Name:  "CONFIG_LOGGING_NAME",
 				Value: "config-logging",
@@ -235,4 +277,8 @@ func containerTemplate(args Args) corev1.Container {
 			},
 		},
 	}
+	if args.ClaimCheckBucket != "" {
+		container.Env = append(container.Env, corev1.EnvVar{Name: "CLAIM_CHECK_BUCKET", Value: args.ClaimCheckBucket})
+	}
+	return container
 }
@@ -0,0 +1,49 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// MakeTargetFilters converts the Trigger filter expressions to the targets config
+// representation.
+func MakeTargetFilters(filters []brokerv1beta1.SubscriptionsAPIFilter) []*config.Filter {
+	if len(filters) == 0 {
+		return nil
+	}
+	out := make([]*config.Filter, 0, len(filters))
+	for i := range filters {
+		out = append(out, makeTargetFilter(&filters[i]))
+	}
+	return out
+}
+
+func makeTargetFilter(f *brokerv1beta1.SubscriptionsAPIFilter) *config.Filter {
+	out := &config.Filter{
+		Exact:  f.Exact,
+		Prefix: f.Prefix,
+		Suffix: f.Suffix,
+		All:    MakeTargetFilters(f.All),
+		Any:    MakeTargetFilters(f.Any),
+	}
+	if f.Not != nil {
+		out.Not = makeTargetFilter(f.Not)
+	}
+	return out
+}
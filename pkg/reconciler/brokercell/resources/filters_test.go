@@ -0,0 +1,77 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func TestMakeTargetFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters []brokerv1beta1.SubscriptionsAPIFilter
+		want    []*config.Filter
+	}{{
+		name: "no filters",
+	}, {
+		name: "nested filters",
+		filters: []brokerv1beta1.SubscriptionsAPIFilter{{
+			Prefix: map[string]string{"type": "com.google.cloud.storage."},
+		}, {
+			Any: []brokerv1beta1.SubscriptionsAPIFilter{{
+				Suffix: map[string]string{"subject": ".jpg"},
+			}, {
+				All: []brokerv1beta1.SubscriptionsAPIFilter{{
+					Exact: map[string]string{"source": "a"},
+				}, {
+					Not: &brokerv1beta1.SubscriptionsAPIFilter{
+						Exact: map[string]string{"subject": "b"},
+					},
+				}},
+			}},
+		}},
+		want: []*config.Filter{{
+			Prefix: map[string]string{"type": "com.google.cloud.storage."},
+		}, {
+			Any: []*config.Filter{{
+				Suffix: map[string]string{"subject": ".jpg"},
+			}, {
+				All: []*config.Filter{{
+					Exact: map[string]string{"source": "a"},
+				}, {
+					Not: &config.Filter{
+						Exact: map[string]string{"subject": "b"},
+					},
+				}},
+			}},
+		}},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := MakeTargetFilters(test.filters)
+			if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected filters (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+)
+
+// MakeTargetReplay converts the replay settings and the replay window recorded in the
+// Trigger status to the targets config representation. It returns nil if the Trigger
+// doesn't replay events.
+func MakeTargetReplay(b *brokerv1beta1.Broker, t *brokerv1beta1.Trigger) *config.Replay {
+	if t.GetReplayRetention() == 0 {
+		return nil
+	}
+	out := &config.Replay{
+		Queue: &config.Queue{
+			Topic:        brokerresources.GenerateDecouplingTopicName(b),
+			Subscription: brokerresources.GenerateReplaySubscriptionName(t),
+			State:        config.State_UNKNOWN,
+		},
+	}
+	if t.Status.IsReplayReady() {
+		out.Queue.State = config.State_READY
+	}
+	if from, until, ok := t.Status.GetReplayWindow(); ok {
+		out.From = timestamppb.New(from)
+		out.Until = timestamppb.New(until)
+	}
+	return out
+}
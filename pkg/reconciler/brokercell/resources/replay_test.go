@@ -0,0 +1,100 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func TestMakeTargetReplay(t *testing.T) {
+	b := &brokerv1beta1.Broker{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "broker", UID: "broker-uid"}}
+	from := time.Date(2020, 10, 1, 8, 0, 0, 0, time.UTC)
+	until := time.Date(2020, 10, 1, 9, 0, 0, 0, time.UTC)
+	newTrigger := func(retention string, mutate func(*brokerv1beta1.TriggerStatus)) *brokerv1beta1.Trigger {
+		trig := &brokerv1beta1.Trigger{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "trigger", UID: "trigger-uid"}}
+		if retention != "" {
+			trig.Annotations = map[string]string{brokerv1beta1.ReplayRetentionAnnotation: retention}
+		}
+		if mutate != nil {
+			mutate(&trig.Status)
+		}
+		return trig
+	}
+
+	tests := []struct {
+		name    string
+		trigger *brokerv1beta1.Trigger
+		want    *config.Replay
+	}{{
+		name:    "no replay",
+		trigger: newTrigger("", nil),
+	}, {
+		name:    "replay subscription not ready",
+		trigger: newTrigger("PT1H", nil),
+		want: &config.Replay{
+			Queue: &config.Queue{
+				Topic:        "cre-bkr_ns_broker_broker-uid",
+				Subscription: "cre-rpl_ns_trigger_trigger-uid",
+				State:        config.State_UNKNOWN,
+			},
+		},
+	}, {
+		name: "replay available",
+		trigger: newTrigger("PT1H", func(s *brokerv1beta1.TriggerStatus) {
+			s.MarkReplayAvailable()
+		}),
+		want: &config.Replay{
+			Queue: &config.Queue{
+				Topic:        "cre-bkr_ns_broker_broker-uid",
+				Subscription: "cre-rpl_ns_trigger_trigger-uid",
+				State:        config.State_READY,
+			},
+		},
+	}, {
+		name: "replay started",
+		trigger: newTrigger("PT1H", func(s *brokerv1beta1.TriggerStatus) {
+			s.SetReplayWindow(from, until)
+			s.MarkReplayStarted(from, until)
+		}),
+		want: &config.Replay{
+			Queue: &config.Queue{
+				Topic:        "cre-bkr_ns_broker_broker-uid",
+				Subscription: "cre-rpl_ns_trigger_trigger-uid",
+				State:        config.State_READY,
+			},
+			From:  timestamppb.New(from),
+			Until: timestamppb.New(until),
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MakeTargetReplay(b, tt.trigger)
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("MakeTargetReplay() (-want,+got): %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"knative.dev/pkg/kmeta"
+
+	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+)
+
+const (
+	headersSecretName      = "broker-headers"
+	headersSecretMountPath = "/var/run/cloud-run-events/broker-headers"
+)
+
+// MakeHeadersSecret creates the Secret holding the values of the secret headers of the
+// triggers, keyed by config.SecretKey. It's mounted in the fanout and retry pods, so that
+// the values aren't part of the targets config.
+func MakeHeadersSecret(bc *intv1alpha1.BrokerCell, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            Name(bc.Name, headersSecretName),
+			Namespace:       bc.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(bc)},
+			Labels:          Labels(bc.Name, headersSecretName),
+		},
+		Data: data,
+	}
+}
+
+// HeadersSecretSelector selects the Secrets created by MakeHeadersSecret.
+func HeadersSecretSelector() labels.Selector {
+	return labels.SelectorFromSet(map[string]string{"app": "cloud-run-events", "role": headersSecretName})
+}
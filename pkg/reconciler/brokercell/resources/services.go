@@ -42,6 +42,10 @@ func MakeIngressService(args IngressArgs) *corev1.Service {
 					Port:       80,
 					TargetPort: intstr.FromInt(args.Port),
 				},
+				{
+					Name: "grpc",
+					Port: int32(args.GRPCPort),
+				},
 				{
 					Name: "http-metrics",
 					Port: int32(args.MetricsPort),
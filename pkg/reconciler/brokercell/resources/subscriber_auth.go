@@ -0,0 +1,48 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// MakeTargetSubscriberAuth converts the subscriber auth of the Trigger to the targets
+// config representation. The audience of ID tokens defaults to the origin of the resolved
+// subscriber URI. It returns nil if the requests to the subscriber aren't authenticated.
+func MakeTargetSubscriberAuth(t *brokerv1beta1.Trigger) *config.SubscriberAuth {
+	// The subscriber auth annotation is validated by the webhook.
+	spec, _ := t.GetSubscriberAuth()
+	if spec == nil {
+		return nil
+	}
+	switch spec.Mode {
+	case brokerv1beta1.SubscriberAuthIDToken:
+		audience := spec.Audience
+		if audience == "" && t.Status.SubscriberURI != nil {
+			audience = t.Status.SubscriberURI.Scheme + "://" + t.Status.SubscriberURI.Host
+		}
+		return &config.SubscriberAuth{
+			Mode:     config.SubscriberAuth_ID_TOKEN,
+			Audience: audience,
+		}
+	case brokerv1beta1.SubscriberAuthAccessToken:
+		return &config.SubscriberAuth{Mode: config.SubscriberAuth_ACCESS_TOKEN}
+	default:
+		return nil
+	}
+}
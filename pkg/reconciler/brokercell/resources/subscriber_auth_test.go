@@ -0,0 +1,76 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func TestMakeTargetSubscriberAuth(t *testing.T) {
+	newTrigger := func(auth string) *brokerv1beta1.Trigger {
+		trig := &brokerv1beta1.Trigger{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "trigger"}}
+		if auth != "" {
+			trig.Annotations = map[string]string{brokerv1beta1.SubscriberAuthAnnotation: auth}
+		}
+		trig.Status.SubscriberURI = apis.HTTPS("subscriber-abc.a.run.app")
+		trig.Status.SubscriberURI.Path = "/events"
+		return trig
+	}
+
+	tests := []struct {
+		name    string
+		trigger *brokerv1beta1.Trigger
+		want    *config.SubscriberAuth
+	}{{
+		name:    "no subscriber auth",
+		trigger: newTrigger(""),
+	}, {
+		name:    "ID token with default audience",
+		trigger: newTrigger(`{"mode":"IDToken"}`),
+		want: &config.SubscriberAuth{
+			Mode:     config.SubscriberAuth_ID_TOKEN,
+			Audience: "https://subscriber-abc.a.run.app",
+		},
+	}, {
+		name:    "ID token with audience",
+		trigger: newTrigger(`{"mode":"IDToken","audience":"123.apps.googleusercontent.com"}`),
+		want: &config.SubscriberAuth{
+			Mode:     config.SubscriberAuth_ID_TOKEN,
+			Audience: "123.apps.googleusercontent.com",
+		},
+	}, {
+		name:    "access token",
+		trigger: newTrigger(`{"mode":"AccessToken"}`),
+		want:    &config.SubscriberAuth{Mode: config.SubscriberAuth_ACCESS_TOKEN},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := MakeTargetSubscriberAuth(test.trigger)
+			if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("MakeTargetSubscriberAuth (-want,+got): %v", diff)
+			}
+		})
+	}
+}
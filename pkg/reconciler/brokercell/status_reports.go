@@ -0,0 +1,66 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package brokercell
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	"github.com/google/knative-gcp/pkg/broker/status"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// staleReportGracePeriod is how long the report of a pod missing from the pod lister is
+// kept, as the report of a new pod can be written before the lister has the pod.
+const staleReportGracePeriod = time.Minute
+
+var statusReportSelector = labels.SelectorFromSet(map[string]string{status.ReportLabelKey: "true"})
+
+// deleteStaleStatusReports deletes the status reports of the data plane pods which are gone.
+// The reports are in the status namespace, so they can't be owned by the pods.
+// TODO(#866) Only delete the reports of the pods of the brokercell, the pods of all the
+// brokercells are in the system namespace for now.
+func (r *Reconciler) deleteStaleStatusReports(ctx context.Context, bc *intv1alpha1.BrokerCell) {
+	cms, err := r.configMapLister.ConfigMaps(status.Namespace()).List(statusReportSelector)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list status reports", zap.Error(err))
+		return
+	}
+	for _, cm := range cms {
+		report, err := status.ParseReport(cm)
+		if err != nil || time.Since(report.Time) < staleReportGracePeriod {
+			continue
+		}
+		pod, err := r.podLister.Pods(bc.Namespace).Get(report.Pod)
+		if err == nil && pod.UID == report.PodUID {
+			continue
+		}
+		if err != nil && !apierrs.IsNotFound(err) {
+			continue
+		}
+		err = r.KubeClientSet.CoreV1().ConfigMaps(cm.Namespace).Delete(ctx, cm.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrs.IsNotFound(err) {
+			logging.FromContext(ctx).Error("Failed to delete stale status report", zap.String("configmap", cm.Name), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package brokercell
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+	"knative.dev/pkg/configmap"
+	. "knative.dev/pkg/reconciler/testing"
+
+	"github.com/google/knative-gcp/pkg/broker/status"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+func TestDeleteStaleStatusReports(t *testing.T) {
+	setReconcilerEnv()
+	bc := NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)
+	old := time.Now().Add(-time.Hour)
+	objects := []runtime.Object{
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "fanout-running", UID: "uid-running"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "fanout-replaced", UID: "uid-new"}},
+		statusReportConfigMap("fanout-running", "uid-running", old),
+		statusReportConfigMap("fanout-replaced", "uid-old", old),
+		statusReportConfigMap("fanout-deleted", "uid-deleted", old),
+		// The report of a new pod may be written before the pod lister has the pod.
+		statusReportConfigMap("fanout-new", "uid-new", time.Now()),
+	}
+	ctx, _ := SetupFakeContext(t)
+	ctx, client := fakekubeclient.With(ctx, objects...)
+	base := reconciler.NewBase(ctx, controllerAgentName, configmap.NewStaticWatcher())
+	testingListers := NewListers(objects)
+	r := &Reconciler{
+		Base: base,
+		listers: listers{
+			configMapLister: testingListers.GetConfigMapLister(),
+			podLister:       testingListers.GetPodLister(),
+		},
+	}
+
+	r.deleteStaleStatusReports(ctx, bc)
+
+	for pod, wantDeleted := range map[string]bool{
+		"fanout-running":  false,
+		"fanout-replaced": true,
+		"fanout-deleted":  true,
+		"fanout-new":      false,
+	} {
+		_, err := client.CoreV1().ConfigMaps(status.Namespace()).Get(context.Background(), status.ConfigMapName(pod), metav1.GetOptions{})
+		if gotDeleted := apierrs.IsNotFound(err); gotDeleted != wantDeleted {
+			t.Errorf("Report of pod %s deleted got=%v, want=%v (err: %v)", pod, gotDeleted, wantDeleted, err)
+		}
+	}
+}
+
+func statusReportConfigMap(pod string, uid types.UID, reportTime time.Time) *corev1.ConfigMap {
+	report, _ := json.Marshal(&status.Report{Pod: pod, PodUID: uid, Time: reportTime})
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: status.Namespace(),
+			Name:      status.ConfigMapName(pod),
+			Labels:    map[string]string{status.ReportLabelKey: "true"},
+		},
+		Data: map[string]string{status.ReportDataKey: string(report)},
+	}
+}
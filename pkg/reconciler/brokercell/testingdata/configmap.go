@@ -25,6 +25,7 @@ import (
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
 	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
+	"google.golang.org/protobuf/types/known/durationpb"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -38,13 +39,16 @@ func Config(t *testing.T, bc *intv1alpha1.BrokerCell, broker *brokerv1beta1.Brok
 	targets := make(map[string]*config.Target, len(triggers))
 	for _, t := range triggers {
 		state := config.State_UNKNOWN
-		if t.Status.IsReady() {
+		if t.Status.IsReady() && t.IsPaused() {
+			state = config.State_PAUSED
+		} else if t.Status.IsReady() {
 			state = config.State_READY
 		}
 		var filterAttributes map[string]string
 		if t.Spec.Filter != nil && t.Spec.Filter.Attributes != nil {
 			filterAttributes = t.Spec.Filter.Attributes
 		}
+		filters, _ := t.GetFilters()
 		target := &config.Target{
 			Id:        string(t.UID),
 			Name:      t.Name,
@@ -55,8 +59,47 @@ func Config(t *testing.T, bc *intv1alpha1.BrokerCell, broker *brokerv1beta1.Brok
 				Topic:        brokerresources.GenerateRetryTopicName(t),
 				Subscription: brokerresources.GenerateRetrySubscriptionName(t),
 			},
-			State:            state,
-			FilterAttributes: filterAttributes,
+			State:                state,
+			FilterAttributes:     filterAttributes,
+			Filters:              resources.MakeTargetFilters(filters),
+			CelFilter:            t.GetCELFilter(),
+			OrderingKeyAttribute: t.GetOrderingKeyAttribute(),
+			Replay:               resources.MakeTargetReplay(broker, t),
+			SubscriberAuth:       resources.MakeTargetSubscriberAuth(t),
+		}
+
+		var deadLetterAddress string
+		if d := broker.Spec.Delivery; d != nil && d.DeadLetterSink != nil && d.DeadLetterSink.URI != nil && !brokerv1beta1.IsPubsubDeadLetterSink(d.DeadLetterSink) {
+			deadLetterAddress = d.DeadLetterSink.URI.String()
+		}
+		triggerDelivery, _ := t.GetDelivery()
+		target.DeliverySpec = resources.MakeTargetDeliverySpec(broker.Spec.Delivery, triggerDelivery, deadLetterAddress)
+		// The secret headers only reference their secrets, which are assumed to exist.
+		headers, _ := t.GetHeaders()
+		for _, h := range headers {
+			if h.SecretKeyRef != nil {
+				if target.SecretHeaders == nil {
+					target.SecretHeaders = make(map[string]*config.SecretKeyRef)
+				}
+				target.SecretHeaders[h.Name] = &config.SecretKeyRef{
+					Name:     h.SecretKeyRef.Name,
+					Key:      h.SecretKeyRef.Key,
+					Optional: h.SecretKeyRef.Optional != nil && *h.SecretKeyRef.Optional,
+				}
+				continue
+			}
+			if target.Headers == nil {
+				target.Headers = make(map[string]string)
+			}
+			target.Headers[h.Name] = h.Value
+		}
+		target.Extensions, _ = t.GetExtensions()
+		if reply, _ := t.GetReply(); reply != nil {
+			target.DropReplies = reply.Drop
+			if reply.Destination != nil && reply.Destination.URI != nil {
+				target.ReplyAddress = reply.Destination.URI.String()
+				target.ReplyToBroker = target.ReplyAddress == broker.Status.Address.URL.String()
+			}
 		}
 
 		targets[t.Name] = target
@@ -71,6 +114,7 @@ func Config(t *testing.T, bc *intv1alpha1.BrokerCell, broker *brokerv1beta1.Brok
 	if broker.Status.GetCondition(brokerv1beta1.BrokerConditionTopic).IsTrue() && broker.Status.GetCondition(brokerv1beta1.BrokerConditionSubscription).IsTrue() {
 		brokerQueueState = config.State_READY
 	}
+	allowedSenders, allowedSendersErr := broker.GetAllowedSenders()
 	brokerConfig := &config.Broker{
 		Id:        string(broker.UID),
 		Name:      broker.Name,
@@ -81,8 +125,17 @@ func Config(t *testing.T, bc *intv1alpha1.BrokerCell, broker *brokerv1beta1.Brok
 			Subscription: brokerresources.GenerateDecouplingSubscriptionName(broker),
 			State:        brokerQueueState,
 		},
-		Targets: targets,
-		State:   state,
+		Targets:              targets,
+		State:                state,
+		OrderingKeyAttribute: broker.GetOrderingKeyAttribute(),
+		AllowedSenders:       allowedSenders,
+		DenyAllSenders:       allowedSendersErr != nil,
+	}
+	if transformer, _ := broker.GetTransformer(); transformer != nil && transformer.URI != nil {
+		brokerConfig.TransformerAddress = transformer.URI.String()
+	}
+	if window := broker.GetDeduplicationWindow(); window > 0 {
+		brokerConfig.DeduplicationWindow = durationpb.New(window)
 	}
 	bt := &config.TargetsConfig{
 		Brokers: map[string]*config.Broker{
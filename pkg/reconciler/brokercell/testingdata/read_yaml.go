@@ -38,6 +38,22 @@ func IngressDeploymentWithFilteringAnnotation(t *testing.T) *appsv1.Deployment {
 	return getDeployment(t, "testingdata/ingress_deployment_with_filtering_annotation.yaml")
 }
 
+func IngressDeploymentWithAuthenticationAnnotation(t *testing.T) *appsv1.Deployment {
+	return getDeployment(t, "testingdata/ingress_deployment_with_authentication_annotation.yaml")
+}
+
+func IngressDeploymentWithClaimCheckAnnotation(t *testing.T) *appsv1.Deployment {
+	return getDeployment(t, "testingdata/ingress_deployment_with_claim_check_annotation.yaml")
+}
+
+func FanoutDeploymentWithClaimCheckAnnotation(t *testing.T) *appsv1.Deployment {
+	return getDeployment(t, "testingdata/fanout_deployment_with_claim_check_annotation.yaml")
+}
+
+func RetryDeploymentWithClaimCheckAnnotation(t *testing.T) *appsv1.Deployment {
+	return getDeployment(t, "testingdata/retry_deployment_with_claim_check_annotation.yaml")
+}
+
 func FanoutDeployment(t *testing.T) *appsv1.Deployment {
 	return getDeployment(t, "testingdata/fanout_deployment.yaml")
 }
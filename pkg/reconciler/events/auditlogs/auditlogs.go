@@ -21,8 +21,6 @@ import (
 
 	"cloud.google.com/go/logging/logadmin"
 	"go.uber.org/zap"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 
@@ -32,16 +30,14 @@ import (
 	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
 	cloudauditlogssourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudauditlogssource"
 	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1"
-	glogadmin "github.com/google/knative-gcp/pkg/gclient/logging/logadmin"
-	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
 	"github.com/google/knative-gcp/pkg/reconciler/events/auditlogs/resources"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	"github.com/google/knative-gcp/pkg/reconciler/logsink"
 )
 
 const (
 	resourceGroup = "cloudauditlogssources.events.cloud.google.com"
-	publisherRole = "roles/pubsub.publisher"
 
 	deletePubSubFailed           = "PubSubDeleteFailed"
 	deleteSinkFailed             = "SinkDeleteFailed"
@@ -56,9 +52,9 @@ type Reconciler struct {
 	*intevents.PubSubBase
 	// identity reconciler for reconciling workload identity.
 	*identity.Identity
-	auditLogsSourceLister  listers.CloudAuditLogsSourceLister
-	logadminClientProvider glogadmin.CreateFn
-	pubsubClientProvider   gpubsub.CreateFn
+	auditLogsSourceLister listers.CloudAuditLogsSourceLister
+	// sinkReconciler for reconciling the Stackdriver sink.
+	sinkReconciler *logsink.Reconciler
 	// serviceAccountLister for reading serviceAccounts.
 	serviceAccountLister corev1listers.ServiceAccountLister
 }
@@ -86,7 +82,7 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, s *v1.CloudAuditLogsSour
 	}
 	c.Logger.Debugf("Reconciled: PubSub: %+v PullSubscription: %+v", t, ps)
 
-	sink, err := c.reconcileSink(ctx, s)
+	sink, err := c.sinkReconciler.ReconcileSink(ctx, s.Status.ProjectID, s.Status.TopicID, makeSink(s), &s.Status)
 	if err != nil {
 		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile Sink failed with: %s", err.Error())
 	}
@@ -97,93 +93,23 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, s *v1.CloudAuditLogsSour
 	return reconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudAuditLogsSource reconciled: "%s/%s"`, s.Namespace, s.Name)
 }
 
-func (c *Reconciler) reconcileSink(ctx context.Context, s *v1.CloudAuditLogsSource) (string, error) {
-	sink, err := c.ensureSinkCreated(ctx, s)
-	if err != nil {
-		s.Status.MarkSinkNotReady("SinkCreateFailed", "failed to ensure creation of logging sink: %s", err.Error())
-		return "", err
-	}
-	err = c.ensureSinkIsPublisher(ctx, s, sink)
-	if err != nil {
-		s.Status.MarkSinkNotReady("SinkNotPublisher", "failed to ensure sink has pubsub.publisher permission on source topic: %s", err.Error())
-		return "", err
-	}
-	return sink.ID, nil
-}
-
-func (c *Reconciler) ensureSinkCreated(ctx context.Context, s *v1.CloudAuditLogsSource) (*logadmin.Sink, error) {
+// makeSink makes the Stackdriver sink of the source, which is created if it
+// doesn't exist yet.
+func makeSink(s *v1.CloudAuditLogsSource) *logadmin.Sink {
 	sinkID := s.Status.StackdriverSink
 	if sinkID == "" {
 		sinkID = resources.GenerateSinkName(s)
 	}
-	logadminClient, err := c.logadminClientProvider(ctx, s.Status.ProjectID)
-	if err != nil {
-		logging.FromContext(ctx).Desugar().Error("Failed to create LogAdmin client", zap.Error(err))
-		return nil, err
-	}
-	sink, err := logadminClient.Sink(ctx, sinkID)
-	if status.Code(err) == codes.NotFound {
-		filterBuilder := resources.FilterBuilder{}
-		filterBuilder.WithServiceName(s.Spec.ServiceName).WithMethodName(s.Spec.MethodName)
-		if s.Spec.ResourceName != "" {
-			filterBuilder.WithResourceName(s.Spec.ResourceName)
-		}
-		sink = &logadmin.Sink{
-			ID:          sinkID,
-			Destination: resources.GenerateTopicResourceName(s),
-			Filter:      filterBuilder.GetFilterQuery(),
-		}
-		sink, err = logadminClient.CreateSinkOpt(ctx, sink, logadmin.SinkOptions{UniqueWriterIdentity: true})
-		// Handle AlreadyExists in-case of a race between another create call.
-		if status.Code(err) == codes.AlreadyExists {
-			sink, err = logadminClient.Sink(ctx, sinkID)
-		}
-	}
-	return sink, err
-}
-
-// Ensures that the sink has been granted the pubsub.publisher role on the source topic.
-func (c *Reconciler) ensureSinkIsPublisher(ctx context.Context, s *v1.CloudAuditLogsSource, sink *logadmin.Sink) error {
-	pubsubClient, err := c.pubsubClientProvider(ctx, s.Status.ProjectID)
-	if err != nil {
-		logging.FromContext(ctx).Desugar().Error("Failed to create PubSub client", zap.Error(err))
-		return err
-	}
-	topicIam := pubsubClient.Topic(s.Status.TopicID).IAM()
-	topicPolicy, err := topicIam.Policy(ctx)
-	if err != nil {
-		return err
+	filterBuilder := resources.FilterBuilder{}
+	filterBuilder.WithServiceName(s.Spec.ServiceName).WithMethodName(s.Spec.MethodName)
+	if s.Spec.ResourceName != "" {
+		filterBuilder.WithResourceName(s.Spec.ResourceName)
 	}
-	if !topicPolicy.HasRole(sink.WriterIdentity, publisherRole) {
-		topicPolicy.Add(sink.WriterIdentity, publisherRole)
-		if err = topicIam.SetPolicy(ctx, topicPolicy); err != nil {
-			return err
-		}
-		logging.FromContext(ctx).Desugar().Debug(
-			"Granted the Stackdriver Sink writer identity roles/pubsub.publisher on PubSub Topic.",
-			zap.String("writerIdentity", sink.WriterIdentity),
-			zap.String("topicID", s.Status.TopicID))
+	return &logadmin.Sink{
+		ID:          sinkID,
+		Destination: resources.GenerateTopicResourceName(s),
+		Filter:      filterBuilder.GetFilterQuery(),
 	}
-	return nil
-}
-
-// deleteSink looks at status.SinkID and if non-empty will delete the
-// previously created stackdriver sink.
-func (c *Reconciler) deleteSink(ctx context.Context, s *v1.CloudAuditLogsSource) error {
-	if s.Status.StackdriverSink == "" {
-		return nil
-	}
-	logadminClient, err := c.logadminClientProvider(ctx, s.Status.ProjectID)
-	if err != nil {
-		logging.FromContext(ctx).Desugar().Error("Failed to create LogAdmin client", zap.Error(err))
-		s.Status.MarkSinkUnknown(deleteSinkFailed, "Failed to create LogAdmin Client: %s", err.Error())
-		return err
-	}
-	if err = logadminClient.DeleteSink(ctx, s.Status.StackdriverSink); err != nil && status.Code(err) != codes.NotFound {
-		s.Status.MarkSinkUnknown(deleteSinkFailed, "Failed to delete Stackdriver sink: %s", err.Error())
-		return err
-	}
-	return nil
 }
 
 func (c *Reconciler) FinalizeKind(ctx context.Context, s *v1.CloudAuditLogsSource) reconciler.Event {
@@ -196,7 +122,7 @@ func (c *Reconciler) FinalizeKind(ctx context.Context, s *v1.CloudAuditLogsSourc
 		}
 	}
 
-	if err := c.deleteSink(ctx, s); err != nil {
+	if err := c.sinkReconciler.DeleteSink(ctx, s.Status.ProjectID, s.Status.StackdriverSink, &s.Status); err != nil {
 		return reconciler.NewEvent(corev1.EventTypeWarning, deleteSinkFailed, "Failed to delete Stackdriver sink: %s", err.Error())
 	}
 
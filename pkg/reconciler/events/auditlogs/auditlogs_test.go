@@ -53,6 +53,7 @@ import (
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	"github.com/google/knative-gcp/pkg/reconciler/logsink"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
 )
 
@@ -1431,11 +1432,10 @@ func TestAllCases(t *testing.T) {
 								ReceiveAdapterType:  string(converters.CloudAuditLogs),
 								ConfigWatcher:       cmw,
 							}),
-						Identity:               identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
-						auditLogsSourceLister:  listers.GetCloudAuditLogsSourceLister(),
-						logadminClientProvider: logadminClientProvider,
-						pubsubClientProvider:   gpubsub.TestClientCreator(testData["pubsub"]),
-						serviceAccountLister:   listers.GetServiceAccountLister(),
+						Identity:              identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+						auditLogsSourceLister: listers.GetCloudAuditLogsSourceLister(),
+						sinkReconciler:        logsink.NewReconciler(logadminClientProvider, gpubsub.TestClientCreator(testData["pubsub"])),
+						serviceAccountLister:  listers.GetServiceAccountLister(),
 					}
 					return cloudauditlogssource.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetCloudAuditLogsSourceLister(), r.Recorder, r)
 				}))
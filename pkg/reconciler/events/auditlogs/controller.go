@@ -34,6 +34,7 @@ import (
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	"github.com/google/knative-gcp/pkg/reconciler/logsink"
 
 	cloudauditlogssourceinformers "github.com/google/knative-gcp/pkg/client/injection/informers/events/v1/cloudauditlogssource"
 	pullsubscriptioninformers "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1/pullsubscription"
@@ -83,11 +84,10 @@ func newController(
 				ReceiveAdapterType:  string(converters.CloudAuditLogs),
 				ConfigWatcher:       cmw,
 			}),
-		Identity:               identity.NewIdentity(ctx, ipm, gcpas),
-		auditLogsSourceLister:  cloudauditlogssourceInformer.Lister(),
-		logadminClientProvider: glogadmin.NewClient,
-		pubsubClientProvider:   gpubsub.NewClient,
-		serviceAccountLister:   serviceAccountInformer.Lister(),
+		Identity:              identity.NewIdentity(ctx, ipm, gcpas),
+		auditLogsSourceLister: cloudauditlogssourceInformer.Lister(),
+		sinkReconciler:        logsink.NewReconciler(glogadmin.NewClient, gpubsub.NewClient),
+		serviceAccountLister:  serviceAccountInformer.Lister(),
 	}
 	impl := cloudauditlogssourcereconciler.NewImpl(ctx, r)
 
@@ -0,0 +1,223 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package billingbudget
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	budgetspb "google.golang.org/genproto/googleapis/cloud/billing/budgets/v1beta1"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/reconciler"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	cloudbillingbudgetsourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudbillingbudgetsource"
+	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1"
+	gbudgets "github.com/google/knative-gcp/pkg/gclient/budgets"
+	"github.com/google/knative-gcp/pkg/reconciler/events/billingbudget/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+const (
+	resourceGroup = "cloudbillingbudgetsources.events.cloud.google.com"
+
+	// schemaVersion is the version of the budget notification format.
+	schemaVersion = "1.0"
+
+	// pubsubTopicField and schemaVersionField are the paths of the budget
+	// fields updated by this reconciler.
+	pubsubTopicField   = "all_updates_rule.pubsub_topic"
+	schemaVersionField = "all_updates_rule.schema_version"
+
+	deleteBudgetNotificationFailed = "BudgetNotificationDeleteFailed"
+	deletePubSubFailed             = "PubSubDeleteFailed"
+	deleteWorkloadIdentityFailed   = "WorkloadIdentityDeleteFailed"
+	reconciledPubSubFailedReason   = "PubSubReconcileFailed"
+	reconciledFailedReason         = "BudgetReconcileFailed"
+	reconciledSuccessReason        = "CloudBillingBudgetSourceReconciled"
+	workloadIdentityFailed         = "WorkloadIdentityReconcileFailed"
+)
+
+// Reconciler is the controller implementation for Google Cloud Billing
+// budget notifications.
+type Reconciler struct {
+	*intevents.PubSubBase
+	// identity reconciler for reconciling workload identity.
+	*identity.Identity
+	// budgetLister for reading CloudBillingBudgetSources.
+	budgetLister listers.CloudBillingBudgetSourceLister
+
+	// createClientFn is the function used to create the client that manages budgets.
+	// This is needed so that we can inject a mock client for UTs purposes.
+	createClientFn gbudgets.CreateFn
+}
+
+// Check that our Reconciler implements Interface.
+var _ cloudbillingbudgetsourcereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, source *v1.CloudBillingBudgetSource) reconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("billingbudget", source)))
+
+	source.Status.InitializeConditions()
+	source.Status.ObservedGeneration = source.Generation
+
+	// If ServiceAccountName is provided, reconcile workload identity.
+	if source.Spec.ServiceAccountName != "" {
+		if _, err := r.Identity.ReconcileWorkloadIdentity(ctx, source.Spec.Project, source); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, workloadIdentityFailed, "Failed to reconcile CloudBillingBudgetSource workload identity: %s", err.Error())
+		}
+	}
+
+	topic := resources.GenerateTopicName(source)
+	_, _, err := r.PubSubBase.ReconcilePubSub(ctx, source, topic, resourceGroup)
+	if err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledPubSubFailedReason, "Reconcile PubSub failed with: %s", err.Error())
+	}
+
+	if err := r.reconcileBudget(ctx, source, topic); err != nil {
+		source.Status.MarkBudgetNotReady(reconciledFailedReason, "Failed to reconcile CloudBillingBudgetSource budget: %s", err.Error())
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile Budget failed with: %s", err.Error())
+	}
+	source.Status.MarkBudgetReady()
+	return reconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudBillingBudgetSource reconciled: "%s/%s"`, source.Namespace, source.Name)
+}
+
+// reconcileBudget makes sure the budget publishes its notifications to topic.
+// The budget itself is owned by the user, so only its Pub/Sub notification
+// settings are updated. A budget already publishing to another topic is left
+// untouched, as it can only have one.
+func (r *Reconciler) reconcileBudget(ctx context.Context, source *v1.CloudBillingBudgetSource, topic string) error {
+	if source.Status.ProjectID == "" {
+		projectID, err := utils.ProjectIDOrDefault(source.Spec.Project)
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to find project id", zap.Error(err))
+			return err
+		}
+		// Set the projectID in the status.
+		source.Status.ProjectID = projectID
+	}
+
+	client, err := r.createClientFn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudBillingBudgetSource client", zap.Error(err))
+		return err
+	}
+	defer client.Close()
+
+	budgetName := resources.GenerateBudgetName(source)
+	budget, err := client.GetBudget(ctx, &budgetspb.GetBudgetRequest{Name: budgetName})
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed from CloudBillingBudgetSource client while retrieving budget", zap.String("budget", budgetName), zap.Error(err))
+		return err
+	}
+
+	topicPath := resources.GenerateTopicPath(source, topic)
+	switch budget.GetAllUpdatesRule().GetPubsubTopic() {
+	case topicPath:
+		return nil
+	case "":
+	default:
+		return fmt.Errorf("budget already publishes its notifications to topic %q", budget.GetAllUpdatesRule().GetPubsubTopic())
+	}
+
+	if budget.AllUpdatesRule == nil {
+		budget.AllUpdatesRule = &budgetspb.AllUpdatesRule{}
+	}
+	budget.AllUpdatesRule.PubsubTopic = topicPath
+	budget.AllUpdatesRule.SchemaVersion = schemaVersion
+	if _, err := client.UpdateBudget(ctx, &budgetspb.UpdateBudgetRequest{
+		Budget:     budget,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{pubsubTopicField, schemaVersionField}},
+	}); err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to update CloudBillingBudgetSource budget", zap.String("budget", budgetName), zap.String("topic", topic), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// deleteBudgetNotification stops the budget from publishing its notifications
+// to topic. The budget is left alone if it is gone or was pointed at a
+// different topic in the meantime.
+func (r *Reconciler) deleteBudgetNotification(ctx context.Context, source *v1.CloudBillingBudgetSource) error {
+	if source.Status.ProjectID == "" {
+		return nil
+	}
+
+	client, err := r.createClientFn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudBillingBudgetSource client", zap.Error(err))
+		source.Status.MarkBudgetUnknown(deleteBudgetNotificationFailed, "Failed to create CloudBillingBudgetSource client: %s", err.Error())
+		return err
+	}
+	defer client.Close()
+
+	budgetName := resources.GenerateBudgetName(source)
+	budget, err := client.GetBudget(ctx, &budgetspb.GetBudgetRequest{Name: budgetName})
+	if err != nil {
+		if st, ok := gstatus.FromError(err); ok && st.Code() == codes.NotFound {
+			return nil
+		}
+		logging.FromContext(ctx).Desugar().Error("Failed from CloudBillingBudgetSource client while retrieving budget", zap.String("budget", budgetName), zap.Error(err))
+		source.Status.MarkBudgetUnknown(deleteBudgetNotificationFailed, "Failed to retrieve CloudBillingBudgetSource budget: %s", err.Error())
+		return err
+	}
+
+	if budget.GetAllUpdatesRule().GetPubsubTopic() != resources.GenerateTopicPath(source, resources.GenerateTopicName(source)) {
+		return nil
+	}
+
+	budget.AllUpdatesRule.PubsubTopic = ""
+	if _, err := client.UpdateBudget(ctx, &budgetspb.UpdateBudgetRequest{
+		Budget:     budget,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{pubsubTopicField}},
+	}); err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to update CloudBillingBudgetSource budget", zap.String("budget", budgetName), zap.Error(err))
+		source.Status.MarkBudgetUnknown(deleteBudgetNotificationFailed, "Failed to update CloudBillingBudgetSource budget: %s", err.Error())
+		return err
+	}
+	logging.FromContext(ctx).Desugar().Debug("Deleted CloudBillingBudgetSource budget notification", zap.String("budget", budgetName))
+	return nil
+}
+
+func (r *Reconciler) FinalizeKind(ctx context.Context, source *v1.CloudBillingBudgetSource) reconciler.Event {
+	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
+	// remove the corresponding GCP ServiceAccount iam policy binding.
+	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
+	if source.Spec.ServiceAccountName != "" {
+		if err := r.Identity.DeleteWorkloadIdentity(ctx, source.Spec.Project, source); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, deleteWorkloadIdentityFailed, "Failed to delete CloudBillingBudgetSource workload identity: %s", err.Error())
+		}
+	}
+
+	logging.FromContext(ctx).Desugar().Debug("Deleting CloudBillingBudgetSource budget notification")
+	if err := r.deleteBudgetNotification(ctx, source); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deleteBudgetNotificationFailed, "Failed to delete CloudBillingBudgetSource budget notification: %s", err.Error())
+	}
+
+	if err := r.PubSubBase.DeletePubSub(ctx, source); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deletePubSubFailed, "Failed to delete CloudBillingBudgetSource PubSub: %s", err.Error())
+	}
+
+	return nil
+}
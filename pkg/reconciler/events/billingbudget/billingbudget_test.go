@@ -0,0 +1,667 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package billingbudget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	reconcilertestingv1 "github.com/google/knative-gcp/pkg/reconciler/testing/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	. "knative.dev/pkg/reconciler/testing"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	billingbudgetv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	. "github.com/google/knative-gcp/pkg/apis/intevents"
+	inteventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
+	"github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudbillingbudgetsource"
+	gbudgets "github.com/google/knative-gcp/pkg/gclient/budgets/testing"
+	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+)
+
+const (
+	billingBudgetName = "my-test-billingbudget"
+	billingBudgetUID  = "test-billingbudget-uid"
+	sinkName          = "sink"
+
+	testNS             = "testnamespace"
+	testProject        = "test-project-id"
+	testBillingAccount = "012345-6789AB-CDEF01"
+	testBudget         = "my-budget"
+	testTopicURI       = "http://" + billingBudgetName + "-topic." + testNS + ".svc.cluster.local"
+	otherTopicPath     = "projects/" + testProject + "/topics/other"
+
+	// Message for when the topic and pullsubscription with the above variables are not ready.
+	failedToReconcileTopicMsg  = `Topic has not yet been reconciled`
+	failedToReconcileBudgetMsg = `Failed to reconcile CloudBillingBudgetSource budget`
+	failedToUpdateBudgetMsg    = `Failed to update CloudBillingBudgetSource budget`
+)
+
+var (
+	trueVal  = true
+	falseVal = false
+
+	sinkDNS = sinkName + ".mynamespace.svc.cluster.local"
+	sinkURI = apis.HTTP(sinkDNS)
+
+	testTopicID   = fmt.Sprintf("cre-src_%s_%s_%s", testNS, billingBudgetName, billingBudgetUID)
+	testTopicPath = fmt.Sprintf("projects/%s/topics/%s", testProject, testTopicID)
+
+	sinkGVK = metav1.GroupVersionKind{
+		Group:   "testing.cloud.google.com",
+		Version: "v1",
+		Kind:    "Sink",
+	}
+
+	secret = corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: "google-cloud-key",
+		},
+		Key: "key.json",
+	}
+)
+
+func init() {
+	// Add types to scheme
+	_ = billingbudgetv1.AddToScheme(scheme.Scheme)
+}
+
+// Returns an ownerref for the test CloudBillingBudgetSource object
+func ownerRef() metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion:         "events.cloud.google.com/v1",
+		Kind:               "CloudBillingBudgetSource",
+		Name:               billingBudgetName,
+		UID:                billingBudgetUID,
+		Controller:         &trueVal,
+		BlockOwnerDeletion: &trueVal,
+	}
+}
+
+func patchFinalizers(namespace, name string, add bool) clientgotesting.PatchActionImpl {
+	action := clientgotesting.PatchActionImpl{}
+	action.Name = name
+	action.Namespace = namespace
+	var fname string
+	if add {
+		fname = fmt.Sprintf("%q", resourceGroup)
+	}
+	patch := `{"metadata":{"finalizers":[` + fname + `],"resourceVersion":""}}`
+	action.Patch = []byte(patch)
+	return action
+}
+
+func newSink() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "testing.cloud.google.com/v1",
+			"kind":       "Sink",
+			"metadata": map[string]interface{}{
+				"namespace": testNS,
+				"name":      sinkName,
+			},
+			"status": map[string]interface{}{
+				"address": map[string]interface{}{
+					"hostname": sinkDNS,
+				},
+			},
+		},
+	}
+}
+
+func newSinkDestination() duckv1.Destination {
+	return duckv1.Destination{
+		Ref: &duckv1.KReference{
+			APIVersion: "testing.cloud.google.com/v1",
+			Kind:       "Sink",
+			Name:       sinkName,
+		},
+	}
+}
+
+func newReadyTopic() *inteventsv1.Topic {
+	return reconcilertestingv1.NewTopic(billingBudgetName, testNS,
+		reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+			Topic:             testTopicID,
+			PropagationPolicy: "CreateDelete",
+			Project:           testProject,
+			EnablePublisher:   &falseVal,
+		}),
+		reconcilertestingv1.WithTopicReady(testTopicID),
+		reconcilertestingv1.WithTopicAddress(testTopicURI),
+		reconcilertestingv1.WithTopicProjectID(testProject),
+		reconcilertestingv1.WithTopicSetDefaults,
+	)
+}
+
+func newReadyPullSubscription() *inteventsv1.PullSubscription {
+	return reconcilertestingv1.NewPullSubscription(billingBudgetName, testNS,
+		reconcilertestingv1.WithPullSubscriptionReady(sinkURI),
+		reconcilertestingv1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+			Topic: testTopicID,
+			PubSubSpec: gcpduckv1.PubSubSpec{
+				Secret: &secret,
+				SourceSpec: duckv1.SourceSpec{
+					Sink: newSinkDestination(),
+				},
+				Project: testProject,
+			},
+			AdapterType: string(converters.CloudBillingBudget),
+		}),
+	)
+}
+
+func TestAllCases(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		// Make sure Reconcile handles bad keys.
+		Key: "too/many/parts",
+	}, {
+		Name: "key not found",
+		// Make sure Reconcile handles good keys that don't exist.
+		Key: "foo/not-found",
+	}, {
+		Name: "topic created, not ready",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudBillingBudgetSourceAnnotations(map[string]string{
+					duck.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+			newSink(),
+		},
+		Key: testNS + "/" + billingBudgetName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudBillingBudgetSourceConditions,
+				reconcilertestingv1.WithCloudBillingBudgetSourceAnnotations(map[string]string{
+					duck.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+				reconcilertestingv1.WithCloudBillingBudgetSourceTopicUnknown("TopicNotConfigured", failedToReconcileTopicMsg),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+		}},
+		WantCreates: []runtime.Object{
+			reconcilertestingv1.NewTopic(billingBudgetName, testNS,
+				reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+					Topic:             testTopicID,
+					PropagationPolicy: "CreateDelete",
+					EnablePublisher:   &falseVal,
+				}),
+				reconcilertestingv1.WithTopicLabels(map[string]string{
+					"receive-adapter": receiveAdapterName,
+					SourceLabelKey:    billingBudgetName,
+				}),
+				reconcilertestingv1.WithTopicAnnotations(map[string]string{
+					duck.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+				reconcilertestingv1.WithTopicOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+				reconcilertestingv1.WithTopicSetDefaults,
+			),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, billingBudgetName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", billingBudgetName),
+			Eventf(corev1.EventTypeWarning, reconciledPubSubFailedReason, "Reconcile PubSub failed with: Topic %q has not yet been reconciled", billingBudgetName),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, create client fails",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"billingbudget": gbudgets.TestClientData{
+				CreateClientErr: errors.New("create-client-induced-error"),
+			},
+		},
+		Key: testNS + "/" + billingBudgetName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudBillingBudgetSourceConditions,
+				reconcilertestingv1.WithCloudBillingBudgetSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudgetNotReady(reconciledFailedReason, fmt.Sprintf("%s: %s", failedToReconcileBudgetMsg, "create-client-induced-error")),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, billingBudgetName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", billingBudgetName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile Budget failed with: create-client-induced-error"),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, budget not found",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"billingbudget": gbudgets.TestClientData{
+				GetBudgetErr: gstatus.Error(codes.NotFound, "get-budget-induced-error"),
+			},
+		},
+		Key: testNS + "/" + billingBudgetName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudBillingBudgetSourceConditions,
+				reconcilertestingv1.WithCloudBillingBudgetSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudgetNotReady(reconciledFailedReason,
+					fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToReconcileBudgetMsg, codes.NotFound, "get-budget-induced-error")),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, billingBudgetName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", billingBudgetName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile Budget failed with: rpc error: code = %s desc = %s", codes.NotFound, "get-budget-induced-error"),
+		},
+	}, {
+		Name: "budget publishes to another topic",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"billingbudget": gbudgets.TestClientData{
+				PubsubTopic: otherTopicPath,
+			},
+		},
+		Key: testNS + "/" + billingBudgetName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudBillingBudgetSourceConditions,
+				reconcilertestingv1.WithCloudBillingBudgetSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudgetNotReady(reconciledFailedReason,
+					fmt.Sprintf("%s: budget already publishes its notifications to topic %q", failedToReconcileBudgetMsg, otherTopicPath)),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, billingBudgetName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", billingBudgetName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile Budget failed with: budget already publishes its notifications to topic %q", otherTopicPath),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, update budget fails",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"billingbudget": gbudgets.TestClientData{
+				UpdateBudgetErr: gstatus.Error(codes.PermissionDenied, "update-budget-induced-error"),
+			},
+		},
+		Key: testNS + "/" + billingBudgetName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudBillingBudgetSourceConditions,
+				reconcilertestingv1.WithCloudBillingBudgetSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudgetNotReady(reconciledFailedReason,
+					fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToReconcileBudgetMsg, codes.PermissionDenied, "update-budget-induced-error")),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, billingBudgetName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", billingBudgetName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile Budget failed with: rpc error: code = %s desc = %s", codes.PermissionDenied, "update-budget-induced-error"),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, budget updated",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key: testNS + "/" + billingBudgetName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudBillingBudgetSourceConditions,
+				reconcilertestingv1.WithCloudBillingBudgetSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudgetReady,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, billingBudgetName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", billingBudgetName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudBillingBudgetSource reconciled: "%s/%s"`, testNS, billingBudgetName),
+		},
+	}, {
+		Name: "budget already publishes to topic",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"billingbudget": gbudgets.TestClientData{
+				PubsubTopic: testTopicPath,
+				// The budget must not be updated again.
+				UpdateBudgetErr: errors.New("update-budget-induced-error"),
+			},
+		},
+		Key: testNS + "/" + billingBudgetName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudBillingBudgetSourceConditions,
+				reconcilertestingv1.WithCloudBillingBudgetSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudgetReady,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, billingBudgetName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", billingBudgetName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudBillingBudgetSource reconciled: "%s/%s"`, testNS, billingBudgetName),
+		},
+	}, {
+		Name: "budget notification fails to delete with Unknown grpc error",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudBillingBudgetSourceConditions,
+				reconcilertestingv1.WithCloudBillingBudgetSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudgetReady,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudBillingBudgetSourceDeletionTimestamp,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key: testNS + "/" + billingBudgetName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudBillingBudgetSourceConditions,
+				reconcilertestingv1.WithCloudBillingBudgetSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudgetUnknown(deleteBudgetNotificationFailed,
+					fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToUpdateBudgetMsg, codes.Unknown, "update-budget-induced-error")),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudBillingBudgetSourceDeletionTimestamp,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+		}},
+		OtherTestData: map[string]interface{}{
+			"billingbudget": gbudgets.TestClientData{
+				PubsubTopic:     testTopicPath,
+				UpdateBudgetErr: gstatus.Error(codes.Unknown, "update-budget-induced-error"),
+			},
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, deleteBudgetNotificationFailed, "Failed to delete CloudBillingBudgetSource budget notification: rpc error: code = %s desc = %s", codes.Unknown, "update-budget-induced-error"),
+		},
+	}, {
+		Name: "budget notification successfully deleted",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudBillingBudgetSourceConditions,
+				reconcilertestingv1.WithCloudBillingBudgetSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudgetReady,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudBillingBudgetSourceDeletionTimestamp,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key: testNS + "/" + billingBudgetName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudBillingBudgetSourceConditions,
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudgetDeleted,
+				reconcilertestingv1.WithCloudBillingBudgetSourceTopicDeleted,
+				reconcilertestingv1.WithCloudBillingBudgetSourcePullSubscriptionDeleted,
+				reconcilertestingv1.WithCloudBillingBudgetSourceDeletionTimestamp,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+		}},
+		WantDeletes: []clientgotesting.DeleteActionImpl{
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "topics"}},
+				Name: billingBudgetName,
+			},
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "pullsubscriptions"}},
+				Name: billingBudgetName,
+			},
+		},
+		OtherTestData: map[string]interface{}{
+			"billingbudget": gbudgets.TestClientData{
+				PubsubTopic: testTopicPath,
+			},
+		},
+	}, {
+		Name: "budget successfully deleted with NotFound grpc error",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudBillingBudgetSourceConditions,
+				reconcilertestingv1.WithCloudBillingBudgetSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudgetReady,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudBillingBudgetSourceDeletionTimestamp,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key: testNS + "/" + billingBudgetName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudBillingBudgetSource(billingBudgetName, testNS,
+				reconcilertestingv1.WithCloudBillingBudgetSourceProject(testProject),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBillingAccount(testBillingAccount),
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudget(testBudget),
+				reconcilertestingv1.WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudBillingBudgetSourceConditions,
+				reconcilertestingv1.WithCloudBillingBudgetSourceBudgetDeleted,
+				reconcilertestingv1.WithCloudBillingBudgetSourceTopicDeleted,
+				reconcilertestingv1.WithCloudBillingBudgetSourcePullSubscriptionDeleted,
+				reconcilertestingv1.WithCloudBillingBudgetSourceDeletionTimestamp,
+				reconcilertestingv1.WithCloudBillingBudgetSourceSetDefaults,
+			),
+		}},
+		WantDeletes: []clientgotesting.DeleteActionImpl{
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "topics"}},
+				Name: billingBudgetName,
+			},
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "pullsubscriptions"}},
+				Name: billingBudgetName,
+			},
+		},
+		OtherTestData: map[string]interface{}{
+			"billingbudget": gbudgets.TestClientData{
+				GetBudgetErr: gstatus.Error(codes.NotFound, "get-budget-induced-error"),
+			},
+		},
+	}}
+
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, testData map[string]interface{}) controller.Reconciler {
+		r := &Reconciler{
+			PubSubBase: intevents.NewPubSubBase(ctx,
+				&intevents.PubSubBaseArgs{
+					ControllerAgentName: controllerAgentName,
+					ReceiveAdapterName:  receiveAdapterName,
+					ReceiveAdapterType:  string(converters.CloudBillingBudget),
+					ConfigWatcher:       cmw,
+				}),
+			Identity:       identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+			budgetLister:   listers.GetCloudBillingBudgetSourceLister(),
+			createClientFn: gbudgets.TestClientCreator(testData["billingbudget"]),
+		}
+		return cloudbillingbudgetsource.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetCloudBillingBudgetSourceLister(), r.Recorder, r)
+	}))
+}
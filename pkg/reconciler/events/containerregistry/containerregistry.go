@@ -0,0 +1,93 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerregistry
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	"github.com/google/knative-gcp/pkg/apis/events"
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	cloudcontainerregistrysourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudcontainerregistrysource"
+	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+)
+
+const (
+	finalizerName = controllerAgentName
+
+	resourceGroup = "cloudcontainerregistrysources.events.cloud.google.com"
+
+	createFailedReason           = "PullSubscriptionCreateFailed"
+	deleteWorkloadIdentityFailed = "WorkloadIdentityDeleteFailed"
+	workloadIdentityFailed       = "WorkloadIdentityReconcileFailed"
+	reconciledSuccessReason      = "CloudContainerRegistrySourceReconciled"
+)
+
+// Reconciler is the controller implementation for the CloudContainerRegistrySource source.
+type Reconciler struct {
+	*intevents.PubSubBase
+
+	// identity reconciler for reconciling workload identity.
+	*identity.Identity
+	// containerRegistryLister for reading cloudcontainerregistrysources.
+	containerRegistryLister listers.CloudContainerRegistrySourceLister
+	// serviceAccountLister for reading serviceAccounts.
+	serviceAccountLister corev1listers.ServiceAccountLister
+}
+
+// Check that our Reconciler implements Interface.
+var _ cloudcontainerregistrysourcereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, source *v1.CloudContainerRegistrySource) pkgreconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("containerregistry", source)))
+
+	source.Status.InitializeConditions()
+	source.Status.ObservedGeneration = source.Generation
+	// If ServiceAccountName is provided, reconcile workload identity.
+	if source.Spec.ServiceAccountName != "" {
+		if _, err := r.Identity.ReconcileWorkloadIdentity(ctx, source.Spec.Project, source); err != nil {
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, workloadIdentityFailed, "Failed to reconcile CloudContainerRegistrySource workload identity: %s", err.Error())
+		}
+	}
+	_, event := r.PubSubBase.ReconcilePullSubscription(ctx, source, events.ContainerRegistryTopic, resourceGroup)
+	if event != nil {
+		return event
+	}
+
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudContainerRegistrySource reconciled: "%s/%s"`, source.Namespace, source.Name)
+}
+
+func (r *Reconciler) FinalizeKind(ctx context.Context, source *v1.CloudContainerRegistrySource) pkgreconciler.Event {
+	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
+	// remove the corresponding GCP ServiceAccount iam policy binding.
+	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
+	if source.Spec.ServiceAccountName != "" {
+		if err := r.Identity.DeleteWorkloadIdentity(ctx, source.Spec.Project, source); err != nil {
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, deleteWorkloadIdentityFailed, "Failed to delete CloudContainerRegistrySource workload identity: %s", err.Error())
+		}
+	}
+	return nil
+}
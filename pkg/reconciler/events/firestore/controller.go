@@ -0,0 +1,122 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package firestore implements the CloudFirestoreSource controller.
+//
+// Cloud Firestore doesn't publish notifications of document changes, so the
+// controller doesn't call the Firestore API and has no gclient wrapper of its
+// own. It routes the Data Access audit logs of the writes to the topic of the
+// source with a Stackdriver sink, managed with the logadmin and pubsub
+// wrappers and their fakes.
+package firestore
+
+import (
+	"context"
+
+	"knative.dev/pkg/injection"
+
+	"k8s.io/client-go/tools/cache"
+	serviceaccountinformers "knative.dev/pkg/client/injection/kube/informers/core/v1/serviceaccount"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+
+	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	"github.com/google/knative-gcp/pkg/reconciler/logsink"
+
+	cloudfirestoresourceinformers "github.com/google/knative-gcp/pkg/client/injection/informers/events/v1/cloudfirestoresource"
+	pullsubscriptioninformers "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1/pullsubscription"
+	topicinformers "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1/topic"
+	cloudfirestoresourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudfirestoresource"
+	glogadmin "github.com/google/knative-gcp/pkg/gclient/logging/logadmin"
+	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
+)
+
+const (
+	// reconcilerName is the name of the reconciler
+	reconcilerName = "CloudFirestoreSource"
+
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "cloud-run-events-cloudfirestoresource-controller"
+
+	// receiveAdapterName is the string used as name for the receive adapter pod.
+	receiveAdapterName = "cloudfirestoresource.events.cloud.google.com"
+)
+
+type Constructor injection.ControllerConstructor
+
+// NewConstructor creates a constructor to make a CloudFirestoreSource controller.
+func NewConstructor(ipm iam.IAMPolicyManager, gcpas *gcpauth.StoreSingleton) Constructor {
+	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+		return newController(ctx, cmw, ipm, gcpas.Store(ctx, cmw))
+	}
+}
+
+func newController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+	ipm iam.IAMPolicyManager,
+	gcpas *gcpauth.Store,
+) *controller.Impl {
+	pullsubscriptionInformer := pullsubscriptioninformers.Get(ctx)
+	topicInformer := topicinformers.Get(ctx)
+	cloudfirestoresourceInformer := cloudfirestoresourceinformers.Get(ctx)
+	serviceAccountInformer := serviceaccountinformers.Get(ctx)
+
+	r := &Reconciler{
+		PubSubBase: intevents.NewPubSubBase(ctx,
+			&intevents.PubSubBaseArgs{
+				ControllerAgentName: controllerAgentName,
+				ReceiveAdapterName:  receiveAdapterName,
+				ReceiveAdapterType:  string(converters.CloudFirestore),
+				ConfigWatcher:       cmw,
+			}),
+		Identity:              identity.NewIdentity(ctx, ipm, gcpas),
+		firestoreSourceLister: cloudfirestoresourceInformer.Lister(),
+		sinkReconciler:        logsink.NewReconciler(glogadmin.NewClient, gpubsub.NewClient),
+		serviceAccountLister:  serviceAccountInformer.Lister(),
+	}
+	impl := cloudfirestoresourcereconciler.NewImpl(ctx, r)
+
+	r.Logger.Info("Setting up event handlers")
+	cloudfirestoresourceInformer.Informer().AddEventHandlerWithResyncPeriod(
+		controller.HandleAll(impl.Enqueue), reconciler.DefaultResyncPeriod)
+
+	firestoreGK := v1.Kind("CloudFirestoreSource")
+
+	topicInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterControllerGK(firestoreGK),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	pullsubscriptionInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterControllerGK(firestoreGK),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	serviceAccountInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterControllerGK(firestoreGK),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	return impl
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firestore
+
+import (
+	"context"
+
+	"cloud.google.com/go/logging/logadmin"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/reconciler"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	cloudfirestoresourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudfirestoresource"
+	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1"
+	"github.com/google/knative-gcp/pkg/reconciler/events/firestore/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	"github.com/google/knative-gcp/pkg/reconciler/logsink"
+)
+
+const (
+	resourceGroup = "cloudfirestoresources.events.cloud.google.com"
+
+	deletePubSubFailed           = "PubSubDeleteFailed"
+	deleteSinkFailed             = "SinkDeleteFailed"
+	deleteWorkloadIdentityFailed = "WorkloadIdentityDeleteFailed"
+	reconciledFailedReason       = "SinkReconcileFailed"
+	reconciledPubSubFailedReason = "PubSubReconcileFailed"
+	reconciledSuccessReason      = "CloudFirestoreSourceReconciled"
+	workloadIdentityFailed       = "WorkloadIdentityReconcileFailed"
+)
+
+type Reconciler struct {
+	*intevents.PubSubBase
+	// identity reconciler for reconciling workload identity.
+	*identity.Identity
+	firestoreSourceLister listers.CloudFirestoreSourceLister
+	// sinkReconciler for reconciling the Stackdriver sink.
+	sinkReconciler *logsink.Reconciler
+	// serviceAccountLister for reading serviceAccounts.
+	serviceAccountLister corev1listers.ServiceAccountLister
+}
+
+// Check that our Reconciler implements Interface.
+var _ cloudfirestoresourcereconciler.Interface = (*Reconciler)(nil)
+
+func (c *Reconciler) ReconcileKind(ctx context.Context, s *v1.CloudFirestoreSource) reconciler.Event {
+	ctx = logging.WithLogger(ctx, c.Logger.With(zap.Any("firestoresource", s)))
+
+	s.Status.InitializeConditions()
+	s.Status.ObservedGeneration = s.Generation
+
+	// If ServiceAccountName is provided, reconcile workload identity.
+	if s.Spec.ServiceAccountName != "" {
+		if _, err := c.Identity.ReconcileWorkloadIdentity(ctx, s.Spec.Project, s); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, workloadIdentityFailed, "Failed to reconcile CloudFirestoreSource workload identity: %s", err.Error())
+		}
+	}
+
+	topic := resources.GenerateTopicName(s)
+	t, ps, err := c.PubSubBase.ReconcilePubSub(ctx, s, topic, resourceGroup)
+	if err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledPubSubFailedReason, "Reconcile PubSub failed with: %s", err.Error())
+	}
+	c.Logger.Debugf("Reconciled: PubSub: %+v PullSubscription: %+v", t, ps)
+
+	sink, err := c.sinkReconciler.ReconcileSink(ctx, s.Status.ProjectID, s.Status.TopicID, makeSink(s), &s.Status)
+	if err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile Sink failed with: %s", err.Error())
+	}
+	s.Status.StackdriverSink = sink
+	s.Status.MarkSinkReady()
+	c.Logger.Debugf("Reconciled Stackdriver sink: %+v", sink)
+
+	return reconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudFirestoreSource reconciled: "%s/%s"`, s.Namespace, s.Name)
+}
+
+// makeSink makes the Stackdriver sink of the source, which is created if it
+// doesn't exist yet.
+func makeSink(s *v1.CloudFirestoreSource) *logadmin.Sink {
+	sinkID := s.Status.StackdriverSink
+	if sinkID == "" {
+		sinkID = resources.GenerateSinkName(s)
+	}
+	return &logadmin.Sink{
+		ID:          sinkID,
+		Destination: resources.GenerateTopicResourceName(s),
+		Filter:      resources.GenerateFilter(s),
+	}
+}
+
+func (c *Reconciler) FinalizeKind(ctx context.Context, s *v1.CloudFirestoreSource) reconciler.Event {
+	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
+	// remove the corresponding GCP ServiceAccount iam policy binding.
+	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
+	if s.Spec.ServiceAccountName != "" {
+		if err := c.Identity.DeleteWorkloadIdentity(ctx, s.Spec.Project, s); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, deleteWorkloadIdentityFailed, "Failed to delete CloudFirestoreSource workload identity: %s", err.Error())
+		}
+	}
+
+	if err := c.sinkReconciler.DeleteSink(ctx, s.Status.ProjectID, s.Status.StackdriverSink, &s.Status); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deleteSinkFailed, "Failed to delete Stackdriver sink: %s", err.Error())
+	}
+
+	if err := c.PubSubBase.DeletePubSub(ctx, s); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deletePubSubFailed, "Failed to delete CloudFirestoreSource PubSub: %s", err.Error())
+	}
+	s.Status.StackdriverSink = ""
+	return nil
+}
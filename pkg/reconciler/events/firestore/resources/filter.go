@@ -0,0 +1,77 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+const (
+	serviceFilter = `protoPayload.serviceName="firestore.googleapis.com"`
+	methodFilter  = `(protoPayload.methodName="google.firestore.v1.Firestore.Commit" OR protoPayload.methodName="google.firestore.v1.Firestore.BatchWrite")`
+	typeFilter    = `protoPayload."@type"="type.googleapis.com/google.cloud.audit.AuditLog"`
+
+	updateNameKey = "protoPayload.request.writes.update.name"
+	deleteKey     = "protoPayload.request.writes.delete"
+	createdKey    = "protoPayload.request.writes.currentDocument.exists"
+)
+
+// GenerateFilter generates the Stackdriver query of the sink of a
+// CloudFirestoreSource. It matches the Data Access audit logs of the Commit
+// and BatchWrite calls that write a document of Spec.Collection with one of
+// Spec.EventTypes.
+func GenerateFilter(s *v1.CloudFirestoreSource) string {
+	documents := DocumentPattern(s.Status.ProjectID, s.Spec.Database, s.Spec.Collection)
+
+	var writes []string
+	for _, t := range s.Spec.EventTypes {
+		switch t {
+		case schemasv1.CloudFirestoreDocumentCreatedEventType:
+			writes = append(writes, fmt.Sprintf("(%s=~%q AND %s=false)", updateNameKey, documents, createdKey))
+		case schemasv1.CloudFirestoreDocumentUpdatedEventType:
+			writes = append(writes, fmt.Sprintf("(%s=~%q AND NOT %s=false)", updateNameKey, documents, createdKey))
+		case schemasv1.CloudFirestoreDocumentDeletedEventType:
+			writes = append(writes, fmt.Sprintf("%s=~%q", deleteKey, documents))
+		}
+	}
+
+	filters := []string{serviceFilter, methodFilter, typeFilter}
+	if len(writes) > 0 {
+		filters = append(filters, "("+strings.Join(writes, " OR ")+")")
+	}
+	return strings.Join(filters, " AND ")
+}
+
+// DocumentPattern returns the regular expression matching the names of the
+// documents of the collection. A "*" document ID matches any document.
+func DocumentPattern(project, database, collection string) string {
+	segments := strings.Split(collection, "/")
+	for i, s := range segments {
+		if s == "*" {
+			segments[i] = "[^/]+"
+		} else {
+			segments[i] = regexp.QuoteMeta(s)
+		}
+	}
+	return fmt.Sprintf("^projects/%s/databases/%s/documents/%s/[^/]+$",
+		regexp.QuoteMeta(project), regexp.QuoteMeta(database), strings.Join(segments, "/"))
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+func TestGenerateFilter(t *testing.T) {
+	const prefix = `protoPayload.serviceName="firestore.googleapis.com" AND ` +
+		`(protoPayload.methodName="google.firestore.v1.Firestore.Commit" OR protoPayload.methodName="google.firestore.v1.Firestore.BatchWrite") AND ` +
+		`protoPayload."@type"="type.googleapis.com/google.cloud.audit.AuditLog"`
+	const documents = `"^projects/project/databases/\\(default\\)/documents/users/[^/]+/orders/[^/]+$"`
+
+	testCases := map[string]struct {
+		eventTypes []string
+		want       string
+	}{
+		"created": {
+			eventTypes: []string{schemasv1.CloudFirestoreDocumentCreatedEventType},
+			want: prefix + ` AND ((protoPayload.request.writes.update.name=~` + documents +
+				` AND protoPayload.request.writes.currentDocument.exists=false))`,
+		},
+		"updated and deleted": {
+			eventTypes: []string{schemasv1.CloudFirestoreDocumentUpdatedEventType, schemasv1.CloudFirestoreDocumentDeletedEventType},
+			want: prefix + ` AND ((protoPayload.request.writes.update.name=~` + documents +
+				` AND NOT protoPayload.request.writes.currentDocument.exists=false) OR protoPayload.request.writes.delete=~` + documents + `)`,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := GenerateFilter(&v1.CloudFirestoreSource{
+				Spec: v1.CloudFirestoreSourceSpec{
+					Database:   schemasv1.CloudFirestoreDefaultDatabase,
+					Collection: "users/*/orders",
+					EventTypes: tc.eventTypes,
+				},
+				Status: v1.CloudFirestoreSourceStatus{
+					PubSubStatus: duckv1.PubSubStatus{
+						ProjectID: "project",
+					},
+				},
+			})
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocumentPattern(t *testing.T) {
+	re := regexp.MustCompile(DocumentPattern("project", "(default)", "users/*/orders"))
+	testCases := map[string]bool{
+		"projects/project/databases/(default)/documents/users/alice/orders/1":         true,
+		"projects/project/databases/(default)/documents/users/alice/orders":           false,
+		"projects/project/databases/(default)/documents/users/alice/orders/1/items/2": false,
+		"projects/project/databases/(default)/documents/users/alice/invoices/1":       false,
+		"projects/project/databases/other/documents/users/alice/orders/1":             false,
+		"projects/other-project/databases/(default)/documents/users/alice/orders/1":   false,
+	}
+	for name, want := range testCases {
+		if got := re.MatchString(name); got != want {
+			t.Errorf("MatchString(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
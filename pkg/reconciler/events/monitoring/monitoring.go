@@ -0,0 +1,208 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/reconciler"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	cloudmonitoringalertsourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudmonitoringalertsource"
+	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1"
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring"
+	"github.com/google/knative-gcp/pkg/reconciler/events/monitoring/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+const (
+	resourceGroup = "cloudmonitoringalertsources.events.cloud.google.com"
+
+	// pubsubChannelType is the type of the Cloud Monitoring notification
+	// channels that publish incidents to a Pub/Sub topic.
+	pubsubChannelType = "pubsub"
+	// topicLabel is the label holding the topic of a pubsub notification channel.
+	topicLabel = "topic"
+
+	deleteNotificationChannelFailed = "NotificationChannelDeleteFailed"
+	deletePubSubFailed              = "PubSubDeleteFailed"
+	deleteWorkloadIdentityFailed    = "WorkloadIdentityDeleteFailed"
+	reconciledPubSubFailedReason    = "PubSubReconcileFailed"
+	reconciledFailedReason          = "NotificationChannelReconcileFailed"
+	reconciledSuccessReason         = "CloudMonitoringAlertSourceReconciled"
+	workloadIdentityFailed          = "WorkloadIdentityReconcileFailed"
+)
+
+// Reconciler is the controller implementation for Google Cloud Monitoring
+// alert notification channels.
+type Reconciler struct {
+	*intevents.PubSubBase
+	// identity reconciler for reconciling workload identity.
+	*identity.Identity
+	// monitoringLister for reading CloudMonitoringAlertSources.
+	monitoringLister listers.CloudMonitoringAlertSourceLister
+
+	// createClientFn is the function used to create the client that manages notification channels.
+	// This is needed so that we can inject a mock client for UTs purposes.
+	createClientFn gmonitoring.CreateFn
+}
+
+// Check that our Reconciler implements Interface.
+var _ cloudmonitoringalertsourcereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, source *v1.CloudMonitoringAlertSource) reconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("monitoring", source)))
+
+	source.Status.InitializeConditions()
+	source.Status.ObservedGeneration = source.Generation
+
+	// If ServiceAccountName is provided, reconcile workload identity.
+	if source.Spec.ServiceAccountName != "" {
+		if _, err := r.Identity.ReconcileWorkloadIdentity(ctx, source.Spec.Project, source); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, workloadIdentityFailed, "Failed to reconcile CloudMonitoringAlertSource workload identity: %s", err.Error())
+		}
+	}
+
+	topic := resources.GenerateTopicName(source)
+	_, _, err := r.PubSubBase.ReconcilePubSub(ctx, source, topic, resourceGroup)
+	if err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledPubSubFailedReason, "Reconcile PubSub failed with: %s", err.Error())
+	}
+
+	channel, err := r.reconcileNotificationChannel(ctx, source, topic)
+	if err != nil {
+		source.Status.MarkNotificationChannelNotReady(reconciledFailedReason, "Failed to reconcile CloudMonitoringAlertSource notification channel: %s", err.Error())
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile NotificationChannel failed with: %s", err.Error())
+	}
+	source.Status.MarkNotificationChannelReady(channel)
+	return reconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudMonitoringAlertSource reconciled: "%s/%s"`, source.Namespace, source.Name)
+}
+
+// reconcileNotificationChannel makes sure a pubsub notification channel
+// publishing to topic exists and returns its name. The name of a notification
+// channel is assigned by Cloud Monitoring, so the one created previously is
+// looked up from status.notificationChannel.
+func (r *Reconciler) reconcileNotificationChannel(ctx context.Context, source *v1.CloudMonitoringAlertSource, topic string) (string, error) {
+	if source.Status.ProjectID == "" {
+		projectID, err := utils.ProjectIDOrDefault(source.Spec.Project)
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to find project id", zap.Error(err))
+			return "", err
+		}
+		// Set the projectID in the status.
+		source.Status.ProjectID = projectID
+	}
+
+	client, err := r.createClientFn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudMonitoringAlertSource client", zap.Error(err))
+		return "", err
+	}
+	defer client.Close()
+
+	// Check if the notification channel exists.
+	if source.Status.NotificationChannel != "" {
+		channel, err := client.GetNotificationChannel(ctx, &monitoringpb.GetNotificationChannelRequest{Name: source.Status.NotificationChannel})
+		if err == nil {
+			return channel.Name, nil
+		}
+		if st, ok := gstatus.FromError(err); !ok || st.Code() != codes.NotFound {
+			logging.FromContext(ctx).Desugar().Error("Failed from CloudMonitoringAlertSource client while retrieving notification channel", zap.String("channel", source.Status.NotificationChannel), zap.Error(err))
+			return "", err
+		}
+	}
+
+	// Create the notification channel as it does not exist.
+	channel, err := client.CreateNotificationChannel(ctx, &monitoringpb.CreateNotificationChannelRequest{
+		Name: resources.GenerateProjectName(source),
+		NotificationChannel: &monitoringpb.NotificationChannel{
+			Type:        pubsubChannelType,
+			DisplayName: resources.GenerateDisplayName(source),
+			Labels: map[string]string{
+				topicLabel: resources.GenerateTopicPath(source, topic),
+			},
+		},
+	})
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudMonitoringAlertSource notification channel", zap.String("topic", topic), zap.Error(err))
+		return "", err
+	}
+	return channel.Name, nil
+}
+
+// deleteNotificationChannel looks at the status.NotificationChannel and if
+// non-empty, hence indicating that we have created a notification channel
+// successfully, remove it. Alert policies still referencing the channel do
+// not prevent the deletion.
+func (r *Reconciler) deleteNotificationChannel(ctx context.Context, source *v1.CloudMonitoringAlertSource) error {
+	if source.Status.NotificationChannel == "" {
+		return nil
+	}
+
+	client, err := r.createClientFn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudMonitoringAlertSource client", zap.Error(err))
+		source.Status.MarkNotificationChannelUnknown(deleteNotificationChannelFailed, "Failed to create CloudMonitoringAlertSource client: %s", err.Error())
+		return err
+	}
+	defer client.Close()
+
+	err = client.DeleteNotificationChannel(ctx, &monitoringpb.DeleteNotificationChannelRequest{
+		Name:  source.Status.NotificationChannel,
+		Force: true,
+	})
+	if err == nil {
+		logging.FromContext(ctx).Desugar().Debug("Deleted CloudMonitoringAlertSource notification channel", zap.String("channel", source.Status.NotificationChannel))
+		return nil
+	}
+	if st, ok := gstatus.FromError(err); !ok || st.Code() != codes.NotFound {
+		logging.FromContext(ctx).Desugar().Error("Failed to delete CloudMonitoringAlertSource notification channel", zap.String("channel", source.Status.NotificationChannel), zap.Error(err))
+		source.Status.MarkNotificationChannelUnknown(deleteNotificationChannelFailed, "Failed to delete CloudMonitoringAlertSource notification channel: %s", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (r *Reconciler) FinalizeKind(ctx context.Context, source *v1.CloudMonitoringAlertSource) reconciler.Event {
+	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
+	// remove the corresponding GCP ServiceAccount iam policy binding.
+	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
+	if source.Spec.ServiceAccountName != "" {
+		if err := r.Identity.DeleteWorkloadIdentity(ctx, source.Spec.Project, source); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, deleteWorkloadIdentityFailed, "Failed to delete CloudMonitoringAlertSource workload identity: %s", err.Error())
+		}
+	}
+
+	logging.FromContext(ctx).Desugar().Debug("Deleting CloudMonitoringAlertSource notification channel")
+	if err := r.deleteNotificationChannel(ctx, source); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deleteNotificationChannelFailed, "Failed to delete CloudMonitoringAlertSource notification channel: %s", err.Error())
+	}
+
+	if err := r.PubSubBase.DeletePubSub(ctx, source); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deletePubSubFailed, "Failed to delete CloudMonitoringAlertSource PubSub: %s", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,580 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	reconcilertestingv1 "github.com/google/knative-gcp/pkg/reconciler/testing/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	. "knative.dev/pkg/reconciler/testing"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	monitoringv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	. "github.com/google/knative-gcp/pkg/apis/intevents"
+	inteventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
+	"github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudmonitoringalertsource"
+	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring/testing"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+)
+
+const (
+	monitoringName = "my-test-monitoring"
+	monitoringUID  = "test-monitoring-uid"
+	sinkName       = "sink"
+
+	testNS       = "testnamespace"
+	testProject  = "test-project-id"
+	testTopicURI = "http://" + monitoringName + "-topic." + testNS + ".svc.cluster.local"
+	channelName  = "projects/" + testProject + "/notificationChannels/" + gmonitoring.TestNotificationChannelID
+
+	// Message for when the topic and pullsubscription with the above variables are not ready.
+	failedToReconcileTopicMsg            = `Topic has not yet been reconciled`
+	failedToReconcileChannelMsg          = `Failed to reconcile CloudMonitoringAlertSource notification channel`
+	failedToDeleteNotificationChannelMsg = `Failed to delete CloudMonitoringAlertSource notification channel`
+)
+
+var (
+	trueVal  = true
+	falseVal = false
+
+	sinkDNS = sinkName + ".mynamespace.svc.cluster.local"
+	sinkURI = apis.HTTP(sinkDNS)
+
+	testTopicID = fmt.Sprintf("cre-src_%s_%s_%s", testNS, monitoringName, monitoringUID)
+
+	sinkGVK = metav1.GroupVersionKind{
+		Group:   "testing.cloud.google.com",
+		Version: "v1",
+		Kind:    "Sink",
+	}
+
+	secret = corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: "google-cloud-key",
+		},
+		Key: "key.json",
+	}
+)
+
+func init() {
+	// Add types to scheme
+	_ = monitoringv1.AddToScheme(scheme.Scheme)
+}
+
+// Returns an ownerref for the test CloudMonitoringAlertSource object
+func ownerRef() metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion:         "events.cloud.google.com/v1",
+		Kind:               "CloudMonitoringAlertSource",
+		Name:               monitoringName,
+		UID:                monitoringUID,
+		Controller:         &trueVal,
+		BlockOwnerDeletion: &trueVal,
+	}
+}
+
+func patchFinalizers(namespace, name string, add bool) clientgotesting.PatchActionImpl {
+	action := clientgotesting.PatchActionImpl{}
+	action.Name = name
+	action.Namespace = namespace
+	var fname string
+	if add {
+		fname = fmt.Sprintf("%q", resourceGroup)
+	}
+	patch := `{"metadata":{"finalizers":[` + fname + `],"resourceVersion":""}}`
+	action.Patch = []byte(patch)
+	return action
+}
+
+func newSink() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "testing.cloud.google.com/v1",
+			"kind":       "Sink",
+			"metadata": map[string]interface{}{
+				"namespace": testNS,
+				"name":      sinkName,
+			},
+			"status": map[string]interface{}{
+				"address": map[string]interface{}{
+					"hostname": sinkDNS,
+				},
+			},
+		},
+	}
+}
+
+func newSinkDestination() duckv1.Destination {
+	return duckv1.Destination{
+		Ref: &duckv1.KReference{
+			APIVersion: "testing.cloud.google.com/v1",
+			Kind:       "Sink",
+			Name:       sinkName,
+		},
+	}
+}
+
+func newReadyTopic() *inteventsv1.Topic {
+	return reconcilertestingv1.NewTopic(monitoringName, testNS,
+		reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+			Topic:             testTopicID,
+			PropagationPolicy: "CreateDelete",
+			Project:           testProject,
+			EnablePublisher:   &falseVal,
+		}),
+		reconcilertestingv1.WithTopicReady(testTopicID),
+		reconcilertestingv1.WithTopicAddress(testTopicURI),
+		reconcilertestingv1.WithTopicProjectID(testProject),
+		reconcilertestingv1.WithTopicSetDefaults,
+	)
+}
+
+func newReadyPullSubscription() *inteventsv1.PullSubscription {
+	return reconcilertestingv1.NewPullSubscription(monitoringName, testNS,
+		reconcilertestingv1.WithPullSubscriptionReady(sinkURI),
+		reconcilertestingv1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+			Topic: testTopicID,
+			PubSubSpec: gcpduckv1.PubSubSpec{
+				Secret: &secret,
+				SourceSpec: duckv1.SourceSpec{
+					Sink: newSinkDestination(),
+				},
+				Project: testProject,
+			},
+			AdapterType: string(converters.CloudMonitoring),
+		}),
+	)
+}
+
+func TestAllCases(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		// Make sure Reconcile handles bad keys.
+		Key: "too/many/parts",
+	}, {
+		Name: "key not found",
+		// Make sure Reconcile handles good keys that don't exist.
+		Key: "foo/not-found",
+	}, {
+		Name: "topic created, not ready",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceAnnotations(map[string]string{
+					duck.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+			newSink(),
+		},
+		Key: testNS + "/" + monitoringName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudMonitoringAlertSourceConditions,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceAnnotations(map[string]string{
+					duck.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceTopicUnknown("TopicNotConfigured", failedToReconcileTopicMsg),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+		}},
+		WantCreates: []runtime.Object{
+			reconcilertestingv1.NewTopic(monitoringName, testNS,
+				reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+					Topic:             testTopicID,
+					PropagationPolicy: "CreateDelete",
+					EnablePublisher:   &falseVal,
+				}),
+				reconcilertestingv1.WithTopicLabels(map[string]string{
+					"receive-adapter": receiveAdapterName,
+					SourceLabelKey:    monitoringName,
+				}),
+				reconcilertestingv1.WithTopicAnnotations(map[string]string{
+					duck.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+				reconcilertestingv1.WithTopicOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+				reconcilertestingv1.WithTopicSetDefaults,
+			),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, monitoringName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", monitoringName),
+			Eventf(corev1.EventTypeWarning, reconciledPubSubFailedReason, "Reconcile PubSub failed with: Topic %q has not yet been reconciled", monitoringName),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, create client fails",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				CreateClientErr: errors.New("create-client-induced-error"),
+			},
+		},
+		Key: testNS + "/" + monitoringName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudMonitoringAlertSourceConditions,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelNotReady(reconciledFailedReason, fmt.Sprintf("%s: %s", failedToReconcileChannelMsg, "create-client-induced-error")),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, monitoringName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", monitoringName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile NotificationChannel failed with: create-client-induced-error"),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, create notification channel fails",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				CreateNotificationChannelErr: gstatus.Error(codes.PermissionDenied, "create-channel-induced-error"),
+			},
+		},
+		Key: testNS + "/" + monitoringName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudMonitoringAlertSourceConditions,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelNotReady(reconciledFailedReason,
+					fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToReconcileChannelMsg, codes.PermissionDenied, "create-channel-induced-error")),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, monitoringName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", monitoringName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile NotificationChannel failed with: rpc error: code = %s desc = %s", codes.PermissionDenied, "create-channel-induced-error"),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, notification channel created",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key: testNS + "/" + monitoringName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudMonitoringAlertSourceConditions,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, monitoringName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", monitoringName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudMonitoringAlertSource reconciled: "%s/%s"`, testNS, monitoringName),
+		},
+	}, {
+		Name: "notification channel exists",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannel(channelName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				// The existing channel must not be recreated.
+				CreateNotificationChannelErr: errors.New("create-channel-induced-error"),
+			},
+		},
+		Key: testNS + "/" + monitoringName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudMonitoringAlertSourceConditions,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, monitoringName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", monitoringName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudMonitoringAlertSource reconciled: "%s/%s"`, testNS, monitoringName),
+		},
+	}, {
+		Name: "notification channel exists, get notification channel fails with grpc unknown error",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannel(channelName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				GetNotificationChannelErr: gstatus.Error(codes.Unknown, "get-channel-induced-error"),
+			},
+		},
+		Key: testNS + "/" + monitoringName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannel(channelName),
+				reconcilertestingv1.WithInitCloudMonitoringAlertSourceConditions,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelNotReady(reconciledFailedReason,
+					fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToReconcileChannelMsg, codes.Unknown, "get-channel-induced-error")),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, monitoringName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", monitoringName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile NotificationChannel failed with: rpc error: code = %s desc = %s", codes.Unknown, "get-channel-induced-error"),
+		},
+	}, {
+		Name: "notification channel deleted out of band, notification channel recreated",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannel("projects/"+testProject+"/notificationChannels/deleted"),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				GetNotificationChannelErr: gstatus.Error(codes.NotFound, "get-channel-induced-error"),
+			},
+		},
+		Key: testNS + "/" + monitoringName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudMonitoringAlertSourceConditions,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, monitoringName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", monitoringName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudMonitoringAlertSource reconciled: "%s/%s"`, testNS, monitoringName),
+		},
+	}, {
+		Name: "notification channel fails to delete with Unknown grpc error",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudMonitoringAlertSourceConditions,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceDeletionTimestamp,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key: testNS + "/" + monitoringName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudMonitoringAlertSourceConditions,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannel(channelName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelUnknown(deleteNotificationChannelFailed,
+					fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToDeleteNotificationChannelMsg, codes.Unknown, "delete-channel-induced-error")),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceDeletionTimestamp,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+		}},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				DeleteNotificationChannelErr: gstatus.Error(codes.Unknown, "delete-channel-induced-error"),
+			},
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, deleteNotificationChannelFailed, fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToDeleteNotificationChannelMsg, codes.Unknown, "delete-channel-induced-error")),
+		},
+	}, {
+		Name: "notification channel successfully deleted with NotFound grpc error",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudMonitoringAlertSourceConditions,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceDeletionTimestamp,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key: testNS + "/" + monitoringName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudMonitoringAlertSource(monitoringName, testNS,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudMonitoringAlertSourceConditions,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelDeleted(channelName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceTopicDeleted,
+				reconcilertestingv1.WithCloudMonitoringAlertSourcePullSubscriptionDeleted,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceDeletionTimestamp,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+			),
+		}},
+		WantDeletes: []clientgotesting.DeleteActionImpl{
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "topics"}},
+				Name: monitoringName,
+			},
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "pullsubscriptions"}},
+				Name: monitoringName,
+			},
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				DeleteNotificationChannelErr: gstatus.Error(codes.NotFound, "delete-channel-induced-error"),
+			},
+		},
+	}}
+
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, testData map[string]interface{}) controller.Reconciler {
+		r := &Reconciler{
+			PubSubBase: intevents.NewPubSubBase(ctx,
+				&intevents.PubSubBaseArgs{
+					ControllerAgentName: controllerAgentName,
+					ReceiveAdapterName:  receiveAdapterName,
+					ReceiveAdapterType:  string(converters.CloudMonitoring),
+					ConfigWatcher:       cmw,
+				}),
+			Identity:         identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+			monitoringLister: listers.GetCloudMonitoringAlertSourceLister(),
+			createClientFn:   gmonitoring.TestClientCreator(testData["monitoring"]),
+		}
+		return cloudmonitoringalertsource.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetCloudMonitoringAlertSourceLister(), r.Recorder, r)
+	}))
+}
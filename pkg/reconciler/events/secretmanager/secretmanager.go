@@ -0,0 +1,217 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+	"google.golang.org/api/googleapi"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/reconciler"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	cloudsecretmanagersourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudsecretmanagersource"
+	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1"
+	gsecretmanager "github.com/google/knative-gcp/pkg/gclient/secretmanager"
+	"github.com/google/knative-gcp/pkg/reconciler/events/secretmanager/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+const (
+	resourceGroup = "cloudsecretmanagersources.events.cloud.google.com"
+
+	deleteSecretsFailed          = "SecretsDeleteFailed"
+	deletePubSubFailed           = "PubSubDeleteFailed"
+	deleteWorkloadIdentityFailed = "WorkloadIdentityDeleteFailed"
+	reconciledPubSubFailedReason = "PubSubReconcileFailed"
+	reconciledFailedReason       = "SecretsReconcileFailed"
+	reconciledSuccessReason      = "CloudSecretManagerSourceReconciled"
+	workloadIdentityFailed       = "WorkloadIdentityReconcileFailed"
+)
+
+// Reconciler is the controller implementation for Secret Manager secret
+// events.
+type Reconciler struct {
+	*intevents.PubSubBase
+	// identity reconciler for reconciling workload identity.
+	*identity.Identity
+	// secretManagerLister for reading CloudSecretManagerSources.
+	secretManagerLister listers.CloudSecretManagerSourceLister
+
+	// createClientFn is the function used to create the client that manages secrets.
+	// This is needed so that we can inject a mock client for UTs purposes.
+	createClientFn gsecretmanager.CreateFn
+}
+
+// Check that our Reconciler implements Interface.
+var _ cloudsecretmanagersourcereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, source *v1.CloudSecretManagerSource) reconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("secretmanager", source)))
+
+	source.Status.InitializeConditions()
+	source.Status.ObservedGeneration = source.Generation
+
+	// If ServiceAccountName is provided, reconcile workload identity.
+	if source.Spec.ServiceAccountName != "" {
+		if _, err := r.Identity.ReconcileWorkloadIdentity(ctx, source.Spec.Project, source); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, workloadIdentityFailed, "Failed to reconcile CloudSecretManagerSource workload identity: %s", err.Error())
+		}
+	}
+
+	topic := resources.GenerateTopicName(source)
+	_, _, err := r.PubSubBase.ReconcilePubSub(ctx, source, topic, resourceGroup)
+	if err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledPubSubFailedReason, "Reconcile PubSub failed with: %s", err.Error())
+	}
+
+	if err := r.reconcileSecrets(ctx, source, topic); err != nil {
+		source.Status.MarkSecretsNotReady(reconciledFailedReason, "Failed to reconcile CloudSecretManagerSource secrets: %s", err.Error())
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile Secrets failed with: %s", err.Error())
+	}
+	source.Status.MarkSecretsReady()
+	return reconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudSecretManagerSource reconciled: "%s/%s"`, source.Namespace, source.Name)
+}
+
+// reconcileSecrets makes sure all the secrets publish their events to topic.
+// The topics of the secrets that are not managed by this source are kept.
+func (r *Reconciler) reconcileSecrets(ctx context.Context, source *v1.CloudSecretManagerSource, topic string) error {
+	if source.Status.ProjectID == "" {
+		projectID, err := utils.ProjectIDOrDefault(source.Spec.Project)
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to find project id", zap.Error(err))
+			return err
+		}
+		// Set the projectID in the status.
+		source.Status.ProjectID = projectID
+	}
+
+	client, err := r.createClientFn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudSecretManagerSource client", zap.Error(err))
+		return err
+	}
+	defer client.Close()
+
+	topicPath := resources.GenerateTopicPath(source, topic)
+	for _, s := range source.Spec.Secrets {
+		name := resources.GenerateSecretName(source, s)
+		secret, err := client.GetSecret(ctx, name)
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed from CloudSecretManagerSource client while retrieving secret", zap.String("secret", name), zap.Error(err))
+			return fmt.Errorf("failed to get secret %q: %w", s, err)
+		}
+		if _, found := removeTopic(secret.Topics, topicPath); found {
+			continue
+		}
+
+		secret.Topics = append(secret.Topics, &gsecretmanager.Topic{Name: topicPath})
+		if _, err := client.UpdateSecretTopics(ctx, secret); err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to add topic to CloudSecretManagerSource secret", zap.String("secret", name), zap.String("topic", topic), zap.Error(err))
+			return fmt.Errorf("failed to add topic to secret %q: %w", s, err)
+		}
+	}
+	return nil
+}
+
+// deleteSecrets removes the topic from the topics of all the secrets. Secrets
+// that have already been deleted are skipped.
+func (r *Reconciler) deleteSecrets(ctx context.Context, source *v1.CloudSecretManagerSource) error {
+	// The project is only known once the secrets have been reconciled.
+	if source.Status.ProjectID == "" {
+		return nil
+	}
+
+	client, err := r.createClientFn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudSecretManagerSource client", zap.Error(err))
+		source.Status.MarkSecretsUnknown(deleteSecretsFailed, "Failed to create CloudSecretManagerSource client: %s", err.Error())
+		return err
+	}
+	defer client.Close()
+
+	topicPath := resources.GenerateTopicPath(source, resources.GenerateTopicName(source))
+	for _, s := range source.Spec.Secrets {
+		name := resources.GenerateSecretName(source, s)
+		secret, err := client.GetSecret(ctx, name)
+		if err != nil {
+			// If the secret was already deleted, then we should proceed.
+			var gerr *googleapi.Error
+			if errors.As(err, &gerr) && gerr.Code == http.StatusNotFound {
+				logging.FromContext(ctx).Desugar().Info("Secret does not exist.", zap.String("secret", name))
+				continue
+			}
+			logging.FromContext(ctx).Desugar().Error("Failed from CloudSecretManagerSource client while retrieving secret", zap.String("secret", name), zap.Error(err))
+			source.Status.MarkSecretsUnknown(deleteSecretsFailed, "Failed to get secret %q: %s", s, err.Error())
+			return fmt.Errorf("failed to get secret %q: %w", s, err)
+		}
+
+		topics, found := removeTopic(secret.Topics, topicPath)
+		if !found {
+			continue
+		}
+		secret.Topics = topics
+		if _, err := client.UpdateSecretTopics(ctx, secret); err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to remove topic from CloudSecretManagerSource secret", zap.String("secret", name), zap.Error(err))
+			source.Status.MarkSecretsUnknown(deleteSecretsFailed, "Failed to remove topic from secret %q: %s", s, err.Error())
+			return fmt.Errorf("failed to remove topic from secret %q: %w", s, err)
+		}
+		logging.FromContext(ctx).Desugar().Debug("Removed topic from secret", zap.String("secret", name))
+	}
+	return nil
+}
+
+// removeTopic returns topics without the topic named topicPath, and whether
+// it was found.
+func removeTopic(topics []*gsecretmanager.Topic, topicPath string) ([]*gsecretmanager.Topic, bool) {
+	kept := make([]*gsecretmanager.Topic, 0, len(topics))
+	for _, t := range topics {
+		if t.Name != topicPath {
+			kept = append(kept, t)
+		}
+	}
+	return kept, len(kept) != len(topics)
+}
+
+func (r *Reconciler) FinalizeKind(ctx context.Context, source *v1.CloudSecretManagerSource) reconciler.Event {
+	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
+	// remove the corresponding GCP ServiceAccount iam policy binding.
+	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
+	if source.Spec.ServiceAccountName != "" {
+		if err := r.Identity.DeleteWorkloadIdentity(ctx, source.Spec.Project, source); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, deleteWorkloadIdentityFailed, "Failed to delete CloudSecretManagerSource workload identity: %s", err.Error())
+		}
+	}
+
+	logging.FromContext(ctx).Desugar().Debug("Removing CloudSecretManagerSource topic from secrets")
+	if err := r.deleteSecrets(ctx, source); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deleteSecretsFailed, "Failed to remove CloudSecretManagerSource topic from secrets: %s", err.Error())
+	}
+
+	if err := r.PubSubBase.DeletePubSub(ctx, source); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deletePubSubFailed, "Failed to delete CloudSecretManagerSource PubSub: %s", err.Error())
+	}
+
+	return nil
+}
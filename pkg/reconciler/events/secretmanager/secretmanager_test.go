@@ -0,0 +1,610 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	reconcilertestingv1 "github.com/google/knative-gcp/pkg/reconciler/testing/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	. "knative.dev/pkg/reconciler/testing"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	secretmanagerv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	. "github.com/google/knative-gcp/pkg/apis/intevents"
+	inteventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
+	"github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudsecretmanagersource"
+	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
+	gsecretmanager "github.com/google/knative-gcp/pkg/gclient/secretmanager/testing"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	secretManagerName = "my-test-secretmanager"
+	secretManagerUID  = "test-secretmanager-uid"
+	sinkName          = "sink"
+
+	testNS         = "testnamespace"
+	testProject    = "test-project-id"
+	testSecret     = "my-secret"
+	otherSecret    = "my-other-secret"
+	testTopicURI   = "http://" + secretManagerName + "-topic." + testNS + ".svc.cluster.local"
+	otherTopicPath = "projects/" + testProject + "/topics/other"
+
+	// Message for when the topic and pullsubscription with the above variables are not ready.
+	failedToReconcileTopicMsg   = `Topic has not yet been reconciled`
+	failedToReconcileSecretsMsg = `Failed to reconcile CloudSecretManagerSource secrets`
+)
+
+var (
+	trueVal  = true
+	falseVal = false
+
+	sinkDNS = sinkName + ".mynamespace.svc.cluster.local"
+	sinkURI = apis.HTTP(sinkDNS)
+
+	testTopicID   = fmt.Sprintf("cre-src_%s_%s_%s", testNS, secretManagerName, secretManagerUID)
+	testTopicPath = fmt.Sprintf("projects/%s/topics/%s", testProject, testTopicID)
+
+	notFoundErr = &googleapi.Error{Code: http.StatusNotFound, Message: "get-secret-induced-error"}
+
+	sinkGVK = metav1.GroupVersionKind{
+		Group:   "testing.cloud.google.com",
+		Version: "v1",
+		Kind:    "Sink",
+	}
+
+	secret = corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: "google-cloud-key",
+		},
+		Key: "key.json",
+	}
+)
+
+func init() {
+	// Add types to scheme
+	_ = secretmanagerv1.AddToScheme(scheme.Scheme)
+}
+
+// Returns an ownerref for the test CloudSecretManagerSource object
+func ownerRef() metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion:         "events.cloud.google.com/v1",
+		Kind:               "CloudSecretManagerSource",
+		Name:               secretManagerName,
+		UID:                secretManagerUID,
+		Controller:         &trueVal,
+		BlockOwnerDeletion: &trueVal,
+	}
+}
+
+func patchFinalizers(namespace, name string, add bool) clientgotesting.PatchActionImpl {
+	action := clientgotesting.PatchActionImpl{}
+	action.Name = name
+	action.Namespace = namespace
+	var fname string
+	if add {
+		fname = fmt.Sprintf("%q", resourceGroup)
+	}
+	patch := `{"metadata":{"finalizers":[` + fname + `],"resourceVersion":""}}`
+	action.Patch = []byte(patch)
+	return action
+}
+
+func newSink() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "testing.cloud.google.com/v1",
+			"kind":       "Sink",
+			"metadata": map[string]interface{}{
+				"namespace": testNS,
+				"name":      sinkName,
+			},
+			"status": map[string]interface{}{
+				"address": map[string]interface{}{
+					"hostname": sinkDNS,
+				},
+			},
+		},
+	}
+}
+
+func newSinkDestination() duckv1.Destination {
+	return duckv1.Destination{
+		Ref: &duckv1.KReference{
+			APIVersion: "testing.cloud.google.com/v1",
+			Kind:       "Sink",
+			Name:       sinkName,
+		},
+	}
+}
+
+func newReadyTopic() *inteventsv1.Topic {
+	return reconcilertestingv1.NewTopic(secretManagerName, testNS,
+		reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+			Topic:             testTopicID,
+			PropagationPolicy: "CreateDelete",
+			Project:           testProject,
+			EnablePublisher:   &falseVal,
+		}),
+		reconcilertestingv1.WithTopicReady(testTopicID),
+		reconcilertestingv1.WithTopicAddress(testTopicURI),
+		reconcilertestingv1.WithTopicProjectID(testProject),
+		reconcilertestingv1.WithTopicSetDefaults,
+	)
+}
+
+func newReadyPullSubscription() *inteventsv1.PullSubscription {
+	return reconcilertestingv1.NewPullSubscription(secretManagerName, testNS,
+		reconcilertestingv1.WithPullSubscriptionReady(sinkURI),
+		reconcilertestingv1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+			Topic: testTopicID,
+			PubSubSpec: gcpduckv1.PubSubSpec{
+				Secret: &secret,
+				SourceSpec: duckv1.SourceSpec{
+					Sink: newSinkDestination(),
+				},
+				Project: testProject,
+			},
+			AdapterType: string(converters.CloudSecretManager),
+		}),
+	)
+}
+
+func TestAllCases(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		// Make sure Reconcile handles bad keys.
+		Key: "too/many/parts",
+	}, {
+		Name: "key not found",
+		// Make sure Reconcile handles good keys that don't exist.
+		Key: "foo/not-found",
+	}, {
+		Name: "topic created, not ready",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudSecretManagerSourceAnnotations(map[string]string{
+					duck.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+			newSink(),
+		},
+		Key: testNS + "/" + secretManagerName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudSecretManagerSourceConditions,
+				reconcilertestingv1.WithCloudSecretManagerSourceAnnotations(map[string]string{
+					duck.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+				reconcilertestingv1.WithCloudSecretManagerSourceTopicUnknown("TopicNotConfigured", failedToReconcileTopicMsg),
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+		}},
+		WantCreates: []runtime.Object{
+			reconcilertestingv1.NewTopic(secretManagerName, testNS,
+				reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+					Topic:             testTopicID,
+					PropagationPolicy: "CreateDelete",
+					EnablePublisher:   &falseVal,
+				}),
+				reconcilertestingv1.WithTopicLabels(map[string]string{
+					"receive-adapter": receiveAdapterName,
+					SourceLabelKey:    secretManagerName,
+				}),
+				reconcilertestingv1.WithTopicAnnotations(map[string]string{
+					duck.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+				reconcilertestingv1.WithTopicOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+				reconcilertestingv1.WithTopicSetDefaults,
+			),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, secretManagerName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", secretManagerName),
+			Eventf(corev1.EventTypeWarning, reconciledPubSubFailedReason, "Reconcile PubSub failed with: Topic %q has not yet been reconciled", secretManagerName),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, create client fails",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceProject(testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"secretmanager": gsecretmanager.TestClientData{
+				CreateClientErr: errors.New("create-client-induced-error"),
+			},
+		},
+		Key: testNS + "/" + secretManagerName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceProject(testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudSecretManagerSourceConditions,
+				reconcilertestingv1.WithCloudSecretManagerSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudSecretManagerSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecretsNotReady(reconciledFailedReason, fmt.Sprintf("%s: %s", failedToReconcileSecretsMsg, "create-client-induced-error")),
+				reconcilertestingv1.WithCloudSecretManagerSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, secretManagerName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", secretManagerName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile Secrets failed with: create-client-induced-error"),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, secret not found",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceProject(testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"secretmanager": gsecretmanager.TestClientData{
+				GetSecretErr: notFoundErr,
+			},
+		},
+		Key: testNS + "/" + secretManagerName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceProject(testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudSecretManagerSourceConditions,
+				reconcilertestingv1.WithCloudSecretManagerSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudSecretManagerSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecretsNotReady(reconciledFailedReason, fmt.Sprintf("%s: failed to get secret %q: %s", failedToReconcileSecretsMsg, testSecret, notFoundErr)),
+				reconcilertestingv1.WithCloudSecretManagerSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, secretManagerName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", secretManagerName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile Secrets failed with: failed to get secret %q: %s", testSecret, notFoundErr),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, update secret fails",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceProject(testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"secretmanager": gsecretmanager.TestClientData{
+				UpdateSecretTopicsErr: errors.New("update-secret-induced-error"),
+			},
+		},
+		Key: testNS + "/" + secretManagerName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceProject(testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudSecretManagerSourceConditions,
+				reconcilertestingv1.WithCloudSecretManagerSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudSecretManagerSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecretsNotReady(reconciledFailedReason, fmt.Sprintf("%s: failed to add topic to secret %q: %s", failedToReconcileSecretsMsg, testSecret, "update-secret-induced-error")),
+				reconcilertestingv1.WithCloudSecretManagerSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, secretManagerName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", secretManagerName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile Secrets failed with: failed to add topic to secret %q: %s", testSecret, "update-secret-induced-error"),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, topic added to secrets",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceProject(testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"secretmanager": gsecretmanager.TestClientData{
+				// The topics of other sources are kept.
+				Topics: []string{otherTopicPath},
+			},
+		},
+		Key: testNS + "/" + secretManagerName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceProject(testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudSecretManagerSourceConditions,
+				reconcilertestingv1.WithCloudSecretManagerSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudSecretManagerSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecretsReady,
+				reconcilertestingv1.WithCloudSecretManagerSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, secretManagerName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", secretManagerName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudSecretManagerSource reconciled: "%s/%s"`, testNS, secretManagerName),
+		},
+	}, {
+		Name: "secrets already publish to topic",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceProject(testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"secretmanager": gsecretmanager.TestClientData{
+				Topics: []string{otherTopicPath, testTopicPath},
+				// The secrets must not be updated again.
+				UpdateSecretTopicsErr: errors.New("update-secret-induced-error"),
+			},
+		},
+		Key: testNS + "/" + secretManagerName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceProject(testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudSecretManagerSourceConditions,
+				reconcilertestingv1.WithCloudSecretManagerSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudSecretManagerSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecretsReady,
+				reconcilertestingv1.WithCloudSecretManagerSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, secretManagerName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", secretManagerName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudSecretManagerSource reconciled: "%s/%s"`, testNS, secretManagerName),
+		},
+	}, {
+		Name: "topic fails to be removed from secrets",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceProject(testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudSecretManagerSourceConditions,
+				reconcilertestingv1.WithCloudSecretManagerSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudSecretManagerSourceSecretsReady,
+				reconcilertestingv1.WithCloudSecretManagerSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudSecretManagerSourceDeletionTimestamp,
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key: testNS + "/" + secretManagerName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceProject(testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudSecretManagerSourceConditions,
+				reconcilertestingv1.WithCloudSecretManagerSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudSecretManagerSourceSecretsUnknown(deleteSecretsFailed, fmt.Sprintf("Failed to remove topic from secret %q: %s", testSecret, "update-secret-induced-error")),
+				reconcilertestingv1.WithCloudSecretManagerSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudSecretManagerSourceDeletionTimestamp,
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+		}},
+		OtherTestData: map[string]interface{}{
+			"secretmanager": gsecretmanager.TestClientData{
+				Topics:                []string{testTopicPath},
+				UpdateSecretTopicsErr: errors.New("update-secret-induced-error"),
+			},
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, deleteSecretsFailed, "Failed to remove CloudSecretManagerSource topic from secrets: failed to remove topic from secret %q: %s", testSecret, "update-secret-induced-error"),
+		},
+	}, {
+		Name: "topic successfully removed from secrets",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceProject(testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudSecretManagerSourceConditions,
+				reconcilertestingv1.WithCloudSecretManagerSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudSecretManagerSourceSecretsReady,
+				reconcilertestingv1.WithCloudSecretManagerSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudSecretManagerSourceDeletionTimestamp,
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key: testNS + "/" + secretManagerName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceProject(testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudSecretManagerSourceConditions,
+				reconcilertestingv1.WithCloudSecretManagerSourceSecretsDeleted,
+				reconcilertestingv1.WithCloudSecretManagerSourceTopicDeleted,
+				reconcilertestingv1.WithCloudSecretManagerSourcePullSubscriptionDeleted,
+				reconcilertestingv1.WithCloudSecretManagerSourceDeletionTimestamp,
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+		}},
+		WantDeletes: []clientgotesting.DeleteActionImpl{
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "topics"}},
+				Name: secretManagerName,
+			},
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "pullsubscriptions"}},
+				Name: secretManagerName,
+			},
+		},
+		OtherTestData: map[string]interface{}{
+			"secretmanager": gsecretmanager.TestClientData{
+				Topics: []string{otherTopicPath, testTopicPath},
+			},
+		},
+	}, {
+		Name: "secrets already deleted",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceProject(testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudSecretManagerSourceConditions,
+				reconcilertestingv1.WithCloudSecretManagerSourceTopicReady(testTopicID, testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourcePullSubscriptionReady,
+				reconcilertestingv1.WithCloudSecretManagerSourceSecretsReady,
+				reconcilertestingv1.WithCloudSecretManagerSourceSinkURI(sinkURI),
+				reconcilertestingv1.WithCloudSecretManagerSourceDeletionTimestamp,
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key: testNS + "/" + secretManagerName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudSecretManagerSource(secretManagerName, testNS,
+				reconcilertestingv1.WithCloudSecretManagerSourceProject(testProject),
+				reconcilertestingv1.WithCloudSecretManagerSourceSecrets(testSecret, otherSecret),
+				reconcilertestingv1.WithCloudSecretManagerSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithInitCloudSecretManagerSourceConditions,
+				reconcilertestingv1.WithCloudSecretManagerSourceSecretsDeleted,
+				reconcilertestingv1.WithCloudSecretManagerSourceTopicDeleted,
+				reconcilertestingv1.WithCloudSecretManagerSourcePullSubscriptionDeleted,
+				reconcilertestingv1.WithCloudSecretManagerSourceDeletionTimestamp,
+				reconcilertestingv1.WithCloudSecretManagerSourceSetDefaults,
+			),
+		}},
+		WantDeletes: []clientgotesting.DeleteActionImpl{
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "topics"}},
+				Name: secretManagerName,
+			},
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "pullsubscriptions"}},
+				Name: secretManagerName,
+			},
+		},
+		OtherTestData: map[string]interface{}{
+			"secretmanager": gsecretmanager.TestClientData{
+				GetSecretErr: notFoundErr,
+			},
+		},
+	}}
+
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, testData map[string]interface{}) controller.Reconciler {
+		r := &Reconciler{
+			PubSubBase: intevents.NewPubSubBase(ctx,
+				&intevents.PubSubBaseArgs{
+					ControllerAgentName: controllerAgentName,
+					ReceiveAdapterName:  receiveAdapterName,
+					ReceiveAdapterType:  string(converters.CloudSecretManager),
+					ConfigWatcher:       cmw,
+				}),
+			Identity:            identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+			secretManagerLister: listers.GetCloudSecretManagerSourceLister(),
+			createClientFn:      gsecretmanager.TestClientCreator(testData["secretmanager"]),
+		}
+		return cloudsecretmanagersource.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetCloudSecretManagerSourceLister(), r.Recorder, r)
+	}))
+}
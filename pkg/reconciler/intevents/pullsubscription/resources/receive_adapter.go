@@ -161,6 +161,36 @@ func makeReceiveAdapterPodSpec(ctx context.Context, args *ReceiveAdapterArgs) *c
 		}},
 	}
 
+	// Only sources that filter their events set an event filter.
+	if len(args.PullSubscription.Spec.EventFilter) > 0 {
+		eventFilter, err := utils.MapToBase64(args.PullSubscription.Spec.EventFilter)
+		if err != nil {
+			logging.FromContext(ctx).Warnw("failed to make event filter",
+				zap.Error(err),
+				zap.Any("eventFilter", args.PullSubscription.Spec.EventFilter))
+		} else {
+			receiveAdapterContainer.Env = append(receiveAdapterContainer.Env, corev1.EnvVar{
+				Name:  "K_EVENT_FILTER",
+				Value: eventFilter,
+			})
+		}
+	}
+
+	// Only the converters which depend on the source have arguments.
+	if len(args.PullSubscription.Spec.ConverterArgs) > 0 {
+		converterArgs, err := utils.MapToBase64(args.PullSubscription.Spec.ConverterArgs)
+		if err != nil {
+			logging.FromContext(ctx).Warnw("failed to make converter arguments",
+				zap.Error(err),
+				zap.Any("converterArgs", args.PullSubscription.Spec.ConverterArgs))
+		} else {
+			receiveAdapterContainer.Env = append(receiveAdapterContainer.Env, corev1.EnvVar{
+				Name:  "K_CONVERTER_ARGS",
+				Value: converterArgs,
+			})
+		}
+	}
+
 	// This is added purely for the TestCloudLogging E2E tests, which verify that the log line is
 	// written certain annotations are present.
 	receiveAdapterContainer.Env = testloggingutil.PropagateLoggingE2ETestAnnotation(
@@ -221,6 +221,12 @@ func TestMakeFullReceiveAdapter(t *testing.T) {
 			},
 			Topic:       "topic",
 			AdapterType: string(converters.PubSubPull),
+			EventFilter: map[string]string{
+				"tag": "latest", // base64 value is eyJ0YWciOiJsYXRlc3QifQ==
+			},
+			ConverterArgs: map[string]string{
+				"arg": "value", // base64 value is eyJhcmciOiJ2YWx1ZSJ9
+			},
 		},
 	}
 
@@ -329,6 +335,12 @@ func TestMakeFullReceiveAdapter(t *testing.T) {
 						}, {
 							Name:  "METRICS_DOMAIN",
 							Value: metricsDomain,
+						}, {
+							Name:  "K_EVENT_FILTER",
+							Value: "eyJ0YWciOiJsYXRlc3QifQ==",
+						}, {
+							Name:  "K_CONVERTER_ARGS",
+							Value: "eyJhcmciOiJ2YWx1ZSJ9",
 						}, {
 							Name:  "GOOGLE_APPLICATION_CREDENTIALS",
 							Value: "/var/secrets/google/eventing-secret-key",
@@ -155,6 +155,13 @@ func (psb *PubSubBase) ReconcilePullSubscription(ctx context.Context, pubsubable
 		Annotations: resources.GetAnnotations(annotations, resourceGroup),
 	}
 
+	if filterable, ok := pubsubable.(duck.EventFilterable); ok {
+		args.EventFilter = filterable.EventFilter()
+	}
+	if configurable, ok := pubsubable.(duck.ConverterConfigurable); ok {
+		args.ConverterArgs = configurable.ConverterArgs()
+	}
+
 	if v, present := pubsubable.GetObjectMeta().GetAnnotations()[testloggingutil.LoggingE2ETestAnnotation]; present {
 		// This is added purely for the TestCloudLogging E2E tests, which verify that the log line
 		// is written if this annotation is present.
@@ -26,14 +26,16 @@ import (
 )
 
 type PullSubscriptionArgs struct {
-	Namespace   string
-	Name        string
-	Spec        *gcpduckv1.PubSubSpec
-	Owner       kmeta.OwnerRefable
-	Topic       string
-	AdapterType string
-	Labels      map[string]string
-	Annotations map[string]string
+	Namespace     string
+	Name          string
+	Spec          *gcpduckv1.PubSubSpec
+	Owner         kmeta.OwnerRefable
+	Topic         string
+	AdapterType   string
+	EventFilter   map[string]string
+	ConverterArgs map[string]string
+	Labels        map[string]string
+	Annotations   map[string]string
 }
 
 // MakePullSubscription creates the spec for, but does not create, a GCP PullSubscription
@@ -58,8 +60,10 @@ func MakePullSubscription(args *PullSubscriptionArgs) *inteventsv1.PullSubscript
 					Sink: args.Spec.SourceSpec.Sink,
 				},
 			},
-			Topic:       args.Topic,
-			AdapterType: args.AdapterType,
+			Topic:         args.Topic,
+			AdapterType:   args.AdapterType,
+			EventFilter:   args.EventFilter,
+			ConverterArgs: args.ConverterArgs,
 		},
 	}
 	if args.Spec.CloudEventOverrides != nil && args.Spec.CloudEventOverrides.Extensions != nil {
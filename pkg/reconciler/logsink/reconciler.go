@@ -0,0 +1,140 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logsink reconciles the Stackdriver sinks which publish log entries
+// to the topic of a source.
+package logsink
+
+import (
+	"context"
+
+	"cloud.google.com/go/logging/logadmin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"knative.dev/pkg/logging"
+
+	glogadmin "github.com/google/knative-gcp/pkg/gclient/logging/logadmin"
+	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
+)
+
+const (
+	publisherRole = "roles/pubsub.publisher"
+
+	deleteSinkFailed = "SinkDeleteFailed"
+)
+
+// Status is the status of a source whose events are read from the log
+// entries published by its sink.
+type Status interface {
+	MarkSinkNotReady(reason, messageFormat string, messageA ...interface{})
+	MarkSinkUnknown(reason, messageFormat string, messageA ...interface{})
+}
+
+// Reconciler reconciles the Stackdriver sinks of the sources.
+type Reconciler struct {
+	logadminClientProvider glogadmin.CreateFn
+	pubsubClientProvider   gpubsub.CreateFn
+}
+
+// NewReconciler creates a Reconciler whose clients are created with the given
+// providers.
+func NewReconciler(logadminClientProvider glogadmin.CreateFn, pubsubClientProvider gpubsub.CreateFn) *Reconciler {
+	return &Reconciler{
+		logadminClientProvider: logadminClientProvider,
+		pubsubClientProvider:   pubsubClientProvider,
+	}
+}
+
+// ReconcileSink ensures that the sink exists in the project, or creates it,
+// and that it has been granted the pubsub.publisher role on the topic. An
+// existing sink is not updated. It returns the ID of the sink, and marks the
+// status as not ready on failure.
+func (r *Reconciler) ReconcileSink(ctx context.Context, project, topicID string, sink *logadmin.Sink, s Status) (string, error) {
+	sink, err := r.ensureSinkCreated(ctx, project, sink)
+	if err != nil {
+		s.MarkSinkNotReady("SinkCreateFailed", "failed to ensure creation of logging sink: %s", err.Error())
+		return "", err
+	}
+	err = r.ensureSinkIsPublisher(ctx, project, topicID, sink)
+	if err != nil {
+		s.MarkSinkNotReady("SinkNotPublisher", "failed to ensure sink has pubsub.publisher permission on source topic: %s", err.Error())
+		return "", err
+	}
+	return sink.ID, nil
+}
+
+func (r *Reconciler) ensureSinkCreated(ctx context.Context, project string, sink *logadmin.Sink) (*logadmin.Sink, error) {
+	logadminClient, err := r.logadminClientProvider(ctx, project)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create LogAdmin client", zap.Error(err))
+		return nil, err
+	}
+	existing, err := logadminClient.Sink(ctx, sink.ID)
+	if status.Code(err) == codes.NotFound {
+		existing, err = logadminClient.CreateSinkOpt(ctx, sink, logadmin.SinkOptions{UniqueWriterIdentity: true})
+		// Handle AlreadyExists in-case of a race between another create call.
+		if status.Code(err) == codes.AlreadyExists {
+			existing, err = logadminClient.Sink(ctx, sink.ID)
+		}
+	}
+	return existing, err
+}
+
+// Ensures that the sink has been granted the pubsub.publisher role on the source topic.
+func (r *Reconciler) ensureSinkIsPublisher(ctx context.Context, project, topicID string, sink *logadmin.Sink) error {
+	pubsubClient, err := r.pubsubClientProvider(ctx, project)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create PubSub client", zap.Error(err))
+		return err
+	}
+	topicIam := pubsubClient.Topic(topicID).IAM()
+	topicPolicy, err := topicIam.Policy(ctx)
+	if err != nil {
+		return err
+	}
+	if !topicPolicy.HasRole(sink.WriterIdentity, publisherRole) {
+		topicPolicy.Add(sink.WriterIdentity, publisherRole)
+		if err = topicIam.SetPolicy(ctx, topicPolicy); err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Desugar().Debug(
+			"Granted the Stackdriver Sink writer identity roles/pubsub.publisher on PubSub Topic.",
+			zap.String("writerIdentity", sink.WriterIdentity),
+			zap.String("topicID", topicID))
+	}
+	return nil
+}
+
+// DeleteSink deletes the sink from the project if sinkID is non-empty, and
+// marks the status as unknown on failure.
+func (r *Reconciler) DeleteSink(ctx context.Context, project, sinkID string, s Status) error {
+	if sinkID == "" {
+		return nil
+	}
+	logadminClient, err := r.logadminClientProvider(ctx, project)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create LogAdmin client", zap.Error(err))
+		s.MarkSinkUnknown(deleteSinkFailed, "Failed to create LogAdmin Client: %s", err.Error())
+		return err
+	}
+	if err = logadminClient.DeleteSink(ctx, sinkID); err != nil && status.Code(err) != codes.NotFound {
+		s.MarkSinkUnknown(deleteSinkFailed, "Failed to delete Stackdriver sink: %s", err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logsink
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/logging/logadmin"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	testiam "github.com/google/knative-gcp/pkg/gclient/iam/testing"
+	glogadmin "github.com/google/knative-gcp/pkg/gclient/logging/logadmin"
+	glogadmintesting "github.com/google/knative-gcp/pkg/gclient/logging/logadmin/testing"
+	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub/testing"
+)
+
+const (
+	testProject = "test-project"
+	testTopicID = "test-topic"
+	testSinkID  = "test-sink"
+)
+
+func newTestSink(filter string) *logadmin.Sink {
+	return &logadmin.Sink{
+		ID:          testSinkID,
+		Destination: "pubsub.googleapis.com/projects/test-project/topics/test-topic",
+		Filter:      filter,
+	}
+}
+
+type testStatus struct {
+	notReady string
+	unknown  string
+}
+
+func (s *testStatus) MarkSinkNotReady(reason, messageFormat string, messageA ...interface{}) {
+	s.notReady = reason
+}
+
+func (s *testStatus) MarkSinkUnknown(reason, messageFormat string, messageA ...interface{}) {
+	s.unknown = reason
+}
+
+func TestReconcileSink(t *testing.T) {
+	tests := []struct {
+		name         string
+		logadmin     glogadmintesting.TestClientConfiguration
+		pubsub       gpubsub.TestClientData
+		existing     *logadmin.Sink
+		wantNotReady string
+		wantSink     *logadmin.Sink
+	}{{
+		name:     "sink created",
+		wantSink: newTestSink("filter"),
+	}, {
+		name:     "sink exists",
+		existing: newTestSink("existing-filter"),
+		wantSink: newTestSink("existing-filter"),
+	}, {
+		name:         "logging client create fails",
+		logadmin:     glogadmintesting.TestClientConfiguration{CreateClientErr: errors.New("create-client-induced-error")},
+		wantNotReady: "SinkCreateFailed",
+	}, {
+		name:         "get sink fails",
+		logadmin:     glogadmintesting.TestClientConfiguration{SinkErr: errors.New("get-sink-induced-error")},
+		wantNotReady: "SinkCreateFailed",
+	}, {
+		name:         "create sink fails",
+		logadmin:     glogadmintesting.TestClientConfiguration{CreateSinkErr: errors.New("create-sink-induced-error")},
+		wantNotReady: "SinkCreateFailed",
+	}, {
+		name:         "pubsub client create fails",
+		pubsub:       gpubsub.TestClientData{CreateClientErr: errors.New("create-client-induced-error")},
+		wantNotReady: "SinkNotPublisher",
+		wantSink:     newTestSink("filter"),
+	}, {
+		name: "get pubsub IAM policy fails",
+		pubsub: gpubsub.TestClientData{
+			HandleData: testiam.TestHandleData{PolicyErr: errors.New("policy-induced-error")},
+		},
+		wantNotReady: "SinkNotPublisher",
+		wantSink:     newTestSink("filter"),
+	}, {
+		name: "set pubsub IAM policy fails",
+		pubsub: gpubsub.TestClientData{
+			HandleData: testiam.TestHandleData{SetPolicyErr: errors.New("set-policy-induced-error")},
+		},
+		wantNotReady: "SinkNotPublisher",
+		wantSink:     newTestSink("filter"),
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			logadminClientProvider := glogadmintesting.TestClientCreator(tc.logadmin)
+			if tc.existing != nil {
+				createSink(t, logadminClientProvider, tc.existing)
+			}
+			r := NewReconciler(logadminClientProvider, gpubsub.TestClientCreator(tc.pubsub))
+			var s testStatus
+			gotID, err := r.ReconcileSink(ctx, testProject, testTopicID, newTestSink("filter"), &s)
+			if (err != nil) != (tc.wantNotReady != "") {
+				t.Errorf("ReconcileSink got error=%v, want error=%v", err, tc.wantNotReady != "")
+			}
+			if s.notReady != tc.wantNotReady {
+				t.Errorf("MarkSinkNotReady reason got=%q, want=%q", s.notReady, tc.wantNotReady)
+			}
+			if tc.wantNotReady == "" && gotID != testSinkID {
+				t.Errorf("ReconcileSink got ID=%q, want=%q", gotID, testSinkID)
+			}
+			if tc.wantSink != nil {
+				expectSink(t, logadminClientProvider, tc.wantSink)
+			}
+		})
+	}
+}
+
+func TestDeleteSink(t *testing.T) {
+	tests := []struct {
+		name        string
+		logadmin    glogadmintesting.TestClientConfiguration
+		sinkID      string
+		wantUnknown string
+		wantDeleted bool
+	}{{
+		name: "no sink",
+	}, {
+		name:        "sink deleted",
+		sinkID:      testSinkID,
+		wantDeleted: true,
+	}, {
+		name:   "sink does not exist",
+		sinkID: "other-sink",
+	}, {
+		name:        "logging client create fails",
+		logadmin:    glogadmintesting.TestClientConfiguration{CreateClientErr: errors.New("create-client-induced-error")},
+		sinkID:      testSinkID,
+		wantUnknown: "SinkDeleteFailed",
+	}, {
+		name:        "delete sink fails",
+		logadmin:    glogadmintesting.TestClientConfiguration{DeleteSinkErr: errors.New("delete-sink-induced-error")},
+		sinkID:      testSinkID,
+		wantUnknown: "SinkDeleteFailed",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			logadminClientProvider := glogadmintesting.TestClientCreator(tc.logadmin)
+			if tc.logadmin.CreateClientErr == nil {
+				createSink(t, logadminClientProvider, newTestSink("filter"))
+			}
+			r := NewReconciler(logadminClientProvider, gpubsub.TestClientCreator(nil))
+			var s testStatus
+			err := r.DeleteSink(ctx, testProject, tc.sinkID, &s)
+			if (err != nil) != (tc.wantUnknown != "") {
+				t.Errorf("DeleteSink got error=%v, want error=%v", err, tc.wantUnknown != "")
+			}
+			if s.unknown != tc.wantUnknown {
+				t.Errorf("MarkSinkUnknown reason got=%q, want=%q", s.unknown, tc.wantUnknown)
+			}
+			if tc.wantDeleted {
+				expectSink(t, logadminClientProvider, nil)
+			}
+		})
+	}
+}
+
+func createSink(t *testing.T, clientProvider glogadmin.CreateFn, sink *logadmin.Sink) {
+	t.Helper()
+	logadminClient, err := clientProvider(context.Background(), testProject)
+	if err != nil {
+		t.Fatalf("failed to create logadmin client during setup: %s", err)
+	}
+	if _, err := logadminClient.CreateSinkOpt(context.Background(), sink, logadmin.SinkOptions{}); err != nil {
+		t.Fatalf("failed to create sink during setup: %s", err)
+	}
+}
+
+func expectSink(t *testing.T, clientProvider glogadmin.CreateFn, want *logadmin.Sink) {
+	t.Helper()
+	logadminClient, err := clientProvider(context.Background(), testProject)
+	if err != nil {
+		t.Fatalf("failed to create logadmin client during verification: %s", err)
+	}
+	got, err := logadminClient.Sink(context.Background(), testSinkID)
+	if err != nil && !(status.Code(err) == codes.NotFound && want == nil) {
+		t.Errorf("failed to get expected sink %s: %v", testSinkID, err)
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(logadmin.Sink{}, "WriterIdentity")); diff != "" {
+		t.Errorf("unexpected sink (-want, +got) = %v", diff)
+	}
+}
@@ -169,6 +169,42 @@ func WithBrokerClass(bc string) BrokerOption {
 	}
 }
 
+func WithBrokerOrderedDelivery(orderingKeyAttribute string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 2)
+		}
+		annotations[brokerv1beta1.OrderedDeliveryAnnotation] = "true"
+		annotations[brokerv1beta1.OrderingKeyAttributeAnnotation] = orderingKeyAttribute
+		b.SetAnnotations(annotations)
+	}
+}
+
+// WithBrokerDeduplicationWindow sets the Broker's deduplication window annotation.
+func WithBrokerDeduplicationWindow(window string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[brokerv1beta1.DeduplicationWindowAnnotation] = window
+		b.SetAnnotations(annotations)
+	}
+}
+
+// WithBrokerAllowedSenders sets the Broker's allowed senders annotation.
+func WithBrokerAllowedSenders(senders string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[brokerv1beta1.AllowedSendersAnnotation] = senders
+		b.SetAnnotations(annotations)
+	}
+}
+
 func WithBrokerSetDefaults(b *brokerv1beta1.Broker) {
 	b.SetDefaults(context.Background())
 }
@@ -179,3 +215,15 @@ func WithBrokerDeliverySpec(deliverySpec *eventingduckv1beta1.DeliverySpec) Brok
 		b.Spec.Delivery = deliverySpec
 	}
 }
+
+// WithBrokerTransformer sets the Broker's transformer annotation.
+func WithBrokerTransformer(transformer string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[brokerv1beta1.TransformerAnnotation] = transformer
+		b.SetAnnotations(annotations)
+	}
+}
@@ -101,6 +101,11 @@ func MakeFactory(ctor Ctor) Factory {
 			return ValidateCreates(ctx, action)
 		})
 		client.PrependReactor("update", "*", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+			// The Triggers aren't validated by our webhook, so a Trigger with invalid annotations
+			// can be reconciled and its status updated.
+			if action.GetResource().Resource == "triggers" && action.GetSubresource() == "status" {
+				return false, nil, nil
+			}
 			return ValidateUpdates(ctx, action)
 		})
 
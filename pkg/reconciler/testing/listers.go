@@ -154,6 +154,26 @@ func (l *Listers) GetCloudBuildSourceLister() eventslisters.CloudBuildSourceList
 	return eventslisters.NewCloudBuildSourceLister(l.indexerFor(&EventsV1.CloudBuildSource{}))
 }
 
+func (l *Listers) GetCloudContainerRegistrySourceLister() eventslisters.CloudContainerRegistrySourceLister {
+	return eventslisters.NewCloudContainerRegistrySourceLister(l.indexerFor(&EventsV1.CloudContainerRegistrySource{}))
+}
+
+func (l *Listers) GetCloudFirestoreSourceLister() eventslisters.CloudFirestoreSourceLister {
+	return eventslisters.NewCloudFirestoreSourceLister(l.indexerFor(&EventsV1.CloudFirestoreSource{}))
+}
+
+func (l *Listers) GetCloudMonitoringAlertSourceLister() eventslisters.CloudMonitoringAlertSourceLister {
+	return eventslisters.NewCloudMonitoringAlertSourceLister(l.indexerFor(&EventsV1.CloudMonitoringAlertSource{}))
+}
+
+func (l *Listers) GetCloudBillingBudgetSourceLister() eventslisters.CloudBillingBudgetSourceLister {
+	return eventslisters.NewCloudBillingBudgetSourceLister(l.indexerFor(&EventsV1.CloudBillingBudgetSource{}))
+}
+
+func (l *Listers) GetCloudSecretManagerSourceLister() eventslisters.CloudSecretManagerSourceLister {
+	return eventslisters.NewCloudSecretManagerSourceLister(l.indexerFor(&EventsV1.CloudSecretManagerSource{}))
+}
+
 func (l *Listers) GetDeploymentLister() appsv1listers.DeploymentLister {
 	return appsv1listers.NewDeploymentLister(l.indexerFor(&appsv1.Deployment{}))
 }
@@ -190,6 +210,10 @@ func (l *Listers) GetConfigMapLister() corev1listers.ConfigMapLister {
 	return corev1listers.NewConfigMapLister(l.indexerFor(&corev1.ConfigMap{}))
 }
 
+func (l *Listers) GetSecretLister() corev1listers.SecretLister {
+	return corev1listers.NewSecretLister(l.indexerFor(&corev1.Secret{}))
+}
+
 func (l *Listers) GetBrokerLister() brokerlisters.BrokerLister {
 	return brokerlisters.NewBrokerLister(l.indexerFor(&brokerv1beta1.Broker{}))
 }
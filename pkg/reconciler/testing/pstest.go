@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"cloud.google.com/go/pubsub/pstest"
@@ -145,6 +146,34 @@ func SubscriptionHasRetryPolicy(id string, wantPolicy *pubsub.RetryPolicy) func(
 	}
 }
 
+func SubscriptionHasMessageOrdering(id string, want bool) func(*testing.T, *rtesting.TableRow) {
+	return func(t *testing.T, r *rtesting.TableRow) {
+		c := getPubsubClient(r)
+		sub := c.Subscription(id)
+		cfg, err := sub.Config(context.Background())
+		if err != nil {
+			t.Errorf("Error getting pubsub config: %v", err)
+		}
+		if cfg.EnableMessageOrdering != want {
+			t.Errorf("Pubsub config message ordering got=%v, want=%v", cfg.EnableMessageOrdering, want)
+		}
+	}
+}
+
+func SubscriptionHasRetention(id string, wantRetention time.Duration, wantRetainAcked bool) func(*testing.T, *rtesting.TableRow) {
+	return func(t *testing.T, r *rtesting.TableRow) {
+		c := getPubsubClient(r)
+		sub := c.Subscription(id)
+		cfg, err := sub.Config(context.Background())
+		if err != nil {
+			t.Errorf("Error getting pubsub config: %v", err)
+		}
+		if cfg.RetentionDuration != wantRetention || cfg.RetainAckedMessages != wantRetainAcked {
+			t.Errorf("Pubsub config retention got=(%v, %v), want=(%v, %v)", cfg.RetentionDuration, cfg.RetainAckedMessages, wantRetention, wantRetainAcked)
+		}
+	}
+}
+
 func SubscriptionHasDeadLetterPolicy(id string, wantPolicy *pubsub.DeadLetterPolicy) func(*testing.T, *rtesting.TableRow) {
 	return func(t *testing.T, r *rtesting.TableRow) {
 		c := getPubsubClient(r)
@@ -151,6 +151,33 @@ func WithDependencyAnnotation(dependencyAnnotation string) TriggerOption {
 	}
 }
 
+func WithTriggerFiltersAnnotation(filters string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.FiltersAnnotation] = filters
+	}
+}
+
+func WithTriggerCELFilterAnnotation(expr string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.CELFilterAnnotation] = expr
+	}
+}
+
+func WithTriggerDeliveryAnnotation(delivery string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.DeliveryAnnotation] = delivery
+	}
+}
+
 func WithTriggerDependencyReady(t *brokerv1beta1.Trigger) {
 	t.Status.MarkDependencySucceeded()
 }
@@ -187,6 +214,16 @@ func WithTriggerSubscriptionReady(t *brokerv1beta1.Trigger) {
 	t.Status.MarkSubscriptionReady()
 }
 
+func WithTriggerFilterValid(t *brokerv1beta1.Trigger) {
+	t.Status.MarkFilterValid()
+}
+
+func WithTriggerFilterFailed(reason, msg string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.MarkFilterFailed(reason, msg)
+	}
+}
+
 func WithTriggerTopicReady(t *brokerv1beta1.Trigger) {
 	t.Status.MarkTopicReady()
 }
@@ -223,3 +260,119 @@ func WithTriggerFinalizers(finalizers ...string) TriggerOption {
 func WithTriggerSetDefaults(t *brokerv1beta1.Trigger) {
 	t.SetDefaults(context.Background())
 }
+
+func WithTriggerOrderedDelivery(orderingKeyAttribute string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.OrderedDeliveryAnnotation] = "true"
+		t.Annotations[brokerv1beta1.OrderingKeyAttributeAnnotation] = orderingKeyAttribute
+	}
+}
+
+// WithTriggerReplay sets the replay annotations of the Trigger. An empty from only enables
+// replays.
+func WithTriggerReplay(retention, from string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.ReplayRetentionAnnotation] = retention
+		if from != "" {
+			t.Annotations[brokerv1beta1.ReplayFromAnnotation] = from
+		}
+	}
+}
+
+func WithTriggerReplayWindow(from, until time.Time) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.SetReplayWindow(from, until)
+	}
+}
+
+func WithTriggerReplayAvailable(t *brokerv1beta1.Trigger) {
+	t.Status.MarkReplayAvailable()
+}
+
+func WithTriggerReplayPending(from, until time.Time) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.MarkReplayPending(from, until)
+	}
+}
+
+func WithTriggerReplayStarted(from, until time.Time) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.MarkReplayStarted(from, until)
+	}
+}
+
+func WithTriggerCircuitBreakerOpen(pods int) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.MarkCircuitBreakerOpen(pods)
+	}
+}
+
+func WithTriggerCircuitBreakerHalfOpen(pods int) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.MarkCircuitBreakerHalfOpen(pods)
+	}
+}
+
+func WithTriggerCircuitBreakerClosed(t *brokerv1beta1.Trigger) {
+	t.Status.MarkCircuitBreakerClosed()
+}
+
+func WithTriggerDeliveryStatus(ds brokerv1beta1.DeliveryStatus) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.PropagateDeliveryStatus(ds)
+	}
+}
+
+func WithTriggerPaused(t *brokerv1beta1.Trigger) {
+	if t.Annotations == nil {
+		t.Annotations = make(map[string]string)
+	}
+	t.Annotations[brokerv1beta1.PausedAnnotation] = "true"
+}
+
+// WithTriggerReply sets the reply annotation of the trigger to the JSON reply spec.
+func WithTriggerReply(reply string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.ReplyAnnotation] = reply
+	}
+}
+
+// WithTriggerSubscriberAuth sets the subscriber auth annotation of the trigger to the JSON
+// subscriber auth spec.
+func WithTriggerSubscriberAuth(auth string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.SubscriberAuthAnnotation] = auth
+	}
+}
+
+// WithTriggerHeaders sets the headers annotation of the trigger to the JSON headers.
+func WithTriggerHeaders(headers string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.HeadersAnnotation] = headers
+	}
+}
+
+// WithTriggerExtensions sets the extensions annotation of the trigger to the JSON extensions.
+func WithTriggerExtensions(extensions string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.ExtensionsAnnotation] = extensions
+	}
+}
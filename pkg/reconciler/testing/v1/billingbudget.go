@@ -0,0 +1,225 @@
+/*
+Copyright 2020 Google LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"time"
+
+	"github.com/google/knative-gcp/pkg/reconciler/testing"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+)
+
+// CloudBillingBudgetSourceOption enables further configuration of a CloudBillingBudgetSource.
+type CloudBillingBudgetSourceOption func(*v1.CloudBillingBudgetSource)
+
+// NewCloudBillingBudgetSource creates a CloudBillingBudgetSource with CloudBillingBudgetSourceOptions
+func NewCloudBillingBudgetSource(name, namespace string, so ...CloudBillingBudgetSourceOption) *v1.CloudBillingBudgetSource {
+	s := &v1.CloudBillingBudgetSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       "test-billingbudget-uid",
+		},
+	}
+	for _, opt := range so {
+		opt(s)
+	}
+	return s
+}
+
+func WithCloudBillingBudgetSourceSink(gvk metav1.GroupVersionKind, name string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Spec.Sink = duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: testing.ApiVersion(gvk),
+				Kind:       gvk.Kind,
+				Name:       name,
+			},
+		}
+	}
+}
+
+func WithCloudBillingBudgetSourceProject(project string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Spec.Project = project
+	}
+}
+
+func WithCloudBillingBudgetSourceBillingAccount(billingAccount string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Spec.BillingAccount = billingAccount
+	}
+}
+
+func WithCloudBillingBudgetSourceBudget(budget string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Spec.Budget = budget
+	}
+}
+
+func WithCloudBillingBudgetSourceServiceAccount(kServiceAccount string) CloudBillingBudgetSourceOption {
+	return func(ps *v1.CloudBillingBudgetSource) {
+		ps.Spec.ServiceAccountName = kServiceAccount
+	}
+}
+
+func WithCloudBillingBudgetSourceDeletionTimestamp(s *v1.CloudBillingBudgetSource) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	s.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+// WithInitCloudBillingBudgetSourceConditions initializes the CloudBillingBudgetSources's conditions.
+func WithInitCloudBillingBudgetSourceConditions(s *v1.CloudBillingBudgetSource) {
+	s.Status.InitializeConditions()
+}
+
+func WithCloudBillingBudgetSourceWorkloadIdentityFailed(reason, message string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.MarkWorkloadIdentityFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudBillingBudgetSourceTopicFailed marks the condition that the
+// status of topic is False.
+func WithCloudBillingBudgetSourceTopicFailed(reason, message string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.MarkTopicFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudBillingBudgetSourceTopicUnknown marks the condition that the
+// status of topic is Unknown.
+func WithCloudBillingBudgetSourceTopicUnknown(reason, message string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.MarkTopicUnknown(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudBillingBudgetSourceTopicNotReady marks the condition that the
+// topic is not ready.
+func WithCloudBillingBudgetSourceTopicReady(topicID, projectID string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.MarkTopicReady(s.ConditionSet())
+		s.Status.TopicID = topicID
+		s.Status.ProjectID = projectID
+	}
+}
+
+// WithCloudBillingBudgetSourceTopicDeleted is a wrapper to indicate that the
+// topic is deleted. Inside the function, we still mark the status of topic to be ready,
+// as the status of topic is unchanged if the deletion is successful. We do not set the
+// topicID and projectID because they are set to empty when deleting the topic.
+func WithCloudBillingBudgetSourceTopicDeleted(s *v1.CloudBillingBudgetSource) {
+	s.Status.MarkTopicReady(s.ConditionSet())
+}
+
+// WithCloudBillingBudgetSourcePullSubscriptionFailed marks the condition that the
+// topic is False.
+func WithCloudBillingBudgetSourcePullSubscriptionFailed(reason, message string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.MarkPullSubscriptionFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudBillingBudgetSourcePullSubscriptionUnknown marks the condition that the
+// topic is Unknown.
+func WithCloudBillingBudgetSourcePullSubscriptionUnknown(reason, message string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.MarkPullSubscriptionUnknown(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudBillingBudgetSourcePullSubscriptionReady marks the condition that the
+// topic is ready.
+func WithCloudBillingBudgetSourcePullSubscriptionReady(s *v1.CloudBillingBudgetSource) {
+	s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+}
+
+// WithCloudBillingBudgetSourcePullSubscriptionDeleted is a wrapper to indicate that the
+// PullSubscription is deleted. Inside the function, we still mark the status of
+// PullSubscription to be ready, as the status of PullSubscription is unchanged
+// if the deletion is successful.
+func WithCloudBillingBudgetSourcePullSubscriptionDeleted(s *v1.CloudBillingBudgetSource) {
+	s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+}
+
+// WithCloudBillingBudgetSourceBudgetNotReady marks the condition that the
+// budget is not ready.
+func WithCloudBillingBudgetSourceBudgetNotReady(reason, message string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.MarkBudgetNotReady(reason, message)
+	}
+}
+
+// WithCloudBillingBudgetSourceBudgetUnknown marks the condition that the
+// budget is unknown.
+func WithCloudBillingBudgetSourceBudgetUnknown(reason, message string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.MarkBudgetUnknown(reason, message)
+	}
+}
+
+// WithCloudBillingBudgetSourceBudgetReady marks the condition that the
+// budget is ready.
+func WithCloudBillingBudgetSourceBudgetReady(s *v1.CloudBillingBudgetSource) {
+	s.Status.MarkBudgetReady()
+}
+
+// WithCloudBillingBudgetSourceBudgetDeleted is a wrapper to indicate that the
+// budget notification is deleted. Inside the function, we still mark the status
+// of the budget to be ready, as the status of the budget is unchanged if the
+// deletion is successful.
+func WithCloudBillingBudgetSourceBudgetDeleted(s *v1.CloudBillingBudgetSource) {
+	s.Status.MarkBudgetReady()
+}
+
+// WithCloudBillingBudgetSourceSinkURI sets the status for sink URI
+func WithCloudBillingBudgetSourceSinkURI(url *apis.URL) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.SinkURI = url
+	}
+}
+
+func WithCloudBillingBudgetSourceSubscriptionID(subscriptionID string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Status.SubscriptionID = subscriptionID
+	}
+}
+
+func WithCloudBillingBudgetSourceFinalizers(finalizers ...string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.Finalizers = finalizers
+	}
+}
+
+func WithCloudBillingBudgetSourceAnnotations(Annotations map[string]string) CloudBillingBudgetSourceOption {
+	return func(s *v1.CloudBillingBudgetSource) {
+		s.ObjectMeta.Annotations = Annotations
+	}
+}
+
+func WithCloudBillingBudgetSourceSetDefaults(s *v1.CloudBillingBudgetSource) {
+	s.SetDefaults(gcpauthtesthelper.ContextWithDefaults())
+}
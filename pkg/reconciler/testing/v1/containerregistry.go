@@ -0,0 +1,167 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"time"
+
+	"github.com/google/knative-gcp/pkg/reconciler/testing"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+)
+
+// CloudContainerRegistrySourceOption enables further configuration of a CloudContainerRegistrySource.
+type CloudContainerRegistrySourceOption func(*v1.CloudContainerRegistrySource)
+
+// NewCloudContainerRegistrySource creates a CloudContainerRegistrySource with CloudContainerRegistrySourceOptions
+func NewCloudContainerRegistrySource(name, namespace string, so ...CloudContainerRegistrySourceOption) *v1.CloudContainerRegistrySource {
+	s := &v1.CloudContainerRegistrySource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       "test-containerregistry-uid",
+		},
+	}
+	for _, opt := range so {
+		opt(s)
+	}
+	return s
+}
+
+func WithCloudContainerRegistrySourceSink(gvk metav1.GroupVersionKind, name string) CloudContainerRegistrySourceOption {
+	return func(s *v1.CloudContainerRegistrySource) {
+		s.Spec.Sink = duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: testing.ApiVersion(gvk),
+				Kind:       gvk.Kind,
+				Name:       name,
+			},
+		}
+	}
+}
+
+func WithCloudContainerRegistrySourceDeletionTimestamp(s *v1.CloudContainerRegistrySource) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	s.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+func WithCloudContainerRegistrySourceProject(project string) CloudContainerRegistrySourceOption {
+	return func(s *v1.CloudContainerRegistrySource) {
+		s.Spec.Project = project
+	}
+}
+
+func WithCloudContainerRegistrySourceServiceAccount(kServiceAccount string) CloudContainerRegistrySourceOption {
+	return func(s *v1.CloudContainerRegistrySource) {
+		s.Spec.ServiceAccountName = kServiceAccount
+	}
+}
+
+func WithCloudContainerRegistrySourceRepository(repository string) CloudContainerRegistrySourceOption {
+	return func(s *v1.CloudContainerRegistrySource) {
+		s.Spec.Repository = repository
+	}
+}
+
+func WithCloudContainerRegistrySourceTag(tag string) CloudContainerRegistrySourceOption {
+	return func(s *v1.CloudContainerRegistrySource) {
+		s.Spec.Tag = tag
+	}
+}
+
+// WithInitCloudContainerRegistrySourceConditions initializes the CloudContainerRegistrySource's conditions.
+func WithInitCloudContainerRegistrySourceConditions(s *v1.CloudContainerRegistrySource) {
+	s.Status.InitializeConditions()
+}
+
+func WithCloudContainerRegistrySourceWorkloadIdentityFailed(reason, message string) CloudContainerRegistrySourceOption {
+	return func(s *v1.CloudContainerRegistrySource) {
+		s.Status.MarkWorkloadIdentityFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudContainerRegistrySourcePullSubscriptionFailed marks the condition that the
+// status of PullSubscription is False
+func WithCloudContainerRegistrySourcePullSubscriptionFailed(reason, message string) CloudContainerRegistrySourceOption {
+	return func(s *v1.CloudContainerRegistrySource) {
+		s.Status.MarkPullSubscriptionFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudContainerRegistrySourcePullSubscriptionUnknown marks the condition that the
+// topic is Unknown
+func WithCloudContainerRegistrySourcePullSubscriptionUnknown(reason, message string) CloudContainerRegistrySourceOption {
+	return func(s *v1.CloudContainerRegistrySource) {
+		s.Status.MarkPullSubscriptionUnknown(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudContainerRegistrySourcePullSubscriptionReady marks the condition that the
+// topic is not ready
+func WithCloudContainerRegistrySourcePullSubscriptionReady() CloudContainerRegistrySourceOption {
+	return func(s *v1.CloudContainerRegistrySource) {
+		s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+	}
+}
+
+// WithCloudContainerRegistrySourceSinkURI sets the status for sink URI
+func WithCloudContainerRegistrySourceSinkURI(url *apis.URL) CloudContainerRegistrySourceOption {
+	return func(s *v1.CloudContainerRegistrySource) {
+		s.Status.SinkURI = url
+	}
+}
+
+func WithCloudContainerRegistrySourceSubscriptionID(subscriptionID string) CloudContainerRegistrySourceOption {
+	return func(s *v1.CloudContainerRegistrySource) {
+		s.Status.SubscriptionID = subscriptionID
+	}
+}
+
+func WithCloudContainerRegistrySourceFinalizers(finalizers ...string) CloudContainerRegistrySourceOption {
+	return func(s *v1.CloudContainerRegistrySource) {
+		s.Finalizers = finalizers
+	}
+}
+
+func WithCloudContainerRegistrySourceStatusObservedGeneration(generation int64) CloudContainerRegistrySourceOption {
+	return func(s *v1.CloudContainerRegistrySource) {
+		s.Status.Status.ObservedGeneration = generation
+	}
+}
+
+func WithCloudContainerRegistrySourceObjectMetaGeneration(generation int64) CloudContainerRegistrySourceOption {
+	return func(s *v1.CloudContainerRegistrySource) {
+		s.ObjectMeta.Generation = generation
+	}
+}
+
+func WithCloudContainerRegistrySourceAnnotations(Annotations map[string]string) CloudContainerRegistrySourceOption {
+	return func(s *v1.CloudContainerRegistrySource) {
+		s.ObjectMeta.Annotations = Annotations
+	}
+}
+
+func WithCloudContainerRegistrySourceSetDefault(s *v1.CloudContainerRegistrySource) {
+	s.SetDefaults(gcpauthtesthelper.ContextWithDefaults())
+}
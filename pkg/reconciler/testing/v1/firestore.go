@@ -0,0 +1,214 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"time"
+
+	"github.com/google/knative-gcp/pkg/reconciler/testing"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+type CloudFirestoreSourceOption func(*v1.CloudFirestoreSource)
+
+func NewCloudFirestoreSource(name, namespace string, opts ...CloudFirestoreSourceOption) *v1.CloudFirestoreSource {
+	fs := &v1.CloudFirestoreSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	for _, opt := range opts {
+		opt(fs)
+	}
+	return fs
+}
+
+// WithInitCloudFirestoreSourceConditions initializes the CloudFirestoreSource's conditions.
+func WithInitCloudFirestoreSourceConditions(s *v1.CloudFirestoreSource) {
+	s.Status.InitializeConditions()
+}
+
+// WithCloudFirestoreSourceUID sets the CloudFirestoreSource's ObjectMeta.UID.
+func WithCloudFirestoreSourceUID(uid string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.ObjectMeta.UID = types.UID(uid)
+	}
+}
+
+// WithCloudFirestoreSourceWorkloadIdentityFailed marks the condition that the
+// WorkloadIdentity is False.
+func WithCloudFirestoreSourceWorkloadIdentityFailed(reason, message string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Status.MarkWorkloadIdentityFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudFirestoreSourceTopicUnknown marks the condition that the
+// topic is Unknown.
+func WithCloudFirestoreSourceTopicUnknown(reason, message string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Status.MarkTopicUnknown(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudFirestoreSourceTopicUnknown marks the condition that the
+// topic is Ready.
+func WithCloudFirestoreSourceTopicReady(topicID string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Status.MarkTopicReady(s.ConditionSet())
+		s.Status.TopicID = topicID
+	}
+}
+
+// WithCloudFirestoreSourceTopicDeleted is a wrapper to indicate that the
+// topic is deleted. Inside the function, we still mark the status of topic to be ready,
+// as the status of topic is unchanged if the deletion is successful. We do not set the
+// topicID because the topicID is set to empty when deleting the topic.
+func WithCloudFirestoreSourceTopicDeleted(s *v1.CloudFirestoreSource) {
+	s.Status.MarkTopicReady(s.ConditionSet())
+}
+
+// WithCloudFirestoreSourcePullSubscriptionUnknown marks the condition that the
+// PullSubscription is Unknown.
+func WithCloudFirestoreSourcePullSubscriptionUnknown(reason, message string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Status.MarkPullSubscriptionUnknown(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudFirestoreSourcePullSubscriptionReady marks the condition that the
+// PullSubscription is Ready.
+func WithCloudFirestoreSourcePullSubscriptionReady(s *v1.CloudFirestoreSource) {
+	s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+}
+
+// WithCloudFirestoreSourcePullSubscriptionDeleted is a wrapper to indicate that the
+// PullSubscription is deleted. Inside the function, we still mark the status of
+// PullSubscription to be ready, as the status of PullSubscription is unchanged
+// if the deletion is successful.
+func WithCloudFirestoreSourcePullSubscriptionDeleted(s *v1.CloudFirestoreSource) {
+	s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+}
+
+// WithCloudFirestoreSourceSinkReady marks the condition that the
+// sink is ready.
+func WithCloudFirestoreSourceSinkReady(s *v1.CloudFirestoreSource) {
+	s.Status.MarkSinkReady()
+}
+
+// WithCloudFirestoreSourceSinkDeleted is a wrapper to indicate that the
+// sink is deleted. Inside the function, we still mark the status of sink to be ready,
+// as the status of sink is unchanged if the deletion is successful.
+func WithCloudFirestoreSourceSinkDeleted(s *v1.CloudFirestoreSource) {
+	s.Status.MarkSinkReady()
+}
+
+func WithCloudFirestoreSourceSink(gvk metav1.GroupVersionKind, name string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Spec.Sink = duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: testing.ApiVersion(gvk),
+				Kind:       gvk.Kind,
+				Name:       name,
+			},
+		}
+	}
+}
+
+func WithCloudFirestoreSourceSinkURI(url *apis.URL) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Status.SinkURI = url
+	}
+}
+
+func WithCloudFirestoreSourceProjectID(projectID string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Status.ProjectID = projectID
+	}
+}
+
+func WithCloudFirestoreSourceSubscriptionID(subscriptionID string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Status.SubscriptionID = subscriptionID
+	}
+}
+
+func WithCloudFirestoreSourceSinkID(sinkID string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Status.StackdriverSink = sinkID
+	}
+}
+
+func WithCloudFirestoreSourceProject(project string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Spec.Project = project
+	}
+}
+
+func WithCloudFirestoreSourceDatabase(database string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Spec.Database = database
+	}
+}
+
+func WithCloudFirestoreSourceCollection(collection string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Spec.Collection = collection
+	}
+}
+
+func WithCloudFirestoreSourceEventTypes(eventTypes ...string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Spec.EventTypes = eventTypes
+	}
+}
+
+func WithCloudFirestoreSourceServiceAccount(kServiceAccount string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Spec.ServiceAccountName = kServiceAccount
+	}
+}
+
+func WithCloudFirestoreSourceFinalizers(finalizers ...string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Finalizers = finalizers
+	}
+}
+
+func WithCloudFirestoreSourceDeletionTimestamp(s *v1.CloudFirestoreSource) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	s.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+func WithCloudFirestoreSourceAnnotations(Annotations map[string]string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.ObjectMeta.Annotations = Annotations
+	}
+}
+
+func WithCloudFirestoreSourceSetDefaults(s *v1.CloudFirestoreSource) {
+	s.SetDefaults(gcpauthtesthelper.ContextWithDefaults())
+}
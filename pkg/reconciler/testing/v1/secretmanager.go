@@ -0,0 +1,219 @@
+/*
+Copyright 2020 Google LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"time"
+
+	"github.com/google/knative-gcp/pkg/reconciler/testing"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+)
+
+// CloudSecretManagerSourceOption enables further configuration of a CloudSecretManagerSource.
+type CloudSecretManagerSourceOption func(*v1.CloudSecretManagerSource)
+
+// NewCloudSecretManagerSource creates a CloudSecretManagerSource with CloudSecretManagerSourceOptions
+func NewCloudSecretManagerSource(name, namespace string, so ...CloudSecretManagerSourceOption) *v1.CloudSecretManagerSource {
+	s := &v1.CloudSecretManagerSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       "test-secretmanager-uid",
+		},
+	}
+	for _, opt := range so {
+		opt(s)
+	}
+	return s
+}
+
+func WithCloudSecretManagerSourceSink(gvk metav1.GroupVersionKind, name string) CloudSecretManagerSourceOption {
+	return func(s *v1.CloudSecretManagerSource) {
+		s.Spec.Sink = duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: testing.ApiVersion(gvk),
+				Kind:       gvk.Kind,
+				Name:       name,
+			},
+		}
+	}
+}
+
+func WithCloudSecretManagerSourceProject(project string) CloudSecretManagerSourceOption {
+	return func(s *v1.CloudSecretManagerSource) {
+		s.Spec.Project = project
+	}
+}
+
+func WithCloudSecretManagerSourceSecrets(secrets ...string) CloudSecretManagerSourceOption {
+	return func(s *v1.CloudSecretManagerSource) {
+		s.Spec.Secrets = secrets
+	}
+}
+
+func WithCloudSecretManagerSourceServiceAccount(kServiceAccount string) CloudSecretManagerSourceOption {
+	return func(ps *v1.CloudSecretManagerSource) {
+		ps.Spec.ServiceAccountName = kServiceAccount
+	}
+}
+
+func WithCloudSecretManagerSourceDeletionTimestamp(s *v1.CloudSecretManagerSource) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	s.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+// WithInitCloudSecretManagerSourceConditions initializes the CloudSecretManagerSources's conditions.
+func WithInitCloudSecretManagerSourceConditions(s *v1.CloudSecretManagerSource) {
+	s.Status.InitializeConditions()
+}
+
+func WithCloudSecretManagerSourceWorkloadIdentityFailed(reason, message string) CloudSecretManagerSourceOption {
+	return func(s *v1.CloudSecretManagerSource) {
+		s.Status.MarkWorkloadIdentityFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudSecretManagerSourceTopicFailed marks the condition that the
+// status of topic is False.
+func WithCloudSecretManagerSourceTopicFailed(reason, message string) CloudSecretManagerSourceOption {
+	return func(s *v1.CloudSecretManagerSource) {
+		s.Status.MarkTopicFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudSecretManagerSourceTopicUnknown marks the condition that the
+// status of topic is Unknown.
+func WithCloudSecretManagerSourceTopicUnknown(reason, message string) CloudSecretManagerSourceOption {
+	return func(s *v1.CloudSecretManagerSource) {
+		s.Status.MarkTopicUnknown(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudSecretManagerSourceTopicNotReady marks the condition that the
+// topic is not ready.
+func WithCloudSecretManagerSourceTopicReady(topicID, projectID string) CloudSecretManagerSourceOption {
+	return func(s *v1.CloudSecretManagerSource) {
+		s.Status.MarkTopicReady(s.ConditionSet())
+		s.Status.TopicID = topicID
+		s.Status.ProjectID = projectID
+	}
+}
+
+// WithCloudSecretManagerSourceTopicDeleted is a wrapper to indicate that the
+// topic is deleted. Inside the function, we still mark the status of topic to be ready,
+// as the status of topic is unchanged if the deletion is successful. We do not set the
+// topicID and projectID because they are set to empty when deleting the topic.
+func WithCloudSecretManagerSourceTopicDeleted(s *v1.CloudSecretManagerSource) {
+	s.Status.MarkTopicReady(s.ConditionSet())
+}
+
+// WithCloudSecretManagerSourcePullSubscriptionFailed marks the condition that the
+// topic is False.
+func WithCloudSecretManagerSourcePullSubscriptionFailed(reason, message string) CloudSecretManagerSourceOption {
+	return func(s *v1.CloudSecretManagerSource) {
+		s.Status.MarkPullSubscriptionFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudSecretManagerSourcePullSubscriptionUnknown marks the condition that the
+// topic is Unknown.
+func WithCloudSecretManagerSourcePullSubscriptionUnknown(reason, message string) CloudSecretManagerSourceOption {
+	return func(s *v1.CloudSecretManagerSource) {
+		s.Status.MarkPullSubscriptionUnknown(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudSecretManagerSourcePullSubscriptionReady marks the condition that the
+// topic is ready.
+func WithCloudSecretManagerSourcePullSubscriptionReady(s *v1.CloudSecretManagerSource) {
+	s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+}
+
+// WithCloudSecretManagerSourcePullSubscriptionDeleted is a wrapper to indicate that the
+// PullSubscription is deleted. Inside the function, we still mark the status of
+// PullSubscription to be ready, as the status of PullSubscription is unchanged
+// if the deletion is successful.
+func WithCloudSecretManagerSourcePullSubscriptionDeleted(s *v1.CloudSecretManagerSource) {
+	s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+}
+
+// WithCloudSecretManagerSourceSecretsNotReady marks the condition that the
+// secrets are not ready.
+func WithCloudSecretManagerSourceSecretsNotReady(reason, message string) CloudSecretManagerSourceOption {
+	return func(s *v1.CloudSecretManagerSource) {
+		s.Status.MarkSecretsNotReady(reason, message)
+	}
+}
+
+// WithCloudSecretManagerSourceSecretsUnknown marks the condition that the
+// secrets are unknown.
+func WithCloudSecretManagerSourceSecretsUnknown(reason, message string) CloudSecretManagerSourceOption {
+	return func(s *v1.CloudSecretManagerSource) {
+		s.Status.MarkSecretsUnknown(reason, message)
+	}
+}
+
+// WithCloudSecretManagerSourceSecretsReady marks the condition that the
+// secrets are ready.
+func WithCloudSecretManagerSourceSecretsReady(s *v1.CloudSecretManagerSource) {
+	s.Status.MarkSecretsReady()
+}
+
+// WithCloudSecretManagerSourceSecretsDeleted is a wrapper to indicate that the
+// topic is removed from the secrets. Inside the function, we still mark the status
+// of the secrets to be ready, as the status of the secrets is unchanged if the
+// removal is successful.
+func WithCloudSecretManagerSourceSecretsDeleted(s *v1.CloudSecretManagerSource) {
+	s.Status.MarkSecretsReady()
+}
+
+// WithCloudSecretManagerSourceSinkURI sets the status for sink URI
+func WithCloudSecretManagerSourceSinkURI(url *apis.URL) CloudSecretManagerSourceOption {
+	return func(s *v1.CloudSecretManagerSource) {
+		s.Status.SinkURI = url
+	}
+}
+
+func WithCloudSecretManagerSourceSubscriptionID(subscriptionID string) CloudSecretManagerSourceOption {
+	return func(s *v1.CloudSecretManagerSource) {
+		s.Status.SubscriptionID = subscriptionID
+	}
+}
+
+func WithCloudSecretManagerSourceFinalizers(finalizers ...string) CloudSecretManagerSourceOption {
+	return func(s *v1.CloudSecretManagerSource) {
+		s.Finalizers = finalizers
+	}
+}
+
+func WithCloudSecretManagerSourceAnnotations(Annotations map[string]string) CloudSecretManagerSourceOption {
+	return func(s *v1.CloudSecretManagerSource) {
+		s.ObjectMeta.Annotations = Annotations
+	}
+}
+
+func WithCloudSecretManagerSourceSetDefaults(s *v1.CloudSecretManagerSource) {
+	s.SetDefaults(gcpauthtesthelper.ContextWithDefaults())
+}
@@ -30,6 +30,7 @@ import (
 	"knative.dev/eventing/pkg/duck"
 	"knative.dev/pkg/client/injection/ducks/duck/v1/addressable"
 	"knative.dev/pkg/client/injection/ducks/duck/v1/source"
+	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	pkgcontroller "knative.dev/pkg/controller"
@@ -94,12 +95,14 @@ func newController(ctx context.Context, cmw configmap.Watcher, drs *dataresidenc
 	r := &Reconciler{
 		Base:               reconciler.NewBase(ctx, controllerAgentName, cmw),
 		brokerLister:       brokerinformer.Get(ctx).Lister(),
+		configMapLister:    configmapinformer.Get(ctx).Lister(),
 		pubsubClient:       client,
 		projectID:          projectID,
 		dataresidencyStore: drs,
 	}
 
 	impl := triggerreconciler.NewImpl(ctx, r, withAgentAndFinalizer)
+	r.enqueueAfter = impl.EnqueueAfter
 	r.sourceTracker = duck.NewListableTracker(ctx, source.Get, impl.EnqueueKey, controller.GetTrackerLease(ctx))
 	r.addressableTracker = duck.NewListableTracker(ctx, addressable.Get, impl.EnqueueKey, controller.GetTrackerLease(ctx))
 	r.uriResolver = resolver.NewURIResolver(ctx, impl.EnqueueKey)
@@ -128,6 +131,9 @@ func newController(ctx context.Context, cmw configmap.Watcher, drs *dataresidenc
 		},
 	)
 
+	// Watch the status reports of the data plane pods.
+	configmapinformer.Get(ctx).Informer().AddEventHandler(statusReportHandler(impl.EnqueueKey))
+
 	return impl
 }
 
@@ -0,0 +1,170 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/handler/breaker"
+	"github.com/google/knative-gcp/pkg/broker/status"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// deliveryStatusRefreshPeriod is the period at which the delivery times of a Trigger are
+// refreshed. The status reports only trigger a reconciliation when the subscriber becomes
+// reachable or unreachable, or when its circuit breaker changes state.
+const deliveryStatusRefreshPeriod = 5 * time.Minute
+
+// statusReportSelector selects the ConfigMaps of the status reports of the data plane pods.
+var statusReportSelector = labels.SelectorFromSet(map[string]string{status.ReportLabelKey: "true"})
+
+// reconcileDeliveryStatus surfaces on the Trigger the status of its subscriber reported by
+// the data plane pods: the last successful and failed deliveries, the backlog of its retry
+// queue, and the state of the circuit breaker of the subscriber.
+func (r *Reconciler) reconcileDeliveryStatus(ctx context.Context, t *brokerv1beta1.Trigger) error {
+	cms, err := r.configMapLister.ConfigMaps(status.Namespace()).List(statusReportSelector)
+	if err != nil {
+		return err
+	}
+	// The last deliveries are kept when the pods which made them are gone, the backlog is
+	// only kept while it's reported.
+	ds := t.Status.GetDeliveryStatus()
+	ds.RetryBacklog = nil
+	var open, halfOpen int
+	for _, cm := range cms {
+		report, err := status.ParseReport(cm)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to parse status report", zap.Error(err))
+			continue
+		}
+		ts, ok := report.Targets[string(t.UID)]
+		if !ok {
+			continue
+		}
+		ds = mergeDeliveryStatus(ds, ts)
+		switch ts.CircuitBreaker {
+		case breaker.Open.String():
+			open++
+		case breaker.HalfOpen.String():
+			halfOpen++
+		}
+	}
+
+	t.Status.PropagateDeliveryStatus(ds)
+	if !ds.IsEmpty() {
+		r.enqueueAfter(t, deliveryStatusRefreshPeriod)
+	}
+
+	switch {
+	case open > 0:
+		t.Status.MarkCircuitBreakerOpen(open)
+	case halfOpen > 0:
+		t.Status.MarkCircuitBreakerHalfOpen(halfOpen)
+	case t.Status.GetCondition(brokerv1beta1.TriggerConditionCircuitBreaker) != nil:
+		// The condition is only set once a circuit breaker was opened.
+		t.Status.MarkCircuitBreakerClosed()
+	}
+	return nil
+}
+
+// mergeDeliveryStatus returns the delivery status with the latest deliveries and the largest
+// backlog of ds and of the reported target status.
+func mergeDeliveryStatus(ds brokerv1beta1.DeliveryStatus, ts status.TargetStatus) brokerv1beta1.DeliveryStatus {
+	if ts.LastSuccessTime != nil && (ds.LastSuccessTime == nil || ts.LastSuccessTime.After(*ds.LastSuccessTime)) {
+		ds.LastSuccessTime = ts.LastSuccessTime
+	}
+	if ts.LastFailureTime != nil && (ds.LastFailureTime == nil || ts.LastFailureTime.After(*ds.LastFailureTime)) {
+		ds.LastFailureTime = ts.LastFailureTime
+		ds.LastError = ts.LastError
+		ds.LastErrorCode = ts.LastErrorCode
+	}
+	if ts.RetryBacklog != nil && (ds.RetryBacklog == nil || *ts.RetryBacklog > *ds.RetryBacklog) {
+		ds.RetryBacklog = ts.RetryBacklog
+	}
+	return ds
+}
+
+// reportedState is the part of the status of a target in a report which triggers the
+// reconciliation of its Trigger when it changes.
+type reportedState struct {
+	circuitBreaker string
+	unreachable    bool
+	errorCode      int
+}
+
+func reportedStates(obj interface{}) map[types.NamespacedName]reportedState {
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return nil
+	}
+	report, err := status.ParseReport(cm)
+	if err != nil {
+		return nil
+	}
+	states := make(map[types.NamespacedName]reportedState, len(report.Targets))
+	for _, ts := range report.Targets {
+		ds := mergeDeliveryStatus(brokerv1beta1.DeliveryStatus{}, ts)
+		state := reportedState{circuitBreaker: ts.CircuitBreaker, unreachable: ds.IsUnreachable()}
+		if state.unreachable {
+			state.errorCode = ts.LastErrorCode
+		}
+		states[types.NamespacedName{Namespace: ts.Namespace, Name: ts.Name}] = state
+	}
+	return states
+}
+
+// statusReportHandler enqueues the Triggers in the status reports which are added or deleted,
+// and the Triggers whose reported state changed in the updated reports, including the Triggers
+// which are no longer in an updated report.
+func statusReportHandler(enqueue func(types.NamespacedName)) cache.ResourceEventHandler {
+	enqueueTargets := func(obj interface{}) {
+		for key := range reportedStates(obj) {
+			enqueue(key)
+		}
+	}
+	return cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			cm, ok := obj.(*corev1.ConfigMap)
+			return ok && cm.Namespace == status.Namespace() && statusReportSelector.Matches(labels.Set(cm.Labels))
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: enqueueTargets,
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldStates, newStates := reportedStates(oldObj), reportedStates(newObj)
+				for key, state := range newStates {
+					if old, ok := oldStates[key]; !ok || old != state {
+						enqueue(key)
+					}
+				}
+				for key := range oldStates {
+					if _, ok := newStates[key]; !ok {
+						enqueue(key)
+					}
+				}
+			},
+			DeleteFunc: enqueueTargets,
+		},
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/google/knative-gcp/pkg/broker/status"
+)
+
+func TestStatusReportHandlerUpdate(t *testing.T) {
+	success := lastSuccessTime
+	laterSuccess := lastSuccessTime.Add(time.Minute)
+	failure := lastFailureTime
+	oldReport := statusReport("fanout-1", map[string]status.TargetStatus{
+		"uid1": {Namespace: testNS, Name: "times-changed", LastSuccessTime: &success},
+		"uid2": {Namespace: testNS, Name: "became-unreachable", LastSuccessTime: &success},
+		"uid3": {Namespace: testNS, Name: "breaker-opened"},
+		"uid4": {Namespace: testNS, Name: "removed", LastSuccessTime: &success},
+	})
+	newReport := statusReport("fanout-1", map[string]status.TargetStatus{
+		"uid1": {Namespace: testNS, Name: "times-changed", LastSuccessTime: &laterSuccess, RetryBacklog: &retryBacklog},
+		"uid2": {Namespace: testNS, Name: "became-unreachable", LastSuccessTime: &success, LastFailureTime: &failure, LastErrorCode: 503},
+		"uid3": {Namespace: testNS, Name: "breaker-opened", CircuitBreaker: "Open"},
+		"uid5": {Namespace: testNS, Name: "added", LastSuccessTime: &success},
+	})
+
+	var got []string
+	h := statusReportHandler(func(key types.NamespacedName) {
+		got = append(got, key.Name)
+	})
+	h.OnUpdate(oldReport, newReport)
+
+	want := []string{"became-unreachable", "breaker-opened", "removed", "added"}
+	if diff := cmp.Diff(want, got, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("enqueued Triggers (-want,+got): %v", diff)
+	}
+}
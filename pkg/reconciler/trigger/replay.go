@@ -0,0 +1,157 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"go.uber.org/zap"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+	reconcilerutilspubsub "github.com/google/knative-gcp/pkg/reconciler/utils/pubsub"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+// replaySeekDelay is how long after a replay is requested the replay subscription is seeked,
+// so that the data plane has loaded the replay window by then. Otherwise it would drop the
+// replayed events published after the window of the previous replay.
+const replaySeekDelay = time.Minute
+
+// reconcileReplay reconciles the replay subscription of the Trigger, a subscription of the
+// Broker decoupling topic which retains the events published to the Broker, and seeks it to
+// the start of the requested replay. A replay is done in two steps: the replay window is first
+// recorded in the status, to be propagated to the data plane, and the subscription is seeked
+// once replaySeekDelay has elapsed.
+func (r *Reconciler) reconcileReplay(ctx context.Context, t *brokerv1beta1.Trigger, b *brokerv1beta1.Broker) error {
+	logger := logging.FromContext(ctx)
+	client, err := r.replayClient(ctx, t)
+	if err != nil {
+		return err
+	}
+	pubsubReconciler := reconcilerutilspubsub.NewReconciler(client, r.Recorder)
+	status := &replayStatusUpdater{status: &t.Status}
+
+	retention := t.GetReplayRetention()
+	if retention == 0 {
+		// Only the Triggers which used to replay events have a replay subscription.
+		if t.Status.GetCondition(brokerv1beta1.TriggerConditionReplay) == nil {
+			return nil
+		}
+		if err := pubsubReconciler.DeleteSubscription(ctx, resources.GenerateReplaySubscriptionName(t), t, status); err != nil {
+			return err
+		}
+		t.Status.ClearReplay()
+		return nil
+	}
+
+	subConfig := pubsub.SubscriptionConfig{
+		Topic: client.Topic(resources.GenerateDecouplingTopicName(b)),
+		Labels: map[string]string{
+			"resource":  "triggers",
+			"namespace": t.Namespace,
+			"name":      t.Name,
+		},
+		RetainAckedMessages: true,
+		RetentionDuration:   retention,
+		// The subscription is only pulled by the data plane during a replay.
+		ExpirationPolicy: time.Duration(0),
+	}
+	sub, err := pubsubReconciler.ReconcileSubscription(ctx, resources.GenerateReplaySubscriptionName(t), subConfig, t, status)
+	if err != nil {
+		return err
+	}
+
+	from, ok := t.GetReplayFrom()
+	if !ok {
+		t.Status.ClearReplayWindow()
+		t.Status.MarkReplayAvailable()
+		return nil
+	}
+	recordedFrom, until, ok := t.Status.GetReplayWindow()
+	if !ok || !recordedFrom.Equal(from) {
+		// A new replay is requested. It replays the events published until now.
+		until = time.Now()
+		t.Status.SetReplayWindow(from, until)
+		t.Status.MarkReplayPending(from, until)
+		r.enqueueAfter(t, replaySeekDelay)
+		return nil
+	}
+	if t.Status.IsReplayStarted() {
+		return nil
+	}
+	if wait := time.Until(until.Add(replaySeekDelay)); wait > 0 {
+		t.Status.MarkReplayPending(from, until)
+		r.enqueueAfter(t, wait)
+		return nil
+	}
+	if err := sub.SeekToTime(ctx, from); err != nil {
+		logger.Error("Failed to seek the replay subscription", zap.String("subscription", sub.ID()), zap.Error(err))
+		t.Status.MarkReplayFailed("ReplaySeekFailed", "Failed to seek the replay subscription: %v", err)
+		return err
+	}
+	logger.Info("Seeked the replay subscription", zap.String("subscription", sub.ID()), zap.Time("from", from), zap.Time("until", until))
+	t.Status.MarkReplayStarted(from, until)
+	return nil
+}
+
+// deleteReplaySubscription deletes the replay subscription of the Trigger if it exists.
+func (r *Reconciler) deleteReplaySubscription(ctx context.Context, t *brokerv1beta1.Trigger) error {
+	client, err := r.replayClient(ctx, t)
+	if err != nil {
+		return err
+	}
+	pubsubReconciler := reconcilerutilspubsub.NewReconciler(client, r.Recorder)
+	return pubsubReconciler.DeleteSubscription(ctx, resources.GenerateReplaySubscriptionName(t), t, &replayStatusUpdater{status: &t.Status})
+}
+
+func (r *Reconciler) replayClient(ctx context.Context, t *brokerv1beta1.Trigger) (*pubsub.Client, error) {
+	projectID, err := utils.ProjectIDOrDefault(r.projectID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to find project id", zap.Error(err))
+		t.Status.MarkReplayUnknown("ProjectIdNotFound", "Failed to find project id: %v", err)
+		return nil, err
+	}
+	return r.getClientOrCreateNew(ctx, projectID, t)
+}
+
+// replayStatusUpdater reports the reconciliation of the replay subscription of a Trigger with
+// its replay condition, without affecting the conditions of its retry topic and subscription.
+type replayStatusUpdater struct {
+	status *brokerv1beta1.TriggerStatus
+}
+
+var _ reconcilerutilspubsub.StatusUpdater = (*replayStatusUpdater)(nil)
+
+func (u *replayStatusUpdater) MarkTopicFailed(string, string, ...interface{})  {}
+func (u *replayStatusUpdater) MarkTopicUnknown(string, string, ...interface{}) {}
+func (u *replayStatusUpdater) MarkTopicReady()                                 {}
+
+func (u *replayStatusUpdater) MarkSubscriptionFailed(reason, format string, args ...interface{}) {
+	u.status.MarkReplayFailed(reason, format, args...)
+}
+
+func (u *replayStatusUpdater) MarkSubscriptionUnknown(reason, format string, args ...interface{}) {
+	u.status.MarkReplayUnknown(reason, format, args...)
+}
+
+// MarkSubscriptionReady leaves the replay condition to reconcileReplay, which reports the
+// progress of the replay.
+func (u *replayStatusUpdater) MarkSubscriptionReady() {}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/record"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+func TestReconcileReplayRequested(t *testing.T) {
+	ctx := context.Background()
+	client, close := TestPubsubClient(ctx, testProject)
+	defer close()
+
+	b := NewBroker(brokerName, testNS, WithBrokerUID(testUID))
+	if _, err := client.CreateTopic(ctx, resources.GenerateDecouplingTopicName(b)); err != nil {
+		t.Fatalf("Failed to create the decoupling topic: %v", err)
+	}
+	trig := NewTrigger(triggerName, testNS, brokerName,
+		WithTriggerUID(testUID),
+		WithTriggerReplay("PT1H", replayFrom.Format(time.RFC3339)))
+
+	var enqueuedAfter time.Duration
+	r := &Reconciler{
+		Base:         &reconciler.Base{Recorder: record.NewFakeRecorder(10)},
+		projectID:    testProject,
+		pubsubClient: client,
+		enqueueAfter: func(_ interface{}, after time.Duration) {
+			enqueuedAfter = after
+		},
+	}
+	before := time.Now().Truncate(time.Second)
+	if err := r.reconcileReplay(ctx, trig, b); err != nil {
+		t.Fatalf("reconcileReplay() failed: %v", err)
+	}
+
+	from, until, ok := trig.Status.GetReplayWindow()
+	if !ok {
+		t.Fatal("The replay window wasn't recorded")
+	}
+	if !from.Equal(replayFrom) {
+		t.Errorf("Unexpected replay from, got: %v, want: %v", from, replayFrom)
+	}
+	if until.Before(before) || until.After(time.Now()) {
+		t.Errorf("Unexpected replay until, got: %v, want around now", until)
+	}
+	if !trig.Status.IsReplayReady() || trig.Status.IsReplayStarted() {
+		t.Errorf("Unexpected replay condition: %+v", trig.Status.GetCondition(brokerv1beta1.TriggerConditionReplay))
+	}
+	if enqueuedAfter != replaySeekDelay {
+		t.Errorf("Unexpected requeue delay, got: %v, want: %v", enqueuedAfter, replaySeekDelay)
+	}
+}
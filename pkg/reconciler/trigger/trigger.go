@@ -26,6 +26,7 @@ import (
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 
 	"github.com/google/knative-gcp/pkg/logging"
 	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
@@ -70,6 +71,9 @@ type Reconciler struct {
 
 	brokerLister brokerlisters.BrokerLister
 
+	// configMapLister lists the status reports of the data plane pods.
+	configMapLister corev1listers.ConfigMapLister
+
 	// Dynamic tracker to track sources. It tracks the dependency between Triggers and Sources.
 	sourceTracker duck.ListableTracker
 
@@ -83,6 +87,10 @@ type Reconciler struct {
 	pubsubClient *pubsub.Client
 
 	dataresidencyStore *dataresidency.Store
+
+	// enqueueAfter enqueues a Trigger to be reconciled again after a delay, when a replay
+	// is pending or to refresh its delivery status.
+	enqueueAfter func(obj interface{}, after time.Duration)
 }
 
 // Check that TriggerReconciler implements Interface
@@ -142,6 +150,15 @@ func (r *Reconciler) reconcile(ctx context.Context, t *brokerv1beta1.Trigger, b
 	t.Status.InitializeConditions()
 	t.Status.PropagateBrokerStatus(&b.Status)
 
+	// The webhook of eventing doesn't validate the annotations of the Trigger, so the filters
+	// are validated here. A Trigger with invalid filters is left out of the config of the
+	// data plane.
+	if err := t.ValidateFilters(); err != nil {
+		t.Status.MarkFilterFailed("InvalidFilter", "%v", err)
+	} else {
+		t.Status.MarkFilterValid()
+	}
+
 	if err := r.resolveSubscriber(ctx, t, b); err != nil {
 		return err
 	}
@@ -149,7 +166,14 @@ func (r *Reconciler) reconcile(ctx context.Context, t *brokerv1beta1.Trigger, b
 	if b.Spec.Delivery == nil {
 		b.SetDefaults(ctx)
 	}
-	if err := r.reconcileRetryTopicAndSubscription(ctx, t, b.Spec.Delivery); err != nil {
+	// The delivery settings of the Trigger override the ones of the Broker. An invalid
+	// delivery annotation is rejected by the webhook, so it's ignored here.
+	triggerDelivery, _ := t.GetDelivery()
+	if err := r.reconcileRetryTopicAndSubscription(ctx, t, triggerDelivery.ApplyTo(b.Spec.Delivery)); err != nil {
+		return err
+	}
+
+	if err := r.reconcileReplay(ctx, t, b); err != nil {
 		return err
 	}
 
@@ -157,6 +181,10 @@ func (r *Reconciler) reconcile(ctx context.Context, t *brokerv1beta1.Trigger, b
 		return err
 	}
 
+	if err := r.reconcileDeliveryStatus(ctx, t); err != nil {
+		return err
+	}
+
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, triggerReconciled, "Trigger reconciled: \"%s/%s\"", t.Namespace, t.Name)
 }
 
@@ -175,6 +203,11 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, t *brokerv1beta1.Trigger)
 	if err := r.deleteRetryTopicAndSubscription(ctx, t); err != nil {
 		return err
 	}
+	if t.Status.GetCondition(brokerv1beta1.TriggerConditionReplay) != nil {
+		if err := r.deleteReplaySubscription(ctx, t); err != nil {
+			return err
+		}
+	}
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, triggerFinalized, "Trigger finalized: \"%s/%s\"", t.Namespace, t.Name)
 }
 
@@ -263,10 +296,11 @@ func (r *Reconciler) reconcileRetryTopicAndSubscription(ctx context.Context, tri
 	// Check if PullSub exists, and if not, create it.
 	subID := resources.GenerateRetrySubscriptionName(trig)
 	subConfig := pubsub.SubscriptionConfig{
-		Topic:            topic,
-		Labels:           labels,
-		RetryPolicy:      retryPolicy,
-		DeadLetterPolicy: deadLetterPolicy,
+		Topic:                 topic,
+		Labels:                labels,
+		RetryPolicy:           retryPolicy,
+		DeadLetterPolicy:      deadLetterPolicy,
+		EnableMessageOrdering: trig.GetOrderingKeyAttribute() != "",
 		//TODO(grantr): configure these settings?
 		// AckDeadline
 		// RetentionDuration
@@ -304,8 +338,10 @@ func getPubsubRetryPolicy(spec *eventingduckv1beta1.DeliverySpec) *pubsub.RetryP
 
 // getPubsubDeadLetterPolicy gets the eventing dead letter policy from the
 // Broker delivery spec and translates it to a pubsub dead letter policy.
+// Only a Pub/Sub topic dead letter sink is translated, events are sent to
+// other dead letter sinks by the broker data plane.
 func getPubsubDeadLetterPolicy(projectID string, spec *eventingduckv1beta1.DeliverySpec) *pubsub.DeadLetterPolicy {
-	if spec.DeadLetterSink == nil {
+	if !brokerv1beta1.IsPubsubDeadLetterSink(spec.DeadLetterSink) {
 		return nil
 	}
 	// Translate to the pubsub dead letter policy format.
@@ -18,12 +18,14 @@ package trigger
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
 
 	"cloud.google.com/go/pubsub"
 	"cloud.google.com/go/pubsub/pstest"
+	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -44,6 +46,7 @@ import (
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	"github.com/google/knative-gcp/pkg/apis/configs/dataresidency"
+	"github.com/google/knative-gcp/pkg/broker/status"
 	"github.com/google/knative-gcp/pkg/client/injection/ducks/duck/v1alpha1/resource"
 	triggerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/trigger"
 	"github.com/google/knative-gcp/pkg/reconciler"
@@ -72,17 +75,31 @@ var (
 
 	testKey = fmt.Sprintf("%s/%s", testNS, triggerName)
 
-	triggerFinalizerUpdatedEvent   = Eventf(corev1.EventTypeNormal, "FinalizerUpdate", `Updated "test-trigger" finalizers`)
-	triggerReconciledEvent         = Eventf(corev1.EventTypeNormal, "TriggerReconciled", `Trigger reconciled: "testnamespace/test-trigger"`)
-	triggerFinalizedEvent          = Eventf(corev1.EventTypeNormal, "TriggerFinalized", `Trigger finalized: "testnamespace/test-trigger"`)
-	topicCreatedEvent              = Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-tgr_testnamespace_test-trigger_abc123"`)
-	topicDeletedEvent              = Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "cre-tgr_testnamespace_test-trigger_abc123"`)
-	deadLetterTopicCreatedEvent    = Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "test-dead-letter-topic-id"`)
-	subscriptionCreatedEvent       = Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`)
-	subscriptionDeletedEvent       = Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`)
-	subscriptionConfigUpdatedEvent = Eventf(corev1.EventTypeNormal, "SubscriptionConfigUpdated", `Updated config for PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`)
-	subscriberAPIVersion           = fmt.Sprintf("%s/%s", subscriberGroup, subscriberVersion)
-	subscriberGVK                  = metav1.GroupVersionKind{
+	decouplingTopicID    = "cre-bkr_testnamespace_test-broker_abc123"
+	replaySubscriptionID = "cre-rpl_testnamespace_test-trigger_abc123"
+	replayFrom           = time.Date(2020, 10, 1, 8, 0, 0, 0, time.UTC)
+	replayUntil          = time.Date(2020, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	lastSuccessTime           = time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	earlierFailureTime        = time.Date(2020, 10, 1, 11, 0, 0, 0, time.UTC)
+	lastFailureTime           = time.Date(2020, 10, 1, 13, 0, 0, 0, time.UTC)
+	retryBacklog        int64 = 7
+	smallerRetryBacklog int64 = 3
+
+	triggerFinalizerUpdatedEvent         = Eventf(corev1.EventTypeNormal, "FinalizerUpdate", `Updated "test-trigger" finalizers`)
+	triggerReconciledEvent               = Eventf(corev1.EventTypeNormal, "TriggerReconciled", `Trigger reconciled: "testnamespace/test-trigger"`)
+	triggerFinalizedEvent                = Eventf(corev1.EventTypeNormal, "TriggerFinalized", `Trigger finalized: "testnamespace/test-trigger"`)
+	topicCreatedEvent                    = Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-tgr_testnamespace_test-trigger_abc123"`)
+	topicDeletedEvent                    = Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "cre-tgr_testnamespace_test-trigger_abc123"`)
+	deadLetterTopicCreatedEvent          = Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "test-dead-letter-topic-id"`)
+	subscriptionCreatedEvent             = Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`)
+	subscriptionDeletedEvent             = Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`)
+	subscriptionConfigUpdatedEvent       = Eventf(corev1.EventTypeNormal, "SubscriptionConfigUpdated", `Updated config for PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`)
+	replaySubscriptionCreatedEvent       = Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-rpl_testnamespace_test-trigger_abc123"`)
+	replaySubscriptionDeletedEvent       = Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "cre-rpl_testnamespace_test-trigger_abc123"`)
+	replaySubscriptionConfigUpdatedEvent = Eventf(corev1.EventTypeNormal, "SubscriptionConfigUpdated", `Updated config for PubSub subscription "cre-rpl_testnamespace_test-trigger_abc123"`)
+	subscriberAPIVersion                 = fmt.Sprintf("%s/%s", subscriberGroup, subscriberVersion)
+	subscriberGVK                        = metav1.GroupVersionKind{
 		Group:   subscriberGroup,
 		Version: subscriberVersion,
 		Kind:    subscriberKind,
@@ -274,6 +291,7 @@ func TestAllCasesTrigger(t *testing.T) {
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
 					WithTriggerBrokerUnknown("Broker/", ""),
+					WithTriggerFilterValid,
 					WithTriggerSubscriptionReady,
 					WithTriggerTopicReady,
 					WithTriggerDependencyReady,
@@ -322,6 +340,7 @@ func TestAllCasesTrigger(t *testing.T) {
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
 					WithInitTriggerConditions,
 					WithTriggerBrokerReady,
+					WithTriggerFilterValid,
 					WithTriggerSubscriberResolvedFailed("Unable to get the Subscriber's URI", `services.serving.knative.dev "subscriber-name" not found`),
 					WithTriggerSetDefaults,
 				),
@@ -357,6 +376,7 @@ func TestAllCasesTrigger(t *testing.T) {
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
 					WithTriggerBrokerReady,
+					WithTriggerFilterValid,
 					WithTriggerSubscriptionReady,
 					WithTriggerTopicReady,
 					WithTriggerDependencyReady,
@@ -400,7 +420,7 @@ func TestAllCasesTrigger(t *testing.T) {
 			},
 		},
 		{
-			Name: "Sub already exists, update config",
+			Name: "Trigger with invalid filters",
 			Key:  testKey,
 			Objects: []runtime.Object{
 				NewBroker(brokerName, testNS,
@@ -414,13 +434,16 @@ func TestAllCasesTrigger(t *testing.T) {
 				NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerFiltersAnnotation(`[{"exact": {"type": "a"}, "prefix": {"type": "b"}}]`),
 					WithTriggerSetDefaults),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerFiltersAnnotation(`[{"exact": {"type": "a"}, "prefix": {"type": "b"}}]`),
 					WithTriggerBrokerReady,
+					WithTriggerFilterFailed("InvalidFilter", "expected exactly one, got both: metadata.annotations[events.cloud.google.com/filters][0].exact, metadata.annotations[events.cloud.google.com/filters][0].prefix"),
 					WithTriggerSubscriptionReady,
 					WithTriggerTopicReady,
 					WithTriggerDependencyReady,
@@ -431,7 +454,56 @@ func TestAllCasesTrigger(t *testing.T) {
 			}},
 			WantEvents: []string{
 				triggerFinalizerUpdatedEvent,
-				subscriptionConfigUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+			},
+		},
+		{
+			Name: "Trigger delivery overrides broker delivery",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerDeliveryAnnotation(`{"retry":7,"backoffPolicy":"exponential","backoffDelay":"PT2S"}`),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerDeliveryAnnotation(`{"retry":7,"backoffPolicy":"exponential","backoffDelay":"PT2S"}`),
+					WithTriggerBrokerReady,
+					WithTriggerFilterValid,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
 				triggerReconciledEvent,
 			},
 			WantPatches: []clientgotesting.PatchActionImpl{
@@ -439,7 +511,6 @@ func TestAllCasesTrigger(t *testing.T) {
 			},
 			OtherTestData: map[string]interface{}{
 				"pre": []PubsubAction{
-					TopicAndSub("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123"),
 					Topic("test-dead-letter-topic-id"),
 				},
 			},
@@ -448,18 +519,18 @@ func TestAllCasesTrigger(t *testing.T) {
 				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
 				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123",
 					&pubsub.RetryPolicy{
-						MaximumBackoff: 5 * time.Second,
-						MinimumBackoff: 5 * time.Second,
+						MaximumBackoff: 600 * time.Second,
+						MinimumBackoff: 2 * time.Second,
 					}),
 				SubscriptionHasDeadLetterPolicy("cre-tgr_testnamespace_test-trigger_abc123",
 					&pubsub.DeadLetterPolicy{
-						MaxDeliveryAttempts: 3,
+						MaxDeliveryAttempts: 7,
 						DeadLetterTopic:     "projects/test-project-id/topics/test-dead-letter-topic-id",
 					}),
 			},
 		},
 		{
-			Name: "Check topic config and labels",
+			Name: "Ordered trigger",
 			Key:  testKey,
 			Objects: []runtime.Object{
 				NewBroker(brokerName, testNS,
@@ -473,13 +544,16 @@ func TestAllCasesTrigger(t *testing.T) {
 				NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerOrderedDelivery("orderid"),
 					WithTriggerSetDefaults),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerOrderedDelivery("orderid"),
 					WithTriggerBrokerReady,
+					WithTriggerFilterValid,
 					WithTriggerSubscriptionReady,
 					WithTriggerTopicReady,
 					WithTriggerDependencyReady,
@@ -501,36 +575,19 @@ func TestAllCasesTrigger(t *testing.T) {
 				"pre": []PubsubAction{
 					Topic("test-dead-letter-topic-id"),
 				},
-				"dataResidencyConfigMap": NewDataresidencyConfigMapFromRegions([]string{"us-east1"}),
 			},
 			PostConditions: []func(*testing.T, *TableRow){
 				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id"),
 				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
-				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123",
-					&pubsub.RetryPolicy{
-						MaximumBackoff: 5 * time.Second,
-						MinimumBackoff: 5 * time.Second,
-					}),
-				SubscriptionHasDeadLetterPolicy("cre-tgr_testnamespace_test-trigger_abc123",
-					&pubsub.DeadLetterPolicy{
-						MaxDeliveryAttempts: 3,
-						DeadLetterTopic:     "projects/test-project-id/topics/test-dead-letter-topic-id",
-					}),
-				TopicExistsWithConfig("cre-tgr_testnamespace_test-trigger_abc123", &pubsub.TopicConfig{
-					MessageStoragePolicy: pubsub.MessageStoragePolicy{
-						AllowedPersistenceRegions: []string{"us-east1"},
-					},
-					Labels: map[string]string{
-						"name": "test-trigger", "namespace": "testnamespace", "resource": "triggers",
-					},
-				}),
+				SubscriptionHasMessageOrdering("cre-tgr_testnamespace_test-trigger_abc123", true),
 			},
 		},
 		{
-			Name: "Trigger created, broker ready, subscriber is addressable, nil pubsub client",
+			Name: "Replay enabled",
 			Key:  testKey,
 			Objects: []runtime.Object{
 				NewBroker(brokerName, testNS,
+					WithBrokerUID(testUID),
 					WithBrokerClass(brokerv1beta1.BrokerClass),
 					WithInitBrokerConditions,
 					WithBrokerReady("url"),
@@ -541,15 +598,19 @@ func TestAllCasesTrigger(t *testing.T) {
 				NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerReplay("PT1H", ""),
 					WithTriggerSetDefaults),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerReplay("PT1H", ""),
 					WithTriggerBrokerReady,
+					WithTriggerFilterValid,
 					WithTriggerSubscriptionReady,
 					WithTriggerTopicReady,
+					WithTriggerReplayAvailable,
 					WithTriggerDependencyReady,
 					WithTriggerSubscriberResolvedSucceeded,
 					WithTriggerStatusSubscriberURI(subscriberURI),
@@ -560,6 +621,7 @@ func TestAllCasesTrigger(t *testing.T) {
 				triggerFinalizerUpdatedEvent,
 				topicCreatedEvent,
 				subscriptionCreatedEvent,
+				replaySubscriptionCreatedEvent,
 				triggerReconciledEvent,
 			},
 			WantPatches: []clientgotesting.PatchActionImpl{
@@ -568,36 +630,20 @@ func TestAllCasesTrigger(t *testing.T) {
 			OtherTestData: map[string]interface{}{
 				"pre": []PubsubAction{
 					Topic("test-dead-letter-topic-id"),
+					Topic(decouplingTopicID),
 				},
-				// TODO: This should make sure the function is called only once, but currently this case only check the create function
-				// will be called on demand since there is no test that reconcile twice or with both reconcile and delete.
-				"maxPSClientCreateTime": 1,
 			},
 			PostConditions: []func(*testing.T, *TableRow){
-				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id"),
-				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
-				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123",
-					&pubsub.RetryPolicy{
-						MaximumBackoff: 5 * time.Second,
-						MinimumBackoff: 5 * time.Second,
-					}),
-				SubscriptionHasDeadLetterPolicy("cre-tgr_testnamespace_test-trigger_abc123",
-					&pubsub.DeadLetterPolicy{
-						MaxDeliveryAttempts: 3,
-						DeadLetterTopic:     "projects/test-project-id/topics/test-dead-letter-topic-id",
-					}),
-				TopicExistsWithConfig("cre-tgr_testnamespace_test-trigger_abc123", &pubsub.TopicConfig{
-					Labels: map[string]string{
-						"name": "test-trigger", "namespace": "testnamespace", "resource": "triggers",
-					},
-				}),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123", replaySubscriptionID),
+				SubscriptionHasRetention(replaySubscriptionID, time.Hour, true),
 			},
 		},
 		{
-			Name: "Trigger created, broker ready, subscriber is addressable, pubsub client creation fails",
+			Name: "Replay pending, seek the replay subscription",
 			Key:  testKey,
 			Objects: []runtime.Object{
 				NewBroker(brokerName, testNS,
+					WithBrokerUID(testUID),
 					WithBrokerClass(brokerv1beta1.BrokerClass),
 					WithInitBrokerConditions,
 					WithBrokerReady("url"),
@@ -608,26 +654,34 @@ func TestAllCasesTrigger(t *testing.T) {
 				NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerReplay("PT1H", replayFrom.Format(time.RFC3339)),
+					WithTriggerReplayWindow(replayFrom, replayUntil),
+					WithTriggerReplayPending(replayFrom, replayUntil),
 					WithTriggerSetDefaults),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerReplay("PT1H", replayFrom.Format(time.RFC3339)),
+					WithTriggerReplayWindow(replayFrom, replayUntil),
 					WithTriggerBrokerReady,
+					WithTriggerFilterValid,
 					WithTriggerSubscriptionReady,
 					WithTriggerTopicReady,
+					WithTriggerReplayStarted(replayFrom, replayUntil),
+					WithTriggerDependencyReady,
 					WithTriggerSubscriberResolvedSucceeded,
 					WithTriggerStatusSubscriberURI(subscriberURI),
 					WithTriggerSetDefaults,
-					WithTriggerDependencyUnknown("", ""),
-					WithTriggerTopicUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client: Invoke time 0 reaches the max invoke time 0"),
-					WithTriggerSubscriptionUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client: Invoke time 0 reaches the max invoke time 0"),
 				),
 			}},
 			WantEvents: []string{
 				triggerFinalizerUpdatedEvent,
-				Eventf(corev1.EventTypeWarning, "InternalError", "Invoke time 0 reaches the max invoke time 0"),
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				replaySubscriptionConfigUpdatedEvent,
+				triggerReconciledEvent,
 			},
 			WantPatches: []clientgotesting.PatchActionImpl{
 				patchFinalizers(testNS, triggerName, finalizerName),
@@ -635,83 +689,704 @@ func TestAllCasesTrigger(t *testing.T) {
 			OtherTestData: map[string]interface{}{
 				"pre": []PubsubAction{
 					Topic("test-dead-letter-topic-id"),
+					TopicAndSub(decouplingTopicID, replaySubscriptionID),
 				},
-				"maxPSClientCreateTime": 0,
 			},
-			WantErr: true,
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123", replaySubscriptionID),
+			},
 		},
-	}
-
-	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, testData map[string]interface{}) controller.Reconciler {
-		srv := pstest.NewServer()
-		// Insert pubsub client for PostConditions and create fixtures
-		psclient, _ := GetTestClientCreateFunc(srv.Addr)(ctx, testProject)
-		savedCreateFn := createPubsubClientFn
-		close := func() {
-			srv.Close()
-			createPubsubClientFn = savedCreateFn
-		}
-		t.Cleanup(close)
-		var drStore *dataresidency.Store
-		if testData != nil {
-			InjectPubsubClient(testData, psclient)
-			if testData["pre"] != nil {
-				fixtures := testData["pre"].([]PubsubAction)
-				for _, f := range fixtures {
-					f(ctx, t, psclient)
-				}
-			}
-
-			// If we found "dataResidencyConfigMap" in OtherData, we create a store with the configmap
-			if cm, ok := testData["dataResidencyConfigMap"]; ok {
-				drStore = NewDataresidencyTestStore(t, cm.(*corev1.ConfigMap))
-			}
-		}
-
-		// If maxPSClientCreateTime is in testData, no pubsub client is passed to reconciler, the reconciler
-		// will create one in demand
-		testPSClient := psclient
-		if maxTime, ok := testData["maxPSClientCreateTime"]; ok {
-			// Overwrite the createPubsubClientFn to one that failed when called more than maxTime times.
-			// maxTime=0 is used to inject error
-			createPubsubClientFn = GetFailedTestClientCreateFunc(srv.Addr, maxTime.(int))
-			testPSClient = nil
-		}
-
-		ctx = addressable.WithDuck(ctx)
-		ctx = resource.WithDuck(ctx)
-		ctx = source.WithDuck(ctx)
-
-		r := &Reconciler{
-			Base:               reconciler.NewBase(ctx, controllerAgentName, cmw),
-			brokerLister:       listers.GetBrokerLister(),
-			sourceTracker:      duck.NewListableTracker(ctx, source.Get, func(types.NamespacedName) {}, 0),
-			addressableTracker: duck.NewListableTracker(ctx, addressable.Get, func(types.NamespacedName) {}, 0),
-			uriResolver:        resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
-			projectID:          testProject,
-			pubsubClient:       testPSClient,
-			dataresidencyStore: drStore,
-		}
-
-		return triggerreconciler.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetTriggerLister(), r.Recorder, r, withAgentAndFinalizer(nil))
-	}))
-}
-
-func makeSubscriberAddressableAsUnstructured() *unstructured.Unstructured {
-	return &unstructured.Unstructured{
-		Object: map[string]interface{}{
-			"apiVersion": subscriberAPIVersion,
-			"kind":       subscriberKind,
-			"metadata": map[string]interface{}{
-				"namespace": testNS,
-				"name":      subscriberName,
+		{
+			Name: "Replay disabled, delete the replay subscription",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerUID(testUID),
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerReplayWindow(replayFrom, replayUntil),
+					WithTriggerReplayStarted(replayFrom, replayUntil),
+					WithTriggerSetDefaults),
 			},
-			"status": map[string]interface{}{
-				"address": map[string]interface{}{
-					"url": subscriberURI,
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerFilterValid,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				replaySubscriptionDeletedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+					TopicAndSub(decouplingTopicID, replaySubscriptionID),
 				},
 			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+			},
 		},
+		{
+			Name: "Circuit breaker open in a fanout pod",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSetDefaults),
+				statusReport("fanout-1", map[string]status.TargetStatus{
+					testUID:     {Namespace: testNS, Name: triggerName, CircuitBreaker: "Open"},
+					"other-uid": {Namespace: testNS, Name: "other-trigger", CircuitBreaker: "Open"},
+				}),
+				statusReport("fanout-2", map[string]status.TargetStatus{
+					testUID: {Namespace: testNS, Name: triggerName, CircuitBreaker: "HalfOpen"},
+				}),
+				statusReport("fanout-3", nil),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerFilterValid,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerCircuitBreakerOpen(1),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+			},
+		},
+		{
+			Name: "Circuit breaker half-open in a fanout pod",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerCircuitBreakerOpen(1),
+					WithTriggerSetDefaults),
+				statusReport("fanout-1", map[string]status.TargetStatus{
+					testUID: {Namespace: testNS, Name: triggerName, CircuitBreaker: "HalfOpen"},
+				}),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerFilterValid,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerCircuitBreakerHalfOpen(1),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+			},
+		},
+		{
+			Name: "Circuit breaker closed again",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerCircuitBreakerOpen(1),
+					WithTriggerSetDefaults),
+				statusReport("fanout-1", nil),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerFilterValid,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerCircuitBreakerClosed,
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+			},
+		},
+		{
+			Name: "Delivery status reported by the data plane pods",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSetDefaults),
+				statusReport("fanout-1", map[string]status.TargetStatus{
+					testUID: {Namespace: testNS, Name: triggerName, LastSuccessTime: &lastSuccessTime, LastFailureTime: &earlierFailureTime, LastError: "HTTP status code 400", LastErrorCode: 400},
+				}),
+				statusReport("retry-1", map[string]status.TargetStatus{
+					testUID: {Namespace: testNS, Name: triggerName, LastFailureTime: &lastFailureTime, LastError: "HTTP status code 503", LastErrorCode: 503, RetryBacklog: &retryBacklog},
+				}),
+				statusReport("retry-2", map[string]status.TargetStatus{
+					testUID: {Namespace: testNS, Name: triggerName, RetryBacklog: &smallerRetryBacklog},
+				}),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerFilterValid,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerDeliveryStatus(brokerv1beta1.DeliveryStatus{
+						LastSuccessTime: &lastSuccessTime,
+						LastFailureTime: &lastFailureTime,
+						LastError:       "HTTP status code 503",
+						LastErrorCode:   503,
+						RetryBacklog:    &retryBacklog,
+					}),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+			},
+		},
+		{
+			Name: "Delivery status kept without reports",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerDeliveryStatus(brokerv1beta1.DeliveryStatus{
+						LastSuccessTime: &lastSuccessTime,
+						RetryBacklog:    &retryBacklog,
+					}),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerFilterValid,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					// The backlog is no longer reported.
+					WithTriggerDeliveryStatus(brokerv1beta1.DeliveryStatus{
+						LastSuccessTime: &lastSuccessTime,
+					}),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+			},
+		},
+		{
+			Name: "Sub already exists, update config",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerFilterValid,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				subscriptionConfigUpdatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					TopicAndSub("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123"),
+					Topic("test-dead-letter-topic-id"),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id"),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.RetryPolicy{
+						MaximumBackoff: 5 * time.Second,
+						MinimumBackoff: 5 * time.Second,
+					}),
+				SubscriptionHasDeadLetterPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.DeadLetterPolicy{
+						MaxDeliveryAttempts: 3,
+						DeadLetterTopic:     "projects/test-project-id/topics/test-dead-letter-topic-id",
+					}),
+			},
+		},
+		{
+			Name: "Check topic config and labels",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerFilterValid,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+				"dataResidencyConfigMap": NewDataresidencyConfigMapFromRegions([]string{"us-east1"}),
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id"),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.RetryPolicy{
+						MaximumBackoff: 5 * time.Second,
+						MinimumBackoff: 5 * time.Second,
+					}),
+				SubscriptionHasDeadLetterPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.DeadLetterPolicy{
+						MaxDeliveryAttempts: 3,
+						DeadLetterTopic:     "projects/test-project-id/topics/test-dead-letter-topic-id",
+					}),
+				TopicExistsWithConfig("cre-tgr_testnamespace_test-trigger_abc123", &pubsub.TopicConfig{
+					MessageStoragePolicy: pubsub.MessageStoragePolicy{
+						AllowedPersistenceRegions: []string{"us-east1"},
+					},
+					Labels: map[string]string{
+						"name": "test-trigger", "namespace": "testnamespace", "resource": "triggers",
+					},
+				}),
+			},
+		},
+		{
+			Name: "Trigger created, broker ready, subscriber is addressable, nil pubsub client",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerFilterValid,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+				// TODO: This should make sure the function is called only once, but currently this case only check the create function
+				// will be called on demand since there is no test that reconcile twice or with both reconcile and delete.
+				"maxPSClientCreateTime": 1,
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id"),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.RetryPolicy{
+						MaximumBackoff: 5 * time.Second,
+						MinimumBackoff: 5 * time.Second,
+					}),
+				SubscriptionHasDeadLetterPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.DeadLetterPolicy{
+						MaxDeliveryAttempts: 3,
+						DeadLetterTopic:     "projects/test-project-id/topics/test-dead-letter-topic-id",
+					}),
+				TopicExistsWithConfig("cre-tgr_testnamespace_test-trigger_abc123", &pubsub.TopicConfig{
+					Labels: map[string]string{
+						"name": "test-trigger", "namespace": "testnamespace", "resource": "triggers",
+					},
+				}),
+			},
+		},
+		{
+			Name: "Trigger created, broker ready, subscriber is addressable, pubsub client creation fails",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerFilterValid,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+					WithTriggerDependencyUnknown("", ""),
+					WithTriggerTopicUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client: Invoke time 0 reaches the max invoke time 0"),
+					WithTriggerSubscriptionUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client: Invoke time 0 reaches the max invoke time 0"),
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				Eventf(corev1.EventTypeWarning, "InternalError", "Invoke time 0 reaches the max invoke time 0"),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+				"maxPSClientCreateTime": 0,
+			},
+			WantErr: true,
+		},
+	}
+
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, testData map[string]interface{}) controller.Reconciler {
+		srv := pstest.NewServer()
+		// Insert pubsub client for PostConditions and create fixtures
+		psclient, _ := GetTestClientCreateFunc(srv.Addr)(ctx, testProject)
+		savedCreateFn := createPubsubClientFn
+		close := func() {
+			srv.Close()
+			createPubsubClientFn = savedCreateFn
+		}
+		t.Cleanup(close)
+		var drStore *dataresidency.Store
+		if testData != nil {
+			InjectPubsubClient(testData, psclient)
+			if testData["pre"] != nil {
+				fixtures := testData["pre"].([]PubsubAction)
+				for _, f := range fixtures {
+					f(ctx, t, psclient)
+				}
+			}
+
+			// If we found "dataResidencyConfigMap" in OtherData, we create a store with the configmap
+			if cm, ok := testData["dataResidencyConfigMap"]; ok {
+				drStore = NewDataresidencyTestStore(t, cm.(*corev1.ConfigMap))
+			}
+		}
+
+		// If maxPSClientCreateTime is in testData, no pubsub client is passed to reconciler, the reconciler
+		// will create one in demand
+		testPSClient := psclient
+		if maxTime, ok := testData["maxPSClientCreateTime"]; ok {
+			// Overwrite the createPubsubClientFn to one that failed when called more than maxTime times.
+			// maxTime=0 is used to inject error
+			createPubsubClientFn = GetFailedTestClientCreateFunc(srv.Addr, maxTime.(int))
+			testPSClient = nil
+		}
+
+		ctx = addressable.WithDuck(ctx)
+		ctx = resource.WithDuck(ctx)
+		ctx = source.WithDuck(ctx)
+
+		r := &Reconciler{
+			Base:               reconciler.NewBase(ctx, controllerAgentName, cmw),
+			brokerLister:       listers.GetBrokerLister(),
+			configMapLister:    listers.GetConfigMapLister(),
+			sourceTracker:      duck.NewListableTracker(ctx, source.Get, func(types.NamespacedName) {}, 0),
+			addressableTracker: duck.NewListableTracker(ctx, addressable.Get, func(types.NamespacedName) {}, 0),
+			uriResolver:        resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
+			projectID:          testProject,
+			pubsubClient:       testPSClient,
+			dataresidencyStore: drStore,
+			enqueueAfter:       func(interface{}, time.Duration) {},
+		}
+
+		return triggerreconciler.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetTriggerLister(), r.Recorder, r, withAgentAndFinalizer(nil))
+	}))
+}
+
+// statusReport returns the ConfigMap of the status report of a fanout pod.
+func statusReport(pod string, targets map[string]status.TargetStatus) *corev1.ConfigMap {
+	report, _ := json.Marshal(&status.Report{Pod: pod, Targets: targets})
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: status.Namespace(),
+			Name:      status.ConfigMapName(pod),
+			Labels:    map[string]string{status.ReportLabelKey: "true"},
+		},
+		Data: map[string]string{status.ReportDataKey: string(report)},
+	}
+}
+
+func makeSubscriberAddressableAsUnstructured() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": subscriberAPIVersion,
+			"kind":       subscriberKind,
+			"metadata": map[string]interface{}{
+				"namespace": testNS,
+				"name":      subscriberName,
+			},
+			"status": map[string]interface{}{
+				"address": map[string]interface{}{
+					"url": subscriberURI,
+				},
+			},
+		},
+	}
+}
+
+func TestGetPubsubDeadLetterPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *eventingduckv1beta1.DeliverySpec
+		want *pubsub.DeadLetterPolicy
+	}{{
+		name: "no dead letter sink",
+		spec: &eventingduckv1beta1.DeliverySpec{},
+	}, {
+		name: "pubsub dead letter sink",
+		spec: brokerDeliverySpec,
+		want: &pubsub.DeadLetterPolicy{
+			MaxDeliveryAttempts: int(retry),
+			DeadLetterTopic:     "projects/test-project-id/topics/test-dead-letter-topic-id",
+		},
+	}, {
+		name: "addressable dead letter sink",
+		spec: &eventingduckv1beta1.DeliverySpec{
+			Retry: &retry,
+			DeadLetterSink: &duckv1.Destination{
+				URI: apis.HTTP("dead-letter.testnamespace.svc.cluster.local"),
+			},
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := getPubsubDeadLetterPolicy(testProject, test.spec)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("getPubsubDeadLetterPolicy (-want,+got): %v", diff)
+			}
+		})
 	}
 }
 
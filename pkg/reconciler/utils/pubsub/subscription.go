@@ -31,10 +31,11 @@ import (
 const (
 	// If the topic of the subscription has been deleted, the value of its topic becomes "_deleted-topic_".
 	// See https://cloud.google.com/pubsub/docs/reference/rpc/google.pubsub.v1#subscription
-	deletedTopic     = "_deleted-topic_"
-	subCreated       = "SubscriptionCreated"
-	subDeleted       = "SubscriptionDeleted"
-	subConfigUpdated = "SubscriptionConfigUpdated"
+	deletedTopic         = "_deleted-topic_"
+	subCreated           = "SubscriptionCreated"
+	subDeleted           = "SubscriptionDeleted"
+	subConfigUpdated     = "SubscriptionConfigUpdated"
+	subOrderingImmutable = "SubscriptionOrderingImmutable"
 )
 
 func (r *Reconciler) ReconcileSubscription(ctx context.Context, id string, subConfig pubsub.SubscriptionConfig, obj runtime.Object, updater StatusUpdater) (*pubsub.Subscription, error) {
@@ -66,12 +67,15 @@ func (r *Reconciler) ReconcileSubscription(ctx context.Context, id string, subCo
 			}
 			return r.createSubscription(ctx, id, subConfig, obj, updater)
 		}
-		// Update the subscription config in case the retry or dead letter policy changed. A nil policy indicates no change.
+		// Update the subscription config in case the retry or dead letter policy, or the retention changed. A nil
+		// policy or a zero retention indicates no change.
 		if (subConfig.RetryPolicy != nil && !equality.Semantic.DeepEqual(config.RetryPolicy, subConfig.RetryPolicy)) ||
-			(subConfig.DeadLetterPolicy != nil && !equality.Semantic.DeepEqual(config.DeadLetterPolicy, subConfig.DeadLetterPolicy)) {
+			(subConfig.DeadLetterPolicy != nil && !equality.Semantic.DeepEqual(config.DeadLetterPolicy, subConfig.DeadLetterPolicy)) ||
+			(subConfig.RetentionDuration != 0 && config.RetentionDuration != subConfig.RetentionDuration) {
 			updateSubConfig := pubsub.SubscriptionConfigToUpdate{
-				RetryPolicy:      subConfig.RetryPolicy,
-				DeadLetterPolicy: subConfig.DeadLetterPolicy,
+				RetryPolicy:       subConfig.RetryPolicy,
+				DeadLetterPolicy:  subConfig.DeadLetterPolicy,
+				RetentionDuration: subConfig.RetentionDuration,
 			}
 			if _, err := sub.Update(ctx, updateSubConfig); err != nil {
 				updater.MarkSubscriptionFailed("SubscriptionConfigUpdateFailed", "Failed to update Pub/Sub subscription config: %v", err)
@@ -80,6 +84,11 @@ func (r *Reconciler) ReconcileSubscription(ctx context.Context, id string, subCo
 			logger.Info("Updated PubSub subscription config", zap.String("name", sub.ID()))
 			r.recorder.Eventf(obj, corev1.EventTypeNormal, subConfigUpdated, "Updated config for PubSub subscription %q", sub.ID())
 		}
+		// Message ordering can't be changed once the subscription is created.
+		if config.EnableMessageOrdering != subConfig.EnableMessageOrdering {
+			logger.Warn("Message ordering of the Pub/Sub subscription can't be changed", zap.String("name", sub.ID()), zap.Bool("enableMessageOrdering", config.EnableMessageOrdering))
+			r.recorder.Eventf(obj, corev1.EventTypeWarning, subOrderingImmutable, "Message ordering of PubSub subscription %q can't be changed, it's %v", sub.ID(), config.EnableMessageOrdering)
+		}
 		updater.MarkSubscriptionReady()
 		return sub, nil
 	}
@@ -78,6 +78,17 @@ func TestReconcileSub(t *testing.T) {
 			},
 			wantSubCondition: apis.Condition{Status: corev1.ConditionTrue},
 		},
+		{
+			name: "sub already exists, enable message ordering",
+			pre:  []reconcilertesting.PubsubAction{reconcilertesting.TopicAndSub(topic, sub)},
+			wantSubConfig: &pubsub.SubscriptionConfig{
+				EnableMessageOrdering: true,
+			},
+			wantEvents: []string{
+				`Warning SubscriptionOrderingImmutable Message ordering of PubSub subscription "test-sub" can't be changed, it's false`,
+			},
+			wantSubCondition: apis.Condition{Status: corev1.ConditionTrue},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -90,6 +101,7 @@ func TestReconcileSub(t *testing.T) {
 				subConfig.Labels = tc.wantSubConfig.Labels
 				subConfig.RetryPolicy = tc.wantSubConfig.RetryPolicy
 				subConfig.DeadLetterPolicy = tc.wantSubConfig.DeadLetterPolicy
+				subConfig.EnableMessageOrdering = tc.wantSubConfig.EnableMessageOrdering
 			}
 			res, err := r.ReconcileSubscription(context.Background(), sub, subConfig, obj, su)
 
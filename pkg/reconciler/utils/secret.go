@@ -0,0 +1,78 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	secretCreated = "SecretCreated"
+	secretUpdated = "SecretUpdated"
+)
+
+type SecretReconciler struct {
+	KubeClient kubernetes.Interface
+	Lister     corev1listers.SecretLister
+	Recorder   record.EventRecorder
+}
+
+// ReconcileSecret reconciles the K8s Secret 'secret'. The Secret is updated if its Data differs.
+func (r *SecretReconciler) ReconcileSecret(ctx context.Context, obj runtime.Object, secret *corev1.Secret, handlers ...cache.ResourceEventHandlerFuncs) (*corev1.Secret, error) {
+	current, err := r.Lister.Secrets(secret.Namespace).Get(secret.Name)
+	if apierrs.IsNotFound(err) {
+		current, err = r.KubeClient.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+		if apierrs.IsAlreadyExists(err) {
+			return current, nil
+		}
+		if err == nil {
+			r.Recorder.Eventf(obj, corev1.EventTypeNormal, secretCreated, "Created secret %s/%s", secret.Namespace, secret.Name)
+			for _, h := range handlers {
+				h.OnAdd(current)
+			}
+		}
+		return current, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	// An empty and a nil Data are equal, as the API server drops empty Data.
+	if (len(secret.Data) != 0 || len(current.Data) != 0) && !equality.Semantic.DeepEqual(secret.Data, current.Data) {
+		// Don't modify the informers copy.
+		desired := current.DeepCopy()
+		desired.Data = secret.Data
+		res, err := r.KubeClient.CoreV1().Secrets(desired.Namespace).Update(ctx, desired, metav1.UpdateOptions{})
+		if err == nil {
+			r.Recorder.Eventf(obj, corev1.EventTypeNormal, secretUpdated, "Updated secret %s/%s", res.Namespace, res.Name)
+			for _, h := range handlers {
+				h.OnUpdate(current, desired)
+			}
+		}
+		return res, err
+	}
+	return current, nil
+}
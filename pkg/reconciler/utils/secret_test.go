@@ -0,0 +1,133 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+	pkgreconcilertesting "knative.dev/pkg/reconciler/testing"
+)
+
+const (
+	secretCreatedEvent = "Normal SecretCreated Created secret testns/test"
+	secretUpdatedEvent = "Normal SecretUpdated Updated secret testns/test"
+)
+
+var (
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+		Data:       map[string][]byte{"key": []byte("value")},
+	}
+	secretDifferentData = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+		Data:       map[string][]byte{"key": []byte("different value")},
+	}
+	secretEmpty = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+	}
+
+	secretCreateFailure = pkgreconcilertesting.InduceFailure("create", "secrets")
+	secretUpdateFailure = pkgreconcilertesting.InduceFailure("update", "secrets")
+)
+
+func TestSecretReconciler(t *testing.T) {
+	var tests = []struct {
+		commonCase
+		in   *corev1.Secret
+		want *corev1.Secret
+	}{
+		{
+			commonCase: commonCase{
+				name:     "secret exists, nothing to do",
+				existing: []runtime.Object{secret},
+			},
+			in:   secret,
+			want: secret,
+		},
+		{
+			commonCase: commonCase{
+				name:     "empty secret exists, nothing to do",
+				existing: []runtime.Object{secretEmpty},
+			},
+			in: &corev1.Secret{
+				ObjectMeta: secretEmpty.ObjectMeta,
+				Data:       map[string][]byte{},
+			},
+			want: secretEmpty,
+		},
+		{
+			commonCase: commonCase{
+				name:       "secret created",
+				wantEvents: []string{secretCreatedEvent},
+			},
+			in:   secret,
+			want: secret,
+		},
+		{
+			commonCase: commonCase{
+				name:      "secret creation error",
+				reactions: []clientgotesting.ReactionFunc{secretCreateFailure},
+				wantErr:   true,
+			},
+			in: secret,
+		},
+		{
+			commonCase: commonCase{
+				name:       "secret updated - different data",
+				existing:   []runtime.Object{secretDifferentData},
+				wantEvents: []string{secretUpdatedEvent},
+			},
+			in:   secret,
+			want: secret,
+		},
+		{
+			commonCase: commonCase{
+				name:      "secret update error",
+				reactions: []clientgotesting.ReactionFunc{secretUpdateFailure},
+				existing:  []runtime.Object{secretDifferentData},
+				wantErr:   true,
+			},
+			in: secret,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tr.setup(test.commonCase)
+
+			rec := SecretReconciler{
+				KubeClient: tr.client,
+				Lister:     tr.listers.GetSecretLister(),
+				Recorder:   tr.recorder,
+			}
+			out, err := rec.ReconcileSecret(context.Background(), obj, test.in)
+
+			tr.verify(t, test.commonCase, err)
+
+			if diff := cmp.Diff(out, test.want); diff != "" {
+				t.Errorf("Unexpected reconciler result (-got, +want): %s", diff)
+				return
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import "fmt"
+
+const (
+	CloudBillingBudgetCostUpdatedEventType = "google.cloud.billing.budget.v1.costUpdated"
+
+	// CloudBillingBudgetCostAmountExtension is the CloudEvent extension with
+	// the cost accrued in the current budget period, e.g. 140.32.
+	CloudBillingBudgetCostAmountExtension = "costamount"
+	// CloudBillingBudgetThresholdExceededExtension is the CloudEvent extension
+	// with the highest alert threshold of the budget that has been exceeded
+	// in the current period, if any, e.g. 0.9 for 90% of the budget.
+	CloudBillingBudgetThresholdExceededExtension = "thresholdexceeded"
+	// CloudBillingBudgetCurrencyExtension is the CloudEvent extension with the
+	// currency code of the cost amount, e.g. USD.
+	CloudBillingBudgetCurrencyExtension = "currency"
+)
+
+// CloudBillingBudgetEventSource returns the Cloud Billing budget CloudEvent
+// source value.
+// Format e.g. //billingbudgets.googleapis.com/billingAccounts/012345-6789AB-CDEF01
+func CloudBillingBudgetEventSource(billingAccount string) string {
+	return fmt.Sprintf("//billingbudgets.googleapis.com/billingAccounts/%s", billingAccount)
+}
+
+// CloudBillingBudgetEventSubject returns the Cloud Billing budget CloudEvent
+// subject value.
+// Format e.g. budgets/budget-id
+func CloudBillingBudgetEventSubject(budget string) string {
+	return fmt.Sprintf("budgets/%s", budget)
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import "fmt"
+
+const (
+	CloudContainerRegistryPushedEventType  = "google.cloud.artifactregistry.v1.pushed"
+	CloudContainerRegistryDeletedEventType = "google.cloud.artifactregistry.v1.deleted"
+
+	// CloudContainerRegistryRepositoryExtension is the CloudEvent extension
+	// with the repository of the image, e.g. gcr.io/my-project/hello-world.
+	CloudContainerRegistryRepositoryExtension = "repository"
+	// CloudContainerRegistryTagExtension is the CloudEvent extension with the
+	// tag of the image, if any, e.g. latest.
+	CloudContainerRegistryTagExtension = "tag"
+)
+
+// CloudContainerRegistryEventSource returns the Container Registry and
+// Artifact Registry CloudEvent source value.
+// Format e.g. //artifactregistry.googleapis.com/projects/project-id
+func CloudContainerRegistryEventSource(project string) string {
+	return fmt.Sprintf("//artifactregistry.googleapis.com/projects/%s", project)
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import "fmt"
+
+const (
+	CloudFirestoreDocumentCreatedEventType = "google.cloud.firestore.document.v1.created"
+	CloudFirestoreDocumentUpdatedEventType = "google.cloud.firestore.document.v1.updated"
+	CloudFirestoreDocumentDeletedEventType = "google.cloud.firestore.document.v1.deleted"
+
+	// CloudFirestoreDefaultDatabase is the ID of the default database of a project.
+	CloudFirestoreDefaultDatabase = "(default)"
+)
+
+// CloudFirestoreEventSource returns the Cloud Firestore CloudEvent source value.
+// Format e.g. //firestore.googleapis.com/projects/project-id/databases/(default)
+func CloudFirestoreEventSource(project, database string) string {
+	return fmt.Sprintf("//firestore.googleapis.com/projects/%s/databases/%s", project, database)
+}
+
+// CloudFirestoreEventSubject returns the Cloud Firestore CloudEvent subject value.
+// Format e.g. documents/users/alice
+func CloudFirestoreEventSubject(document string) string {
+	return fmt.Sprintf("documents/%s", document)
+}
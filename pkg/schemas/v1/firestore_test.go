@@ -0,0 +1,37 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+)
+
+func TestCloudFirestoreEventSource(t *testing.T) {
+	want := "//firestore.googleapis.com/projects/project/databases/(default)"
+	got := CloudFirestoreEventSource("project", "(default)")
+	if got != want {
+		t.Errorf("CloudFirestoreEventSource got=%s, want=%s", got, want)
+	}
+}
+
+func TestCloudFirestoreEventSubject(t *testing.T) {
+	want := "documents/users/alice"
+	got := CloudFirestoreEventSubject("users/alice")
+	if got != want {
+		t.Errorf("CloudFirestoreEventSubject got=%s, want=%s", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import "fmt"
+
+const (
+	CloudMonitoringIncidentOpenedEventType = "google.cloud.monitoring.incident.v1.opened"
+	CloudMonitoringIncidentClosedEventType = "google.cloud.monitoring.incident.v1.closed"
+
+	// CloudMonitoringSeverityExtension is the CloudEvent extension with the
+	// severity of the alert policy of the incident, if any, e.g. critical.
+	CloudMonitoringSeverityExtension = "severity"
+	// CloudMonitoringStateExtension is the CloudEvent extension with the
+	// state of the incident, either open or closed.
+	CloudMonitoringStateExtension = "state"
+)
+
+// CloudMonitoringEventSource returns the Cloud Monitoring CloudEvent source value.
+// Format e.g. //monitoring.googleapis.com/projects/project-id
+func CloudMonitoringEventSource(project string) string {
+	return fmt.Sprintf("//monitoring.googleapis.com/projects/%s", project)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import "fmt"
+
+const (
+	CloudSecretManagerSecretCreatedEventType          = "google.cloud.secretmanager.secret.v1.created"
+	CloudSecretManagerSecretUpdatedEventType          = "google.cloud.secretmanager.secret.v1.updated"
+	CloudSecretManagerSecretDeletedEventType          = "google.cloud.secretmanager.secret.v1.deleted"
+	CloudSecretManagerSecretRotatedEventType          = "google.cloud.secretmanager.secret.v1.rotated"
+	CloudSecretManagerSecretVersionAddedEventType     = "google.cloud.secretmanager.secret.v1.versionAdded"
+	CloudSecretManagerSecretVersionEnabledEventType   = "google.cloud.secretmanager.secret.v1.versionEnabled"
+	CloudSecretManagerSecretVersionDisabledEventType  = "google.cloud.secretmanager.secret.v1.versionDisabled"
+	CloudSecretManagerSecretVersionDestroyedEventType = "google.cloud.secretmanager.secret.v1.versionDestroyed"
+)
+
+// CloudSecretManagerEventSource returns the Secret Manager CloudEvent source
+// value from the resource name of the secret.
+// Format e.g. //secretmanager.googleapis.com/projects/my-project/secrets/my-secret
+func CloudSecretManagerEventSource(secret string) string {
+	return fmt.Sprintf("//secretmanager.googleapis.com/%s", secret)
+}
+
+// CloudSecretManagerEventSubject returns the Secret Manager CloudEvent subject
+// value of the events about a secret version.
+// Format e.g. versions/1
+func CloudSecretManagerEventSubject(version string) string {
+	return fmt.Sprintf("versions/%s", version)
+}
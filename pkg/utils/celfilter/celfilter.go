@@ -0,0 +1,168 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package celfilter compiles and evaluates CEL expressions used to filter
+// events delivered to broker targets.
+//
+// An expression can reference two variables:
+//   - ce is a map of the event context attributes and extensions, e.g.
+//     ce.type == "google.cloud.storage.object.v1.finalized".
+//   - data is the event payload. JSON payloads are decoded, so fields can be
+//     selected, e.g. data.bucket == "prod-uploads" && data.size > 1e6.
+//     Other payloads are exposed as a string.
+package celfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+)
+
+const (
+	// attributesVar is the variable holding the event context attributes and extensions.
+	attributesVar = "ce"
+	// dataVar is the variable holding the event payload.
+	dataVar = "data"
+)
+
+var (
+	envOnce sync.Once
+	env     *cel.Env
+	envErr  error
+)
+
+// Program is a compiled CEL filter expression. It is safe for concurrent use.
+type Program struct {
+	expr string
+	prg  cel.Program
+}
+
+// Compile parses and type-checks the CEL filter expression. The expression must
+// evaluate to a bool.
+func Compile(expr string) (*Program, error) {
+	e, err := celEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := e.Compile(expr)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if !proto.Equal(ast.ResultType(), decls.Bool) && !proto.Equal(ast.ResultType(), decls.Dyn) {
+		return nil, fmt.Errorf("expression must evaluate to a bool, got %v", ast.ResultType())
+	}
+	prg, err := e.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+	return &Program{expr: expr, prg: prg}, nil
+}
+
+// Expression returns the source of the compiled expression.
+func (p *Program) Expression() string {
+	return p.expr
+}
+
+// Eval evaluates the filter expression against the event. An error is returned
+// if the evaluation fails, e.g. when the expression selects a missing field.
+func (p *Program) Eval(e *event.Event) (bool, error) {
+	out, _, err := p.prg.Eval(map[string]interface{}{
+		attributesVar: attributes(e),
+		// The payload is only decoded if the expression references it.
+		dataVar: func() interface{} { return data(e) },
+	})
+	if err != nil {
+		return false, err
+	}
+	pass, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression evaluated to %v, not a bool", out.Value())
+	}
+	return pass, nil
+}
+
+func celEnv() (*cel.Env, error) {
+	envOnce.Do(func() {
+		env, envErr = cel.NewEnv(
+			cel.Declarations(
+				decls.NewVar(attributesVar, decls.NewMapType(decls.String, decls.Dyn)),
+				decls.NewVar(dataVar, decls.Dyn),
+			),
+		)
+	})
+	return env, envErr
+}
+
+// attributes returns the context attributes and extensions which are set on the event.
+func attributes(e *event.Event) map[string]interface{} {
+	attrs := map[string]interface{}{
+		"specversion": e.SpecVersion(),
+		"type":        e.Type(),
+		"source":      e.Source(),
+		"id":          e.ID(),
+	}
+	if v := e.Subject(); v != "" {
+		attrs["subject"] = v
+	}
+	if v := e.DataSchema(); v != "" {
+		attrs["dataschema"] = v
+	}
+	if v := e.DataContentType(); v != "" {
+		attrs["datacontenttype"] = v
+	}
+	if v := e.Time(); !v.IsZero() {
+		attrs["time"] = types.FormatTime(v)
+	}
+	for k, v := range e.Extensions() {
+		switch v.(type) {
+		case string, bool, int32:
+			attrs[k] = v
+		default:
+			// URIs and timestamps are matched by their canonical string form.
+			if s, err := types.Format(v); err == nil {
+				attrs[k] = s
+			}
+		}
+	}
+	return attrs
+}
+
+// data returns the decoded JSON payload of the event, or the payload as a string
+// if it isn't JSON.
+func data(e *event.Event) interface{} {
+	b := e.Data()
+	if len(b) == 0 {
+		return nil
+	}
+	if isJSON(e.DataMediaType()) {
+		var v interface{}
+		if err := json.Unmarshal(b, &v); err == nil {
+			return v
+		}
+	}
+	return string(b)
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "" || mediaType == event.ApplicationJSON || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
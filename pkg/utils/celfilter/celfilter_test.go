@@ -0,0 +1,147 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package celfilter
+
+import (
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{{
+		name: "attribute expression",
+		expr: `ce.type == "foo" && ce.source.startsWith("bar")`,
+	}, {
+		name: "data expression",
+		expr: `data.bucket == "prod-uploads" && data.size > 1e6`,
+	}, {
+		name:    "syntax error",
+		expr:    `ce.type ==`,
+		wantErr: true,
+	}, {
+		name:    "undeclared variable",
+		expr:    `type == "foo"`,
+		wantErr: true,
+	}, {
+		name:    "not a bool",
+		expr:    `"foo"`,
+		wantErr: true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := Compile(test.expr)
+			if test.wantErr != (err != nil) {
+				t.Fatalf("Compile error got=%v, wantErr=%v", err, test.wantErr)
+			}
+			if err == nil && p.Expression() != test.expr {
+				t.Errorf("Expression got=%q, want=%q", p.Expression(), test.expr)
+			}
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	newEvent := func(contentType string, data string) *event.Event {
+		e := event.New()
+		e.SetID("id")
+		e.SetType("google.cloud.storage.object.v1.finalized")
+		e.SetSource("//storage.googleapis.com/projects/_/buckets/prod-uploads")
+		e.SetExtension("priority", 5)
+		if data != "" {
+			e.SetData(contentType, []byte(data))
+		}
+		return &e
+	}
+
+	tests := []struct {
+		name    string
+		expr    string
+		event   *event.Event
+		want    bool
+		wantErr bool
+	}{{
+		name:  "attributes pass",
+		expr:  `ce.type.startsWith("google.cloud.storage.") && ce.id == "id"`,
+		event: newEvent("", ""),
+		want:  true,
+	}, {
+		name:  "attributes not pass",
+		expr:  `ce.type == "google.cloud.pubsub.topic.v1.messagePublished"`,
+		event: newEvent("", ""),
+		want:  false,
+	}, {
+		name:  "extension pass",
+		expr:  `ce.priority > 3`,
+		event: newEvent("", ""),
+		want:  true,
+	}, {
+		name:  "missing attribute check",
+		expr:  `!has(ce.subject)`,
+		event: newEvent("", ""),
+		want:  true,
+	}, {
+		name:    "missing attribute",
+		expr:    `ce.subject == "foo"`,
+		event:   newEvent("", ""),
+		wantErr: true,
+	}, {
+		name:  "json data pass",
+		expr:  `data.bucket == "prod-uploads" && data.size > 1e6`,
+		event: newEvent(event.ApplicationJSON, `{"bucket":"prod-uploads","size":2000000}`),
+		want:  true,
+	}, {
+		name:  "json data not pass",
+		expr:  `data.bucket == "prod-uploads" && data.size > 1e6`,
+		event: newEvent(event.ApplicationJSON, `{"bucket":"prod-uploads","size":10}`),
+		want:  false,
+	}, {
+		name:    "json data missing field",
+		expr:    `data.bucket == "prod-uploads"`,
+		event:   newEvent(event.ApplicationJSON, `{"name":"foo"}`),
+		wantErr: true,
+	}, {
+		name:  "text data",
+		expr:  `data.contains("hello")`,
+		event: newEvent("text/plain", "hello world"),
+		want:  true,
+	}, {
+		name:  "no data",
+		expr:  `data == null`,
+		event: newEvent("", ""),
+		want:  true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := Compile(test.expr)
+			if err != nil {
+				t.Fatalf("Compile error: %v", err)
+			}
+			got, err := p.Eval(test.event)
+			if test.wantErr != (err != nil) {
+				t.Fatalf("Eval error got=%v, wantErr=%v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("Eval got=%v, want=%v", got, test.want)
+			}
+		})
+	}
+}
@@ -30,6 +30,7 @@ import (
 )
 
 type Port int
+type GRPCPort int
 type ProjectID string
 type MaxConnsPerHost int
 
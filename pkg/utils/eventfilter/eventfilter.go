@@ -0,0 +1,77 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventfilter matches CloudEvents against the attributes of a filter.
+// It is shared by the broker targets and the receive adapters of the sources.
+package eventfilter
+
+import (
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// PassFilter checks given event against attributes available in the attrs map to determine
+// if the event should pass or not.
+func PassFilter(ctx context.Context, attrs map[string]string, event *event.Event) bool {
+	ce := Attributes(event)
+	for k, v := range attrs {
+		var value interface{}
+		value, ok := ce[k]
+		// If the attribute does not exist in the event, return false.
+		if !ok {
+			logging.FromContext(ctx).Debug("Attribute not found", zap.String("attribute", k))
+			trace.FromContext(ctx).Annotatef(nil, "event missing filter attribute %q", k)
+			return false
+		}
+		// If the attribute is not set to any and is different than the one from the event, return false.
+		if v != "" && v != value {
+			logging.FromContext(ctx).Debug("Attribute had non-matching value", zap.String("attribute", k), zap.String("filter", v), zap.Any("received", value))
+			trace.FromContext(ctx).Annotatef(nil, "event attribute %q does not match filter value %q", k, v)
+			return false
+		}
+	}
+	return true
+}
+
+// Attributes returns the context attributes and extensions of the event keyed by name.
+func Attributes(event *event.Event) map[string]interface{} {
+	// Set standard context attributes. The attributes available may not be
+	// exactly the same as the attributes defined in the current version of the
+	// CloudEvents spec.
+	ce := map[string]interface{}{
+		"specversion":     event.SpecVersion(),
+		"type":            event.Type(),
+		"source":          event.Source(),
+		"subject":         event.Subject(),
+		"id":              event.ID(),
+		"time":            event.Time().String(),
+		"schemaurl":       event.DataSchema(),
+		"datacontenttype": event.DataContentType(),
+		"datamediatype":   event.DataMediaType(),
+		// TODO: use data_base64 when SDK supports it.
+		"datacontentencoding": event.DeprecatedDataContentEncoding(),
+	}
+	ext := event.Extensions()
+	for k, v := range ext {
+		ce[k] = v
+	}
+	return ce
+}
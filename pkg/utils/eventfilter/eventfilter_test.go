@@ -0,0 +1,66 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventfilter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+func TestPassFilter(t *testing.T) {
+	e := event.New()
+	e.SetID("id")
+	e.SetType("type")
+	e.SetSource("source")
+	e.SetExtension("tag", "latest")
+
+	tests := []struct {
+		name  string
+		attrs map[string]string
+		want  bool
+	}{{
+		name: "no filter",
+		want: true,
+	}, {
+		name:  "matching attributes",
+		attrs: map[string]string{"type": "type", "source": "source"},
+		want:  true,
+	}, {
+		name:  "matching extension",
+		attrs: map[string]string{"tag": "latest"},
+		want:  true,
+	}, {
+		name:  "any value",
+		attrs: map[string]string{"tag": ""},
+		want:  true,
+	}, {
+		name:  "non-matching attribute",
+		attrs: map[string]string{"type": "type", "source": "other"},
+	}, {
+		name:  "missing extension",
+		attrs: map[string]string{"repository": ""},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := PassFilter(context.Background(), tc.attrs, &e); got != tc.want {
+				t.Errorf("PassFilter got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}